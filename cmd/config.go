@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle"
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type resolvedConfig struct {
+	ProviderPairs map[string][]string `json:"provider_pairs" yaml:"provider_pairs"`
+	RequiredRates []string            `json:"required_rates" yaml:"required_rates"`
+}
+
+func getConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect price-feeder config files",
+	}
+
+	configCmd.AddCommand(getConfigValidateCmd())
+
+	return configCmd
+}
+
+func getConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [config-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Validate a config file and print the provider pairs and rates it resolves to",
+		Long: `Validate a config file, including the provider config files in its config
+directory, and print the currency pairs of each provider along with the USD
+rates the oracle requires. It exits with an error if the config is invalid,
+such as when the quote of a currency pair has no supported conversion pair.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfigFromFlags(args[0], "")
+			if err != nil {
+				return err
+			}
+
+			providerPairs := cfg.ProviderPairs()
+			resolved := resolvedConfig{
+				ProviderPairs: make(map[string][]string, len(providerPairs)),
+			}
+			for providerName, pairs := range providerPairs {
+				for _, pair := range pairs {
+					resolved.ProviderPairs[string(providerName)] = append(
+						resolved.ProviderPairs[string(providerName)],
+						formatPair(pair),
+					)
+				}
+				sort.Strings(resolved.ProviderPairs[string(providerName)])
+			}
+
+			o := oracle.New(zerolog.Nop(), client.OracleClient{}, providerPairs, 0, 0, nil, nil, false)
+			for _, pair := range o.RequiredRates() {
+				resolved.RequiredRates = append(resolved.RequiredRates, formatPair(pair))
+			}
+			sort.Strings(resolved.RequiredRates)
+
+			bz, err := yaml.Marshal(&resolved)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Print(string(bz))
+			return err
+		},
+	}
+}
+
+// formatPair formats the currency pair in the "BASE/QUOTE" format.
+func formatPair(pair types.CurrencyPair) string {
+	return pair.Base + "/" + pair.Quote
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle"
 	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/types"
 	v1 "github.com/ojo-network/price-feeder/router/v1"
 )
 
@@ -37,6 +39,7 @@ const (
 	flagLogFormat               = "log-format"
 	flagSkipProviderCheck       = "skip-provider-check"
 	flagConfigCurrencyProviders = "config-currency-providers"
+	flagDryRun                  = "dry-run"
 
 	envVariablePass = "PRICE_FEEDER_PASS"
 )
@@ -67,8 +70,14 @@ func init() {
 		false,
 		"use config file for currency pair providers and deviations instead of on chain values",
 	)
+	rootCmd.PersistentFlags().Bool(
+		flagDryRun,
+		false,
+		"compute prices and log the prevotes and votes without broadcasting them; overrides dry_run",
+	)
 
 	rootCmd.AddCommand(getVersionCmd())
+	rootCmd.AddCommand(getConfigCmd())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -106,6 +115,11 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	dryRun, err := cmd.Flags().GetBool(flagDryRun)
+	if err != nil {
+		return err
+	}
+
 	var logWriter io.Writer
 	switch strings.ToLower(logFormatStr) {
 	case logLevelJSON:
@@ -124,6 +138,9 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	for _, deprecatedField := range cfg.DeprecatedFields {
+		logger.Warn().Msg(deprecatedField.Warning())
+	}
 
 	if !skipProviderCheck {
 		err = config.CheckProviderMins(cmd.Context(), logger, cfg)
@@ -155,22 +172,35 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		cfg.Account.ChainID,
 		cfg.Keyring.Backend,
 		cfg.Keyring.Dir,
+		cfg.Keyring.Algo,
 		keyringPass,
 		cfg.RPC.TMRPCEndpoint,
+		cfg.RPC.TMRPCFallbackEndpoints,
 		rpcTimeout,
 		cfg.Account.Address,
 		cfg.Account.Validator,
 		cfg.RPC.GRPCEndpoint,
+		cfg.RPC.GRPCFallbackEndpoints,
 		cfg.GasAdjustment,
 		cfg.Gas,
+		cfg.Account.FeeGranter,
+		cfg.Account.AuthzGranter,
 	)
 	if err != nil {
 		return err
 	}
 
-	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	var signer oracle.Signer
+	if cfg.Server.SignPrices {
+		signer, err = oracleClient.NewSnapshotSigner()
+		if err != nil {
+			return fmt.Errorf("failed to create price signer: %w", err)
+		}
+	}
+
+	providerTimeout, providerTimeouts, err := cfg.ProviderTimeouts()
 	if err != nil {
-		return fmt.Errorf("failed to parse provider timeout: %w", err)
+		return err
 	}
 
 	deviations, err := cfg.DeviationsMap()
@@ -178,16 +208,183 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	conversionRateOverrides, err := cfg.ConversionRateOverridesMap()
+	if err != nil {
+		return err
+	}
+
+	minTotalVolumes, err := cfg.MinTotalVolumesMap()
+	if err != nil {
+		return err
+	}
+
+	priceBands, err := cfg.PriceBandsMap()
+	if err != nil {
+		return err
+	}
+
+	trustedProviders, err := cfg.TrustedProvidersMap()
+	if err != nil {
+		return err
+	}
+
+	providerDeviations, err := cfg.ProviderDeviationsMap()
+	if err != nil {
+		return err
+	}
+
+	providerWeights, err := cfg.ProviderWeightsMap()
+	if err != nil {
+		return err
+	}
+
+	tvwapLookbacks, err := cfg.TVWAPLookbacksMap()
+	if err != nil {
+		return err
+	}
+
+	voteNudgeStep, err := cfg.VoteNudgeStepDec()
+	if err != nil {
+		return err
+	}
+
+	maxPriceChangePct, err := cfg.MaxPriceChangePctDec()
+	if err != nil {
+		return err
+	}
+
+	var adaptiveTimeout *oracle.AdaptiveTimeout
+	if cfg.AdaptiveTimeout.Enabled {
+		adaptiveTimeout, err = newAdaptiveTimeout(cfg.AdaptiveTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	var circuitBreaker *oracle.CircuitBreaker
+	if cfg.CircuitBreaker.Enabled {
+		cooldown, err := time.ParseDuration(cfg.CircuitBreaker.Cooldown)
+		if err != nil {
+			return fmt.Errorf("failed to parse circuit breaker cooldown: %w", err)
+		}
+		circuitBreaker = oracle.NewCircuitBreaker(cfg.CircuitBreaker.FailureThreshold, cooldown)
+	}
+
+	var alerter oracle.Alerter
+	if cfg.Alerting.WebhookURL != "" {
+		alerter = oracle.NewWebhookAlerter(cfg.Alerting.WebhookURL, cfg.AlertEvents())
+	}
+	minBalance, err := cfg.AlertMinBalance()
+	if err != nil {
+		return fmt.Errorf("failed to parse alerting min balance: %w", err)
+	}
+
+	prevoteFile := cfg.PrevoteFile
+	if prevoteFile == "" {
+		prevoteFile = filepath.Join(filepath.Dir(args[0]), oracle.PrevoteFileName)
+	}
+
+	tickInterval, err := time.ParseDuration(cfg.TickInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse tick interval: %w", err)
+	}
+
 	oracle := oracle.New(
 		logger,
 		oracleClient,
 		cfg.ProviderPairs(),
 		providerTimeout,
+		tickInterval,
 		deviations,
 		cfg.ProviderEndpointsMap(),
 		!configCurrencyProviders,
 	)
 
+	if signer != nil {
+		oracle.SetSigner(signer)
+	}
+
+	if cfg.PairRevalidationInterval != "" {
+		pairRevalidationInterval, err := time.ParseDuration(cfg.PairRevalidationInterval)
+		if err != nil {
+			return fmt.Errorf("failed to parse pair revalidation interval: %w", err)
+		}
+		oracle.SetPairRevalidationInterval(pairRevalidationInterval)
+	}
+
+	if cfg.ParamsMaxAge != "" {
+		paramsMaxAge, err := time.ParseDuration(cfg.ParamsMaxAge)
+		if err != nil {
+			return fmt.Errorf("failed to parse params max age: %w", err)
+		}
+		oracle.SetParamsMaxAge(paramsMaxAge)
+	}
+
+	if cfg.ShutdownGracePeriod != "" {
+		shutdownGracePeriod, err := time.ParseDuration(cfg.ShutdownGracePeriod)
+		if err != nil {
+			return fmt.Errorf("failed to parse shutdown grace period: %w", err)
+		}
+		oracle.SetShutdownGracePeriod(shutdownGracePeriod)
+	}
+
+	if cfg.TVWAPMinPeriod != "" {
+		tvwapMinPeriod, err := time.ParseDuration(cfg.TVWAPMinPeriod)
+		if err != nil {
+			return fmt.Errorf("failed to parse tvwap min period: %w", err)
+		}
+		oracle.SetTVWAPMinPeriod(tvwapMinPeriod)
+	}
+	oracle.SetTVWAPLookbacks(tvwapLookbacks)
+
+	if cfg.MaxPriceAge != "" {
+		maxPriceAge, err := time.ParseDuration(cfg.MaxPriceAge)
+		if err != nil {
+			return fmt.Errorf("failed to parse max price age: %w", err)
+		}
+		oracle.SetMaxPriceAge(maxPriceAge)
+	}
+
+	if cfg.CandleGapFillInterval != "" {
+		candleGapFillInterval, err := time.ParseDuration(cfg.CandleGapFillInterval)
+		if err != nil {
+			return fmt.Errorf("failed to parse candle gap fill interval: %w", err)
+		}
+		oracle.SetCandleGapFillInterval(candleGapFillInterval)
+	}
+
+	oracle.SetTickerVolumePolicy(types.TickerVolumePolicy(cfg.TickerVolumePolicy))
+	oracle.SetAggregationMethod(types.AggregationMethod(cfg.AggregationMethod))
+	oracle.SetMinProvidersPerAsset(cfg.MinProvidersPerAsset)
+	oracle.SetMaxPairsPerTick(cfg.MaxPairsPerTick)
+	oracle.SetProviderTimeouts(providerTimeouts)
+	oracle.SetMinTotalVolumes(minTotalVolumes)
+	oracle.SetPriceBands(priceBands)
+	oracle.SetTrustedProviders(trustedProviders)
+	oracle.SetUnfilteredPairs(cfg.UnfilteredPairsMap())
+	oracle.SetProviderDeviationMultipliers(providerDeviations)
+	oracle.SetProviderWeights(providerWeights)
+	oracle.SetReferencePairs(cfg.ReferenceProviderPairs())
+	oracle.SetVotePrecision(cfg.VotePrecision, types.RoundingMode(cfg.RoundingMode))
+	oracle.SetVoteNudgeStep(voteNudgeStep)
+	oracle.SetMaxPriceChangePct(maxPriceChangePct)
+	oracle.SetParamsFallbackMaxAge(cfg.ParamsFallbackMaxAge)
+	oracle.SetVoteAudit(cfg.VoteAudit)
+	oracle.SetDryRun(cfg.DryRun || dryRun)
+	oracle.SetPrevoteFile(prevoteFile)
+	oracle.SetConversionRateOverrides(conversionRateOverrides)
+	oracle.SetConversionRawFallback(cfg.ConversionRawFallback)
+	if adaptiveTimeout != nil {
+		oracle.SetAdaptiveTimeout(adaptiveTimeout)
+	}
+	if circuitBreaker != nil {
+		oracle.SetCircuitBreaker(circuitBreaker)
+	}
+	if alerter != nil {
+		oracle.SetAlerter(alerter)
+		oracle.SetMinBalance(minBalance)
+	}
+
 	if !configCurrencyProviders {
 		err := oracle.LoadProviderPairsAndDeviations(ctx)
 		if err != nil {
@@ -219,6 +416,23 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 	return g.Wait()
 }
 
+func newAdaptiveTimeout(cfg config.AdaptiveTimeout) (*oracle.AdaptiveTimeout, error) {
+	margin, err := time.ParseDuration(cfg.Margin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse adaptive timeout margin: %w", err)
+	}
+	minTimeout, err := time.ParseDuration(cfg.MinTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse adaptive timeout min timeout: %w", err)
+	}
+	maxTimeout, err := time.ParseDuration(cfg.MaxTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse adaptive timeout max timeout: %w", err)
+	}
+
+	return oracle.NewAdaptiveTimeout(cfg.Window, margin, minTimeout, maxTimeout), nil
+}
+
 func getKeyringPassword() (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -311,6 +525,10 @@ func startPriceOracle(ctx context.Context, logger zerolog.Logger, oracle *oracle
 		select {
 		case <-ctx.Done():
 			logger.Info().Msg("shutting down price-feeder oracle...")
+
+			// wait for the in-flight tick to be drained, which the oracle
+			// bounds by its shutdown grace period
+			<-srvErrCh
 			return nil
 
 		case err := <-srvErrCh:
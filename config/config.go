@@ -3,10 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/ojo-network/price-feeder/oracle/provider"
@@ -20,6 +22,39 @@ const (
 	defaultSrvWriteTimeout = 15 * time.Second
 	defaultSrvReadTimeout  = 15 * time.Second
 	defaultProviderTimeout = 100 * time.Millisecond
+	defaultTVWAPMinPeriod  = 1 * time.Minute
+	defaultTickInterval    = 1 * time.Second
+
+	// minTickInterval is the shortest tick interval allowed, so the oracle
+	// loop doesn't hammer the providers and the node.
+	minTickInterval = 100 * time.Millisecond
+
+	// defaultHealthStalenessTicks is the number of tick intervals the last
+	// price sync may be older than for the price feeder to be reported as
+	// available, when no health staleness window is configured.
+	defaultHealthStalenessTicks = 5
+
+	// minHealthStalenessTicks is the number of tick intervals the health
+	// staleness window must span at least, so the price feeder is not reported
+	// as unavailable between two price syncs.
+	minHealthStalenessTicks = 2
+
+	defaultAdaptiveTimeoutWindow = 100
+	defaultAdaptiveTimeoutMargin = 50 * time.Millisecond
+	defaultAdaptiveTimeoutMin    = 100 * time.Millisecond
+	defaultAdaptiveTimeoutMax    = 2 * time.Second
+
+	defaultCircuitBreakerFailureThreshold = 5
+	defaultCircuitBreakerCooldown         = 1 * time.Minute
+
+	// maxUSDConversionHops defines the most supported conversion pairs a
+	// price can be converted through to reach USD, matching the conversion
+	// routes of the oracle.
+	maxUSDConversionHops = 3
+
+	// providerTimeoutDefaultKey is the key of the provider_timeout table
+	// holding the timeout of the providers without their own.
+	providerTimeoutDefaultKey = "default"
 
 	SampleNodeConfigPath = "price-feeder.example.toml"
 )
@@ -38,19 +73,84 @@ var (
 type (
 	// Config defines all necessary price-feeder configuration parameters.
 	Config struct {
-		ConfigDir           string              `mapstructure:"config_dir"`
-		Server              Server              `mapstructure:"server"`
-		CurrencyPairs       []CurrencyPair      `mapstructure:"currency_pairs"`
-		Deviations          []Deviation         `mapstructure:"deviation_thresholds"`
-		Account             Account             `mapstructure:"account"`
-		Keyring             Keyring             `mapstructure:"keyring"`
-		RPC                 RPC                 `mapstructure:"rpc" validate:"required,gt=0,dive,required"`
-		Telemetry           telemetry.Config    `mapstructure:"telemetry"`
-		GasAdjustment       float64             `mapstructure:"gas_adjustment"`
-		Gas                 uint64              `mapstructure:"gas"`
-		ProviderTimeout     string              `mapstructure:"provider_timeout"`
-		ProviderMinOverride bool                `mapstructure:"provider_min_override"`
-		ProviderEndpoints   []provider.Endpoint `mapstructure:"provider_endpoints" validate:"dive"`
+		ConfigDir                 string              `mapstructure:"config_dir"`
+		Server                    Server              `mapstructure:"server"`
+		CurrencyPairs             []CurrencyPair      `mapstructure:"currency_pairs"`
+		ReferencePairs            []CurrencyPair      `mapstructure:"reference_pairs" validate:"dive"`
+		Deviations                []Deviation         `mapstructure:"deviation_thresholds"`
+		ConversionRateOverrides   []ConversionRate    `mapstructure:"conversion_rate_overrides" validate:"dive"`
+		ConversionRawFallback     bool                `mapstructure:"conversion_raw_fallback"`
+		MinTotalVolumes           []MinTotalVolume    `mapstructure:"min_total_volumes" validate:"dive"`
+		PriceBands                []PriceBand         `mapstructure:"price_bands" validate:"dive"`
+		TrustedProviders          []TrustedProvider   `mapstructure:"trusted_providers" validate:"dive"`
+		UnfilteredPairs           []UnfilteredPair    `mapstructure:"unfiltered_pairs" validate:"dive"`
+		ProviderDeviations        []ProviderDeviation `mapstructure:"provider_deviation_multipliers" validate:"dive"`
+		ProviderWeights           []ProviderWeight    `mapstructure:"provider_weights" validate:"dive"`
+		Account                   Account             `mapstructure:"account"`
+		Keyring                   Keyring             `mapstructure:"keyring"`
+		RPC                       RPC                 `mapstructure:"rpc" validate:"required,gt=0,dive,required"`
+		Telemetry                 telemetry.Config    `mapstructure:"telemetry"`
+		GasAdjustment             float64             `mapstructure:"gas_adjustment"`
+		Gas                       uint64              `mapstructure:"gas"`
+		ProviderTimeout           ProviderTimeout     `mapstructure:"provider_timeout"`
+		SkipProviderMinsOnFailure bool                `mapstructure:"skip_provider_mins_on_failure"`
+		ProviderEndpoints         []provider.Endpoint `mapstructure:"provider_endpoints" validate:"dive"`
+		PairRevalidationInterval  string              `mapstructure:"pair_revalidation_interval"`
+		TickerVolumePolicy        string              `mapstructure:"ticker_volume_policy"`
+		AggregationMethod         string              `mapstructure:"aggregation_method"`
+		MinProvidersPerAsset      int                 `mapstructure:"min_providers_per_asset" validate:"gte=0"`
+		MaxPairsPerTick           int                 `mapstructure:"max_pairs_per_tick" validate:"gte=0"`
+		VotePrecision             uint64              `mapstructure:"vote_precision" validate:"lte=18"`
+		RoundingMode              string              `mapstructure:"rounding_mode"`
+		VoteNudgeStep             string              `mapstructure:"vote_nudge_step"`
+		MaxPriceChangePct         string              `mapstructure:"max_price_change_pct"`
+		TVWAPMinPeriod            string              `mapstructure:"tvwap_min_period"`
+		TVWAPLookbacks            []TVWAPLookback     `mapstructure:"tvwap_lookbacks" validate:"dive"`
+		AdaptiveTimeout           AdaptiveTimeout     `mapstructure:"adaptive_timeout"`
+		CircuitBreaker            CircuitBreaker      `mapstructure:"circuit_breaker"`
+		Alerting                  Alerting            `mapstructure:"alerting"`
+		ParamsFallbackMaxAge      int64               `mapstructure:"params_fallback_max_age" validate:"gte=0"`
+		ParamsMaxAge              string              `mapstructure:"params_max_age"`
+		MaxPriceAge               string              `mapstructure:"max_price_age"`
+		CandleGapFillInterval     string              `mapstructure:"candle_gap_fill_interval"`
+		VoteAudit                 bool                `mapstructure:"vote_audit"`
+		DryRun                    bool                `mapstructure:"dry_run"`
+		ShutdownGracePeriod       string              `mapstructure:"shutdown_grace_period"`
+		TickInterval              string              `mapstructure:"tick_interval"`
+		PrevoteFile               string              `mapstructure:"prevote_file"`
+
+		// DeprecatedFields holds the deprecated fields found while loading the
+		// config, so they can be reported once a logger is available.
+		DeprecatedFields []DeprecatedField `mapstructure:"-"`
+	}
+
+	// AdaptiveTimeout defines the configuration for computing the timeout of
+	// each provider from its recently observed fetch latencies, instead of
+	// using the fixed provider timeout.
+	AdaptiveTimeout struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		Window     int    `mapstructure:"window"`
+		Margin     string `mapstructure:"margin"`
+		MinTimeout string `mapstructure:"min_timeout"`
+		MaxTimeout string `mapstructure:"max_timeout"`
+	}
+
+	// CircuitBreaker defines the configuration for skipping the providers
+	// which failed failure_threshold times in a row for a cooldown.
+	CircuitBreaker struct {
+		Enabled          bool   `mapstructure:"enabled"`
+		FailureThreshold int    `mapstructure:"failure_threshold"`
+		Cooldown         string `mapstructure:"cooldown"`
+	}
+
+	// Alerting defines the configuration of the webhook POSTed to on critical
+	// events, such as missed votes. Every event is sent if none are listed.
+	// The low balance event is sent once the feeder account holds less than
+	// the min balance, if set.
+	Alerting struct {
+		WebhookURL string   `mapstructure:"webhook_url" validate:"omitempty,url"`
+		Events     []string `mapstructure:"events"`
+		MinBalance string   `mapstructure:"min_balance"`
 	}
 
 	// Server defines the API server configuration.
@@ -60,6 +160,18 @@ type (
 		ReadTimeout    string   `mapstructure:"read_timeout"`
 		VerboseCORS    bool     `mapstructure:"verbose_cors"`
 		AllowedOrigins []string `mapstructure:"allowed_origins"`
+
+		// HealthStalenessWindow is the maximum age of the last price sync for
+		// the healthz endpoint to report the price feeder as available.
+		HealthStalenessWindow string `mapstructure:"health_staleness_window"`
+
+		// AdminToken is the bearer token of the admin endpoints, which are
+		// not served when it is empty.
+		AdminToken string `mapstructure:"admin_token"`
+
+		// SignPrices defines whether the published prices are signed with the
+		// feeder's key, so their consumers can verify where they come from.
+		SignPrices bool `mapstructure:"sign_prices"`
 	}
 
 	// CurrencyPair defines a price quote of the exchange rate for two different
@@ -83,18 +195,92 @@ type (
 		Threshold string `mapstructure:"threshold" validate:"required"`
 	}
 
+	// ConversionRate defines a fixed USD rate for a given currency, used instead
+	// of the rate derived from providers when converting prices to USD.
+	ConversionRate struct {
+		Base string `mapstructure:"base" validate:"required"`
+		Rate string `mapstructure:"rate" validate:"required"`
+	}
+
+	// MinTotalVolume defines the minimum total volume of a given asset, summed
+	// across the providers contributing to its price, for the price to be
+	// voted.
+	MinTotalVolume struct {
+		Base   string `mapstructure:"base" validate:"required"`
+		Volume string `mapstructure:"volume" validate:"required"`
+	}
+
+	// PriceBand defines the absolute range, in USD, the provider prices of a
+	// given asset must be within to be used.
+	PriceBand struct {
+		Base string `mapstructure:"base" validate:"required"`
+		Min  string `mapstructure:"min" validate:"required"`
+		Max  string `mapstructure:"max" validate:"required"`
+	}
+
+	// TrustedProvider defines a highly trusted provider of a given currency
+	// pair, whose price the prices of the other providers of the pair must be
+	// within max_deviation_pct percent of to be used.
+	TrustedProvider struct {
+		Base            string             `mapstructure:"base" validate:"required"`
+		Quote           string             `mapstructure:"quote" validate:"required"`
+		Provider        types.ProviderName `mapstructure:"provider" validate:"required"`
+		MaxDeviationPct string             `mapstructure:"max_deviation_pct" validate:"required"`
+	}
+
+	// UnfilteredPair defines a currency pair whose prices skip the deviation
+	// filter, such as a new or illiquid asset whose providers legitimately
+	// disagree widely.
+	UnfilteredPair struct {
+		Base  string `mapstructure:"base" validate:"required"`
+		Quote string `mapstructure:"quote" validate:"required"`
+	}
+
+	// ProviderDeviation defines a multiplier applied to the deviation threshold
+	// of a given provider, so a usually accurate provider can be held to a
+	// tighter band than the others.
+	ProviderDeviation struct {
+		Provider   types.ProviderName `mapstructure:"provider" validate:"required"`
+		Multiplier string             `mapstructure:"multiplier" validate:"required"`
+	}
+
+	// ProviderWeight defines a weight scaling the ticker and candle volumes of
+	// a given provider in the VWAP and TVWAP, so a less trusted provider weighs
+	// less than its reported volume.
+	ProviderWeight struct {
+		Provider types.ProviderName `mapstructure:"provider" validate:"required"`
+		Weight   string             `mapstructure:"weight" validate:"required"`
+	}
+
+	// TVWAPLookback defines how far back the candles of a given base are
+	// weighted when computing its TVWAP, shorter for volatile assets and longer
+	// for illiquid ones.
+	TVWAPLookback struct {
+		Base     string `mapstructure:"base" validate:"required"`
+		Lookback string `mapstructure:"lookback" validate:"required"`
+	}
+
+	// ProviderTimeout defines the timeouts of the providers' combined ticker and
+	// candle fetches, keyed by provider name. The timeout under the default key
+	// applies to every other provider. A plain duration is decoded as the
+	// default timeout.
+	ProviderTimeout map[string]string
+
 	// Account defines account related configuration that is related to the Ojo
 	// network and transaction signing functionality.
 	Account struct {
-		ChainID   string `mapstructure:"chain_id"`
-		Address   string `mapstructure:"address"`
-		Validator string `mapstructure:"validator"`
+		ChainID      string `mapstructure:"chain_id"`
+		Address      string `mapstructure:"address"`
+		Validator    string `mapstructure:"validator"`
+		FeeGranter   string `mapstructure:"fee_granter"`
+		AuthzGranter string `mapstructure:"authz_granter"`
 	}
 
 	// Keyring defines the required Ojo keyring configuration.
 	Keyring struct {
 		Backend string `mapstructure:"backend"`
 		Dir     string `mapstructure:"dir"`
+		Algo    string `mapstructure:"algo"`
 	}
 
 	// RPC defines RPC configuration of both the Ojo gRPC and Tendermint nodes.
@@ -102,6 +288,15 @@ type (
 		TMRPCEndpoint string `mapstructure:"tmrpc_endpoint" validate:"required"`
 		GRPCEndpoint  string `mapstructure:"grpc_endpoint" validate:"required"`
 		RPCTimeout    string `mapstructure:"rpc_timeout" validate:"required"`
+
+		// TMRPCFallbackEndpoints are the Tendermint RPC endpoints transactions
+		// are broadcast to, and the chain height is subscribed to, in order,
+		// when TMRPCEndpoint can't be reached.
+		TMRPCFallbackEndpoints []string `mapstructure:"tmrpc_fallback_endpoints" validate:"dive,required"`
+
+		// GRPCFallbackEndpoints are the gRPC endpoints queried, in order, when
+		// GRPCEndpoint can't be reached.
+		GRPCFallbackEndpoints []string `mapstructure:"grpc_fallback_endpoints" validate:"dive,required"`
 	}
 )
 
@@ -118,12 +313,135 @@ func telemetryValidation(sl validator.StructLevel) {
 func endpointValidation(sl validator.StructLevel) {
 	endpoint := sl.Current().Interface().(provider.Endpoint)
 
-	if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || len(endpoint.Websocket) < 1 {
+	// the normalized and coingecko providers only poll a rest endpoint, and
+	// kucoin requests its websocket endpoint over rest
+	requiresWebsocket := endpoint.Name != provider.ProviderNormalized &&
+		endpoint.Name != provider.ProviderCoinGecko &&
+		endpoint.Name != provider.ProviderKuCoin
+	if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || (requiresWebsocket && len(endpoint.Websocket) < 1) {
 		sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
 	}
 	if _, ok := SupportedProviders[endpoint.Name]; !ok {
 		sl.ReportError(endpoint.Name, "name", "Name", "unsupportedEndpointProvider", "")
 	}
+	if endpoint.PriceSource != "" && endpoint.PriceSource != provider.PriceSourceLast {
+		if _, ok := provider.SupportedPriceSources[endpoint.PriceSource]; !ok {
+			sl.ReportError(endpoint.PriceSource, "price_source", "PriceSource", "unsupportedPriceSource", "")
+		}
+		priceSourceProviders := provider.BookPriceProviders
+		if endpoint.PriceSource == provider.PriceSourceDepth {
+			priceSourceProviders = provider.DepthPriceProviders
+		}
+		if _, ok := priceSourceProviders[endpoint.Name]; !ok {
+			sl.ReportError(endpoint.PriceSource, "price_source", "PriceSource", "unsupportedPriceSourceProvider", "")
+		}
+	}
+	if endpoint.PriceSource == provider.PriceSourceDepth || len(endpoint.DepthNotionals) > 0 {
+		if endpoint.PriceSource != provider.PriceSourceDepth || len(endpoint.DepthNotionals) == 0 {
+			sl.ReportError(endpoint.DepthNotionals, "depth_notionals", "DepthNotionals", "invalidDepthNotionals", "")
+		}
+		for _, notional := range endpoint.DepthNotionals {
+			notionalDec, err := math.LegacyNewDecFromStr(notional)
+			if err != nil || !notionalDec.IsPositive() {
+				sl.ReportError(endpoint.DepthNotionals, "depth_notionals", "DepthNotionals", "invalidDepthNotionals", "")
+			}
+		}
+	}
+	for _, pair := range endpoint.AvailablePairs {
+		if _, err := types.ParseCurrencyPair(pair); err != nil {
+			sl.ReportError(endpoint.AvailablePairs, "available_pairs", "AvailablePairs", "invalidAvailablePair", "")
+		}
+	}
+	if endpoint.AvailablePairsRefresh != "" {
+		if _, err := time.ParseDuration(endpoint.AvailablePairsRefresh); err != nil {
+			sl.ReportError(endpoint.AvailablePairsRefresh, "available_pairs_refresh", "AvailablePairsRefresh",
+				"invalidAvailablePairsRefresh", "")
+		}
+	}
+	if endpoint.AvailablePairsCacheTTL != "" {
+		if d, err := time.ParseDuration(endpoint.AvailablePairsCacheTTL); err != nil || d < 0 {
+			sl.ReportError(endpoint.AvailablePairsCacheTTL, "available_pairs_cache_ttl", "AvailablePairsCacheTTL",
+				"invalidAvailablePairsCacheTTL", "")
+		}
+	}
+	if endpoint.RestTimeout != "" {
+		if d, err := time.ParseDuration(endpoint.RestTimeout); err != nil || d <= 0 {
+			sl.ReportError(endpoint.RestTimeout, "rest_timeout", "RestTimeout",
+				"invalidRestTimeout", "")
+		}
+	}
+	if endpoint.MaxResponseSize < 0 {
+		sl.ReportError(endpoint.MaxResponseSize, "max_response_size", "MaxResponseSize", "invalidMaxResponseSize", "")
+	}
+	if endpoint.ReconnectMaxInterval != "" {
+		if d, err := time.ParseDuration(endpoint.ReconnectMaxInterval); err != nil || d <= 0 {
+			sl.ReportError(endpoint.ReconnectMaxInterval, "reconnect_max_interval", "ReconnectMaxInterval",
+				"invalidReconnectMaxInterval", "")
+		}
+	}
+	if endpoint.CandlePeriod != "" {
+		if d, err := time.ParseDuration(endpoint.CandlePeriod); err != nil || d <= 0 {
+			sl.ReportError(endpoint.CandlePeriod, "candle_period", "CandlePeriod",
+				"invalidCandlePeriod", "")
+		}
+	}
+	if endpoint.CandleTimestamp != "" {
+		if _, ok := provider.SupportedCandleTimestamps[endpoint.CandleTimestamp]; !ok {
+			sl.ReportError(endpoint.CandleTimestamp, "candle_timestamp", "CandleTimestamp",
+				"unsupportedCandleTimestamp", "")
+		}
+	}
+	if endpoint.CandleInterval != "" {
+		if d, err := time.ParseDuration(endpoint.CandleInterval); err != nil || d <= 0 {
+			sl.ReportError(endpoint.CandleInterval, "candle_interval", "CandleInterval",
+				"invalidCandleInterval", "")
+		}
+	}
+	if endpoint.SecondaryCandleInterval != "" {
+		intervals, ok := provider.SecondaryCandleIntervalProviders[endpoint.Name]
+		if !ok {
+			sl.ReportError(endpoint.SecondaryCandleInterval, "secondary_candle_interval", "SecondaryCandleInterval",
+				"unsupportedSecondaryCandleIntervalProvider", "")
+		} else if _, ok := intervals[endpoint.SecondaryCandleInterval]; !ok {
+			sl.ReportError(endpoint.SecondaryCandleInterval, "secondary_candle_interval", "SecondaryCandleInterval",
+				"unsupportedSecondaryCandleInterval", "")
+		}
+	}
+	if endpoint.EnableRESTFallback != nil && *endpoint.EnableRESTFallback {
+		if _, ok := provider.RESTFallbackProviders[endpoint.Name]; !ok {
+			sl.ReportError(endpoint.EnableRESTFallback, "enable_rest_fallback", "EnableRESTFallback",
+				"unsupportedRESTFallbackProvider", "")
+		}
+	}
+	if endpoint.PairCoverageWindow != "" {
+		if _, ok := provider.PairCoverageProviders[endpoint.Name]; !ok {
+			sl.ReportError(endpoint.PairCoverageWindow, "pair_coverage_window", "PairCoverageWindow",
+				"unsupportedPairCoverageWindowProvider", "")
+		}
+		if d, err := time.ParseDuration(endpoint.PairCoverageWindow); err != nil || d <= 0 {
+			sl.ReportError(endpoint.PairCoverageWindow, "pair_coverage_window", "PairCoverageWindow",
+				"invalidPairCoverageWindow", "")
+		}
+	}
+	if endpoint.SyntheticVolume != "" {
+		if endpoint.Name != provider.ProviderDeribit {
+			sl.ReportError(endpoint.SyntheticVolume, "synthetic_volume", "SyntheticVolume",
+				"unsupportedSyntheticVolumeProvider", "")
+		}
+		if volume, err := math.LegacyNewDecFromStr(endpoint.SyntheticVolume); err != nil || !volume.IsPositive() {
+			sl.ReportError(endpoint.SyntheticVolume, "synthetic_volume", "SyntheticVolume",
+				"invalidSyntheticVolume", "")
+		}
+	}
+	if len(endpoint.IndexTickerPairs) > 0 && endpoint.Name != provider.ProviderOkx {
+		sl.ReportError(
+			endpoint.IndexTickerPairs,
+			"index_ticker_pairs",
+			"IndexTickerPairs",
+			"unsupportedIndexTickerProvider",
+			"",
+		)
+	}
 }
 
 // hasAPIKey searches through the provided endpoints to return whether or not
@@ -142,12 +460,87 @@ func (c Config) Validate() (err error) {
 	if err = c.validateCurrencyPairs(); err != nil {
 		return err
 	}
+	if err = c.validateReferencePairs(); err != nil {
+		return err
+	}
+	if err = c.validateUSDConversionPaths(); err != nil {
+		return err
+	}
+	if err = c.validateForexConversionPairs(); err != nil {
+		return err
+	}
 	if err = c.validateDeviations(); err != nil {
 		return err
 	}
 	if err = c.validateGas(); err != nil {
 		return err
 	}
+	if err = c.validateConversionRateOverrides(); err != nil {
+		return err
+	}
+	if err = c.validateMinTotalVolumes(); err != nil {
+		return err
+	}
+	if err = c.validatePriceBands(); err != nil {
+		return err
+	}
+	if err = c.validateTrustedProviders(); err != nil {
+		return err
+	}
+	if err = c.validateProviderDeviations(); err != nil {
+		return err
+	}
+	if err = c.validateProviderWeights(); err != nil {
+		return err
+	}
+	if err = c.validateTickerVolumePolicy(); err != nil {
+		return err
+	}
+	if err = c.validateAggregationMethod(); err != nil {
+		return err
+	}
+	if err = c.validateRoundingMode(); err != nil {
+		return err
+	}
+	if err = c.validateVoteNudgeStep(); err != nil {
+		return err
+	}
+	if err = c.validateMaxPriceChangePct(); err != nil {
+		return err
+	}
+	if err = c.validateMaxPairsPerTick(); err != nil {
+		return err
+	}
+	if err = c.validateTVWAPMinPeriod(); err != nil {
+		return err
+	}
+	if err = c.validateTVWAPLookbacks(); err != nil {
+		return err
+	}
+	if err = c.validateCandleGapFillInterval(); err != nil {
+		return err
+	}
+	if err = c.validateShutdownGracePeriod(); err != nil {
+		return err
+	}
+	if err = c.validateTickInterval(); err != nil {
+		return err
+	}
+	if err = c.validateProviderTimeout(); err != nil {
+		return err
+	}
+	if err = c.validateAdaptiveTimeout(); err != nil {
+		return err
+	}
+	if err = c.validateCircuitBreaker(); err != nil {
+		return err
+	}
+	if err = c.validateAlerting(); err != nil {
+		return err
+	}
+	if err = c.validateServer(); err != nil {
+		return err
+	}
 
 	validate.RegisterStructValidation(telemetryValidation, telemetry.Config{})
 	validate.RegisterStructValidation(endpointValidation, provider.Endpoint{})
@@ -168,6 +561,118 @@ func (c Config) validateDeviations() error {
 	return nil
 }
 
+func (c Config) validateConversionRateOverrides() error {
+	for _, override := range c.ConversionRateOverrides {
+		rate, err := math.LegacyNewDecFromStr(override.Rate)
+		if err != nil {
+			return fmt.Errorf("conversion rate overrides must be numeric: %w", err)
+		}
+
+		if !rate.IsPositive() {
+			return fmt.Errorf("conversion rate overrides must be positive")
+		}
+	}
+	return nil
+}
+
+func (c Config) validateMinTotalVolumes() error {
+	for _, minTotalVolume := range c.MinTotalVolumes {
+		volume, err := math.LegacyNewDecFromStr(minTotalVolume.Volume)
+		if err != nil {
+			return fmt.Errorf("min total volumes must be numeric: %w", err)
+		}
+
+		if !volume.IsPositive() {
+			return fmt.Errorf("min total volumes must be positive")
+		}
+	}
+	return nil
+}
+
+func (c Config) validatePriceBands() error {
+	for _, priceBand := range c.PriceBands {
+		minPrice, err := math.LegacyNewDecFromStr(priceBand.Min)
+		if err != nil {
+			return fmt.Errorf("price band min of %s must be numeric: %w", priceBand.Base, err)
+		}
+		maxPrice, err := math.LegacyNewDecFromStr(priceBand.Max)
+		if err != nil {
+			return fmt.Errorf("price band max of %s must be numeric: %w", priceBand.Base, err)
+		}
+
+		if minPrice.IsNegative() {
+			return fmt.Errorf("price band min of %s must not be negative", priceBand.Base)
+		}
+		if !maxPrice.GT(minPrice) {
+			return fmt.Errorf("price band max of %s must be greater than its min", priceBand.Base)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateTrustedProviders() error {
+	for _, trustedProvider := range c.TrustedProviders {
+		cp := types.CurrencyPair{Base: trustedProvider.Base, Quote: trustedProvider.Quote}
+
+		maxDeviationPct, err := math.LegacyNewDecFromStr(trustedProvider.MaxDeviationPct)
+		if err != nil {
+			return fmt.Errorf("trusted provider max deviation pct of %s must be numeric: %w", cp, err)
+		}
+		if !maxDeviationPct.IsPositive() {
+			return fmt.Errorf("trusted provider max deviation pct of %s must be positive", cp)
+		}
+
+		if !c.isPairProvider(cp, trustedProvider.Provider) {
+			return fmt.Errorf("trusted provider %s is not a provider of %s", trustedProvider.Provider, cp)
+		}
+	}
+	return nil
+}
+
+// isPairProvider returns whether the provider is one of the configured
+// providers of the currency pair.
+func (c Config) isPairProvider(cp types.CurrencyPair, providerName types.ProviderName) bool {
+	for _, pair := range c.CurrencyPairs {
+		if pair.Base != cp.Base || pair.Quote != cp.Quote {
+			continue
+		}
+		for _, pairProvider := range pair.Providers {
+			if pairProvider == providerName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Config) validateProviderDeviations() error {
+	for _, providerDeviation := range c.ProviderDeviations {
+		multiplier, err := math.LegacyNewDecFromStr(providerDeviation.Multiplier)
+		if err != nil {
+			return fmt.Errorf("provider deviation multipliers must be numeric: %w", err)
+		}
+
+		if !multiplier.IsPositive() {
+			return fmt.Errorf("provider deviation multipliers must be positive")
+		}
+	}
+	return nil
+}
+
+func (c Config) validateProviderWeights() error {
+	for _, providerWeight := range c.ProviderWeights {
+		weight, err := math.LegacyNewDecFromStr(providerWeight.Weight)
+		if err != nil {
+			return fmt.Errorf("provider weights must be numeric: %w", err)
+		}
+
+		if weight.IsNegative() {
+			return fmt.Errorf("provider weights must not be negative")
+		}
+	}
+	return nil
+}
+
 func (c Config) validateGas() error {
 	if c.Gas <= 0 && c.GasAdjustment <= 0 {
 		return fmt.Errorf("gas or gas adjustment must be set")
@@ -178,6 +683,206 @@ func (c Config) validateGas() error {
 	return nil
 }
 
+func (c Config) validateTickerVolumePolicy() error {
+	if c.TickerVolumePolicy == "" {
+		return nil
+	}
+	if _, ok := types.SupportedTickerVolumePolicies[types.TickerVolumePolicy(c.TickerVolumePolicy)]; !ok {
+		return fmt.Errorf("unsupported ticker volume policy: %s", c.TickerVolumePolicy)
+	}
+	return nil
+}
+
+func (c Config) validateAggregationMethod() error {
+	if c.AggregationMethod == "" {
+		return nil
+	}
+	if _, ok := types.SupportedAggregationMethods[types.AggregationMethod(c.AggregationMethod)]; !ok {
+		return fmt.Errorf("unsupported aggregation method: %s", c.AggregationMethod)
+	}
+	return nil
+}
+
+func (c Config) validateServer() error {
+	if c.Server.HealthStalenessWindow == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(c.Server.HealthStalenessWindow)
+	if err != nil {
+		return fmt.Errorf("invalid health staleness window: %w", err)
+	}
+	if window <= 0 {
+		return fmt.Errorf("health staleness window must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateRoundingMode() error {
+	if c.RoundingMode == "" {
+		return nil
+	}
+	if _, ok := types.SupportedRoundingModes[types.RoundingMode(c.RoundingMode)]; !ok {
+		return fmt.Errorf("unsupported rounding mode: %s", c.RoundingMode)
+	}
+	return nil
+}
+
+func (c Config) validateVoteNudgeStep() error {
+	if c.VoteNudgeStep == "" {
+		return nil
+	}
+	step, err := math.LegacyNewDecFromStr(c.VoteNudgeStep)
+	if err != nil {
+		return fmt.Errorf("vote nudge step must be numeric: %w", err)
+	}
+	if step.IsNegative() {
+		return fmt.Errorf("vote nudge step must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateMaxPriceChangePct() error {
+	if c.MaxPriceChangePct == "" {
+		return nil
+	}
+	maxChangePct, err := math.LegacyNewDecFromStr(c.MaxPriceChangePct)
+	if err != nil {
+		return fmt.Errorf("max price change pct must be numeric: %w", err)
+	}
+	if maxChangePct.IsNegative() {
+		return fmt.Errorf("max price change pct must not be negative")
+	}
+	return nil
+}
+
+// validateMaxPairsPerTick ensures the pairs of the conversion currencies, which
+// are collected every tick, and the pairs of any other base fit within
+// max_pairs_per_tick, so no tick collects more pairs than it allows.
+func (c Config) validateMaxPairsPerTick() error {
+	if c.MaxPairsPerTick == 0 {
+		return nil
+	}
+
+	conversionBases := make(map[string]struct{})
+	for _, cp := range SupportedConversionSlice() {
+		conversionBases[cp.Base] = struct{}{}
+	}
+
+	basePairs := make(map[string]map[types.CurrencyPair]struct{})
+	for _, pairs := range [][]CurrencyPair{c.CurrencyPairs, c.ReferencePairs} {
+		for _, pair := range pairs {
+			if _, ok := basePairs[pair.Base]; !ok {
+				basePairs[pair.Base] = make(map[types.CurrencyPair]struct{})
+			}
+			basePairs[pair.Base][types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = struct{}{}
+		}
+	}
+
+	var conversionPairs, maxBasePairs int
+	for base, pairs := range basePairs {
+		if _, ok := conversionBases[base]; ok {
+			conversionPairs += len(pairs)
+			continue
+		}
+		maxBasePairs = max(maxBasePairs, len(pairs))
+	}
+	if minPairs := conversionPairs + maxBasePairs; c.MaxPairsPerTick < minPairs {
+		return fmt.Errorf("max pairs per tick must be at least %d to fit the conversion pairs and the pairs of each base",
+			minPairs)
+	}
+	return nil
+}
+
+func (c Config) validateAdaptiveTimeout() error {
+	if !c.AdaptiveTimeout.Enabled {
+		return nil
+	}
+	if c.AdaptiveTimeout.Window <= 0 {
+		return fmt.Errorf("adaptive timeout window must be positive")
+	}
+	if _, err := time.ParseDuration(c.AdaptiveTimeout.Margin); err != nil {
+		return fmt.Errorf("failed to parse adaptive timeout margin: %w", err)
+	}
+	minTimeout, err := time.ParseDuration(c.AdaptiveTimeout.MinTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse adaptive timeout min timeout: %w", err)
+	}
+	maxTimeout, err := time.ParseDuration(c.AdaptiveTimeout.MaxTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse adaptive timeout max timeout: %w", err)
+	}
+	if minTimeout > maxTimeout {
+		return fmt.Errorf("adaptive timeout min timeout must not exceed max timeout")
+	}
+	return nil
+}
+
+func (c Config) validateCircuitBreaker() error {
+	if !c.CircuitBreaker.Enabled {
+		return nil
+	}
+	if c.CircuitBreaker.FailureThreshold <= 0 {
+		return fmt.Errorf("circuit breaker failure threshold must be positive")
+	}
+	cooldown, err := time.ParseDuration(c.CircuitBreaker.Cooldown)
+	if err != nil {
+		return fmt.Errorf("failed to parse circuit breaker cooldown: %w", err)
+	}
+	if cooldown <= 0 {
+		return fmt.Errorf("circuit breaker cooldown must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateAlerting() error {
+	if c.Alerting.WebhookURL == "" && len(c.Alerting.Events) > 0 {
+		return fmt.Errorf("alerting events require a webhook url")
+	}
+	for _, event := range c.Alerting.Events {
+		if _, ok := types.SupportedAlertEvents[types.AlertEventType(event)]; !ok {
+			return fmt.Errorf("unsupported alerting event: %s", event)
+		}
+	}
+	if c.Alerting.MinBalance != "" {
+		if c.Alerting.WebhookURL == "" {
+			return fmt.Errorf("alerting min balance requires a webhook url")
+		}
+		if _, err := sdk.ParseCoinNormalized(c.Alerting.MinBalance); err != nil {
+			return fmt.Errorf("failed to parse alerting min balance: %w", err)
+		}
+	}
+	return nil
+}
+
+// AlertMinBalance returns the balance of the feeder account below which the
+// low balance event is sent, or a zero coin if it is not set.
+// DefaultHealthStalenessWindow returns the health staleness window used when
+// none is configured, which spans a few tick intervals so a slow tick does not
+// report the price feeder as unavailable.
+func (c Config) DefaultHealthStalenessWindow() time.Duration {
+	tickInterval, err := time.ParseDuration(c.TickInterval)
+	if err != nil || tickInterval <= 0 {
+		tickInterval = defaultTickInterval
+	}
+	return defaultHealthStalenessTicks * tickInterval
+}
+
+func (c Config) AlertMinBalance() (sdk.Coin, error) {
+	if c.Alerting.MinBalance == "" {
+		return sdk.Coin{}, nil
+	}
+	return sdk.ParseCoinNormalized(c.Alerting.MinBalance)
+}
+
+// AlertEvents returns the alerting events the webhook is sent.
+func (c Config) AlertEvents() []types.AlertEventType {
+	events := make([]types.AlertEventType, 0, len(c.Alerting.Events))
+	for _, event := range c.Alerting.Events {
+		events = append(events, types.AlertEventType(event))
+	}
+	return events
+}
+
 func (c Config) validateCurrencyPairs() error {
 OUTER:
 	for _, cp := range c.CurrencyPairs {
@@ -210,7 +915,205 @@ OUTER:
 				continue OUTER
 			}
 		}
-		return fmt.Errorf("currency pair quote %s is not supported", cp.Quote)
+		return fmt.Errorf(
+			"currency pair quote %s is not supported: no conversion pair %s/%s for currency pair %s/%s",
+			cp.Quote, cp.Quote, DenomUSD, cp.Base, cp.Quote,
+		)
+	}
+	return nil
+}
+
+// validateReferencePairs ensures the reference pairs are well formed and do not
+// price a base which is voted, since the prices of the reference pairs would
+// then contribute to the voted rate of the base.
+func (c Config) validateReferencePairs() error {
+	votedBases := make(map[string]struct{}, len(c.CurrencyPairs))
+	for _, cp := range c.CurrencyPairs {
+		votedBases[cp.Base] = struct{}{}
+	}
+
+	for _, cp := range c.ReferencePairs {
+		if cp.Base == "" || cp.Quote == "" {
+			return fmt.Errorf("reference pair base and quote cannot be empty")
+		}
+		if len(cp.Providers) == 0 {
+			return fmt.Errorf("reference pair must have at least one provider")
+		}
+		if _, ok := votedBases[cp.Base]; ok {
+			return fmt.Errorf("reference pair %s/%s cannot price the voted base %s", cp.Base, cp.Quote, cp.Base)
+		}
+	}
+	return nil
+}
+
+// validateUSDConversionPaths ensures the prices of the currency and reference
+// pairs can be converted to USD, through at most maxUSDConversionHops of the
+// supported conversion pairs from their quote.
+func (c Config) validateUSDConversionPaths() error {
+	conversions := make(map[string][]string)
+	for _, conversionPair := range SupportedConversionSlice() {
+		conversions[conversionPair.Base] = append(conversions[conversionPair.Base], conversionPair.Quote)
+	}
+
+	for _, cp := range append(c.CurrencyPairs, c.ReferencePairs...) {
+		if !reachesUSD(cp.Quote, conversions, maxUSDConversionHops) {
+			return fmt.Errorf(
+				"currency pair base %s cannot be converted to %s: no conversion path from %s to %s within %d conversions",
+				cp.Base, DenomUSD, cp.Quote, DenomUSD, maxUSDConversionHops,
+			)
+		}
+	}
+	return nil
+}
+
+// validateForexConversionPairs ensures the currency and reference pairs quoted
+// in a forex currency, such as BTC/MXN, come with a configured USD pair of
+// that currency, such as MXN/USD, as the conversion rate of a forex currency
+// is only available from the providers configured for it.
+func (c Config) validateForexConversionPairs() error {
+	configuredPairs := make(map[types.CurrencyPair]struct{})
+	for _, cp := range append(c.CurrencyPairs, c.ReferencePairs...) {
+		if len(cp.Providers) > 0 {
+			configuredPairs[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}] = struct{}{}
+		}
+	}
+
+	for _, cp := range append(c.CurrencyPairs, c.ReferencePairs...) {
+		if _, ok := SupportedForexCurrencies[cp.Quote]; !ok || cp.Quote == DenomUSD {
+			continue
+		}
+		conversionPair := types.CurrencyPair{Base: cp.Quote, Quote: DenomUSD}
+		if _, ok := configuredPairs[conversionPair]; !ok {
+			return fmt.Errorf(
+				"currency pair %s/%s requires the %s/%s conversion pair to be configured with providers",
+				cp.Base, cp.Quote, conversionPair.Base, conversionPair.Quote,
+			)
+		}
+	}
+	return nil
+}
+
+// reachesUSD returns whether the denom is USD or can be converted to USD
+// through at most maxHops of the given conversions, from base to quotes.
+func reachesUSD(denom string, conversions map[string][]string, maxHops int) bool {
+	visited := map[string]struct{}{denom: {}}
+	frontier := []string{denom}
+	for hops := 0; len(frontier) > 0; hops++ {
+		next := []string{}
+		for _, d := range frontier {
+			if d == DenomUSD {
+				return true
+			}
+			for _, quote := range conversions[d] {
+				if _, ok := visited[quote]; !ok {
+					visited[quote] = struct{}{}
+					next = append(next, quote)
+				}
+			}
+		}
+		if hops == maxHops {
+			return false
+		}
+		frontier = next
+	}
+	return false
+}
+
+func (c Config) validateTVWAPMinPeriod() error {
+	if c.TVWAPMinPeriod == "" {
+		return nil
+	}
+	minPeriod, err := time.ParseDuration(c.TVWAPMinPeriod)
+	if err != nil {
+		return fmt.Errorf("invalid tvwap min period: %w", err)
+	}
+	if minPeriod < 0 {
+		return fmt.Errorf("tvwap min period must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateTVWAPLookbacks() error {
+	for _, tvwapLookback := range c.TVWAPLookbacks {
+		lookback, err := time.ParseDuration(tvwapLookback.Lookback)
+		if err != nil {
+			return fmt.Errorf("invalid tvwap lookback of %s: %w", tvwapLookback.Base, err)
+		}
+		if lookback <= 0 {
+			return fmt.Errorf("tvwap lookback of %s must be positive", tvwapLookback.Base)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateCandleGapFillInterval() error {
+	if c.CandleGapFillInterval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(c.CandleGapFillInterval)
+	if err != nil {
+		return fmt.Errorf("invalid candle gap fill interval: %w", err)
+	}
+	if interval < 0 {
+		return fmt.Errorf("candle gap fill interval must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateShutdownGracePeriod() error {
+	if c.ShutdownGracePeriod == "" {
+		return nil
+	}
+	gracePeriod, err := time.ParseDuration(c.ShutdownGracePeriod)
+	if err != nil {
+		return fmt.Errorf("invalid shutdown grace period: %w", err)
+	}
+	if gracePeriod < 0 {
+		return fmt.Errorf("shutdown grace period must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateTickInterval() error {
+	if c.TickInterval == "" {
+		return nil
+	}
+	tickInterval, err := time.ParseDuration(c.TickInterval)
+	if err != nil {
+		return fmt.Errorf("invalid tick interval: %w", err)
+	}
+	if tickInterval < minTickInterval {
+		return fmt.Errorf("tick interval must be at least %s", minTickInterval)
+	}
+
+	if c.Server.HealthStalenessWindow == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(c.Server.HealthStalenessWindow)
+	if err != nil {
+		// reported by validateServer
+		return nil
+	}
+	if window < minHealthStalenessTicks*tickInterval {
+		return fmt.Errorf(
+			"health staleness window of %s must be at least %d tick intervals of %s",
+			window,
+			minHealthStalenessTicks,
+			tickInterval,
+		)
+	}
+	return nil
+}
+
+func (c Config) validateProviderTimeout() error {
+	for key, value := range c.ProviderTimeout {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid provider timeout of %s: %w", key, err)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("provider timeout of %s must be positive", key)
+		}
 	}
 	return nil
 }
@@ -225,17 +1128,71 @@ func (c *Config) setDefaults() {
 	if c.Server.ReadTimeout == "" {
 		c.Server.ReadTimeout = defaultSrvReadTimeout.String()
 	}
-	if c.ProviderTimeout == "" {
-		c.ProviderTimeout = defaultProviderTimeout.String()
+	if c.ProviderTimeout == nil {
+		c.ProviderTimeout = make(ProviderTimeout)
+	}
+	if c.ProviderTimeout[providerTimeoutDefaultKey] == "" {
+		c.ProviderTimeout[providerTimeoutDefaultKey] = defaultProviderTimeout.String()
+	}
+	if c.TickerVolumePolicy == "" {
+		c.TickerVolumePolicy = string(types.TickerVolumePolicyFloor)
+	}
+	if c.AggregationMethod == "" {
+		c.AggregationMethod = string(types.AggregationMethodVWAP)
+	}
+	if c.MinProvidersPerAsset == 0 {
+		c.MinProvidersPerAsset = 1
+	}
+	if c.RoundingMode == "" {
+		c.RoundingMode = string(types.RoundingModeHalfUp)
+	}
+	if c.TVWAPMinPeriod == "" {
+		c.TVWAPMinPeriod = defaultTVWAPMinPeriod.String()
+	}
+	if c.TickInterval == "" {
+		c.TickInterval = defaultTickInterval.String()
+	}
+	if c.Server.HealthStalenessWindow == "" {
+		c.Server.HealthStalenessWindow = c.DefaultHealthStalenessWindow().String()
+	}
+	if c.AdaptiveTimeout.Window == 0 {
+		c.AdaptiveTimeout.Window = defaultAdaptiveTimeoutWindow
+	}
+	if c.AdaptiveTimeout.Margin == "" {
+		c.AdaptiveTimeout.Margin = defaultAdaptiveTimeoutMargin.String()
+	}
+	if c.AdaptiveTimeout.MinTimeout == "" {
+		c.AdaptiveTimeout.MinTimeout = defaultAdaptiveTimeoutMin.String()
+	}
+	if c.AdaptiveTimeout.MaxTimeout == "" {
+		c.AdaptiveTimeout.MaxTimeout = defaultAdaptiveTimeoutMax.String()
+	}
+	if c.CircuitBreaker.FailureThreshold == 0 {
+		c.CircuitBreaker.FailureThreshold = defaultCircuitBreakerFailureThreshold
+	}
+	if c.CircuitBreaker.Cooldown == "" {
+		c.CircuitBreaker.Cooldown = defaultCircuitBreakerCooldown.String()
 	}
 }
 
 // ProviderPairs returns a map of provider.CurrencyPair where the key is the
 // provider name.
 func (c Config) ProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	return providerPairs(c.CurrencyPairs)
+}
+
+// ReferenceProviderPairs returns the reference pairs from the config, whose
+// prices are computed and served but never voted, keyed by provider.
+func (c Config) ReferenceProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	return providerPairs(c.ReferencePairs)
+}
+
+// providerPairs converts the given currency pairs into a map of the currency
+// pairs of each provider.
+func providerPairs(currencyPairs []CurrencyPair) map[types.ProviderName][]types.CurrencyPair {
 	providerPairs := make(map[types.ProviderName][]types.CurrencyPair)
 
-	for _, pair := range c.CurrencyPairs {
+	for _, pair := range currencyPairs {
 		for _, provider := range pair.Providers {
 			if len(pair.PairAddress) > 0 {
 				for _, uniPair := range pair.PairAddress {
@@ -269,6 +1226,29 @@ func (c Config) ProviderEndpointsMap() map[types.ProviderName]provider.Endpoint
 	return endpoints
 }
 
+// ProviderTimeouts converts the provider_timeout from the config file into the
+// default provider timeout and a map of the timeouts of the providers which
+// override it.
+func (c Config) ProviderTimeouts() (time.Duration, map[types.ProviderName]time.Duration, error) {
+	defaultTimeout, err := time.ParseDuration(c.ProviderTimeout[providerTimeoutDefaultKey])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse provider timeout: %w", err)
+	}
+
+	timeouts := make(map[types.ProviderName]time.Duration, len(c.ProviderTimeout))
+	for key, value := range c.ProviderTimeout {
+		if key == providerTimeoutDefaultKey {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to parse provider timeout of %s: %w", key, err)
+		}
+		timeouts[types.ProviderName(key)] = timeout
+	}
+	return defaultTimeout, timeouts, nil
+}
+
 // DeviationsMap converts the deviation_thresholds from the config file into
 // a map of math.LegacyDec where the key is the base asset.
 func (c Config) DeviationsMap() (map[string]math.LegacyDec, error) {
@@ -283,6 +1263,142 @@ func (c Config) DeviationsMap() (map[string]math.LegacyDec, error) {
 	return deviations, nil
 }
 
+// ConversionRateOverridesMap converts the conversion_rate_overrides from the
+// config file into a map of math.LegacyDec where the key is the base asset.
+func (c Config) ConversionRateOverridesMap() (map[string]math.LegacyDec, error) {
+	overrides := make(map[string]math.LegacyDec, len(c.ConversionRateOverrides))
+	for _, override := range c.ConversionRateOverrides {
+		rate, err := math.LegacyNewDecFromStr(override.Rate)
+		if err != nil {
+			return nil, err
+		}
+		overrides[strings.ToUpper(override.Base)] = rate
+	}
+	return overrides, nil
+}
+
+// MinTotalVolumesMap converts the min_total_volumes from the config file into
+// a map of math.LegacyDec where the key is the base asset.
+func (c Config) MinTotalVolumesMap() (map[string]math.LegacyDec, error) {
+	minTotalVolumes := make(map[string]math.LegacyDec, len(c.MinTotalVolumes))
+	for _, minTotalVolume := range c.MinTotalVolumes {
+		volume, err := math.LegacyNewDecFromStr(minTotalVolume.Volume)
+		if err != nil {
+			return nil, err
+		}
+		minTotalVolumes[strings.ToUpper(minTotalVolume.Base)] = volume
+	}
+	return minTotalVolumes, nil
+}
+
+// PriceBandsMap converts the price_bands from the config file into a map of
+// types.PriceBand where the key is the base asset.
+func (c Config) PriceBandsMap() (map[string]types.PriceBand, error) {
+	priceBands := make(map[string]types.PriceBand, len(c.PriceBands))
+	for _, priceBand := range c.PriceBands {
+		minPrice, err := math.LegacyNewDecFromStr(priceBand.Min)
+		if err != nil {
+			return nil, err
+		}
+		maxPrice, err := math.LegacyNewDecFromStr(priceBand.Max)
+		if err != nil {
+			return nil, err
+		}
+		priceBands[priceBand.Base] = types.PriceBand{Min: minPrice, Max: maxPrice}
+	}
+	return priceBands, nil
+}
+
+// TrustedProvidersMap converts the trusted_providers from the config file into
+// a map of types.TrustedProvider where the key is the currency pair.
+func (c Config) TrustedProvidersMap() (map[types.CurrencyPair]types.TrustedProvider, error) {
+	trustedProviders := make(map[types.CurrencyPair]types.TrustedProvider, len(c.TrustedProviders))
+	for _, trustedProvider := range c.TrustedProviders {
+		maxDeviationPct, err := math.LegacyNewDecFromStr(trustedProvider.MaxDeviationPct)
+		if err != nil {
+			return nil, err
+		}
+		cp := types.CurrencyPair{Base: trustedProvider.Base, Quote: trustedProvider.Quote}
+		trustedProviders[cp] = types.TrustedProvider{
+			Provider:        trustedProvider.Provider,
+			MaxDeviationPct: maxDeviationPct,
+		}
+	}
+	return trustedProviders, nil
+}
+
+// ProviderDeviationsMap converts the provider_deviation_multipliers from the
+// config file into a map of math.LegacyDec where the key is the provider name.
+func (c Config) ProviderDeviationsMap() (map[types.ProviderName]math.LegacyDec, error) {
+	multipliers := make(map[types.ProviderName]math.LegacyDec, len(c.ProviderDeviations))
+	for _, providerDeviation := range c.ProviderDeviations {
+		multiplier, err := math.LegacyNewDecFromStr(providerDeviation.Multiplier)
+		if err != nil {
+			return nil, err
+		}
+		multipliers[providerDeviation.Provider] = multiplier
+	}
+	return multipliers, nil
+}
+
+// ProviderWeightsMap converts the provider_weights from the config file into a
+// map of math.LegacyDec where the key is the provider name.
+func (c Config) ProviderWeightsMap() (map[types.ProviderName]math.LegacyDec, error) {
+	weights := make(map[types.ProviderName]math.LegacyDec, len(c.ProviderWeights))
+	for _, providerWeight := range c.ProviderWeights {
+		weight, err := math.LegacyNewDecFromStr(providerWeight.Weight)
+		if err != nil {
+			return nil, err
+		}
+		weights[providerWeight.Provider] = weight
+	}
+	return weights, nil
+}
+
+// TVWAPLookbacksMap converts the tvwap_lookbacks from the config file into a
+// map of lookbacks keyed by uppercase base.
+func (c Config) TVWAPLookbacksMap() (map[string]time.Duration, error) {
+	lookbacks := make(map[string]time.Duration, len(c.TVWAPLookbacks))
+	for _, tvwapLookback := range c.TVWAPLookbacks {
+		lookback, err := time.ParseDuration(tvwapLookback.Lookback)
+		if err != nil {
+			return nil, err
+		}
+		lookbacks[strings.ToUpper(tvwapLookback.Base)] = lookback
+	}
+	return lookbacks, nil
+}
+
+// UnfilteredPairsMap converts the unfiltered_pairs from the config file into a
+// set of currency pairs. The USD pair each one is converted to is included, so
+// its converted price skips the deviation filter as well.
+func (c Config) UnfilteredPairsMap() map[types.CurrencyPair]struct{} {
+	unfilteredPairs := make(map[types.CurrencyPair]struct{}, len(c.UnfilteredPairs)*2)
+	for _, pair := range c.UnfilteredPairs {
+		unfilteredPairs[types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = struct{}{}
+		unfilteredPairs[types.CurrencyPair{Base: pair.Base, Quote: DenomUSD}] = struct{}{}
+	}
+	return unfilteredPairs
+}
+
+// VoteNudgeStepDec converts the vote_nudge_step from the config file into a
+// math.LegacyDec, which is zero when it is not set.
+func (c Config) VoteNudgeStepDec() (math.LegacyDec, error) {
+	if c.VoteNudgeStep == "" {
+		return math.LegacyZeroDec(), nil
+	}
+	return math.LegacyNewDecFromStr(c.VoteNudgeStep)
+}
+
+// MaxPriceChangePctDec returns the max price change percentage as a
+// LegacyDec, zero if it is not set.
+func (c Config) MaxPriceChangePctDec() (math.LegacyDec, error) {
+	if c.MaxPriceChangePct == "" {
+		return math.LegacyZeroDec(), nil
+	}
+	return math.LegacyNewDecFromStr(c.MaxPriceChangePct)
+}
+
 // ExpectedSymbols returns a slice of all unique base symbols from the config object.
 func (c Config) ExpectedSymbols() []string {
 	bases := make(map[string]interface{}, len(c.CurrencyPairs))
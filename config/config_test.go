@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	"github.com/rs/zerolog"
@@ -87,44 +88,847 @@ func TestValidate(t *testing.T) {
 		},
 	}
 
+	validRESTOnlyEndpoint := validConfig()
+	validRESTOnlyEndpoint.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name: provider.ProviderNormalized,
+			Rest: "bar",
+		},
+	}
+
+	validPriceSource := validConfig()
+	validPriceSource.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderBinance,
+			Rest:        "bar",
+			Websocket:   "baz",
+			PriceSource: provider.PriceSourceMicro,
+		},
+	}
+
+	invalidPriceSource := validConfig()
+	invalidPriceSource.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderBinance,
+			Rest:        "bar",
+			Websocket:   "baz",
+			PriceSource: "foo",
+		},
+	}
+
+	invalidPriceSourceProvider := validConfig()
+	invalidPriceSourceProvider.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderKraken,
+			Rest:        "bar",
+			Websocket:   "baz",
+			PriceSource: provider.PriceSourceMid,
+		},
+	}
+
+	validPairCoverageWindow := validConfig()
+	validPairCoverageWindow.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:               provider.ProviderKujira,
+			Rest:               "bar",
+			Websocket:          "baz",
+			PairCoverageWindow: "2m",
+		},
+	}
+
+	invalidPairCoverageWindowProvider := validConfig()
+	invalidPairCoverageWindowProvider.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:               provider.ProviderBinance,
+			Rest:               "bar",
+			Websocket:          "baz",
+			PairCoverageWindow: "2m",
+		},
+	}
+
+	enableRESTFallback := true
+	invalidRESTFallbackProvider := validConfig()
+	invalidRESTFallbackProvider.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:               provider.ProviderCoinbase,
+			Rest:               "bar",
+			Websocket:          "baz",
+			EnableRESTFallback: &enableRESTFallback,
+		},
+	}
+
+	validSecondaryCandleInterval := validConfig()
+	validSecondaryCandleInterval.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                    provider.ProviderBinance,
+			Rest:                    "bar",
+			Websocket:               "baz",
+			SecondaryCandleInterval: "5m",
+		},
+	}
+
+	invalidSecondaryCandleInterval := validConfig()
+	invalidSecondaryCandleInterval.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                    provider.ProviderBinance,
+			Rest:                    "bar",
+			Websocket:               "baz",
+			SecondaryCandleInterval: "7m",
+		},
+	}
+
+	validDepthPriceSource := validConfig()
+	validDepthPriceSource.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:           provider.ProviderKraken,
+			Rest:           "bar",
+			Websocket:      "baz",
+			PriceSource:    provider.PriceSourceDepth,
+			DepthNotionals: map[string]string{"usd": "100000", "btc": "2"},
+		},
+	}
+
+	invalidDepthNotional := validConfig()
+	invalidDepthNotional.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderKraken,
+			Rest:        "bar",
+			Websocket:   "baz",
+			PriceSource: provider.PriceSourceDepth,
+		},
+	}
+
+	invalidDepthPriceSourceProvider := validConfig()
+	invalidDepthPriceSourceProvider.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:           provider.ProviderBinance,
+			Rest:           "bar",
+			Websocket:      "baz",
+			PriceSource:    provider.PriceSourceDepth,
+			DepthNotionals: map[string]string{"usd": "100000"},
+		},
+	}
+
+	validIndexTickerPairs := validConfig()
+	validIndexTickerPairs.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:             provider.ProviderOkx,
+			Rest:             "bar",
+			Websocket:        "baz",
+			IndexTickerPairs: []string{"BTC-USDT"},
+		},
+	}
+
+	invalidIndexTickerPairsProvider := validConfig()
+	invalidIndexTickerPairsProvider.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:             provider.ProviderKraken,
+			Rest:             "bar",
+			Websocket:        "baz",
+			IndexTickerPairs: []string{"BTC-USDT"},
+		},
+	}
+
+	validAvailablePairs := validConfig()
+	validAvailablePairs.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                  provider.ProviderBinance,
+			Rest:                  "bar",
+			Websocket:             "baz",
+			AvailablePairs:        []string{"ATOM/USDT", "OSMO/USDT"},
+			AvailablePairsRefresh: "24h",
+		},
+	}
+
+	invalidAvailablePairs := validConfig()
+	invalidAvailablePairs.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:           provider.ProviderBinance,
+			Rest:           "bar",
+			Websocket:      "baz",
+			AvailablePairs: []string{"ATOMUSDT"},
+		},
+	}
+
+	invalidAvailablePairsRefresh := validConfig()
+	invalidAvailablePairsRefresh.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                  provider.ProviderBinance,
+			Rest:                  "bar",
+			Websocket:             "baz",
+			AvailablePairs:        []string{"ATOM/USDT"},
+			AvailablePairsRefresh: "foo",
+		},
+	}
+
+	invalidAvailablePairsCacheTTL := validConfig()
+	invalidAvailablePairsCacheTTL.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                   provider.ProviderBinance,
+			Rest:                   "bar",
+			Websocket:              "baz",
+			AvailablePairsCacheTTL: "-1m",
+		},
+	}
+
+	invalidMaxResponseSize := validConfig()
+	invalidMaxResponseSize.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:            provider.ProviderBinance,
+			Rest:            "bar",
+			Websocket:       "baz",
+			MaxResponseSize: -1,
+		},
+	}
+
+	invalidRestTimeout := validConfig()
+	invalidRestTimeout.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderBinance,
+			Rest:        "bar",
+			Websocket:   "baz",
+			RestTimeout: "0s",
+		},
+	}
+
+	validReconnectMaxInterval := validConfig()
+	validReconnectMaxInterval.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                 provider.ProviderBinance,
+			Rest:                 "bar",
+			Websocket:            "baz",
+			ReconnectMaxInterval: "5m",
+		},
+	}
+
+	invalidReconnectMaxInterval := validConfig()
+	invalidReconnectMaxInterval.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                 provider.ProviderBinance,
+			Rest:                 "bar",
+			Websocket:            "baz",
+			ReconnectMaxInterval: "-5m",
+		},
+	}
+
+	validMinTotalVolumes := validConfig()
+	validMinTotalVolumes.MinTotalVolumes = []config.MinTotalVolume{{Base: "ATOM", Volume: "10000"}}
+
+	invalidMinTotalVolumes := validConfig()
+	invalidMinTotalVolumes.MinTotalVolumes = []config.MinTotalVolume{{Base: "ATOM", Volume: "-1"}}
+
+	validPriceBands := validConfig()
+	validPriceBands.PriceBands = []config.PriceBand{{Base: "OJO", Min: "0.001", Max: "10"}}
+
+	invalidPriceBands := validConfig()
+	invalidPriceBands.PriceBands = []config.PriceBand{{Base: "OJO", Min: "10", Max: "0.001"}}
+
+	validTrustedProviders := validConfig()
+	validTrustedProviders.TrustedProviders = []config.TrustedProvider{
+		{Base: "ATOM", Quote: "USDT", Provider: provider.ProviderKraken, MaxDeviationPct: "2"},
+	}
+
+	invalidTrustedProvider := validConfig()
+	invalidTrustedProvider.TrustedProviders = []config.TrustedProvider{
+		{Base: "ATOM", Quote: "USDT", Provider: provider.ProviderCoinbase, MaxDeviationPct: "2"},
+	}
+
+	validProviderDeviations := validConfig()
+	validProviderDeviations.ProviderDeviations = []config.ProviderDeviation{{Provider: "binance", Multiplier: "0.5"}}
+
+	invalidProviderDeviations := validConfig()
+	invalidProviderDeviations.ProviderDeviations = []config.ProviderDeviation{{Provider: "binance", Multiplier: "0"}}
+
+	validTickerVolumePolicy := validConfig()
+	validTickerVolumePolicy.TickerVolumePolicy = "exclude"
+
+	invalidTickerVolumePolicy := validConfig()
+	invalidTickerVolumePolicy.TickerVolumePolicy = "foo"
+
+	validTMRPCFallbackEndpoints := validConfig()
+	validTMRPCFallbackEndpoints.RPC.TMRPCFallbackEndpoints = []string{"http://localhost:36657"}
+
+	invalidTMRPCFallbackEndpoints := validConfig()
+	invalidTMRPCFallbackEndpoints.RPC.TMRPCFallbackEndpoints = []string{""}
+
+	validGRPCFallbackEndpoints := validConfig()
+	validGRPCFallbackEndpoints.RPC.GRPCFallbackEndpoints = []string{"localhost:9091"}
+
+	invalidGRPCFallbackEndpoints := validConfig()
+	invalidGRPCFallbackEndpoints.RPC.GRPCFallbackEndpoints = []string{""}
+
+	validHealthStalenessWindow := validConfig()
+	validHealthStalenessWindow.Server.HealthStalenessWindow = "5s"
+
+	invalidHealthStalenessWindow := validConfig()
+	invalidHealthStalenessWindow.Server.HealthStalenessWindow = "foo"
+
+	validAggregationMethod := validConfig()
+	validAggregationMethod.AggregationMethod = "median"
+
+	invalidAggregationMethod := validConfig()
+	invalidAggregationMethod.AggregationMethod = "mean"
+
+	validRoundingMode := validConfig()
+	validRoundingMode.VotePrecision = 6
+	validRoundingMode.RoundingMode = "truncate"
+
+	invalidRoundingMode := validConfig()
+	invalidRoundingMode.RoundingMode = "half_even"
+
+	validVoteNudgeStep := validConfig()
+	validVoteNudgeStep.VoteNudgeStep = "0.01"
+
+	invalidVoteNudgeStep := validConfig()
+	invalidVoteNudgeStep.VoteNudgeStep = "-0.01"
+
+	validMaxPriceChangePct := validConfig()
+	validMaxPriceChangePct.MaxPriceChangePct = "10"
+
+	invalidMaxPriceChangePct := validConfig()
+	invalidMaxPriceChangePct.MaxPriceChangePct = "-10"
+
+	validProviderWeights := validConfig()
+	validProviderWeights.ProviderWeights = []config.ProviderWeight{{Provider: "osmosis", Weight: "0"}}
+
+	invalidProviderWeights := validConfig()
+	invalidProviderWeights.ProviderWeights = []config.ProviderWeight{{Provider: "osmosis", Weight: "-0.5"}}
+
+	validTVWAPLookbacks := validConfig()
+	validTVWAPLookbacks.TVWAPLookbacks = []config.TVWAPLookback{{Base: "ATOM", Lookback: "5m"}}
+
+	invalidTVWAPLookbacks := validConfig()
+	invalidTVWAPLookbacks.TVWAPLookbacks = []config.TVWAPLookback{{Base: "ATOM", Lookback: "0s"}}
+
+	validTVWAPMinPeriod := validConfig()
+	validTVWAPMinPeriod.TVWAPMinPeriod = "2m"
+
+	invalidTVWAPMinPeriod := validConfig()
+	invalidTVWAPMinPeriod.TVWAPMinPeriod = "-1m"
+
+	validCandleGapFillInterval := validConfig()
+	validCandleGapFillInterval.CandleGapFillInterval = "1m"
+
+	invalidCandleGapFillInterval := validConfig()
+	invalidCandleGapFillInterval.CandleGapFillInterval = "-1m"
+
+	unparsableCandleGapFillInterval := validConfig()
+	unparsableCandleGapFillInterval.CandleGapFillInterval = "1 minute"
+
+	validShutdownGracePeriod := validConfig()
+	validShutdownGracePeriod.ShutdownGracePeriod = "30s"
+
+	invalidShutdownGracePeriod := validConfig()
+	invalidShutdownGracePeriod.ShutdownGracePeriod = "-10s"
+
+	validTickInterval := validConfig()
+	validTickInterval.TickInterval = "500ms"
+
+	invalidTickInterval := validConfig()
+	invalidTickInterval.TickInterval = "50ms"
+
+	invalidTickIntervalStaleness := validConfig()
+	invalidTickIntervalStaleness.TickInterval = "5s"
+	invalidTickIntervalStaleness.Server.HealthStalenessWindow = "5s"
+
+	invalidVotePrecision := validConfig()
+	invalidVotePrecision.VotePrecision = 19
+
+	invalidMinProvidersPerAsset := validConfig()
+	invalidMinProvidersPerAsset.MinProvidersPerAsset = -1
+
+	invalidMaxPairsPerTick := validConfig()
+	invalidMaxPairsPerTick.MaxPairsPerTick = -1
+
+	validMaxPairsPerTick := validConfig()
+	validMaxPairsPerTick.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+		{Base: "OJO", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+	validMaxPairsPerTick.MaxPairsPerTick = 3
+
+	// the two conversion pairs of ATOM and the pair of OJO do not fit
+	tooFewMaxPairsPerTick := validMaxPairsPerTick
+	tooFewMaxPairsPerTick.MaxPairsPerTick = 2
+
+	invalidParamsFallbackMaxAge := validConfig()
+	invalidParamsFallbackMaxAge.ParamsFallbackMaxAge = -1
+
+	validAdaptiveTimeout := validConfig()
+	validAdaptiveTimeout.AdaptiveTimeout = config.AdaptiveTimeout{
+		Enabled:    true,
+		Window:     100,
+		Margin:     "50ms",
+		MinTimeout: "100ms",
+		MaxTimeout: "2s",
+	}
+
+	invalidAdaptiveTimeoutBounds := validConfig()
+	invalidAdaptiveTimeoutBounds.AdaptiveTimeout = config.AdaptiveTimeout{
+		Enabled:    true,
+		Window:     100,
+		Margin:     "50ms",
+		MinTimeout: "2s",
+		MaxTimeout: "100ms",
+	}
+
+	validCircuitBreaker := validConfig()
+	validCircuitBreaker.CircuitBreaker = config.CircuitBreaker{
+		Enabled:          true,
+		FailureThreshold: 5,
+		Cooldown:         "1m",
+	}
+
+	validAlerting := validConfig()
+	validAlerting.Alerting = config.Alerting{
+		WebhookURL: "https://alerts.example.com/price-feeder",
+		Events:     []string{"missed_vote", "circuit_opened", "low_balance"},
+		MinBalance: "1000000uojo",
+	}
+
+	invalidAlertingEvent := validConfig()
+	invalidAlertingEvent.Alerting = config.Alerting{
+		WebhookURL: "https://alerts.example.com/price-feeder",
+		Events:     []string{"balance_changed"},
+	}
+
+	invalidAlertingMinBalance := validConfig()
+	invalidAlertingMinBalance.Alerting = config.Alerting{
+		WebhookURL: "https://alerts.example.com/price-feeder",
+		MinBalance: "1000000",
+	}
+
+	validReferencePairs := validConfig()
+	validReferencePairs.ReferencePairs = []config.CurrencyPair{
+		{Base: "OSMO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	votedReferencePairs := validConfig()
+	votedReferencePairs.ReferencePairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	invalidCircuitBreakerCooldown := validConfig()
+	invalidCircuitBreakerCooldown.CircuitBreaker = config.CircuitBreaker{
+		Enabled:          true,
+		FailureThreshold: 5,
+		Cooldown:         "-1m",
+	}
+
 	testCases := []struct {
 		name      string
 		cfg       config.Config
 		expectErr bool
 	}{
 		{
-			"valid config",
-			validConfig(),
+			"valid config",
+			validConfig(),
+			false,
+		},
+		{
+			"empty pairs",
+			emptyPairs,
+			false,
+		},
+		{
+			"invalid base",
+			invalidBase,
+			true,
+		},
+		{
+			"invalid quote",
+			invalidQuote,
+			true,
+		},
+		{
+			"empty providers",
+			emptyProviders,
+			true,
+		},
+		{
+			"invalid endpoints",
+			invalidEndpoints,
+			true,
+		},
+		{
+			"invalid endpoint provider",
+			invalidEndpointsProvider,
+			true,
+		},
+		{
+			"valid rest only endpoint",
+			validRESTOnlyEndpoint,
+			false,
+		},
+		{
+			"valid price source",
+			validPriceSource,
+			false,
+		},
+		{
+			"invalid price source",
+			invalidPriceSource,
+			true,
+		},
+		{
+			"invalid price source provider",
+			invalidPriceSourceProvider,
+			true,
+		},
+		{
+			"valid pair coverage window",
+			validPairCoverageWindow,
+			false,
+		},
+		{
+			"invalid pair coverage window provider",
+			invalidPairCoverageWindowProvider,
+			true,
+		},
+		{
+			"invalid rest fallback provider",
+			invalidRESTFallbackProvider,
+			true,
+		},
+		{
+			"valid secondary candle interval",
+			validSecondaryCandleInterval,
+			false,
+		},
+		{
+			"invalid secondary candle interval",
+			invalidSecondaryCandleInterval,
+			true,
+		},
+		{
+			"valid depth price source",
+			validDepthPriceSource,
+			false,
+		},
+		{
+			"invalid depth notional",
+			invalidDepthNotional,
+			true,
+		},
+		{
+			"invalid depth price source provider",
+			invalidDepthPriceSourceProvider,
+			true,
+		},
+		{
+			"valid index ticker pairs",
+			validIndexTickerPairs,
+			false,
+		},
+		{
+			"invalid index ticker pairs provider",
+			invalidIndexTickerPairsProvider,
+			true,
+		},
+		{
+			"valid available pairs",
+			validAvailablePairs,
+			false,
+		},
+		{
+			"invalid available pairs",
+			invalidAvailablePairs,
+			true,
+		},
+		{
+			"invalid available pairs refresh",
+			invalidAvailablePairsRefresh,
+			true,
+		},
+		{
+			"invalid available pairs cache ttl",
+			invalidAvailablePairsCacheTTL,
+			true,
+		},
+		{
+			"invalid rest timeout",
+			invalidRestTimeout,
+			true,
+		},
+		{
+			"invalid max response size",
+			invalidMaxResponseSize,
+			true,
+		},
+		{
+			"valid reconnect max interval",
+			validReconnectMaxInterval,
+			false,
+		},
+		{
+			"invalid reconnect max interval",
+			invalidReconnectMaxInterval,
+			true,
+		},
+		{
+			"valid min total volumes",
+			validMinTotalVolumes,
+			false,
+		},
+		{
+			"invalid min total volumes",
+			invalidMinTotalVolumes,
+			true,
+		},
+		{
+			"valid price bands",
+			validPriceBands,
+			false,
+		},
+		{
+			"invalid price bands",
+			invalidPriceBands,
+			true,
+		},
+		{
+			"valid trusted providers",
+			validTrustedProviders,
+			false,
+		},
+		{
+			"trusted provider not a provider of the pair",
+			invalidTrustedProvider,
+			true,
+		},
+		{
+			"valid provider deviation multipliers",
+			validProviderDeviations,
+			false,
+		},
+		{
+			"invalid provider deviation multipliers",
+			invalidProviderDeviations,
+			true,
+		},
+		{
+			"valid ticker volume policy",
+			validTickerVolumePolicy,
+			false,
+		},
+		{
+			"invalid ticker volume policy",
+			invalidTickerVolumePolicy,
+			true,
+		},
+		{
+			"valid tmrpc fallback endpoints",
+			validTMRPCFallbackEndpoints,
+			false,
+		},
+		{
+			"invalid tmrpc fallback endpoints",
+			invalidTMRPCFallbackEndpoints,
+			true,
+		},
+		{
+			"valid grpc fallback endpoints",
+			validGRPCFallbackEndpoints,
+			false,
+		},
+		{
+			"invalid grpc fallback endpoints",
+			invalidGRPCFallbackEndpoints,
+			true,
+		},
+		{
+			"valid health staleness window",
+			validHealthStalenessWindow,
+			false,
+		},
+		{
+			"invalid health staleness window",
+			invalidHealthStalenessWindow,
+			true,
+		},
+		{
+			"valid aggregation method",
+			validAggregationMethod,
+			false,
+		},
+		{
+			"invalid aggregation method",
+			invalidAggregationMethod,
+			true,
+		},
+		{
+			"valid rounding mode",
+			validRoundingMode,
+			false,
+		},
+		{
+			"invalid rounding mode",
+			invalidRoundingMode,
+			true,
+		},
+		{
+			"valid vote nudge step",
+			validVoteNudgeStep,
+			false,
+		},
+		{
+			"invalid vote nudge step",
+			invalidVoteNudgeStep,
+			true,
+		},
+		{
+			"valid max price change pct",
+			validMaxPriceChangePct,
+			false,
+		},
+		{
+			"invalid max price change pct",
+			invalidMaxPriceChangePct,
+			true,
+		},
+		{
+			"valid provider weights",
+			validProviderWeights,
+			false,
+		},
+		{
+			"invalid provider weights",
+			invalidProviderWeights,
+			true,
+		},
+		{
+			"valid reference pairs",
+			validReferencePairs,
 			false,
 		},
 		{
-			"empty pairs",
-			emptyPairs,
+			"reference pairs of voted bases",
+			votedReferencePairs,
+			true,
+		},
+		{
+			"valid tvwap lookbacks",
+			validTVWAPLookbacks,
 			false,
 		},
 		{
-			"invalid base",
-			invalidBase,
+			"invalid tvwap lookbacks",
+			invalidTVWAPLookbacks,
 			true,
 		},
 		{
-			"invalid quote",
-			invalidQuote,
+			"valid tvwap min period",
+			validTVWAPMinPeriod,
+			false,
+		},
+		{
+			"invalid tvwap min period",
+			invalidTVWAPMinPeriod,
 			true,
 		},
 		{
-			"empty providers",
-			emptyProviders,
+			"valid candle gap fill interval",
+			validCandleGapFillInterval,
+			false,
+		},
+		{
+			"invalid candle gap fill interval",
+			invalidCandleGapFillInterval,
 			true,
 		},
 		{
-			"invalid endpoints",
-			invalidEndpoints,
+			"unparsable candle gap fill interval",
+			unparsableCandleGapFillInterval,
 			true,
 		},
 		{
-			"invalid endpoint provider",
-			invalidEndpointsProvider,
+			"valid shutdown grace period",
+			validShutdownGracePeriod,
+			false,
+		},
+		{
+			"invalid shutdown grace period",
+			invalidShutdownGracePeriod,
+			true,
+		},
+		{
+			"valid tick interval",
+			validTickInterval,
+			false,
+		},
+		{
+			"invalid tick interval",
+			invalidTickInterval,
+			true,
+		},
+		{
+			"tick interval longer than half the health staleness window",
+			invalidTickIntervalStaleness,
+			true,
+		},
+		{
+			"invalid vote precision",
+			invalidVotePrecision,
+			true,
+		},
+		{
+			"invalid min providers per asset",
+			invalidMinProvidersPerAsset,
+			true,
+		},
+		{
+			"invalid max pairs per tick",
+			invalidMaxPairsPerTick,
+			true,
+		},
+		{
+			"valid max pairs per tick",
+			validMaxPairsPerTick,
+			false,
+		},
+		{
+			"too few max pairs per tick",
+			tooFewMaxPairsPerTick,
+			true,
+		},
+		{
+			"invalid params fallback max age",
+			invalidParamsFallbackMaxAge,
+			true,
+		},
+		{
+			"valid adaptive timeout",
+			validAdaptiveTimeout,
+			false,
+		},
+		{
+			"invalid adaptive timeout bounds",
+			invalidAdaptiveTimeoutBounds,
+			true,
+		},
+		{
+			"valid circuit breaker",
+			validCircuitBreaker,
+			false,
+		},
+		{
+			"invalid circuit breaker cooldown",
+			invalidCircuitBreakerCooldown,
+			true,
+		},
+		{
+			"valid alerting",
+			validAlerting,
+			false,
+		},
+		{
+			"invalid alerting event",
+			invalidAlertingEvent,
+			true,
+		},
+		{
+			"invalid alerting min balance",
+			invalidAlertingMinBalance,
 			true,
 		},
 	}
@@ -297,6 +1101,235 @@ enabled = false
 	require.Equal(t, cfg.Telemetry.Enabled, false)
 }
 
+func TestParseConfig_DeprecatedFields(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fields       string
+		skipMins     bool
+		deprecations []config.DeprecatedField
+	}{
+		{
+			"migrated",
+			`provider_min_override = true`,
+			true,
+			[]config.DeprecatedField{
+				{Field: "provider_min_override", Replacement: "skip_provider_mins_on_failure", Migrated: true},
+			},
+		},
+		{
+			"ignored when the replacement is set",
+			"provider_min_override = true\nskip_provider_mins_on_failure = false",
+			false,
+			[]config.DeprecatedField{
+				{Field: "provider_min_override", Replacement: "skip_provider_mins_on_failure", Migrated: false},
+			},
+		},
+		{
+			"not deprecated",
+			`skip_provider_mins_on_failure = true`,
+			true,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			content := []byte(tc.fields + `
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
+`)
+			_, err = tmpFile.Write(content)
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			require.NoError(t, err)
+			require.Equal(t, tc.skipMins, cfg.SkipProviderMinsOnFailure)
+			require.Equal(t, tc.deprecations, cfg.DeprecatedFields)
+			for _, deprecated := range cfg.DeprecatedFields {
+				require.Contains(t, deprecated.Warning(), "config field provider_min_override is deprecated")
+			}
+		})
+	}
+}
+
+func TestParseConfig_ProviderTimeout(t *testing.T) {
+	testCases := []struct {
+		name             string
+		providerTimeout  string
+		defaultTimeout   time.Duration
+		providerTimeouts map[types.ProviderName]time.Duration
+	}{
+		{
+			"plain duration",
+			`provider_timeout = "200ms"`,
+			200 * time.Millisecond,
+			map[types.ProviderName]time.Duration{},
+		},
+		{
+			"per provider durations",
+			`provider_timeout = { default = "200ms", astroport = "3s" }`,
+			200 * time.Millisecond,
+			map[types.ProviderName]time.Duration{provider.ProviderAstroport: 3 * time.Second},
+		},
+		{
+			"per provider durations without a default",
+			`provider_timeout = { astroport = "3s" }`,
+			100 * time.Millisecond,
+			map[types.ProviderName]time.Duration{provider.ProviderAstroport: 3 * time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			content := []byte(`
+gas_adjustment = 1.5
+` + tc.providerTimeout + `
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
+`)
+			_, err = tmpFile.Write(content)
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			require.NoError(t, err)
+
+			defaultTimeout, providerTimeouts, err := cfg.ProviderTimeouts()
+			require.NoError(t, err)
+			require.Equal(t, tc.defaultTimeout, defaultTimeout)
+			require.Equal(t, tc.providerTimeouts, providerTimeouts)
+		})
+	}
+}
+
+func TestParseConfig_HealthStalenessWindow(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tickInterval string
+		window       string
+	}{
+		{
+			"default tick interval",
+			``,
+			"5s",
+		},
+		{
+			"configured tick interval",
+			`tick_interval = "2s"`,
+			"10s",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			content := []byte(`
+gas_adjustment = 1.5
+` + tc.tickInterval + `
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
+`)
+			_, err = tmpFile.Write(content)
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			require.NoError(t, err)
+			require.Equal(t, tc.window, cfg.Server.HealthStalenessWindow)
+		})
+	}
+}
+
 func TestParseConfig_InvalidProvider(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
@@ -990,6 +2023,66 @@ enabled = false
 	require.Error(t, err, "currency pair quote UMEE is not supported")
 }
 
+func TestValidateUSDConversionPaths(t *testing.T) {
+	brokenConversions := []types.CurrencyPair{
+		// BAR only converts to BAZ, which has no path to USD
+		{Base: "FOO", Quote: "BAR"},
+		{Base: "BAR", Quote: "BAZ"},
+		// QUX reaches USD through WSTETH in 4 conversions, one too many
+		{Base: "QUX", Quote: "WSTETH"},
+	}
+	for _, cp := range brokenConversions {
+		config.SupportedConversions[cp] = struct{}{}
+	}
+	defer func() {
+		for _, cp := range brokenConversions {
+			delete(config.SupportedConversions, cp)
+		}
+	}()
+
+	newConfig := func(pairs ...config.CurrencyPair) config.Config {
+		return config.Config{
+			Server: config.Server{
+				ListenAddr:     "0.0.0.0:7171",
+				AllowedOrigins: []string{},
+			},
+			CurrencyPairs: pairs,
+			Account:       config.Account{Address: "fromaddr", Validator: "valaddr", ChainID: "chain-id"},
+			Keyring:       config.Keyring{Backend: "test", Dir: "/Users/username/.ojo"},
+			RPC: config.RPC{
+				TMRPCEndpoint: "http://localhost:26657",
+				GRPCEndpoint:  "localhost:9090",
+				RPCTimeout:    "100ms",
+			},
+			Telemetry:     telemetry.Config{ServiceName: "price-feeder"},
+			GasAdjustment: 1.5,
+		}
+	}
+	providers := []types.ProviderName{provider.ProviderKraken}
+
+	// WSTETH reaches USD through WETH, USDC and USD
+	require.NoError(t, newConfig(
+		config.CurrencyPair{Base: "ATOM", Quote: "USD", Providers: providers},
+		config.CurrencyPair{Base: "STETH", Quote: "WSTETH", Providers: providers},
+	).Validate())
+
+	err := newConfig(config.CurrencyPair{Base: "OJO", Quote: "FOO", Providers: providers}).Validate()
+	require.ErrorContains(t, err, "currency pair base OJO cannot be converted to USD")
+
+	err = newConfig(config.CurrencyPair{Base: "OJO", Quote: "QUX", Providers: providers}).Validate()
+	require.ErrorContains(t, err, "no conversion path from QUX to USD within 3 conversions")
+
+	// a forex quote requires its USD pair to be configured
+	bitso := []types.ProviderName{provider.ProviderBitso}
+	err = newConfig(config.CurrencyPair{Base: "BTC", Quote: "MXN", Providers: bitso}).Validate()
+	require.ErrorContains(t, err, "requires the MXN/USD conversion pair to be configured")
+
+	require.NoError(t, newConfig(
+		config.CurrencyPair{Base: "BTC", Quote: "MXN", Providers: bitso},
+		config.CurrencyPair{Base: "MXN", Quote: "USD", Providers: []types.ProviderName{provider.ProviderMock}},
+	).Validate())
+}
+
 func TestValidCurrencyPairs(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
@@ -1129,3 +2222,18 @@ providers = [
 	_, err = config.ParseConfigs([]string{tmpFile.Name(), tmpFile2.Name()})
 	require.NoError(t, err)
 }
+
+func TestConversionRateOverridesMap(t *testing.T) {
+	cfg := config.Config{
+		ConversionRateOverrides: []config.ConversionRate{
+			{Base: "usdt", Rate: "1"},
+			{Base: "ATOM", Rate: "10.5"},
+		},
+	}
+
+	overrides, err := cfg.ConversionRateOverridesMap()
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+	require.Equal(t, "1.000000000000000000", overrides["USDT"].String())
+	require.Equal(t, "10.500000000000000000", overrides["ATOM"].String())
+}
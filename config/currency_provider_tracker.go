@@ -237,9 +237,9 @@ func CheckProviderMins(ctx context.Context, logger zerolog.Logger, cfg Config) e
 	currencyProviderTracker, err := NewCurrencyProviderTracker(ctx, logger, cfg.CurrencyPairs...)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to start currency provider tracker")
-		// If currency tracker errors out and override flag is set, the price-feeder
+		// If currency tracker errors out and skip flag is set, the price-feeder
 		// will run without enforcing provider minimums.
-		if cfg.ProviderMinOverride {
+		if cfg.SkipProviderMinsOnFailure {
 			return nil
 		}
 	}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// deprecatedFields maps config fields which were renamed to the field which
+// replaced them.
+var deprecatedFields = map[string]string{
+	"provider_min_override": "skip_provider_mins_on_failure",
+}
+
+// DeprecatedField defines a deprecated field found in the config, the field
+// which replaced it, and whether its value was migrated to the replacement.
+type DeprecatedField struct {
+	Field       string
+	Replacement string
+	Migrated    bool
+}
+
+// Warning returns the deprecation warning to report to the operator.
+func (d DeprecatedField) Warning() string {
+	if d.Migrated {
+		return fmt.Sprintf(
+			"config field %s is deprecated and was migrated to %s; please update your config",
+			d.Field, d.Replacement,
+		)
+	}
+	return fmt.Sprintf(
+		"config field %s is deprecated and was ignored since %s is also set; please remove it from your config",
+		d.Field, d.Replacement,
+	)
+}
+
+// migrateDeprecatedFields detects deprecated fields in the config and sets
+// their values on the fields which replaced them, unless those are set too.
+func migrateDeprecatedFields(v *viper.Viper) []DeprecatedField {
+	var found []DeprecatedField
+	for field, replacement := range deprecatedFields {
+		if !v.IsSet(field) {
+			continue
+		}
+
+		deprecated := DeprecatedField{Field: field, Replacement: replacement}
+		if !v.IsSet(replacement) {
+			v.Set(replacement, v.Get(field))
+			deprecated.Migrated = true
+		}
+		found = append(found, deprecated)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Field < found[j].Field
+	})
+	return found
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
@@ -51,7 +53,7 @@ func parseConfigDir(nodeConfigPath string) (string, error) {
 	if err := viper.ReadInConfig(); err != nil {
 		return "", fmt.Errorf("failed to read node config: %w", err)
 	}
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := viper.Unmarshal(&cfg, decodeHook()); err != nil {
 		return "", fmt.Errorf("failed to decode node config: %w", err)
 	}
 	return cfg.ConfigDir, nil
@@ -68,26 +70,52 @@ func ParseConfig(configPath string) (Config, error) {
 func ParseConfigs(configPaths []string) (Config, error) {
 	var cfg Config
 
-	viper.AutomaticEnv()
+	v := viper.New()
+	v.AutomaticEnv()
 	// Allow nested env vars to be read with underscore separators.
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Loop over each config path and merge its values into the previous one
 	for _, configPath := range configPaths {
 		if configPath == "" {
 			return cfg, ErrEmptyConfigPath
 		}
-		viper.SetConfigFile(configPath)
-		if err := viper.MergeInConfig(); err != nil {
+		v.SetConfigFile(configPath)
+		if err := v.MergeInConfig(); err != nil {
 			return cfg, fmt.Errorf("failed to read config: %w", err)
 		}
 	}
 
-	if err := viper.Unmarshal(&cfg); err != nil {
+	deprecatedFields := migrateDeprecatedFields(v)
+
+	if err := v.Unmarshal(&cfg, decodeHook()); err != nil {
 		return cfg, fmt.Errorf("failed to decode config: %w", err)
 	}
 
+	cfg.DeprecatedFields = deprecatedFields
 	cfg.setDefaults()
 
 	return cfg, cfg.Validate()
 }
+
+// decodeHook returns the hooks used to decode the config, which are viper's
+// default hooks along with the decoding of a plain provider_timeout duration.
+func decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToProviderTimeoutHookFunc(),
+	))
+}
+
+// stringToProviderTimeoutHookFunc decodes a plain duration into a
+// ProviderTimeout holding it as the default timeout, so configs predating
+// per-provider timeouts keep working.
+func stringToProviderTimeoutHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(ProviderTimeout{}) {
+			return data, nil
+		}
+		return ProviderTimeout{providerTimeoutDefaultKey: data.(string)}, nil
+	}
+}
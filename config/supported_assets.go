@@ -19,7 +19,9 @@ var (
 		provider.ProviderHuobi:       false,
 		provider.ProviderGate:        false,
 		provider.ProviderCoinbase:    false,
+		provider.ProviderBitso:       false,
 		provider.ProviderBitget:      false,
+		provider.ProviderBybit:       false,
 		provider.ProviderMexc:        false,
 		provider.ProviderCrypto:      false,
 		provider.ProviderPolygon:     true,
@@ -29,7 +31,12 @@ var (
 		provider.ProviderEthPancake:  false,
 		provider.ProviderEthCurve:    false,
 		provider.ProviderKujira:      false,
+		provider.ProviderKuCoin:      false,
 		provider.ProviderAstroport:   false,
+		provider.ProviderNormalized:  false,
+		provider.ProviderCoinGecko:   false,
+		provider.ProviderDeribit:     false,
+		provider.ProviderBitfinex:    false,
 		provider.ProviderMock:        false,
 	}
 
@@ -46,6 +53,8 @@ var (
 		{Base: "OSMO", Quote: "USD"}: {},
 		{Base: "INJ", Quote: "USD"}:  {},
 		{Base: "TIA", Quote: "USD"}:  {},
+		{Base: "MXN", Quote: "USD"}:  {},
+		{Base: "ARS", Quote: "USD"}:  {},
 
 		{Base: "OSMO", Quote: "USDT"}:   {},
 		{Base: "JUNO", Quote: "USDT"}:   {},
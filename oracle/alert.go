@@ -0,0 +1,133 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// alertTimeout defines how long an alert is given to be delivered.
+const alertTimeout = 10 * time.Second
+
+var (
+	_ Alerter = NoopAlerter{}
+	_ Alerter = (*WebhookAlerter)(nil)
+)
+
+type (
+	// Alerter defines an interface an alerting backend must implement to be
+	// notified of the critical events of the oracle.
+	Alerter interface {
+		Alert(ctx context.Context, event types.AlertEvent) error
+	}
+
+	// NoopAlerter defines an Alerter dropping every event, used when alerting
+	// is not configured.
+	NoopAlerter struct{}
+
+	// WebhookAlerter defines an Alerter POSTing the events as JSON to a
+	// webhook URL.
+	WebhookAlerter struct {
+		url    string
+		events map[types.AlertEventType]struct{}
+		client *http.Client
+	}
+)
+
+// Alert implements the Alerter interface.
+func (NoopAlerter) Alert(context.Context, types.AlertEvent) error {
+	return nil
+}
+
+// NewWebhookAlerter returns a new WebhookAlerter POSTing the given events to
+// the webhook URL, or every event if none are given.
+func NewWebhookAlerter(url string, events []types.AlertEventType) *WebhookAlerter {
+	eventSet := make(map[types.AlertEventType]struct{}, len(events))
+	for _, event := range events {
+		eventSet[event] = struct{}{}
+	}
+
+	return &WebhookAlerter{
+		url:    url,
+		events: eventSet,
+		client: &http.Client{Timeout: alertTimeout},
+	}
+}
+
+// Alert POSTs the event to the webhook URL, unless it is filtered out.
+func (wa *WebhookAlerter) Alert(ctx context.Context, event types.AlertEvent) error {
+	if len(wa.events) > 0 {
+		if _, ok := wa.events[event.Type]; !ok {
+			return nil
+		}
+	}
+
+	bz, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wa.url, bytes.NewReader(bz))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wa.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// alert sends the event to the alerter in the background, so a slow or
+// unreachable webhook never delays a tick.
+func (o *Oracle) alert(event types.AlertEvent) {
+	event.Timestamp = time.Now()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+
+		if err := o.alerter.Alert(ctx, event); err != nil {
+			o.logger.Warn().Err(err).Str("event", string(event.Type)).Msg("failed to send alert")
+		}
+	}()
+}
+
+// checkBalance sends the low balance alert once the balance of the feeder
+// account falls below the min balance, and again only after it recovered.
+func (o *Oracle) checkBalance(ctx context.Context) {
+	if o.minBalance.IsNil() || !o.minBalance.IsPositive() || o.dryRun {
+		return
+	}
+
+	balance, err := o.getBalance(ctx, o.oracleClient.FeederAddrString(), o.minBalance.Denom)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to check the feeder account balance")
+		return
+	}
+
+	lowBalance := balance.IsLT(o.minBalance)
+	if lowBalance && !o.lowBalance {
+		o.logger.Warn().
+			Str("balance", balance.String()).
+			Str("min_balance", o.minBalance.String()).
+			Msg("feeder account balance is below the min balance")
+		o.alert(types.AlertEvent{
+			Type:    types.AlertEventLowBalance,
+			Message: fmt.Sprintf("feeder account balance %s is below %s", balance, o.minBalance),
+		})
+	}
+	o.lowBalance = lowBalance
+}
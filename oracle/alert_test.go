@@ -0,0 +1,136 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestWebhookAlerter(t *testing.T) {
+	received := make(chan types.AlertEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var event types.AlertEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	alerter := NewWebhookAlerter(server.URL, []types.AlertEventType{types.AlertEventMissedVote})
+	o.SetAlerter(alerter)
+
+	o.recordMissedVote(2, 10, types.MissedVoteReasonVotePeriodMissed, nil)
+
+	select {
+	case event := <-received:
+		require.Equal(t, types.AlertEventMissedVote, event.Type)
+		require.Equal(t, int64(10), event.Height)
+		require.Equal(t, "missed vote: vote_period_missed", event.Message)
+		require.False(t, event.Timestamp.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook did not receive the missed vote alert")
+	}
+
+	// the following missed votes are only alerted on after a broadcast
+	o.recordMissedVote(3, 15, types.MissedVoteReasonPriceFailure, nil)
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, received)
+	require.Len(t, o.GetMissedVotes(), 2)
+
+	o.recordBroadcast()
+	o.recordMissedVote(4, 20, types.MissedVoteReasonPriceFailure, nil)
+	select {
+	case event := <-received:
+		require.Equal(t, int64(20), event.Height)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook did not receive the missed vote alert")
+	}
+
+	// the events which are not filtered on are not sent
+	require.NoError(t, alerter.Alert(context.Background(), types.AlertEvent{
+		Type:     types.AlertEventCircuitOpened,
+		Provider: provider.ProviderBinance,
+	}))
+	require.Empty(t, received)
+}
+
+// recordingAlerter defines an Alerter recording the events it is sent.
+type recordingAlerter struct {
+	events chan types.AlertEvent
+}
+
+func (ra recordingAlerter) Alert(_ context.Context, event types.AlertEvent) error {
+	ra.events <- event
+	return nil
+}
+
+func TestCheckBalance(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{OracleAddrString: "ojo1feeder"},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	alerter := recordingAlerter{events: make(chan types.AlertEvent, 2)}
+	o.SetAlerter(alerter)
+	o.SetMinBalance(sdk.NewInt64Coin("uojo", 100))
+
+	balance := sdk.NewInt64Coin("uojo", 50)
+	o.getBalance = func(_ context.Context, address, denom string) (sdk.Coin, error) {
+		require.Equal(t, "ojo1feeder", address)
+		require.Equal(t, "uojo", denom)
+		return balance, nil
+	}
+
+	// the alert is sent once when the balance falls below the min balance
+	o.checkBalance(context.Background())
+	o.checkBalance(context.Background())
+	select {
+	case event := <-alerter.events:
+		require.Equal(t, types.AlertEventLowBalance, event.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("low balance alert was not sent")
+	}
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, alerter.events)
+
+	// and again once it fell below it after recovering
+	balance = sdk.NewInt64Coin("uojo", 200)
+	o.checkBalance(context.Background())
+	balance = sdk.NewInt64Coin("uojo", 99)
+	o.checkBalance(context.Background())
+	select {
+	case event := <-alerter.events:
+		require.Equal(t, types.AlertEventLowBalance, event.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("low balance alert was not sent")
+	}
+}
@@ -0,0 +1,68 @@
+package oracle
+
+import (
+	"sort"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	auditSourceCandle = "candle"
+	auditSourceTicker = "ticker"
+)
+
+// GetVoteAudit returns the per-provider breakdown of the given voted prices,
+// listing for each asset the providers whose converted candle or ticker
+// prices were used to compute it, as recorded during the last price
+// computation. It must be called with the prices derived from that
+// computation, before the prices are recomputed.
+func (o *Oracle) GetVoteAudit(prices types.CurrencyPairDec) types.VoteAudit {
+	audit := types.VoteAudit{
+		ExchangeRates: GenerateExchangeRatesString(prices),
+		Assets:        make([]types.AssetAudit, 0, len(prices)),
+	}
+
+	for cp, price := range prices {
+		asset := types.AssetAudit{
+			Base:      cp.Base,
+			Price:     price,
+			Providers: []types.ProviderPriceAudit{},
+		}
+
+		priceDebug, err := o.GetPriceDebug(cp.Base)
+		if err != nil {
+			o.logger.Error().Err(err).Str("asset", cp.Base).Msg("failed to get price breakdown for vote audit")
+			audit.Assets = append(audit.Assets, asset)
+			continue
+		}
+
+		for _, p := range priceDebug.Providers {
+			// the tickers of the pairs priced from candles are recorded as
+			// filtered out, so only the prices used have no filter reason
+			if priceDebug.TVWAP != nil && p.ConvertedCandle != nil && p.CandleFilterReason == "" {
+				asset.Providers = append(asset.Providers, types.ProviderPriceAudit{
+					Provider: p.Provider,
+					Pair:     p.Pair,
+					Source:   auditSourceCandle,
+					Price:    p.ConvertedCandle.Price,
+				})
+			}
+			if priceDebug.VWAP != nil && p.ConvertedTicker != nil && p.TickerFilterReason == "" {
+				asset.Providers = append(asset.Providers, types.ProviderPriceAudit{
+					Provider: p.Provider,
+					Pair:     p.Pair,
+					Source:   auditSourceTicker,
+					Price:    p.ConvertedTicker.Price,
+				})
+			}
+		}
+
+		audit.Assets = append(audit.Assets, asset)
+	}
+
+	sort.Slice(audit.Assets, func(i, j int) bool {
+		return audit.Assets[i].Base < audit.Assets[j].Base
+	})
+
+	return audit
+}
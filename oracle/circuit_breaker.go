@@ -0,0 +1,130 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+
+	// circuitStateOpened and circuitStateClosed label the telemetry of a
+	// circuit opening or closing.
+	circuitStateOpened = "opened"
+	circuitStateClosed = "closed"
+)
+
+// circuitState defines the state of the circuit of a provider.
+type circuitState int
+
+type (
+	// CircuitBreaker tracks the consecutive fetch failures of each provider.
+	// Once a provider fails failureThreshold times in a row its circuit opens
+	// and the provider is skipped for the cooldown. The circuit is then
+	// half-opened to let a fetch test whether the provider recovered, closing
+	// the circuit on success or opening it again on failure.
+	CircuitBreaker struct {
+		failureThreshold int
+		cooldown         time.Duration
+		now              func() time.Time
+
+		mtx      sync.Mutex
+		circuits map[types.ProviderName]*providerCircuit
+	}
+
+	// providerCircuit defines the circuit of a single provider.
+	providerCircuit struct {
+		state    circuitState
+		failures int
+		openedAt time.Time
+	}
+)
+
+// NewCircuitBreaker returns a new CircuitBreaker which opens the circuit of a
+// provider after failureThreshold consecutive failures for the cooldown.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+		circuits:         make(map[types.ProviderName]*providerCircuit),
+	}
+}
+
+// Allow returns whether the given provider may be fetched. An open circuit
+// whose cooldown elapsed is half-opened, allowing trial fetches until one of
+// them succeeds or fails.
+func (cb *CircuitBreaker) Allow(providerName types.ProviderName) bool {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	circuit, ok := cb.circuits[providerName]
+	if !ok {
+		return true
+	}
+
+	switch circuit.state {
+	case circuitOpen:
+		if cb.now().Sub(circuit.openedAt) < cb.cooldown {
+			return false
+		}
+		circuit.state = circuitHalfOpen
+		return true
+
+	default:
+		return true
+	}
+}
+
+// IsOpen returns whether the circuit of the given provider is open, without
+// half-opening it.
+func (cb *CircuitBreaker) IsOpen(providerName types.ProviderName) bool {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	circuit, ok := cb.circuits[providerName]
+	return ok && circuit.state == circuitOpen
+}
+
+// RecordSuccess resets the failures of the given provider, and returns true
+// if it closed the circuit of a recovered provider.
+func (cb *CircuitBreaker) RecordSuccess(providerName types.ProviderName) bool {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	circuit, ok := cb.circuits[providerName]
+	if !ok {
+		return false
+	}
+
+	closed := circuit.state != circuitClosed
+	delete(cb.circuits, providerName)
+	return closed
+}
+
+// RecordFailure counts a failure of the given provider, and returns true if
+// it opened the circuit of the provider, either by reaching the failure
+// threshold or by failing the trial fetch of a half-open circuit.
+func (cb *CircuitBreaker) RecordFailure(providerName types.ProviderName) bool {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	circuit, ok := cb.circuits[providerName]
+	if !ok {
+		circuit = &providerCircuit{}
+		cb.circuits[providerName] = circuit
+	}
+
+	circuit.failures++
+	if circuit.state == circuitHalfOpen ||
+		(circuit.state == circuitClosed && circuit.failures >= cb.failureThreshold) {
+		circuit.state = circuitOpen
+		circuit.openedAt = cb.now()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package oracle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := oracle.NewCircuitBreaker(3, 50*time.Millisecond)
+
+	// the circuit opens on the third consecutive failure
+	require.True(t, cb.Allow(provider.ProviderBinance))
+	require.False(t, cb.RecordFailure(provider.ProviderBinance))
+	require.False(t, cb.RecordFailure(provider.ProviderBinance))
+	require.True(t, cb.RecordFailure(provider.ProviderBinance))
+	require.False(t, cb.Allow(provider.ProviderBinance))
+	require.True(t, cb.IsOpen(provider.ProviderBinance))
+
+	// circuits are tracked per provider
+	require.True(t, cb.Allow(provider.ProviderKraken))
+	require.False(t, cb.IsOpen(provider.ProviderKraken))
+
+	// the circuit is half-opened after the cooldown, and a failed trial
+	// opens it again right away
+	time.Sleep(50 * time.Millisecond)
+	require.True(t, cb.Allow(provider.ProviderBinance))
+	require.True(t, cb.RecordFailure(provider.ProviderBinance))
+	require.False(t, cb.Allow(provider.ProviderBinance))
+
+	// a successful trial closes the circuit and resets the failures
+	time.Sleep(50 * time.Millisecond)
+	require.True(t, cb.Allow(provider.ProviderBinance))
+	require.True(t, cb.RecordSuccess(provider.ProviderBinance))
+	require.True(t, cb.Allow(provider.ProviderBinance))
+	require.False(t, cb.RecordFailure(provider.ProviderBinance))
+	require.True(t, cb.Allow(provider.ProviderBinance))
+}
+
+func TestCircuitBreakerResetOnSuccess(t *testing.T) {
+	cb := oracle.NewCircuitBreaker(2, time.Minute)
+
+	// failures must be consecutive to open the circuit
+	require.False(t, cb.RecordFailure(provider.ProviderBinance))
+	require.False(t, cb.RecordSuccess(provider.ProviderBinance))
+	require.False(t, cb.RecordFailure(provider.ProviderBinance))
+	require.True(t, cb.Allow(provider.ProviderBinance))
+}
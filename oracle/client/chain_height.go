@@ -68,6 +68,15 @@ func NewChainHeight(
 	return chainHeight, nil
 }
 
+// NewStaticChainHeight returns a new ChainHeight struct fixed at the given
+// height, which is not subscribed to new blocks.
+func NewStaticChainHeight(logger zerolog.Logger, height int64) *ChainHeight {
+	return &ChainHeight{
+		Logger:          logger.With().Str("oracle_client", "chain_height").Logger(),
+		lastChainHeight: height,
+	}
+}
+
 // updateChainHeight receives the data to be updated thread safe.
 func (chainHeight *ChainHeight) updateChainHeight(blockHeight int64, err error) {
 	chainHeight.mtx.Lock()
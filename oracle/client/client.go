@@ -15,16 +15,22 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/rpc"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module/testutil"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	ojoparams "github.com/ojo-network/ojo/app/params"
 	"github.com/rs/zerolog"
 )
 
+// supportedKeyringAlgos defines the signing algorithms the oracle's key may be
+// configured to use.
+var supportedKeyringAlgos = keyring.SigningAlgoList{hd.Secp256k1}
+
 type (
 	// OracleClient defines a structure that interfaces with the Ojo node.
 	OracleClient struct {
@@ -32,8 +38,10 @@ type (
 		ChainID             string
 		KeyringBackend      string
 		KeyringDir          string
+		KeyringAlgo         keyring.SignatureAlgo
 		KeyringPass         string
 		TMRPC               string
+		TMRPCFallbacks      []string
 		RPCTimeout          time.Duration
 		OracleAddr          sdk.AccAddress
 		OracleAddrString    string
@@ -44,8 +52,11 @@ type (
 		GasAdjustment       float64
 		Gas                 uint64
 		GRPCEndpoint        string
+		GRPCFallbacks       []string
 		KeyringPassphrase   string
 		ChainHeight         *ChainHeight
+		FeeGranter          sdk.AccAddress
+		AuthzGranter        sdk.AccAddress
 	}
 
 	passReader struct {
@@ -60,27 +71,55 @@ func NewOracleClient(
 	chainID string,
 	keyringBackend string,
 	keyringDir string,
+	keyringAlgoName string,
 	keyringPass string,
 	tmRPC string,
+	tmRPCFallbacks []string,
 	rpcTimeout time.Duration,
 	oracleAddrString string,
 	validatorAddrString string,
 	grpcEndpoint string,
+	grpcFallbacks []string,
 	gasAdjustment float64,
 	gas uint64,
+	feeGranterString string,
+	authzGranterString string,
 ) (OracleClient, error) {
 	oracleAddr, err := sdk.AccAddressFromBech32(oracleAddrString)
 	if err != nil {
 		return OracleClient{}, err
 	}
 
+	keyringAlgo, err := keyringAlgo(keyringAlgoName)
+	if err != nil {
+		return OracleClient{}, err
+	}
+
+	var feeGranter sdk.AccAddress
+	if feeGranterString != "" {
+		feeGranter, err = sdk.AccAddressFromBech32(feeGranterString)
+		if err != nil {
+			return OracleClient{}, fmt.Errorf("invalid fee granter address: %w", err)
+		}
+	}
+
+	var authzGranter sdk.AccAddress
+	if authzGranterString != "" {
+		authzGranter, err = sdk.AccAddressFromBech32(authzGranterString)
+		if err != nil {
+			return OracleClient{}, fmt.Errorf("invalid authz granter address: %w", err)
+		}
+	}
+
 	oracleClient := OracleClient{
 		Logger:              logger.With().Str("module", "oracle_client").Logger(),
 		ChainID:             chainID,
 		KeyringBackend:      keyringBackend,
 		KeyringDir:          keyringDir,
+		KeyringAlgo:         keyringAlgo,
 		KeyringPass:         keyringPass,
 		TMRPC:               tmRPC,
+		TMRPCFallbacks:      tmRPCFallbacks,
 		RPCTimeout:          rpcTimeout,
 		OracleAddr:          oracleAddr,
 		OracleAddrString:    oracleAddrString,
@@ -90,30 +129,61 @@ func NewOracleClient(
 		GasAdjustment:       gasAdjustment,
 		Gas:                 gas,
 		GRPCEndpoint:        grpcEndpoint,
+		GRPCFallbacks:       grpcFallbacks,
+		FeeGranter:          feeGranter,
+		AuthzGranter:        authzGranter,
 	}
 
-	clientCtx, err := oracleClient.CreateClientContext()
+	// the chain height is subscribed to with the first Tendermint RPC endpoint
+	// which can be reached
+	for _, tmRPC := range oracleClient.tmRPCEndpoints() {
+		oracleClient.ChainHeight, err = oracleClient.newChainHeight(ctx, tmRPC)
+		if err == nil {
+			break
+		}
+		oracleClient.Logger.Warn().
+			Err(err).
+			Str("tmrpc_endpoint", tmRPC).
+			Msg("failed to subscribe to the chain height")
+	}
 	if err != nil {
 		return OracleClient{}, err
 	}
 
+	return oracleClient, nil
+}
+
+// newChainHeight returns a ChainHeight subscribed to the new blocks of the
+// given Tendermint RPC endpoint.
+func (oc OracleClient) newChainHeight(ctx context.Context, tmRPC string) (*ChainHeight, error) {
+	clientCtx, err := oc.createClientContext(tmRPC)
+	if err != nil {
+		return nil, err
+	}
+
 	blockHeight, err := rpc.GetChainHeight(clientCtx)
 	if err != nil {
-		return OracleClient{}, err
+		return nil, err
 	}
 
-	chainHeight, err := NewChainHeight(
+	return NewChainHeight(
 		ctx,
 		clientCtx.Client,
-		oracleClient.Logger,
+		oc.Logger,
 		blockHeight,
 	)
-	if err != nil {
-		return OracleClient{}, err
-	}
-	oracleClient.ChainHeight = chainHeight
+}
 
-	return oracleClient, nil
+// tmRPCEndpoints returns the Tendermint RPC endpoint followed by the fallback
+// Tendermint RPC endpoints, in the order they are used.
+func (oc OracleClient) tmRPCEndpoints() []string {
+	return append([]string{oc.TMRPC}, oc.TMRPCFallbacks...)
+}
+
+// GRPCEndpoints returns the gRPC endpoint followed by the fallback gRPC
+// endpoints, in the order they are queried.
+func (oc OracleClient) GRPCEndpoints() []string {
+	return append([]string{oc.GRPCEndpoint}, oc.GRPCFallbacks...)
 }
 
 func newPassReader(pass string) io.Reader {
@@ -134,28 +204,61 @@ func (r *passReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
+// FeederAddrString returns the address to set as the feeder of oracle messages.
+// If an authz granter is configured, messages are executed on behalf of the
+// granter, so the granter is the feeder.
+func (oc OracleClient) FeederAddrString() string {
+	if !oc.AuthzGranter.Empty() {
+		return oc.AuthzGranter.String()
+	}
+	return oc.OracleAddrString
+}
+
+// wrapMsgs wraps the given messages in an authz MsgExec executed by the
+// oracle account if an authz granter is configured.
+func (oc OracleClient) wrapMsgs(msgs ...sdk.Msg) []sdk.Msg {
+	if oc.AuthzGranter.Empty() {
+		return msgs
+	}
+
+	msgExec := authz.NewMsgExec(oc.OracleAddr, msgs)
+	return []sdk.Msg{&msgExec}
+}
+
 // BroadcastTx attempts to broadcast a signed transaction. If it fails, a few re-attempts
-// will be made until the transaction succeeds or ultimately times out or fails.
+// will be made until the transaction succeeds or ultimately times out or fails. Each
+// attempt falls back on the fallback Tendermint RPC endpoints, in order, if the
+// primary endpoint can't be reached. When it times out after the
+// transaction was rejected, the last rejected response is returned along with
+// the error.
 // Ref: https://github.com/terra-money/oracle-feeder/blob/baef2a4a02f57a2ffeaa207932b2e03d7fb0fb25/feeder/src/vote.ts#L230
-func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) error {
+func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
 	maxBlockHeight := nextBlockHeight + timeoutHeight
 	lastCheckHeight := nextBlockHeight - 1
+	msgs = oc.wrapMsgs(msgs...)
 
-	clientCtx, err := oc.CreateClientContext()
-	if err != nil {
-		return err
+	clientCtxs := make([]client.Context, 0, len(oc.TMRPCFallbacks)+1)
+	for _, tmRPC := range oc.tmRPCEndpoints() {
+		clientCtx, err := oc.createClientContext(tmRPC)
+		if err != nil {
+			return nil, err
+		}
+		clientCtxs = append(clientCtxs, clientCtx)
 	}
 
 	factory, err := oc.CreateTxFactory()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// the last rejected response, returned along with the timeout error
+	var rejectedResp *sdk.TxResponse
+
 	// re-try voting until timeout
 	for lastCheckHeight < maxBlockHeight {
 		latestBlockHeight, err := oc.ChainHeight.GetChainHeight()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if latestBlockHeight <= lastCheckHeight {
@@ -165,11 +268,9 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 		// set last check height to latest block height
 		lastCheckHeight = latestBlockHeight
 
-		resp, err := BroadcastTx(clientCtx, factory, msgs...)
-		if resp != nil && resp.Code != 0 {
-			telemetry.IncrCounter(1, "failure", "tx", "code")
-			err = fmt.Errorf("invalid response code from tx: %d", resp.Code)
-		}
+		resp, err := broadcastFailover(oc.Logger, clientCtxs, func(clientCtx client.Context) (*sdk.TxResponse, error) {
+			return BroadcastTx(clientCtx, factory, msgs...)
+		})
 		if err != nil {
 			var (
 				code uint32
@@ -178,6 +279,9 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 			if resp != nil {
 				code = resp.Code
 				hash = resp.TxHash
+				if resp.Code != 0 {
+					rejectedResp = resp
+				}
 			}
 
 			oc.Logger.Debug().
@@ -198,44 +302,80 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 			Int64("tx_height", resp.Height).
 			Msg("successfully broadcasted tx")
 
-		return nil
+		return resp, nil
 	}
 
 	telemetry.IncrCounter(1, "failure", "tx", "timeout")
-	return errors.New("broadcasting tx timed out")
+	return rejectedResp, errors.New("broadcasting tx timed out")
+}
+
+// broadcastFailover broadcasts a transaction with each of the given client
+// contexts in order, until one of them reaches its node. A transaction
+// rejected by the node with a non-zero code is not broadcast again, since the
+// other nodes would reject it too. It returns the response and error of the
+// last attempt if no node could be reached.
+func broadcastFailover(
+	logger zerolog.Logger,
+	clientCtxs []client.Context,
+	broadcast func(client.Context) (*sdk.TxResponse, error),
+) (*sdk.TxResponse, error) {
+	var (
+		resp *sdk.TxResponse
+		err  error
+	)
+	for _, clientCtx := range clientCtxs {
+		resp, err = broadcast(clientCtx)
+		if err == nil {
+			if resp != nil && resp.Code != 0 {
+				telemetry.IncrCounter(1, "failure", "tx", "code")
+				return resp, fmt.Errorf("invalid response code from tx: %d", resp.Code)
+			}
+			return resp, nil
+		}
+
+		logger.Debug().
+			Err(err).
+			Str("tmrpc_endpoint", clientCtx.NodeURI).
+			Msg("failed to broadcast tx to endpoint")
+	}
+
+	return resp, err
 }
 
 // CreateClientContext creates an SDK client Context instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateClientContext() (client.Context, error) {
-	var keyringInput io.Reader
-	if len(oc.KeyringPass) > 0 {
-		keyringInput = newPassReader(oc.KeyringPass)
-	} else {
-		keyringInput = os.Stdin
-	}
+	return oc.createClientContext(oc.TMRPC)
+}
 
-	kr, err := keyring.New("oracle", oc.KeyringBackend, oc.KeyringDir, keyringInput, oc.Encoding.Codec)
+// createClientContext creates an SDK client Context instance connected to the
+// given Tendermint RPC endpoint.
+func (oc OracleClient) createClientContext(tmRPCEndpoint string) (client.Context, error) {
+	kr, err := oc.newKeyring()
 	if err != nil {
 		return client.Context{}, err
 	}
 
-	httpClient, err := tmjsonclient.DefaultHTTPClient(oc.TMRPC)
+	httpClient, err := tmjsonclient.DefaultHTTPClient(tmRPCEndpoint)
 	if err != nil {
 		return client.Context{}, err
 	}
 
 	httpClient.Timeout = oc.RPCTimeout
 
-	tmRPC, err := rpchttp.NewWithClient(oc.TMRPC, "/websocket", httpClient)
+	tmRPC, err := rpchttp.NewWithClient(tmRPCEndpoint, "/websocket", httpClient)
 	if err != nil {
 		return client.Context{}, err
 	}
 
-	keyInfo, err := kr.KeyByAddress(oc.OracleAddr)
+	keyInfo, err := resolveKey(kr, oc.OracleAddr)
 	if err != nil {
 		return client.Context{}, err
 	}
+	if err := checkKeyAlgo(keyInfo, oc.KeyringAlgo); err != nil {
+		return client.Context{}, err
+	}
+
 	clientCtx := client.Context{
 		ChainID:           oc.ChainID,
 		InterfaceRegistry: oc.Encoding.InterfaceRegistry,
@@ -246,7 +386,7 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 		Codec:             oc.Encoding.Codec,
 		LegacyAmino:       oc.Encoding.Amino,
 		Input:             os.Stdin,
-		NodeURI:           oc.TMRPC,
+		NodeURI:           tmRPCEndpoint,
 		Client:            tmRPC,
 		Keyring:           kr,
 		FromAddress:       oc.OracleAddr,
@@ -258,11 +398,110 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 		GenerateOnly:      false,
 		Offline:           false,
 		SkipConfirm:       true,
+		FeeGranter:        oc.FeeGranter,
 	}
 
 	return clientCtx, nil
 }
 
+// newKeyring opens the keyring holding the oracle's key, reading its password
+// from the configured keyring password or from stdin.
+func (oc OracleClient) newKeyring() (keyring.Keyring, error) {
+	var keyringInput io.Reader
+	if len(oc.KeyringPass) > 0 {
+		keyringInput = newPassReader(oc.KeyringPass)
+	} else {
+		keyringInput = os.Stdin
+	}
+
+	return keyring.New(
+		"oracle",
+		oc.KeyringBackend,
+		oc.KeyringDir,
+		keyringInput,
+		oc.Encoding.Codec,
+		withKeyringAlgo(oc.KeyringAlgo),
+	)
+}
+
+// keyringAlgo returns the supported signing algorithm of the given name,
+// defaulting to secp256k1 when it is empty.
+func keyringAlgo(name string) (keyring.SignatureAlgo, error) {
+	if name == "" {
+		return hd.Secp256k1, nil
+	}
+	algo, err := keyring.NewSigningAlgoFromString(name, supportedKeyringAlgos)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported keyring algo %s: %w", name, err)
+	}
+	return algo, nil
+}
+
+// withKeyringAlgo restricts the algorithms of the keyring's keys to the given
+// signing algorithm.
+func withKeyringAlgo(algo keyring.SignatureAlgo) keyring.Option {
+	return func(options *keyring.Options) {
+		options.SupportedAlgos = keyring.SigningAlgoList{algo}
+		options.SupportedAlgosLedger = keyring.SigningAlgoList{algo}
+	}
+}
+
+// checkKeyAlgo ensures the key of the record uses the given signing
+// algorithm.
+func checkKeyAlgo(record *keyring.Record, algo keyring.SignatureAlgo) error {
+	pubKey, err := record.GetPubKey()
+	if err != nil {
+		return err
+	}
+	if pubKey.Type() != string(algo.Name()) {
+		return fmt.Errorf(
+			"key %s uses the %s algo instead of the configured %s algo",
+			record.Name,
+			pubKey.Type(),
+			algo.Name(),
+		)
+	}
+	return nil
+}
+
+// resolveKey returns the keyring record of the given address, and ensures it
+// is the only record matching the address. The same key can be stored under
+// multiple names, in which case the signer's name cannot be chosen reliably.
+func resolveKey(kr keyring.Keyring, addr sdk.AccAddress) (*keyring.Record, error) {
+	records, err := kr.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*keyring.Record
+	for _, record := range records {
+		recordAddr, err := record.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		if recordAddr.Equals(addr) {
+			matches = append(matches, record)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no key with address %s found in keyring", addr)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, record := range matches {
+			names[i] = record.Name
+		}
+		return nil, fmt.Errorf(
+			"ambiguous keyring: address %s matches multiple keys %v, remove all but one of them",
+			addr,
+			names,
+		)
+	}
+}
+
 // CreateTxFactory creates an SDK Factory instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateTxFactory() (tx.Factory, error) {
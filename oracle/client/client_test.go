@@ -0,0 +1,174 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ojoparams "github.com/ojo-network/ojo/app/params"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// duplicateKeyring lists its keys along with a copy of one of them under a
+// second name, which the keyring itself refuses to store.
+type duplicateKeyring struct {
+	keyring.Keyring
+	duplicate *keyring.Record
+}
+
+func (kr duplicateKeyring) List() ([]*keyring.Record, error) {
+	records, err := kr.Keyring.List()
+	if err != nil {
+		return nil, err
+	}
+	return append(records, kr.duplicate), nil
+}
+
+func TestResolveKey(t *testing.T) {
+	encoding := ojoparams.MakeEncodingConfig()
+	kr := keyring.NewInMemory(encoding.Codec)
+
+	record, _, err := kr.NewMnemonic(
+		"feeder",
+		keyring.English,
+		sdk.FullFundraiserPath,
+		keyring.DefaultBIP39Passphrase,
+		hd.Secp256k1,
+	)
+	require.NoError(t, err)
+	addr, err := record.GetAddress()
+	require.NoError(t, err)
+
+	_, _, err = kr.NewMnemonic(
+		"other",
+		keyring.English,
+		sdk.FullFundraiserPath,
+		keyring.DefaultBIP39Passphrase,
+		hd.Secp256k1,
+	)
+	require.NoError(t, err)
+
+	// the address uniquely matches a key
+	resolved, err := resolveKey(kr, addr)
+	require.NoError(t, err)
+	require.Equal(t, "feeder", resolved.Name)
+
+	// the address matches no key
+	_, err = resolveKey(kr, feederAddr)
+	require.ErrorContains(t, err, "no key with address")
+
+	// the same key is stored under a second name
+	pubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+	duplicate, err := keyring.NewOfflineRecord("feeder-copy", pubKey)
+	require.NoError(t, err)
+
+	_, err = resolveKey(duplicateKeyring{Keyring: kr, duplicate: duplicate}, addr)
+	require.ErrorContains(t, err, "ambiguous keyring")
+	require.ErrorContains(t, err, "feeder-copy")
+}
+
+func TestSnapshotSigner(t *testing.T) {
+	encoding := ojoparams.MakeEncodingConfig()
+	kr := keyring.NewInMemory(encoding.Codec)
+
+	record, _, err := kr.NewMnemonic(
+		"feeder",
+		keyring.English,
+		sdk.FullFundraiserPath,
+		keyring.DefaultBIP39Passphrase,
+		hd.Secp256k1,
+	)
+	require.NoError(t, err)
+	addr, err := record.GetAddress()
+	require.NoError(t, err)
+	feederPubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+
+	signer, err := newSnapshotSigner(kr, addr, hd.Secp256k1)
+	require.NoError(t, err)
+
+	snapshot := []byte(`{"ATOMUSD":"34.840000000000000000"}`)
+	sig, pubKey, err := signer.Sign(snapshot)
+	require.NoError(t, err)
+	require.True(t, pubKey.Equals(feederPubKey))
+	require.True(t, feederPubKey.VerifySignature(snapshot, sig))
+	require.False(t, feederPubKey.VerifySignature([]byte(`{"ATOMUSD":"35.000000000000000000"}`), sig))
+
+	_, err = newSnapshotSigner(kr, feederAddr, hd.Secp256k1)
+	require.ErrorContains(t, err, "no key with address")
+}
+
+func TestKeyringAlgo(t *testing.T) {
+	algo, err := keyringAlgo("")
+	require.NoError(t, err)
+	require.Equal(t, hd.Secp256k1, algo)
+
+	algo, err = keyringAlgo("secp256k1")
+	require.NoError(t, err)
+	require.Equal(t, hd.Secp256k1, algo)
+
+	_, err = keyringAlgo("ed25519")
+	require.ErrorContains(t, err, "unsupported keyring algo")
+
+	// the keyring is restricted to the configured algo
+	encoding := ojoparams.MakeEncodingConfig()
+	kr := keyring.NewInMemory(encoding.Codec, withKeyringAlgo(algo))
+	algos, _ := kr.SupportedAlgorithms()
+	require.Equal(t, keyring.SigningAlgoList{hd.Secp256k1}, algos)
+
+	record, _, err := kr.NewMnemonic(
+		"feeder",
+		keyring.English,
+		sdk.FullFundraiserPath,
+		keyring.DefaultBIP39Passphrase,
+		hd.Secp256k1,
+	)
+	require.NoError(t, err)
+	require.NoError(t, checkKeyAlgo(record, algo))
+
+	// a key of another algo is rejected
+	record, err = kr.SaveOfflineKey("offline", ed25519.GenPrivKey().PubKey())
+	require.NoError(t, err)
+	require.ErrorContains(t, checkKeyAlgo(record, algo), "uses the ed25519 algo")
+}
+
+func TestBroadcastFailover(t *testing.T) {
+	clientCtxs := []client.Context{
+		{NodeURI: "http://primary:26657"},
+		{NodeURI: "http://fallback:26657"},
+	}
+
+	var attempted []string
+	resp, err := broadcastFailover(zerolog.Nop(), clientCtxs, func(clientCtx client.Context) (*sdk.TxResponse, error) {
+		attempted = append(attempted, clientCtx.NodeURI)
+		if clientCtx.NodeURI == "http://primary:26657" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return &sdk.TxResponse{TxHash: "hash"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hash", resp.TxHash)
+	require.Equal(t, []string{"http://primary:26657", "http://fallback:26657"}, attempted)
+
+	// a tx rejected with a non-zero code is not broadcast to the fallback
+	attempted = nil
+	resp, err = broadcastFailover(zerolog.Nop(), clientCtxs, func(clientCtx client.Context) (*sdk.TxResponse, error) {
+		attempted = append(attempted, clientCtx.NodeURI)
+		return &sdk.TxResponse{Code: 5}, nil
+	})
+	require.ErrorContains(t, err, "invalid response code from tx: 5")
+	require.Equal(t, uint32(5), resp.Code)
+	require.Equal(t, []string{"http://primary:26657"}, attempted)
+
+	// the last failure is returned once no endpoint could be reached
+	_, err = broadcastFailover(zerolog.Nop(), clientCtxs, func(clientCtx client.Context) (*sdk.TxResponse, error) {
+		return nil, fmt.Errorf("connection refused by %s", clientCtx.NodeURI)
+	})
+	require.ErrorContains(t, err, "connection refused by http://fallback:26657")
+}
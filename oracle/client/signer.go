@@ -0,0 +1,47 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// SnapshotSigner signs the price snapshots published by the price feeder with
+// the oracle's key, so their consumers can verify they come from this feeder.
+type SnapshotSigner struct {
+	keyring keyring.Keyring
+	uid     string
+}
+
+// NewSnapshotSigner returns a SnapshotSigner signing with the oracle's key of
+// the keyring.
+func (oc OracleClient) NewSnapshotSigner() (*SnapshotSigner, error) {
+	kr, err := oc.newKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotSigner(kr, oc.OracleAddr, oc.KeyringAlgo)
+}
+
+func newSnapshotSigner(kr keyring.Keyring, addr sdk.AccAddress, algo keyring.SignatureAlgo) (*SnapshotSigner, error) {
+	keyInfo, err := resolveKey(kr, addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeyAlgo(keyInfo, algo); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotSigner{
+		keyring: kr,
+		uid:     keyInfo.Name,
+	}, nil
+}
+
+// Sign signs the given bytes with the oracle's key, returning the signature
+// along with the public key to verify it with.
+func (s *SnapshotSigner) Sign(bz []byte) ([]byte, cryptotypes.PubKey, error) {
+	return s.keyring.Sign(s.uid, bz, signing.SignMode_SIGN_MODE_DIRECT)
+}
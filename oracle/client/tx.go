@@ -28,14 +28,11 @@ func BroadcastTx(clientCtx client.Context, txf tx.Factory, msgs ...sdk.Msg) (*sd
 		txf = txf.WithGas(adjusted)
 	}
 
-	unsignedTx, err := txf.BuildUnsignedTx(msgs...)
+	unsignedTx, err := buildUnsignedTx(clientCtx, txf, msgs...)
 	if err != nil {
 		return nil, err
 	}
 
-	unsignedTx.SetFeeGranter(clientCtx.GetFeeGranterAddress())
-	// unsignedTx.SetFeePayer(clientCtx.GetFeePayerAddress())
-
 	if err = tx.Sign(clientCtx.CmdContext, txf, clientCtx.GetFromName(), unsignedTx, true); err != nil {
 		return nil, err
 	}
@@ -48,6 +45,21 @@ func BroadcastTx(clientCtx client.Context, txf tx.Factory, msgs ...sdk.Msg) (*sd
 	return clientCtx.BroadcastTx(txBytes)
 }
 
+// buildUnsignedTx builds an unsigned transaction with the given set of messages
+// and sets the fee granter of the client context, so fees are deducted from the
+// granter's allowance instead of the signer's balance.
+func buildUnsignedTx(clientCtx client.Context, txf tx.Factory, msgs ...sdk.Msg) (client.TxBuilder, error) {
+	unsignedTx, err := txf.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	unsignedTx.SetFeeGranter(clientCtx.GetFeeGranterAddress())
+	// unsignedTx.SetFeePayer(clientCtx.GetFeePayerAddress())
+
+	return unsignedTx, nil
+}
+
 // prepareFactory ensures the account defined by ctx.GetFromAddress() exists and
 // if the account number and/or the account sequence number are zero (not set),
 // they will be queried for and set on the provided Factory. A new Factory with
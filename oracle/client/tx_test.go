@@ -0,0 +1,74 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	ojoparams "github.com/ojo-network/ojo/app/params"
+	oracletypes "github.com/ojo-network/ojo/x/oracle/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	feederAddr  = sdk.AccAddress([]byte("feeder______________"))
+	granterAddr = sdk.AccAddress([]byte("granter_____________"))
+)
+
+func TestBuildUnsignedTxFeeGranter(t *testing.T) {
+	encoding := ojoparams.MakeEncodingConfig()
+	oc := OracleClient{
+		OracleAddr:       feederAddr,
+		OracleAddrString: feederAddr.String(),
+		FeeGranter:       granterAddr,
+	}
+	clientCtx := client.Context{
+		TxConfig:   encoding.TxConfig,
+		FeeGranter: oc.FeeGranter,
+	}
+	txf := tx.Factory{}.
+		WithChainID("ojo-testnet").
+		WithTxConfig(encoding.TxConfig).
+		WithGas(100000)
+
+	msg := &oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      "hash",
+		Feeder:    oc.FeederAddrString(),
+		Validator: "validator",
+	}
+
+	t.Run("with fee granter", func(t *testing.T) {
+		builder, err := buildUnsignedTx(clientCtx, txf, oc.wrapMsgs(msg)...)
+		require.NoError(t, err)
+		require.Equal(t, []byte(granterAddr), builder.GetTx().FeeGranter())
+	})
+
+	t.Run("without fee granter", func(t *testing.T) {
+		builder, err := buildUnsignedTx(client.Context{TxConfig: encoding.TxConfig}, txf, oc.wrapMsgs(msg)...)
+		require.NoError(t, err)
+		require.Empty(t, builder.GetTx().FeeGranter())
+	})
+}
+
+func TestWrapMsgsAuthzGranter(t *testing.T) {
+	msg := &oracletypes.MsgAggregateExchangeRatePrevote{Hash: "hash"}
+
+	oc := OracleClient{
+		OracleAddr:       feederAddr,
+		OracleAddrString: feederAddr.String(),
+	}
+	require.Equal(t, feederAddr.String(), oc.FeederAddrString())
+	require.Equal(t, []sdk.Msg{msg}, oc.wrapMsgs(msg))
+
+	oc.AuthzGranter = granterAddr
+	require.Equal(t, granterAddr.String(), oc.FeederAddrString())
+
+	msgs := oc.wrapMsgs(msg)
+	require.Len(t, msgs, 1)
+	msgExec, ok := msgs[0].(*authz.MsgExec)
+	require.True(t, ok)
+	require.Equal(t, feederAddr.String(), msgExec.Grantee)
+	require.Len(t, msgExec.Msgs, 1)
+}
@@ -1,6 +1,8 @@
 package oracle
 
 import (
+	"time"
+
 	"cosmossdk.io/math"
 	"github.com/rs/zerolog"
 
@@ -11,10 +13,22 @@ import (
 // ConvertRatesToUSD converts the rates to USD and updates the currency pair
 // with a USD quote. If no conversion exists the rate is omitted in the return.
 func ConvertRatesToUSD(rates types.CurrencyPairDec) types.CurrencyPairDec {
+	convertedRates, _ := ConvertRatesToUSDWithRoutes(rates)
+	return convertedRates
+}
+
+// ConvertRatesToUSDWithRoutes converts the rates to USD the same way as
+// ConvertRatesToUSD, and additionally returns the conversion route used for
+// each converted rate.
+func ConvertRatesToUSDWithRoutes(
+	rates types.CurrencyPairDec,
+) (types.CurrencyPairDec, types.CurrencyPairConversionRoutes) {
 	convertedRates := make(types.CurrencyPairDec)
+	routes := make(types.CurrencyPairConversionRoutes)
 	for cp, rate := range rates {
 		if cp.Quote == config.DenomUSD {
 			convertedRates[cp] = rate
+			routes[cp] = types.ConversionRoute{{Pair: cp, Rate: rate}}
 			continue
 		}
 
@@ -23,6 +37,10 @@ func ConvertRatesToUSD(rates types.CurrencyPairDec) types.CurrencyPairDec {
 			if cpConvert.Quote == config.DenomUSD && cpConvert.Base == cp.Quote {
 				convertedPair := types.CurrencyPair{Base: cp.Base, Quote: config.DenomUSD}
 				convertedRates[convertedPair] = rate.Mul(rateConvert)
+				routes[convertedPair] = types.ConversionRoute{
+					{Pair: cp, Rate: rate},
+					{Pair: cpConvert, Rate: rateConvert},
+				}
 				converted = true
 			}
 		}
@@ -32,37 +50,164 @@ func ConvertRatesToUSD(rates types.CurrencyPairDec) types.CurrencyPairDec {
 			for cpConvert, rateConvert := range rates {
 				if cpConvert.Base == cp.Quote {
 					var quoteRate math.LegacyDec
+					var quotePair types.CurrencyPair
 					var foundQuoteRate bool
 					for cpConvert2, rateConvert2 := range rates {
 						if cpConvert2.Quote == config.DenomUSD && cpConvert2.Base == cpConvert.Quote {
 							quoteRate = rateConvert2
+							quotePair = cpConvert2
 							foundQuoteRate = true
 						}
 					}
 					if foundQuoteRate {
 						convertedPair := types.CurrencyPair{Base: cp.Base, Quote: config.DenomUSD}
 						convertedRates[convertedPair] = rate.Mul(rateConvert).Mul(quoteRate)
+						routes[convertedPair] = types.ConversionRoute{
+							{Pair: cp, Rate: rate},
+							{Pair: cpConvert, Rate: rateConvert},
+							{Pair: quotePair, Rate: quoteRate},
+						}
 					}
 				}
 			}
 		}
 	}
 
-	return convertedRates
+	return convertedRates, routes
+}
+
+// OverrideConversionRates returns a copy of the rates where every rate of a
+// currency with an override is replaced by its fixed USD rate, so that any
+// conversion to USD through that currency uses the overridden rate.
+func OverrideConversionRates(
+	rates types.CurrencyPairDec,
+	overrides map[string]math.LegacyDec,
+) types.CurrencyPairDec {
+	overriddenRates := make(types.CurrencyPairDec, len(rates)+len(overrides))
+	for cp, rate := range rates {
+		if _, ok := overrides[cp.Base]; ok {
+			continue
+		}
+		overriddenRates[cp] = rate
+	}
+	for base, rate := range overrides {
+		overriddenRates[types.CurrencyPair{Base: base, Quote: config.DenomUSD}] = rate
+	}
+
+	return overriddenRates
 }
 
-// CalcCurrencyPairRates filters the candles and tickers to the currency pair
-// list provided, then filters candles/tickers outside of the deviation threshold,
-// and finally computes the rates for the given currency pairs using TVWAP for candles
-// and VWAP for tickers. It will first compute rates with candles and then attempt
-// to fill in any missing prices with ticker data.
+// FillRawConversionRates returns the conversion rates completed with the
+// unfiltered VWAP of the tickers of the conversion pairs whose rate was
+// filtered out, so the pairs quoted in them are still converted. The rates
+// which were computed are kept as is.
+func FillRawConversionRates(
+	rates types.CurrencyPairDec,
+	tickers types.AggregatedProviderPrices,
+	conversionPairs []types.CurrencyPair,
+	tickerVolumePolicy types.TickerVolumePolicy,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+) types.CurrencyPairDec {
+	missingPairs := make(map[types.CurrencyPair]struct{})
+	for _, cp := range conversionPairs {
+		if _, ok := rates[cp]; !ok {
+			missingPairs[cp] = struct{}{}
+		}
+	}
+	if len(missingPairs) == 0 {
+		return rates
+	}
+
+	missingTickers := make(types.AggregatedProviderPrices)
+	for providerName, cpTickers := range tickers {
+		for cp, ticker := range cpTickers {
+			if _, ok := missingPairs[cp]; !ok {
+				continue
+			}
+			if _, ok := missingTickers[providerName]; !ok {
+				missingTickers[providerName] = make(types.CurrencyPairTickers)
+			}
+			missingTickers[providerName][cp] = ticker
+		}
+	}
+
+	filledRates := make(types.CurrencyPairDec, len(rates)+len(missingPairs))
+	for cp, rate := range rates {
+		filledRates[cp] = rate
+	}
+	for cp, rate := range ComputeVWAP(missingTickers, tickerVolumePolicy, providerWeights) {
+		filledRates[cp] = rate
+	}
+
+	return filledRates
+}
+
+// ConversionRoutes returns the conversion route applied to each of the
+// non-USD quoted currency pairs of the given candles and tickers, based on the
+// routes of the USD rates used to convert them.
+func ConversionRoutes(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	rateRoutes types.CurrencyPairConversionRoutes,
+) types.CurrencyPairConversionRoutes {
+	routes := make(types.CurrencyPairConversionRoutes)
+	addRoute := func(cp types.CurrencyPair) {
+		if cp.Quote == config.DenomUSD {
+			return
+		}
+		quotePair := types.CurrencyPair{Base: cp.Quote, Quote: config.DenomUSD}
+		if route, ok := rateRoutes[quotePair]; ok {
+			routes[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}] = route
+		}
+	}
+
+	for _, cpCandles := range candles {
+		for cp := range cpCandles {
+			addRoute(cp)
+		}
+	}
+	for _, cpTickers := range tickers {
+		for cp := range cpTickers {
+			addRoute(cp)
+		}
+	}
+
+	return routes
+}
+
+// CalcCurrencyPairRates computes the rates of the given currency pairs from
+// the candles and tickers of the providers, along with the number of distinct
+// providers which contributed to each rate.
+//
+// The providers with a zero weight and the candles dated in the future are
+// dropped first. The candles and tickers deviating from the other providers
+// are then filtered out, with the deviation threshold of each provider scaled
+// by its deviation multiplier, except for the unfiltered pairs. The rates are
+// computed with the TVWAP of the candles, and the pairs without candles are
+// filled in with the VWAP of the tickers, or with the medians of the
+// providers' prices when the aggregation method is the median. The candle and
+// ticker volumes of each provider are scaled by its weight. Candles are
+// weighted over a period of at least tvwapMinPeriod, within the TVWAP lookback
+// of their base.
 func CalcCurrencyPairRates(
 	candles types.AggregatedProviderCandles,
 	tickers types.AggregatedProviderPrices,
 	deviationThresholds map[string]math.LegacyDec,
+	providerDeviationMultipliers map[types.ProviderName]math.LegacyDec,
+	unfilteredPairs map[types.CurrencyPair]struct{},
 	currencyPairs []types.CurrencyPair,
+	tickerVolumePolicy types.TickerVolumePolicy,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+	aggregationMethod types.AggregationMethod,
+	tvwapLookbacks map[string]time.Duration,
+	tvwapMinPeriod time.Duration,
+	record *filterRecord,
 	logger zerolog.Logger,
-) (types.CurrencyPairDec, error) {
+) (types.CurrencyPairDec, map[types.CurrencyPair]int, error) {
+	weightedCandles, weightedTickers := FilterZeroWeightProviders(candles, tickers, providerWeights)
+	record.recordFiltered(filterReasonZeroWeight, candles, weightedCandles, tickers, weightedTickers)
+	candles, tickers = weightedCandles, weightedTickers
+
 	candlesFilteredByCP := make(types.AggregatedProviderCandles)
 	for _, ratePair := range currencyPairs {
 		for provider, cpCandles := range candles {
@@ -77,18 +222,62 @@ func CalcCurrencyPairRates(
 		}
 	}
 
+	currentCandles := FilterFutureCandles(logger, candlesFilteredByCP)
+	record.recordFiltered(filterReasonFuture, candlesFilteredByCP, currentCandles, nil, nil)
+
+	// the candles without a TVWAP are dropped by the deviation filter too, but
+	// for having no candle within the TVWAP period
+	if record != nil {
+		currentTvwaps, err := ComputeTvwapsByProvider(currentCandles, tvwapLookbacks, tvwapMinPeriod)
+		if err != nil {
+			return nil, nil, err
+		}
+		for providerName, cpCandles := range currentCandles {
+			for cp := range cpCandles {
+				if _, ok := currentTvwaps[providerName][cp]; !ok {
+					record.recordCandle(filterReasonNoRecentCandles, providerName, cp)
+				}
+			}
+		}
+	}
+
 	candlesFilteredByDeviation, err := FilterCandleDeviations(
 		logger,
-		candlesFilteredByCP,
+		currentCandles,
 		deviationThresholds,
+		providerDeviationMultipliers,
+		unfilteredPairs,
+		tvwapLookbacks,
+		tvwapMinPeriod,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	record.recordFiltered(filterReasonDeviation, currentCandles, candlesFilteredByDeviation, nil, nil)
 
-	conversionRates, err := ComputeTVWAP(candlesFilteredByDeviation)
+	var conversionRates types.CurrencyPairDec
+	if aggregationMethod == types.AggregationMethodMedian {
+		conversionRates, err = ComputeMedianTVWAP(candlesFilteredByDeviation, tvwapLookbacks, tvwapMinPeriod)
+	} else {
+		conversionRates, err = ComputeTVWAP(
+			weightCandleVolumes(candlesFilteredByDeviation, providerWeights),
+			tvwapLookbacks,
+			tvwapMinPeriod,
+		)
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	tvwapsByProvider, err := ComputeTvwapsByProvider(candlesFilteredByDeviation, tvwapLookbacks, tvwapMinPeriod)
+	if err != nil {
+		return nil, nil, err
+	}
+	providerCounts := make(map[types.CurrencyPair]int)
+	for _, tvwaps := range tvwapsByProvider {
+		for cp := range tvwaps {
+			providerCounts[cp]++
+		}
 	}
 
 	// Select tickers that match the currencyPairs and also do
@@ -96,6 +285,11 @@ func CalcCurrencyPairRates(
 	tickersFilteredByCP := make(types.AggregatedProviderPrices)
 	for _, ratePair := range currencyPairs {
 		if _, ok := conversionRates[ratePair]; ok {
+			for providerName, cpTickers := range tickers {
+				if _, ok := cpTickers[ratePair]; ok {
+					record.recordTicker(filterReasonTVWAPAvailable, providerName, ratePair)
+				}
+			}
 			continue
 		}
 		for provider, cpTickers := range tickers {
@@ -114,17 +308,31 @@ func CalcCurrencyPairRates(
 		logger,
 		tickersFilteredByCP,
 		deviationThresholds,
+		providerDeviationMultipliers,
+		unfilteredPairs,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	record.recordFiltered(filterReasonDeviation, nil, nil, tickersFilteredByCP, tickersFilteredByDeviation)
 
-	vwap := ComputeVWAP(tickersFilteredByDeviation)
+	var vwap types.CurrencyPairDec
+	if aggregationMethod == types.AggregationMethodMedian {
+		vwap = ComputeMedian(tickersFilteredByDeviation)
+	} else {
+		vwap = ComputeVWAP(tickersFilteredByDeviation, tickerVolumePolicy, providerWeights)
+	}
+	record.recordRates(conversionRates, vwap)
 	for cp, rate := range vwap {
 		conversionRates[cp] = rate
 	}
+	for _, vwaps := range ComputeVwapsByProvider(tickersFilteredByDeviation, tickerVolumePolicy) {
+		for cp := range vwaps {
+			providerCounts[cp]++
+		}
+	}
 
-	return conversionRates, nil
+	return conversionRates, providerCounts, nil
 }
 
 // ConvertAggregatedCandles converts the candles to USD and updates the currency pair
@@ -148,3 +148,25 @@ func TestConvertAggregatedTickers(t *testing.T) {
 
 	assert.Equal(t, expectedResult, result, "The converted tickers do not match the expected result.")
 }
+
+func TestOverrideConversionRates(t *testing.T) {
+	rates := types.CurrencyPairDec{
+		types.CurrencyPair{Base: "USDT", Quote: "USD"}:  math.LegacyMustNewDecFromStr("0.5"),
+		types.CurrencyPair{Base: "USDT", Quote: "USDC"}: math.LegacyMustNewDecFromStr("0.5"),
+		types.CurrencyPair{Base: "ATOM", Quote: "USDT"}: math.LegacyNewDec(10),
+	}
+	overrides := map[string]math.LegacyDec{
+		"USDT": math.LegacyOneDec(),
+	}
+
+	overriddenRates := oracle.OverrideConversionRates(rates, overrides)
+	assert.Len(t, overriddenRates, 2)
+	assert.Equal(t, math.LegacyOneDec(), overriddenRates[types.CurrencyPair{Base: "USDT", Quote: "USD"}])
+
+	convertedRates := oracle.ConvertRatesToUSD(overriddenRates)
+	assert.Equal(t, math.LegacyOneDec(), convertedRates[types.CurrencyPair{Base: "USDT", Quote: "USD"}])
+	assert.Equal(t, math.LegacyNewDec(10), convertedRates[types.CurrencyPair{Base: "ATOM", Quote: "USD"}])
+
+	// the original rates are left untouched
+	assert.Equal(t, math.LegacyMustNewDecFromStr("0.5"), rates[types.CurrencyPair{Base: "USDT", Quote: "USD"}])
+}
@@ -0,0 +1,331 @@
+package oracle
+
+import (
+	"sort"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	filterReasonZeroWeight       = "provider weight of zero"
+	filterReasonStale            = "latest candle older than the max price age"
+	filterReasonFuture           = "candles dated in the future"
+	filterReasonPriceBand        = "outside of the price band"
+	filterReasonTrustedProvider  = "deviating from the trusted provider"
+	filterReasonNoConversionRate = "no conversion rate to USD"
+	filterReasonDeviation        = "deviating from other providers"
+	filterReasonNoRecentCandles  = "no candles within the TVWAP period"
+	filterReasonTVWAPAvailable   = "TVWAP computed from candles is used instead"
+)
+
+// filterRecord records why the tickers and candles of each provider were
+// filtered out during a price computation, along with the rates computed
+// from the candles and from the tickers, so the actual decisions can be
+// served afterwards. Only the first reason a ticker or candle of a currency
+// pair was filtered out for is kept. A nil filterRecord records nothing.
+type filterRecord struct {
+	tickerReasons map[types.ProviderName]map[types.CurrencyPair]string
+	candleReasons map[types.ProviderName]map[types.CurrencyPair]string
+	candleRates   types.CurrencyPairDec
+	tickerRates   types.CurrencyPairDec
+}
+
+func newFilterRecord() *filterRecord {
+	return &filterRecord{
+		tickerReasons: make(map[types.ProviderName]map[types.CurrencyPair]string),
+		candleReasons: make(map[types.ProviderName]map[types.CurrencyPair]string),
+		candleRates:   make(types.CurrencyPairDec),
+		tickerRates:   make(types.CurrencyPairDec),
+	}
+}
+
+// recordFiltered records the reason for the candles and tickers which are in
+// the inputs of a filter but not in its outputs.
+func (fr *filterRecord) recordFiltered(
+	reason string,
+	candles, filteredCandles types.AggregatedProviderCandles,
+	tickers, filteredTickers types.AggregatedProviderPrices,
+) {
+	if fr == nil {
+		return
+	}
+
+	for providerName, cpCandles := range candles {
+		for cp := range cpCandles {
+			if _, ok := filteredCandles[providerName][cp]; !ok {
+				fr.recordCandle(reason, providerName, cp)
+			}
+		}
+	}
+	for providerName, cpTickers := range tickers {
+		for cp := range cpTickers {
+			if _, ok := filteredTickers[providerName][cp]; !ok {
+				fr.recordTicker(reason, providerName, cp)
+			}
+		}
+	}
+}
+
+// recordTicker records the reason the ticker of a provider was filtered out,
+// unless it was already filtered out.
+func (fr *filterRecord) recordTicker(reason string, providerName types.ProviderName, cp types.CurrencyPair) {
+	if fr == nil {
+		return
+	}
+	recordReason(fr.tickerReasons, reason, providerName, cp)
+}
+
+// recordCandle records the reason the candles of a provider were filtered
+// out, unless they were already filtered out.
+func (fr *filterRecord) recordCandle(reason string, providerName types.ProviderName, cp types.CurrencyPair) {
+	if fr == nil {
+		return
+	}
+	recordReason(fr.candleReasons, reason, providerName, cp)
+}
+
+func recordReason(
+	reasons map[types.ProviderName]map[types.CurrencyPair]string,
+	reason string,
+	providerName types.ProviderName,
+	cp types.CurrencyPair,
+) {
+	if _, ok := reasons[providerName]; !ok {
+		reasons[providerName] = make(map[types.CurrencyPair]string)
+	}
+	if _, ok := reasons[providerName][cp]; !ok {
+		reasons[providerName][cp] = reason
+	}
+}
+
+// recordRates records the rates computed from the candles and from the
+// tickers, replacing the rates previously computed for the same pairs.
+func (fr *filterRecord) recordRates(candleRates, tickerRates types.CurrencyPairDec) {
+	if fr == nil {
+		return
+	}
+
+	for cp, rate := range candleRates {
+		fr.candleRates[cp] = rate
+		delete(fr.tickerRates, cp)
+	}
+	for cp, rate := range tickerRates {
+		fr.tickerRates[cp] = rate
+		delete(fr.candleRates, cp)
+	}
+}
+
+// tickerReason returns the reason the ticker of a provider was filtered out,
+// if any.
+func (fr *filterRecord) tickerReason(providerName types.ProviderName, cp types.CurrencyPair) string {
+	if fr == nil {
+		return ""
+	}
+	return fr.tickerReasons[providerName][cp]
+}
+
+// candleReason returns the reason the candles of a provider were filtered
+// out, if any.
+func (fr *filterRecord) candleReason(providerName types.ProviderName, cp types.CurrencyPair) string {
+	if fr == nil {
+		return ""
+	}
+	return fr.candleReasons[providerName][cp]
+}
+
+// rates returns the rate of the pair computed from the candles and from the
+// tickers, if any.
+func (fr *filterRecord) rates(cp types.CurrencyPair) (candleRate, tickerRate *sdkmath.LegacyDec) {
+	if fr == nil {
+		return nil, nil
+	}
+	if rate, ok := fr.candleRates[cp]; ok {
+		candleRate = &rate
+	}
+	if rate, ok := fr.tickerRates[cp]; ok {
+		tickerRate = &rate
+	}
+	return candleRate, tickerRate
+}
+
+// GetPriceDebug returns the inputs and intermediate results of the last price
+// computation for the given base currency: every provider's raw ticker and
+// latest candle, their values converted to USD, which of them were filtered
+// out and why, the computed TVWAP and VWAP, and the final price. The filter
+// decisions are the ones recorded during the price computation.
+func (o *Oracle) GetPriceDebug(base string) (types.PriceDebug, error) {
+	o.pricesMutex.RLock()
+	providerCandles := o.aggregatedCandles
+	providerPrices := o.aggregatedTickers
+	usdRates := o.usdRates
+	computedPrices := o.computedPrices
+	record := o.filterRecord
+	o.pricesMutex.RUnlock()
+
+	usdPair := types.CurrencyPair{Base: base, Quote: config.DenomUSD}
+	priceDebug := types.PriceDebug{
+		Base:      base,
+		Providers: []types.ProviderPriceDebug{},
+	}
+	priceDebug.TVWAP, priceDebug.VWAP = record.rates(usdPair)
+	if price, ok := computedPrices[usdPair]; ok {
+		priceDebug.Price = &price
+	}
+
+	entries := make(map[types.ProviderName]map[types.CurrencyPair]*types.ProviderPriceDebug)
+	entry := func(providerName types.ProviderName, cp types.CurrencyPair) *types.ProviderPriceDebug {
+		if _, ok := entries[providerName]; !ok {
+			entries[providerName] = make(map[types.CurrencyPair]*types.ProviderPriceDebug)
+		}
+		if _, ok := entries[providerName][cp]; !ok {
+			entries[providerName][cp] = &types.ProviderPriceDebug{Provider: providerName, Pair: cp}
+		}
+		return entries[providerName][cp]
+	}
+
+	// the prices filtered out before being converted are recorded under their
+	// own pair, and the ones filtered out afterwards under the USD pair
+	for providerName, cpCandles := range providerCandles {
+		for cp, candles := range cpCandles {
+			if cp.Base != base {
+				continue
+			}
+			candle, ok := types.LatestCandle(candles)
+			if !ok {
+				continue
+			}
+
+			e := entry(providerName, cp)
+			e.Candle = &candle
+
+			rate, ok := usdRate(usdRates, cp.Quote)
+			if ok {
+				convertedCandle := candle
+				convertedCandle.Price = candle.Price.Mul(rate)
+				e.ConvertedCandle = &convertedCandle
+			}
+
+			e.CandleFilterReason = record.candleReason(providerName, cp)
+			switch {
+			case e.CandleFilterReason != "":
+			case !ok:
+				e.CandleFilterReason = filterReasonNoConversionRate
+			default:
+				e.CandleFilterReason = record.candleReason(providerName, usdPair)
+			}
+		}
+	}
+
+	for providerName, cpTickers := range providerPrices {
+		for cp, ticker := range cpTickers {
+			if cp.Base != base {
+				continue
+			}
+			ticker := ticker
+
+			e := entry(providerName, cp)
+			e.Ticker = &ticker
+
+			rate, ok := usdRate(usdRates, cp.Quote)
+			if ok {
+				convertedTicker := convertTicker(ticker, rate)
+				e.ConvertedTicker = &convertedTicker
+			}
+
+			e.TickerFilterReason = record.tickerReason(providerName, cp)
+			switch {
+			case e.TickerFilterReason != "":
+			case !ok:
+				e.TickerFilterReason = filterReasonNoConversionRate
+			default:
+				e.TickerFilterReason = record.tickerReason(providerName, usdPair)
+			}
+		}
+	}
+
+	for _, cpEntries := range entries {
+		for _, e := range cpEntries {
+			priceDebug.Providers = append(priceDebug.Providers, *e)
+		}
+	}
+	sort.Slice(priceDebug.Providers, func(i, j int) bool {
+		if priceDebug.Providers[i].Provider != priceDebug.Providers[j].Provider {
+			return priceDebug.Providers[i].Provider < priceDebug.Providers[j].Provider
+		}
+		return priceDebug.Providers[i].Pair.String() < priceDebug.Providers[j].Pair.String()
+	})
+
+	return priceDebug, nil
+}
+
+// GetDeviatingProviders returns the providers, sorted by name, whose ticker or
+// candle of the currency pair was filtered out of the last price computation
+// for deviating from the other providers. The prices quoted in other
+// currencies than USD are filtered once converted to USD, so they are checked
+// under their USD pair, and the providers of a USD pair include the ones
+// whose converted prices deviated.
+func (o *Oracle) GetDeviatingProviders(cp types.CurrencyPair) ([]types.ProviderName, error) {
+	o.pricesMutex.RLock()
+	providerCandles := o.aggregatedCandles
+	providerPrices := o.aggregatedTickers
+	record := o.filterRecord
+	o.pricesMutex.RUnlock()
+
+	usdPair := types.CurrencyPair{Base: cp.Base, Quote: config.DenomUSD}
+	isDeviating := func(
+		reason func(types.ProviderName, types.CurrencyPair) string,
+		providerName types.ProviderName,
+		providerPair types.CurrencyPair,
+	) bool {
+		if providerPair != cp && (cp != usdPair || providerPair.Base != cp.Base) {
+			return false
+		}
+		if providerReason := reason(providerName, providerPair); providerReason != "" {
+			return providerReason == filterReasonDeviation
+		}
+		return reason(providerName, usdPair) == filterReasonDeviation
+	}
+
+	deviating := make(map[types.ProviderName]struct{})
+	for providerName, cpCandles := range providerCandles {
+		for providerPair := range cpCandles {
+			if isDeviating(record.candleReason, providerName, providerPair) {
+				deviating[providerName] = struct{}{}
+			}
+		}
+	}
+	for providerName, cpTickers := range providerPrices {
+		for providerPair := range cpTickers {
+			if isDeviating(record.tickerReason, providerName, providerPair) {
+				deviating[providerName] = struct{}{}
+			}
+		}
+	}
+
+	deviatingProviders := []types.ProviderName{}
+	for providerName := range deviating {
+		deviatingProviders = append(deviatingProviders, providerName)
+	}
+	sort.Slice(deviatingProviders, func(i, j int) bool {
+		return deviatingProviders[i] < deviatingProviders[j]
+	})
+	return deviatingProviders, nil
+}
+
+// usdRate returns the rate used to convert a price quoted in the given
+// currency to USD.
+func usdRate(rates types.CurrencyPairDec, quote string) (sdkmath.LegacyDec, bool) {
+	if quote == config.DenomUSD {
+		return sdkmath.LegacyOneDec(), true
+	}
+
+	for cp, rate := range rates {
+		if cp.Base == quote {
+			return rate, true
+		}
+	}
+	return sdkmath.LegacyDec{}, false
+}
@@ -1,9 +1,12 @@
 package oracle
 
 import (
+	"time"
+
 	"cosmossdk.io/math"
 	"github.com/rs/zerolog"
 
+	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
@@ -13,12 +16,21 @@ import (
 // in the config.
 var defaultDeviationThreshold = math.LegacyMustNewDecFromStr("1.0")
 
+// futureCandleTolerance defines how far in the future a candle can be dated
+// without being filtered out. It spans the one minute period of most
+// providers' candles, as the candle in progress is dated at its close.
+const futureCandleTolerance = time.Minute
+
 // FilterTickerDeviations finds the standard deviations of the prices of
 // all assets, and filters out any providers that are not within 2𝜎 of the mean.
+// The threshold of each provider is scaled by its deviation multiplier.
+// The prices of the unfiltered pairs are all kept.
 func FilterTickerDeviations(
 	logger zerolog.Logger,
 	prices types.AggregatedProviderPrices,
 	deviationThresholds map[string]math.LegacyDec,
+	providerDeviationMultipliers map[types.ProviderName]math.LegacyDec,
+	unfilteredPairs map[types.CurrencyPair]struct{},
 ) (types.AggregatedProviderPrices, error) {
 	var (
 		filteredPrices = make(types.AggregatedProviderPrices)
@@ -38,20 +50,18 @@ func FilterTickerDeviations(
 
 	deviations, means, err := StandardDeviation(priceMap)
 	if err != nil {
-		return nil, err
+		logger.Warn().Err(err).Msg("skipping ticker deviation filtering of assets")
 	}
 
-	// We accept any prices that are within (2 * T)𝜎, or for which we couldn't get 𝜎.
+	// We accept any prices that are within (2 * T * M)𝜎, or for which we couldn't get 𝜎.
 	// T is defined as the deviation threshold, either set by the config
-	// or defaulted to 1.
+	// or defaulted to 1, and M as the deviation multiplier of the provider,
+	// defaulted to 1.
 	for providerName, priceTickers := range prices {
+		multiplier := deviationMultiplier(providerName, providerDeviationMultipliers)
 		for cp, tp := range priceTickers {
-			t := defaultDeviationThreshold
-			if _, ok := deviationThresholds[cp.Base]; ok {
-				t = deviationThresholds[cp.Base]
-			}
-
-			if d, ok := deviations[cp]; !ok || isBetween(tp.Price, means[cp], d.Mul(t)) {
+			if isUnfiltered(cp, unfilteredPairs) ||
+				withinDeviation(cp, tp.Price, deviations, means, deviationThresholds, multiplier) {
 				p, ok := filteredPrices[providerName]
 				if !ok {
 					p = make(types.CurrencyPairTickers)
@@ -60,10 +70,8 @@ func FilterTickerDeviations(
 				p[cp] = tp
 			} else {
 				provider.TelemetryFailure(providerName, provider.MessageTypeTicker)
-				logger.Warn().
-					Interface("currency_pair", cp).
-					Str("provider", string(providerName)).
-					Str("price", tp.Price.String()).
+				telemetryDeviationFiltered(providerName, cp)
+				logDeviationFiltered(logger, providerName, cp, tp.Price, deviations, means, deviationThresholds, multiplier).
 					Msg("provider deviating from other prices")
 			}
 		}
@@ -74,10 +82,16 @@ func FilterTickerDeviations(
 
 // FilterCandleDeviations finds the standard deviations of the tvwaps of
 // all assets, and filters out any providers that are not within 2𝜎 of the mean.
+// The threshold of each provider is scaled by its deviation multiplier.
+// The candles of the unfiltered pairs are all kept.
 func FilterCandleDeviations(
 	logger zerolog.Logger,
 	candles types.AggregatedProviderCandles,
 	deviationThresholds map[string]math.LegacyDec,
+	providerDeviationMultipliers map[types.ProviderName]math.LegacyDec,
+	unfilteredPairs map[types.CurrencyPair]struct{},
+	tvwapLookbacks map[string]time.Duration,
+	tvwapMinPeriod time.Duration,
 ) (types.AggregatedProviderCandles, error) {
 	var (
 		filteredCandles = make(types.AggregatedProviderCandles)
@@ -96,7 +110,7 @@ func FilterCandleDeviations(
 			p[currencyPair] = candlePrice
 		}
 
-		tvwap, err := ComputeTVWAP(candlePrices)
+		tvwap, err := ComputeTVWAP(candlePrices, tvwapLookbacks, tvwapMinPeriod)
 		if err != nil {
 			return nil, err
 		}
@@ -112,20 +126,18 @@ func FilterCandleDeviations(
 
 	deviations, means, err := StandardDeviation(tvwaps)
 	if err != nil {
-		return nil, err
+		logger.Warn().Err(err).Msg("skipping candle deviation filtering of assets")
 	}
 
-	// We accept any prices that are within (2 * T)𝜎, or for which we couldn't get 𝜎.
+	// We accept any prices that are within (2 * T * M)𝜎, or for which we couldn't get 𝜎.
 	// T is defined as the deviation threshold, either set by the config
-	// or defaulted to 1.
+	// or defaulted to 1, and M as the deviation multiplier of the provider,
+	// defaulted to 1.
 	for providerName, priceMap := range tvwaps {
+		multiplier := deviationMultiplier(providerName, providerDeviationMultipliers)
 		for cp, price := range priceMap {
-			t := defaultDeviationThreshold
-			if _, ok := deviationThresholds[cp.Base]; ok {
-				t = deviationThresholds[cp.Base]
-			}
-
-			if d, ok := deviations[cp]; !ok || isBetween(price, means[cp], d.Mul(t)) {
+			if isUnfiltered(cp, unfilteredPairs) ||
+				withinDeviation(cp, price, deviations, means, deviationThresholds, multiplier) {
 				p, ok := filteredCandles[providerName]
 				if !ok {
 					p = make(types.CurrencyPairCandles)
@@ -134,16 +146,404 @@ func FilterCandleDeviations(
 				p[cp] = candles[providerName][cp]
 			} else {
 				provider.TelemetryFailure(providerName, provider.MessageTypeCandle)
+				telemetryDeviationFiltered(providerName, cp)
+				logDeviationFiltered(logger, providerName, cp, price, deviations, means, deviationThresholds, multiplier).
+					Msg("provider deviating from other candles")
+			}
+		}
+	}
+
+	return filteredCandles, nil
+}
+
+// FilterFutureCandles filters out the candles of each provider that are dated
+// further in the future than the candle in progress can be, logging the
+// provider responsible, so a provider with a bad clock does not go unnoticed
+// while the candles of other providers are kept.
+func FilterFutureCandles(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+) types.AggregatedProviderCandles {
+	var (
+		filteredCandles = make(types.AggregatedProviderCandles)
+		now             = provider.PastUnixTimeMillis(-futureCandleTolerance)
+	)
+
+	for providerName, priceCandles := range candles {
+		for cp, candlePrices := range priceCandles {
+			validCandles := make([]types.CandlePrice, 0, len(candlePrices))
+			for _, candle := range candlePrices {
+				if candle.TimeStamp > now {
+					provider.TelemetryCandleFromFuture(providerName)
+					logger.Warn().
+						Interface("currency_pair", cp).
+						Str("provider", string(providerName)).
+						Time("timestamp", time.UnixMilli(candle.TimeStamp)).
+						Msg("provider candle is from the future")
+					continue
+				}
+				validCandles = append(validCandles, candle)
+			}
+			if len(validCandles) == 0 {
+				continue
+			}
+
+			p, ok := filteredCandles[providerName]
+			if !ok {
+				p = make(types.CurrencyPairCandles)
+				filteredCandles[providerName] = p
+			}
+			p[cp] = validCandles
+		}
+	}
+
+	return filteredCandles
+}
+
+// FilterStalePrices filters out the tickers and candles of any provider whose
+// most recent candle for a currency pair is older than maxAge, so a provider
+// which stopped receiving data is not used for that pair while fresh providers
+// still are. Tickers carry no timestamp, so the tickers of a provider with no
+// candles for a pair are kept.
+func FilterStalePrices(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	maxAge time.Duration,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	var (
+		filteredCandles = make(types.AggregatedProviderCandles)
+		filteredPrices  = make(types.AggregatedProviderPrices)
+		stale           = make(map[types.ProviderName]map[types.CurrencyPair]struct{})
+		staleTime       = provider.PastUnixTimeMillis(maxAge)
+	)
+
+	for providerName, priceCandles := range candles {
+		for cp, candlePrices := range priceCandles {
+			var latest int64
+			for _, candle := range candlePrices {
+				if candle.TimeStamp > latest {
+					latest = candle.TimeStamp
+				}
+			}
+
+			if len(candlePrices) > 0 && latest < staleTime {
+				if _, ok := stale[providerName]; !ok {
+					stale[providerName] = make(map[types.CurrencyPair]struct{})
+				}
+				stale[providerName][cp] = struct{}{}
+
+				provider.TelemetryPriceStale(providerName, cp)
 				logger.Warn().
 					Interface("currency_pair", cp).
 					Str("provider", string(providerName)).
-					Str("price", price.String()).
-					Msg("provider deviating from other candles")
+					Time("latest_candle", time.UnixMilli(latest)).
+					Msg("provider prices are stale")
+				continue
 			}
+
+			p, ok := filteredCandles[providerName]
+			if !ok {
+				p = make(types.CurrencyPairCandles)
+				filteredCandles[providerName] = p
+			}
+			p[cp] = candlePrices
 		}
 	}
 
-	return filteredCandles, nil
+	for providerName, priceTickers := range prices {
+		for cp, tp := range priceTickers {
+			if _, ok := stale[providerName][cp]; ok {
+				continue
+			}
+
+			p, ok := filteredPrices[providerName]
+			if !ok {
+				p = make(types.CurrencyPairTickers)
+				filteredPrices[providerName] = p
+			}
+			p[cp] = tp
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// FilterZeroWeightProviders filters out the tickers and candles of the
+// providers with a zero weight, which are excluded from the rates.
+func FilterZeroWeightProviders(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	filteredCandles := make(types.AggregatedProviderCandles, len(candles))
+	for providerName, priceCandles := range candles {
+		if !providerWeight(providerName, providerWeights).IsZero() {
+			filteredCandles[providerName] = priceCandles
+		}
+	}
+
+	filteredPrices := make(types.AggregatedProviderPrices, len(prices))
+	for providerName, priceTickers := range prices {
+		if !providerWeight(providerName, providerWeights).IsZero() {
+			filteredPrices[providerName] = priceTickers
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// FilterPriceBands filters out the USD quoted tickers and candles whose price
+// is outside the price band of their base, as a safety net for the pairs with
+// too few providers for their deviation to be filtered. The prices of the
+// bases without a band, or quoted in other currencies, are all kept.
+func FilterPriceBands(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	priceBands map[string]types.PriceBand,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	var (
+		filteredCandles = make(types.AggregatedProviderCandles)
+		filteredPrices  = make(types.AggregatedProviderPrices)
+	)
+
+	outsideBand := func(providerName types.ProviderName, cp types.CurrencyPair, price math.LegacyDec) bool {
+		band, ok := priceBands[cp.Base]
+		if !ok || cp.Quote != config.DenomUSD || band.Contains(price) {
+			return false
+		}
+
+		logger.Warn().
+			Interface("currency_pair", cp).
+			Str("provider", string(providerName)).
+			Str("price", price.String()).
+			Str("min", band.Min.String()).
+			Str("max", band.Max.String()).
+			Msg("provider price outside of the price band")
+		telemetryPriceDropped(cp, "price_band")
+		return true
+	}
+
+	for providerName, priceCandles := range candles {
+		for cp, candlePrices := range priceCandles {
+			bandCandles := make([]types.CandlePrice, 0, len(candlePrices))
+			for _, candle := range candlePrices {
+				if !outsideBand(providerName, cp, candle.Price) {
+					bandCandles = append(bandCandles, candle)
+				}
+			}
+			if len(bandCandles) == 0 {
+				continue
+			}
+
+			p, ok := filteredCandles[providerName]
+			if !ok {
+				p = make(types.CurrencyPairCandles)
+				filteredCandles[providerName] = p
+			}
+			p[cp] = bandCandles
+		}
+	}
+
+	for providerName, priceTickers := range prices {
+		for cp, tp := range priceTickers {
+			if outsideBand(providerName, cp, tp.Price) {
+				continue
+			}
+
+			p, ok := filteredPrices[providerName]
+			if !ok {
+				p = make(types.CurrencyPairTickers)
+				filteredPrices[providerName] = p
+			}
+			p[cp] = tp
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// FilterTrustedProviderDeviations filters out the tickers and candles of the
+// providers of a pair whose price deviates more than the max deviation
+// percentage from the price of the trusted provider of the pair. Candles are
+// compared by their latest price. The prices of the pairs without a trusted
+// provider, or whose trusted provider returned no price, are all kept.
+func FilterTrustedProviderDeviations(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	trustedProviders map[types.CurrencyPair]types.TrustedProvider,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	var (
+		filteredCandles = make(types.AggregatedProviderCandles)
+		filteredPrices  = make(types.AggregatedProviderPrices)
+	)
+
+	deviating := func(
+		providerName types.ProviderName,
+		cp types.CurrencyPair,
+		price math.LegacyDec,
+		referencePrice math.LegacyDec,
+		ok bool,
+	) bool {
+		trusted, isTrusted := trustedProviders[cp]
+		if !isTrusted || !ok || providerName == trusted.Provider || !referencePrice.IsPositive() {
+			return false
+		}
+
+		deviationPct := price.Sub(referencePrice).Abs().Quo(referencePrice).MulInt64(100)
+		if deviationPct.LTE(trusted.MaxDeviationPct) {
+			return false
+		}
+
+		logger.Warn().
+			Interface("currency_pair", cp).
+			Str("provider", string(providerName)).
+			Str("trusted_provider", string(trusted.Provider)).
+			Str("price", price.String()).
+			Str("trusted_price", referencePrice.String()).
+			Str("deviation_pct", deviationPct.String()).
+			Str("max_deviation_pct", trusted.MaxDeviationPct.String()).
+			Msg("provider deviating from the trusted provider")
+		return true
+	}
+
+	for providerName, priceCandles := range candles {
+		for cp, candlePrices := range priceCandles {
+			trustedPrice, ok := trustedCandlePrice(candles, cp, trustedProviders)
+			if price, hasCandles := latestCandlePrice(candlePrices); hasCandles &&
+				deviating(providerName, cp, price, trustedPrice, ok) {
+				provider.TelemetryFailure(providerName, provider.MessageTypeCandle)
+				continue
+			}
+
+			p, ok := filteredCandles[providerName]
+			if !ok {
+				p = make(types.CurrencyPairCandles)
+				filteredCandles[providerName] = p
+			}
+			p[cp] = candlePrices
+		}
+	}
+
+	for providerName, priceTickers := range prices {
+		for cp, tp := range priceTickers {
+			trustedTicker, ok := prices[trustedProviders[cp].Provider][cp]
+			if deviating(providerName, cp, tp.Price, trustedTicker.Price, ok) {
+				provider.TelemetryFailure(providerName, provider.MessageTypeTicker)
+				continue
+			}
+
+			p, ok := filteredPrices[providerName]
+			if !ok {
+				p = make(types.CurrencyPairTickers)
+				filteredPrices[providerName] = p
+			}
+			p[cp] = tp
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// trustedCandlePrice returns the latest candle price of the trusted provider
+// of the pair, and whether it has one.
+func trustedCandlePrice(
+	candles types.AggregatedProviderCandles,
+	cp types.CurrencyPair,
+	trustedProviders map[types.CurrencyPair]types.TrustedProvider,
+) (math.LegacyDec, bool) {
+	trusted, ok := trustedProviders[cp]
+	if !ok {
+		return math.LegacyDec{}, false
+	}
+	return latestCandlePrice(candles[trusted.Provider][cp])
+}
+
+// latestCandlePrice returns the price of the most recent candle, and whether
+// there is one.
+func latestCandlePrice(candlePrices []types.CandlePrice) (math.LegacyDec, bool) {
+	if len(candlePrices) == 0 {
+		return math.LegacyDec{}, false
+	}
+
+	latest := candlePrices[0]
+	for _, candle := range candlePrices[1:] {
+		if candle.TimeStamp > latest.TimeStamp {
+			latest = candle
+		}
+	}
+	return latest.Price, true
+}
+
+// withinDeviation returns true if the price of the given currency pair is
+// within (2 * T * M)𝜎 of the mean, where M is the deviation multiplier of
+// the provider, or if 𝜎 could not be computed for it.
+func withinDeviation(
+	cp types.CurrencyPair,
+	price math.LegacyDec,
+	deviations types.CurrencyPairDec,
+	means types.CurrencyPairDec,
+	deviationThresholds map[string]math.LegacyDec,
+	multiplier math.LegacyDec,
+) bool {
+	d, ok := deviations[cp]
+	return !ok || isBetween(price, means[cp], d.Mul(deviationThreshold(cp, deviationThresholds, multiplier)))
+}
+
+// deviationThreshold returns how many 𝜎 the price of the pair can be away
+// from the mean, scaled by the deviation multiplier of the provider.
+func deviationThreshold(
+	cp types.CurrencyPair,
+	deviationThresholds map[string]math.LegacyDec,
+	multiplier math.LegacyDec,
+) math.LegacyDec {
+	t := defaultDeviationThreshold
+	if _, ok := deviationThresholds[cp.Base]; ok {
+		t = deviationThresholds[cp.Base]
+	}
+	return t.Mul(multiplier)
+}
+
+// logDeviationFiltered returns a warning event with the same fields for every
+// price filtered out for its deviation, so the filtered providers can be
+// charted from the logs.
+func logDeviationFiltered(
+	logger zerolog.Logger,
+	providerName types.ProviderName,
+	cp types.CurrencyPair,
+	price math.LegacyDec,
+	deviations types.CurrencyPairDec,
+	means types.CurrencyPairDec,
+	deviationThresholds map[string]math.LegacyDec,
+	multiplier math.LegacyDec,
+) *zerolog.Event {
+	return logger.Warn().
+		Str("provider", string(providerName)).
+		Str("base", cp.Base).
+		Str("price", price.String()).
+		Str("mean", means[cp].String()).
+		Str("deviation", deviations[cp].String()).
+		Str("threshold", deviationThreshold(cp, deviationThresholds, multiplier).String())
+}
+
+// deviationMultiplier returns the deviation multiplier of the provider, or
+// one if it has none.
+func deviationMultiplier(
+	providerName types.ProviderName,
+	providerDeviationMultipliers map[types.ProviderName]math.LegacyDec,
+) math.LegacyDec {
+	if m, ok := providerDeviationMultipliers[providerName]; ok {
+		return m
+	}
+	return math.LegacyOneDec()
+}
+
+// isUnfiltered returns true if the deviation filter is disabled for the pair.
+func isUnfiltered(cp types.CurrencyPair, unfilteredPairs map[types.CurrencyPair]struct{}) bool {
+	_, ok := unfilteredPairs[cp]
+	return ok
 }
 
 func isBetween(p, mean, margin math.LegacyDec) bool {
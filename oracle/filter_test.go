@@ -1,10 +1,12 @@
 package oracle
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/telemetry"
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
@@ -25,7 +27,7 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 		{
 			Price:     atomPrice,
 			Volume:    atomVolume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 		},
 	}
 
@@ -43,7 +45,7 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 			{
 				Price:     math.LegacyMustNewDecFromStr("27.1"),
 				Volume:    atomVolume,
-				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+				TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 			},
 		},
 	}
@@ -52,6 +54,10 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerCandles,
 		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+		nil,
+		0,
 	)
 
 	_, ok := pricesFiltered[provider.ProviderCoinbase]
@@ -65,6 +71,10 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerCandles,
 		customDeviations,
+		nil,
+		nil,
+		nil,
+		0,
 	)
 
 	_, ok = pricesFilteredCustom[provider.ProviderCoinbase]
@@ -107,6 +117,8 @@ func TestSuccessFilterTickerDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerTickers,
 		make(map[string]math.LegacyDec),
+		nil,
+		nil,
 	)
 
 	_, ok := pricesFiltered[provider.ProviderCoinbase]
@@ -120,9 +132,471 @@ func TestSuccessFilterTickerDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerTickers,
 		customDeviations,
+		nil,
+		nil,
 	)
 
 	_, ok = pricesFilteredCustom[provider.ProviderCoinbase]
 	require.NoError(t, err, "It should successfully not filter out coinbase")
 	require.True(t, ok, "The filtered candle deviation price of coinbase should remain")
 }
+
+func TestFilterTickerDeviationsTelemetry(t *testing.T) {
+	metrics, err := telemetry.New(telemetry.Config{Enabled: true, ServiceName: "price-feeder"})
+	require.NoError(t, err)
+	defer func() {
+		_, err := telemetry.New(telemetry.Config{Enabled: false})
+		require.NoError(t, err)
+	}()
+
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	atomVolume := math.LegacyMustNewDecFromStr("1994674.34000000")
+
+	providerTickers := make(types.AggregatedProviderPrices, 4)
+	for providerName, price := range map[types.ProviderName]string{
+		provider.ProviderBinance:  "29.93",
+		provider.ProviderHuobi:    "29.93",
+		provider.ProviderKraken:   "29.93",
+		provider.ProviderCoinbase: "27.1",
+	} {
+		providerTickers[providerName] = types.CurrencyPairTickers{
+			pair: {Price: math.LegacyMustNewDecFromStr(price), Volume: atomVolume},
+		}
+	}
+
+	_, err = FilterTickerDeviations(zerolog.Nop(), providerTickers, nil, nil, nil)
+	require.NoError(t, err)
+
+	gr, err := metrics.Gather(telemetry.FormatDefault)
+	require.NoError(t, err)
+
+	var summary struct {
+		Counters []struct {
+			Name   string
+			Count  int
+			Labels map[string]string
+		}
+	}
+	require.NoError(t, json.Unmarshal(gr.Metrics, &summary))
+
+	filtered := map[string]int{}
+	for _, counter := range summary.Counters {
+		if counter.Name == "price-feeder.deviation.filtered" {
+			require.Equal(t, "ATOM", counter.Labels["base"])
+			filtered[counter.Labels["provider"]] += counter.Count
+		}
+	}
+	require.Equal(t, map[string]int{provider.ProviderCoinbase.String(): 1}, filtered)
+}
+
+func TestFilterTickerDeviationsProviderMultipliers(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	atomVolume := math.LegacyMustNewDecFromStr("1994674.34000000")
+
+	// okx and coinbase deviate from the mean by the same amount
+	providerTickers := make(types.AggregatedProviderPrices, 5)
+	for providerName, price := range map[types.ProviderName]string{
+		provider.ProviderBinance:  "10",
+		provider.ProviderHuobi:    "10",
+		provider.ProviderKraken:   "10",
+		provider.ProviderOkx:      "11",
+		provider.ProviderCoinbase: "11",
+	} {
+		providerTickers[providerName] = types.CurrencyPairTickers{
+			pair: {Price: math.LegacyMustNewDecFromStr(price), Volume: atomVolume},
+		}
+	}
+	deviations := map[string]math.LegacyDec{pair.Base: math.LegacyNewDec(2)}
+
+	pricesFiltered, err := FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		deviations,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Contains(t, pricesFiltered, provider.ProviderOkx)
+	require.Contains(t, pricesFiltered, provider.ProviderCoinbase)
+
+	pricesFiltered, err = FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		deviations,
+		map[types.ProviderName]math.LegacyDec{
+			provider.ProviderOkx:      math.LegacyMustNewDecFromStr("0.5"),
+			provider.ProviderCoinbase: math.LegacyNewDec(2),
+		},
+		nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, pricesFiltered, provider.ProviderOkx)
+	require.Contains(t, pricesFiltered, provider.ProviderCoinbase)
+	require.Len(t, pricesFiltered, 4)
+}
+
+func TestFilterTickerDeviationsFailedDeviation(t *testing.T) {
+	atomPair := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoPair := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	volume := math.LegacyMustNewDecFromStr("1994674.34000000")
+
+	// the OJO prices are large enough for their variance to overflow
+	ticker := func(price string) types.TickerPrice {
+		return types.TickerPrice{Price: math.LegacyMustNewDecFromStr(price), Volume: volume}
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomPair: ticker("29.93"),
+			ojoPair:  ticker("10000000000000000000000000000000000000000"),
+		},
+		provider.ProviderHuobi: {
+			atomPair: ticker("29.93"),
+			ojoPair:  ticker("20000000000000000000000000000000000000000"),
+		},
+		provider.ProviderKraken: {
+			atomPair: ticker("29.93"),
+			ojoPair:  ticker("30000000000000000000000000000000000000000"),
+		},
+		provider.ProviderCoinbase: {
+			atomPair: ticker("27.1"),
+		},
+	}
+
+	deviations, _, err := StandardDeviation(types.CurrencyPairDecByProvider{
+		provider.ProviderBinance: {ojoPair: providerPrices[provider.ProviderBinance][ojoPair].Price},
+		provider.ProviderHuobi:   {ojoPair: providerPrices[provider.ProviderHuobi][ojoPair].Price},
+		provider.ProviderKraken:  {ojoPair: providerPrices[provider.ProviderKraken][ojoPair].Price},
+	})
+	require.Error(t, err)
+	require.NotContains(t, deviations, ojoPair)
+
+	// the failed OJO deviation does not prevent filtering ATOM, and OJO is
+	// left unfiltered
+	pricesFiltered, err := FilterTickerDeviations(
+		zerolog.Nop(),
+		providerPrices,
+		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, pricesFiltered, provider.ProviderCoinbase)
+	for _, providerName := range []types.ProviderName{
+		provider.ProviderBinance,
+		provider.ProviderHuobi,
+		provider.ProviderKraken,
+	} {
+		require.Contains(t, pricesFiltered[providerName], atomPair)
+		require.Contains(t, pricesFiltered[providerName], ojoPair)
+	}
+}
+
+func TestFilterStalePrices(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("1994674.34000000")
+
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			pair: {{Price: atomPrice, Volume: atomVolume, TimeStamp: provider.PastUnixTimeMillis(time.Minute)}},
+		},
+		provider.ProviderKraken: {
+			pair: {{Price: atomPrice, Volume: atomVolume, TimeStamp: provider.PastUnixTimeMillis(time.Hour)}},
+		},
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {pair: {Price: atomPrice, Volume: atomVolume}},
+		provider.ProviderKraken:  {pair: {Price: atomPrice, Volume: atomVolume}},
+		provider.ProviderHuobi:   {pair: {Price: atomPrice, Volume: atomVolume}},
+	}
+
+	filteredCandles, filteredPrices := FilterStalePrices(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		5*time.Minute,
+	)
+
+	// only the stale provider is dropped, the pair is kept from the fresh ones
+	require.Contains(t, filteredCandles[provider.ProviderBinance], pair)
+	require.NotContains(t, filteredCandles[provider.ProviderKraken], pair)
+	require.Contains(t, filteredPrices[provider.ProviderBinance], pair)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], pair)
+
+	// tickers without candles can not be checked and are kept
+	require.Contains(t, filteredPrices[provider.ProviderHuobi], pair)
+
+	// the pair is dropped once every provider with candles is stale
+	filteredCandles, filteredPrices = FilterStalePrices(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		10*time.Second,
+	)
+	require.Empty(t, filteredCandles)
+	require.NotContains(t, filteredPrices[provider.ProviderBinance], pair)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], pair)
+}
+
+func TestFilterPriceBands(t *testing.T) {
+	pair := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	usdtPair := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	volume := math.LegacyMustNewDecFromStr("1000")
+	insidePrice := math.LegacyMustNewDecFromStr("0.5")
+	outsidePrice := math.LegacyMustNewDecFromStr("500")
+	timeStamp := provider.PastUnixTimeMillis(time.Minute)
+
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			pair: {{Price: insidePrice, Volume: volume, TimeStamp: timeStamp}},
+		},
+		provider.ProviderKraken: {
+			pair: {{Price: outsidePrice, Volume: volume, TimeStamp: timeStamp}},
+		},
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {pair: {Price: insidePrice, Volume: volume}},
+		provider.ProviderKraken: {
+			pair:     {Price: outsidePrice, Volume: volume},
+			usdtPair: {Price: outsidePrice, Volume: volume},
+		},
+	}
+	priceBands := map[string]types.PriceBand{
+		"OJO": {Min: math.LegacyMustNewDecFromStr("0.001"), Max: math.LegacyNewDec(10)},
+	}
+
+	filteredCandles, filteredPrices := FilterPriceBands(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		priceBands,
+	)
+
+	// the price inside the band is kept while the one outside is dropped
+	require.Contains(t, filteredCandles[provider.ProviderBinance], pair)
+	require.NotContains(t, filteredCandles[provider.ProviderKraken], pair)
+	require.Contains(t, filteredPrices[provider.ProviderBinance], pair)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], pair)
+
+	// prices not quoted in USD are only checked once converted
+	require.Contains(t, filteredPrices[provider.ProviderKraken], usdtPair)
+
+	// every price is kept without a band for the base
+	filteredCandles, filteredPrices = FilterPriceBands(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		map[string]types.PriceBand{},
+	)
+	require.Equal(t, providerCandles, filteredCandles)
+	require.Equal(t, providerPrices, filteredPrices)
+}
+
+func TestFilterTrustedProviderDeviations(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	atomVolume := math.LegacyMustNewDecFromStr("1994674.34000000")
+	timeStamp := provider.PastUnixTimeMillis(time.Minute)
+
+	// kraken is within 2% of coinbase while huobi is 10% away
+	providerCandles := make(types.AggregatedProviderCandles, 3)
+	providerPrices := make(types.AggregatedProviderPrices, 3)
+	for providerName, price := range map[types.ProviderName]string{
+		provider.ProviderCoinbase: "10",
+		provider.ProviderKraken:   "10.1",
+		provider.ProviderHuobi:    "11",
+	} {
+		priceDec := math.LegacyMustNewDecFromStr(price)
+		providerCandles[providerName] = types.CurrencyPairCandles{
+			pair: {{Price: priceDec, Volume: atomVolume, TimeStamp: timeStamp}},
+		}
+		providerPrices[providerName] = types.CurrencyPairTickers{
+			pair: {Price: priceDec, Volume: atomVolume},
+		}
+	}
+	trustedProviders := map[types.CurrencyPair]types.TrustedProvider{
+		pair: {Provider: provider.ProviderCoinbase, MaxDeviationPct: math.LegacyNewDec(2)},
+	}
+
+	filteredCandles, filteredPrices := FilterTrustedProviderDeviations(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		trustedProviders,
+	)
+
+	for _, providerName := range []types.ProviderName{provider.ProviderCoinbase, provider.ProviderKraken} {
+		require.Contains(t, filteredCandles[providerName], pair)
+		require.Contains(t, filteredPrices[providerName], pair)
+	}
+	require.NotContains(t, filteredCandles[provider.ProviderHuobi], pair)
+	require.NotContains(t, filteredPrices[provider.ProviderHuobi], pair)
+
+	// every price is kept when the trusted provider returned none
+	delete(providerCandles, provider.ProviderCoinbase)
+	delete(providerPrices, provider.ProviderCoinbase)
+	filteredCandles, filteredPrices = FilterTrustedProviderDeviations(
+		zerolog.Nop(),
+		providerCandles,
+		providerPrices,
+		trustedProviders,
+	)
+	require.Equal(t, providerCandles, filteredCandles)
+	require.Equal(t, providerPrices, filteredPrices)
+}
+
+func TestFilterFutureCandles(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	atomVolume := math.LegacyMustNewDecFromStr("98444.123455")
+
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			pair: {
+				{
+					Price:     math.LegacyMustNewDecFromStr("25.09183"),
+					Volume:    atomVolume,
+					TimeStamp: provider.PastUnixTimeMillis(-5 * time.Minute),
+				},
+			},
+		},
+		provider.ProviderKraken: {
+			pair: {
+				{
+					Price:     math.LegacyMustNewDecFromStr("29.93"),
+					Volume:    atomVolume,
+					TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+				},
+			},
+		},
+		// the candle in progress is dated at its close
+		provider.ProviderHuobi: {
+			pair: {
+				{
+					Price:     math.LegacyMustNewDecFromStr("29.93"),
+					Volume:    atomVolume,
+					TimeStamp: provider.PastUnixTimeMillis(-30 * time.Second),
+				},
+			},
+		},
+	}
+
+	filteredCandles := FilterFutureCandles(zerolog.Nop(), providerCandles)
+	require.NotContains(t, filteredCandles, provider.ProviderBinance)
+	require.Len(t, filteredCandles[provider.ProviderKraken][pair], 1)
+	require.Len(t, filteredCandles[provider.ProviderHuobi][pair], 1)
+
+	// the providers with valid candles still produce a price
+	rates, providerCounts, err := CalcCurrencyPairRates(
+		providerCandles,
+		types.AggregatedProviderPrices{},
+		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+		[]types.CurrencyPair{pair},
+		types.TickerVolumePolicyFloor,
+		nil,
+		types.AggregationMethodVWAP,
+		nil,
+		0,
+		nil,
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("29.93"), rates[pair])
+	require.Equal(t, 2, providerCounts[pair])
+}
+
+func TestCalcCurrencyPairRatesUnfilteredPairs(t *testing.T) {
+	atomPair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	newPair := types.CurrencyPair{Base: "NEW", Quote: "USD"}
+	volume := math.LegacyMustNewDecFromStr("1000")
+
+	// the last provider of each pair is an outlier
+	prices := []string{"10", "10", "10", "10", "20"}
+	providers := []types.ProviderName{
+		provider.ProviderBinance,
+		provider.ProviderKraken,
+		provider.ProviderHuobi,
+		provider.ProviderOkx,
+		provider.ProviderCoinbase,
+	}
+	providerTickers := make(types.AggregatedProviderPrices, len(providers))
+	for i, providerName := range providers {
+		ticker := types.TickerPrice{Price: math.LegacyMustNewDecFromStr(prices[i]), Volume: volume}
+		providerTickers[providerName] = types.CurrencyPairTickers{
+			atomPair: ticker,
+			newPair:  ticker,
+		}
+	}
+
+	rates, providerCounts, err := CalcCurrencyPairRates(
+		types.AggregatedProviderCandles{},
+		providerTickers,
+		make(map[string]math.LegacyDec),
+		nil,
+		map[types.CurrencyPair]struct{}{newPair: {}},
+		[]types.CurrencyPair{atomPair, newPair},
+		types.TickerVolumePolicyFloor,
+		nil,
+		types.AggregationMethodVWAP,
+		nil,
+		0,
+		nil,
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+
+	// the outlier is filtered out of the normal pair
+	require.Equal(t, len(providers)-1, providerCounts[atomPair])
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), rates[atomPair])
+
+	// while every provider of the unfiltered pair is kept
+	require.Equal(t, len(providers), providerCounts[newPair])
+	require.Equal(t, math.LegacyMustNewDecFromStr("12"), rates[newPair])
+}
+
+func TestCalcCurrencyPairRatesProviderWeights(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	timestamp := provider.PastUnixTimeMillis(time.Minute)
+	candles := types.AggregatedProviderCandles{}
+	for providerName, price := range map[types.ProviderName]string{
+		provider.ProviderBinance: "10",
+		provider.ProviderKraken:  "20",
+		provider.ProviderHuobi:   "30",
+	} {
+		candles[providerName] = types.CurrencyPairCandles{
+			pair: {{
+				Price:     math.LegacyMustNewDecFromStr(price),
+				Volume:    math.LegacyMustNewDecFromStr("1000"),
+				TimeStamp: timestamp,
+			}},
+		}
+	}
+
+	record := newFilterRecord()
+	rates, providerCounts, err := CalcCurrencyPairRates(
+		candles,
+		types.AggregatedProviderPrices{},
+		make(map[string]math.LegacyDec),
+		nil,
+		map[types.CurrencyPair]struct{}{pair: {}},
+		[]types.CurrencyPair{pair},
+		types.TickerVolumePolicyFloor,
+		map[types.ProviderName]math.LegacyDec{
+			provider.ProviderKraken: math.LegacyMustNewDecFromStr("0.25"),
+			provider.ProviderHuobi:  math.LegacyZeroDec(),
+		},
+		types.AggregationMethodVWAP,
+		nil,
+		0,
+		record,
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+
+	// the candles of the downweighted provider move the tvwap less, and the
+	// provider with a zero weight is dropped with its reason recorded
+	require.True(t, rates[pair].Sub(math.LegacyNewDec(12)).Abs().LT(math.LegacyMustNewDecFromStr("0.000001")))
+	require.Equal(t, 2, providerCounts[pair])
+	require.Equal(t, filterReasonZeroWeight, record.candleReason(provider.ProviderHuobi, pair))
+	require.Empty(t, record.candleReason(provider.ProviderKraken, pair))
+}
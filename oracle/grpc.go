@@ -2,10 +2,21 @@ package oracle
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
+// grpcQueryTimeout defines how long a query to a gRPC endpoint can take
+// before it is considered unavailable.
+const grpcQueryTimeout = 15 * time.Second
+
 func dialerFunc(_ context.Context, addr string) (net.Conn, error) {
 	return Connect(addr)
 }
@@ -32,3 +43,66 @@ func ProtocolAndAddress(listenAddr string) (string, string) {
 
 	return protocol, address
 }
+
+// queryGRPC runs the query with a connection to the gRPC endpoint of the
+// oracle client, falling back on the fallback gRPC endpoints, in order, when
+// an endpoint can't be reached. The errors returned by a reachable node are
+// returned as is, without falling back.
+func (o *Oracle) queryGRPC(
+	ctx context.Context,
+	query func(ctx context.Context, grpcConn *grpc.ClientConn) error,
+) error {
+	var err error
+	for _, endpoint := range o.oracleClient.GRPCEndpoints() {
+		err = queryGRPCEndpoint(ctx, endpoint, query)
+		if err == nil || !isUnavailable(ctx, err) {
+			return err
+		}
+
+		o.logger.Debug().
+			Err(err).
+			Str("grpc_endpoint", endpoint).
+			Msg("failed to query gRPC endpoint")
+	}
+
+	return err
+}
+
+func queryGRPCEndpoint(
+	ctx context.Context,
+	endpoint string,
+	query func(ctx context.Context, grpcConn *grpc.ClientConn) error,
+) error {
+	//nolint: all
+	grpcConn, err := grpc.Dial(
+		endpoint,
+		// the Cosmos SDK doesn't support any transport security mechanism
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return status.Error(codes.Unavailable, fmt.Sprintf("failed to dial Cosmos gRPC service: %s", err))
+	}
+	defer grpcConn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, grpcQueryTimeout)
+	defer cancel()
+
+	return query(ctx, grpcConn)
+}
+
+// isUnavailable returns whether the error of a gRPC query is due to the
+// endpoint being unreachable, rather than returned by the node, unless the
+// query was canceled by the given context.
+func isUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
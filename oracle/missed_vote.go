@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// maxMissedVotes is the number of the latest missed votes kept in the missed
+// vote history.
+const maxMissedVotes = 100
+
+// missedVotes holds a bounded history of the latest missed votes, along with
+// the vote period of the latest one and whether votes have been missed since
+// the last successful broadcast.
+type missedVotes struct {
+	mtx            sync.Mutex
+	history        []types.MissedVote
+	lastVotePeriod float64
+	missing        bool
+}
+
+// GetMissedVotes returns a copy of the latest missed votes, oldest first.
+func (o *Oracle) GetMissedVotes() []types.MissedVote {
+	o.missedVotes.mtx.Lock()
+	defer o.missedVotes.mtx.Unlock()
+
+	missed := make([]types.MissedVote, len(o.missedVotes.history))
+	copy(missed, o.missedVotes.history)
+	return missed
+}
+
+// recordMissedVote adds the missed vote of a vote period to the missed vote
+// history, dropping the oldest one once the history is full. Only the first
+// missed vote of each vote period is recorded, since the oracle ticks several
+// times per vote period, and an alert is only sent for the first missed vote
+// since the last successful broadcast.
+func (o *Oracle) recordMissedVote(votePeriod float64, height int64, reason types.MissedVoteReason, err error) {
+	o.missedVotes.mtx.Lock()
+	defer o.missedVotes.mtx.Unlock()
+
+	if votePeriod == o.missedVotes.lastVotePeriod {
+		return
+	}
+	o.missedVotes.lastVotePeriod = votePeriod
+
+	missedVote := types.MissedVote{
+		Height:    height,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		missedVote.Error = err.Error()
+	}
+
+	if !o.missedVotes.missing {
+		o.alert(types.AlertEvent{
+			Type:    types.AlertEventMissedVote,
+			Message: fmt.Sprintf("missed vote: %s", reason),
+			Height:  height,
+			Error:   missedVote.Error,
+		})
+	}
+	o.missedVotes.missing = true
+
+	if len(o.missedVotes.history) == maxMissedVotes {
+		o.missedVotes.history = o.missedVotes.history[1:]
+	}
+	o.missedVotes.history = append(o.missedVotes.history, missedVote)
+}
+
+// recordBroadcast records that a prevote or vote was broadcast successfully,
+// so the next missed vote is alerted on again.
+func (o *Oracle) recordBroadcast() {
+	o.missedVotes.mtx.Lock()
+	defer o.missedVotes.mtx.Unlock()
+
+	o.missedVotes.missing = false
+}
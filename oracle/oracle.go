@@ -9,17 +9,18 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/ojo-network/ojo/util"
 	oracletypes "github.com/ojo-network/ojo/x/oracle/types"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle/client"
@@ -28,20 +29,37 @@ import (
 	pfsync "github.com/ojo-network/price-feeder/pkg/sync"
 )
 
-// We define tickerSleep as the minimum timeout between each oracle loop. We
-// define this value empirically based on enough time to collect exchange rates,
-// and broadcast pre-vote and vote transactions such that they're committed in
-// at least one block during each voting period.
+// We define defaultTickInterval as the default minimum timeout between each
+// oracle loop. We define this value empirically based on enough time to collect
+// exchange rates, and broadcast pre-vote and vote transactions such that they're
+// committed in at least one block during each voting period.
 const (
-	tickerSleep = 1000 * time.Millisecond
+	defaultTickInterval = 1000 * time.Millisecond
+
+	// unhealthyProviderRetryInterval defines how often a provider reporting
+	// itself as unhealthy is still fetched, instead of being skipped.
+	unhealthyProviderRetryInterval = 1 * time.Minute
+
+	// defaultShutdownGracePeriod defines how long an in-flight tick is given
+	// to complete its prevote or vote broadcast once the oracle is stopped.
+	defaultShutdownGracePeriod = 10 * time.Second
+
+	// maxPriceChangeRejections defines after how many consecutive ticks a price
+	// dropped by the max price change check is accepted as the new price of
+	// the asset, so a lasting move does not keep the asset from being voted.
+	maxPriceChangeRejections = 5
 )
 
 // PreviousPrevote defines a structure for defining the previous prevote
 // submitted on-chain.
 type PreviousPrevote struct {
-	ExchangeRates     string
-	Salt              string
-	SubmitBlockHeight int64
+	ExchangeRates     string `json:"exchange_rates"`
+	Salt              string `json:"salt"`
+	SubmitBlockHeight int64  `json:"submit_block_height"`
+
+	// Audit holds the per-provider breakdown of the exchange rates, if vote
+	// auditing is enabled.
+	Audit *types.VoteAudit `json:"audit,omitempty"`
 }
 
 func NewPreviousPrevote() *PreviousPrevote {
@@ -60,9 +78,14 @@ type Oracle struct {
 	closer *pfsync.Closer
 
 	providerTimeout    time.Duration
+	providerTimeouts   map[types.ProviderName]time.Duration
+	tickInterval       time.Duration
 	providerPairs      map[types.ProviderName][]types.CurrencyPair
+	providerPairsMtx   sync.RWMutex
+	delistedPairs      map[types.ProviderName]map[types.CurrencyPair]bool
 	previousPrevote    *PreviousPrevote
 	previousVotePeriod float64
+	prevoteFile        string
 	priceProviders     map[types.ProviderName]provider.Provider
 	oracleClient       client.OracleClient
 	deviations         map[string]sdkmath.LegacyDec
@@ -70,9 +93,107 @@ type Oracle struct {
 	ParamCache         *ParamCache
 	chainConfig        bool
 
-	pricesMutex     sync.RWMutex
-	lastPriceSyncTS time.Time
-	prices          types.CurrencyPairDec
+	pairRevalidationInterval time.Duration
+	lastPairRevalidation     time.Time
+	pairRevalidationRunning  atomic.Bool
+	unhealthyProviderRetries map[types.ProviderName]time.Time
+	tickerVolumePolicy       types.TickerVolumePolicy
+	aggregationMethod        types.AggregationMethod
+	tvwapMinPeriod           time.Duration
+	tvwapLookbacks           map[string]time.Duration
+	minProvidersPerAsset     int
+	minTotalVolumes          map[string]sdkmath.LegacyDec
+	priceBands               map[string]types.PriceBand
+	trustedProviders         map[types.CurrencyPair]types.TrustedProvider
+	unfilteredPairs          map[types.CurrencyPair]struct{}
+	deviationMultipliers     map[types.ProviderName]sdkmath.LegacyDec
+	providerWeights          map[types.ProviderName]sdkmath.LegacyDec
+	referencePairs           map[types.ProviderName][]types.CurrencyPair
+	votePrecision            uint64
+	voteNudgeStep            sdkmath.LegacyDec
+	maxPriceChangePct        sdkmath.LegacyDec
+	maxPairsPerTick          int
+	pairCursor               int
+	roundingMode             types.RoundingMode
+	adaptiveTimeout          *AdaptiveTimeout
+	circuitBreaker           *CircuitBreaker
+	alerter                  Alerter
+	minBalance               sdk.Coin
+	providerCancels          map[types.ProviderName]context.CancelFunc
+	paramsFallbackMaxAge     int64
+	paramsMaxAge             time.Duration
+	maxPriceAge              time.Duration
+	candleGapFillInterval    time.Duration
+	conversionRateOverrides  map[string]sdkmath.LegacyDec
+	conversionRawFallback    bool
+	voteAudit                bool
+	dryRun                   bool
+	shutdownGracePeriod      time.Duration
+
+	// pendingTicks tracks the ticks run by drainTick, including the ones
+	// abandoned once the shutdown grace period elapsed.
+	pendingTicks sync.WaitGroup
+
+	// broadcast broadcasts the messages of the oracle's transactions.
+	broadcast func(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) (*sdk.TxResponse, error)
+
+	// getBalance returns the balance of an address in a denom.
+	getBalance func(ctx context.Context, address, denom string) (sdk.Coin, error)
+
+	// pricesSnapshot holds an immutable copy of the prices, swapped once per
+	// price computation, so they can be served without copying under the lock.
+	pricesSnapshot atomic.Pointer[types.CurrencyPairDec]
+
+	// signer signs the price snapshots, which are not signed if it is nil.
+	signer Signer
+
+	// signedPrices holds the signed snapshot of the prices, swapped along
+	// with pricesSnapshot when a signer is set.
+	signedPrices atomic.Pointer[types.SignedPrices]
+
+	// blockHeight holds the block height of the latest tick, at which the
+	// next prices are computed.
+	blockHeight atomic.Int64
+
+	// firstVoteTS holds when the first vote was successfully broadcast, and is
+	// nil until then.
+	firstVoteTS atomic.Pointer[time.Time]
+
+	// votingPaused holds whether prevotes and votes are skipped, toggled at
+	// runtime through the admin API.
+	votingPaused atomic.Bool
+
+	// allProvidersDown holds whether no provider returned any price during
+	// the last tick, and is only accessed by SetPrices.
+	allProvidersDown bool
+
+	// lowBalance holds whether the balance of the feeder account was below the
+	// min balance when it was last checked, and is only accessed by tick.
+	lowBalance bool
+
+	// submittedPrices holds the last price of each asset which passed the
+	// max price change check, and priceChangeRejections the number of
+	// consecutive ticks the price of each asset failed it since. They are only
+	// accessed by SetPrices.
+	submittedPrices       types.CurrencyPairDec
+	priceChangeRejections map[types.CurrencyPair]int
+
+	priceSubscriptions priceSubscriptions
+	missedVotes        missedVotes
+
+	pricesMutex      sync.RWMutex
+	lastPriceSyncTS  time.Time
+	prices           types.CurrencyPairDec
+	lastPrices       types.CurrencyPairTimestampedPrices
+	conversionRoutes types.CurrencyPairConversionRoutes
+
+	// inputs and results of the last price computation, kept for GetPriceDebug
+	aggregatedCandles types.AggregatedProviderCandles
+	aggregatedTickers types.AggregatedProviderPrices
+	usdRates          types.CurrencyPairDec
+	computedPrices    types.CurrencyPairDec
+	providerCounts    map[types.CurrencyPair]int
+	filterRecord      *filterRecord
 
 	tvwapsByProvider types.PricesWithMutex
 	vwapsByProvider  types.PricesWithMutex
@@ -83,11 +204,16 @@ func New(
 	oc client.OracleClient,
 	providerPairs map[types.ProviderName][]types.CurrencyPair,
 	providerTimeout time.Duration,
+	tickInterval time.Duration,
 	deviations map[string]sdkmath.LegacyDec,
 	endpoints map[types.ProviderName]provider.Endpoint,
 	chainConfig bool,
 ) *Oracle {
-	return &Oracle{
+	if tickInterval <= 0 {
+		tickInterval = defaultTickInterval
+	}
+
+	o := &Oracle{
 		logger:          logger.With().Str("module", "oracle").Logger(),
 		closer:          pfsync.NewCloser(),
 		oracleClient:    oc,
@@ -95,11 +221,19 @@ func New(
 		priceProviders:  make(map[types.ProviderName]provider.Provider),
 		previousPrevote: nil,
 		providerTimeout: providerTimeout,
+		tickInterval:    tickInterval,
 		deviations:      deviations,
 		ParamCache:      &ParamCache{params: nil},
 		chainConfig:     chainConfig,
 		endpoints:       endpoints,
+		broadcast:       oc.BroadcastTx,
+		alerter:         NoopAlerter{},
+
+		shutdownGracePeriod: defaultShutdownGracePeriod,
 	}
+	o.getBalance = o.GetBalance
+
+	return o
 }
 
 // LoadProviderPairsAndDeviations loads the on chain pair providers and
@@ -118,7 +252,7 @@ func (o *Oracle) LoadProviderPairsAndDeviations(ctx context.Context) error {
 		return err
 	}
 
-	o.providerPairs = CreatePairProvidersFromCurrencyPairProvidersList(oracleParams.CurrencyPairProviders)
+	o.setProviderPairs(CreatePairProvidersFromCurrencyPairProvidersList(oracleParams.CurrencyPairProviders))
 	o.deviations, err = CreateDeviationsFromCurrencyDeviationThresholdList(oracleParams.CurrencyDeviationThresholds)
 	if err != nil {
 		return err
@@ -127,6 +261,271 @@ func (o *Oracle) LoadProviderPairsAndDeviations(ctx context.Context) error {
 	return nil
 }
 
+// SetPairRevalidationInterval sets the interval at which the subscribed pairs
+// of each provider are checked in the background against the pairs the
+// provider currently has available. A zero interval disables the check.
+func (o *Oracle) SetPairRevalidationInterval(interval time.Duration) {
+	o.pairRevalidationInterval = interval
+}
+
+// SetTickerVolumePolicy sets the policy used to weight tickers with a volume
+// below the minimum ticker volume when computing VWAPs.
+func (o *Oracle) SetTickerVolumePolicy(policy types.TickerVolumePolicy) {
+	o.tickerVolumePolicy = policy
+}
+
+// SetAggregationMethod sets the method used to combine the prices of the
+// providers of each currency pair.
+func (o *Oracle) SetAggregationMethod(method types.AggregationMethod) {
+	o.aggregationMethod = method
+}
+
+// SetTVWAPMinPeriod sets the minimum period the candles of each provider are
+// weighted over when computing TVWAPs. When it is zero, the period is the age
+// of the provider's oldest candle.
+func (o *Oracle) SetTVWAPMinPeriod(minPeriod time.Duration) {
+	o.tvwapMinPeriod = minPeriod
+}
+
+// SetTVWAPLookbacks sets how far back the candles of each base are weighted
+// when computing TVWAPs. The bases without a lookback use the default of 10
+// minutes, and the providers keep their candles for at least the longest one.
+func (o *Oracle) SetTVWAPLookbacks(lookbacks map[string]time.Duration) {
+	o.tvwapLookbacks = lookbacks
+}
+
+// tvwapWindow returns the longest TVWAP lookback of the assets, for which the
+// candles of the providers are kept.
+func (o *Oracle) tvwapWindow() time.Duration {
+	window := defaultTVWAPLookback
+	for _, lookback := range o.tvwapLookbacks {
+		window = max(window, lookback)
+	}
+	return window
+}
+
+// SetMinProvidersPerAsset sets the minimum number of distinct providers a
+// price must be derived from to be reported. Prices derived from fewer
+// providers are dropped.
+func (o *Oracle) SetMinProvidersPerAsset(minProviders int) {
+	o.minProvidersPerAsset = minProviders
+}
+
+// SetMinTotalVolumes sets the minimum total volume, by base currency, summed
+// across the providers contributing to a price for it to be reported. Prices of
+// currencies without a minimum are never dropped for their volume.
+func (o *Oracle) SetMinTotalVolumes(minTotalVolumes map[string]sdkmath.LegacyDec) {
+	o.minTotalVolumes = minTotalVolumes
+}
+
+// SetPriceBands sets the absolute USD ranges, by base currency, the prices of
+// each provider must be within, checked before the deviation filter. Prices
+// of currencies without a band are never dropped for their range.
+func (o *Oracle) SetPriceBands(priceBands map[string]types.PriceBand) {
+	o.priceBands = priceBands
+}
+
+// SetTrustedProviders sets the trusted provider of each currency pair, whose
+// price the prices of the other providers of the pair must be within the max
+// deviation percentage of, checked before the deviation filter.
+func (o *Oracle) SetTrustedProviders(trustedProviders map[types.CurrencyPair]types.TrustedProvider) {
+	o.trustedProviders = trustedProviders
+}
+
+// SetUnfilteredPairs sets the currency pairs whose prices skip the deviation
+// filter, so assets whose providers legitimately disagree widely still get a
+// price from all of them.
+func (o *Oracle) SetUnfilteredPairs(unfilteredPairs map[types.CurrencyPair]struct{}) {
+	o.unfilteredPairs = unfilteredPairs
+}
+
+// SetProviderDeviationMultipliers sets the multipliers applied to the
+// deviation threshold of each provider, so a trusted provider can be held to
+// a tighter band than the others.
+func (o *Oracle) SetProviderDeviationMultipliers(multipliers map[types.ProviderName]sdkmath.LegacyDec) {
+	o.deviationMultipliers = multipliers
+}
+
+// SetProviderWeights sets the weights scaling the ticker and candle volumes of
+// each provider in the VWAP and TVWAP, so a less trusted provider weighs less
+// than its reported volume. Providers without a weight have a weight of one,
+// and the providers with a zero weight are excluded from the rates.
+func (o *Oracle) SetProviderWeights(weights map[types.ProviderName]sdkmath.LegacyDec) {
+	o.providerWeights = weights
+}
+
+// SetReferencePairs sets the currency pairs of each provider whose prices are
+// computed and served like the prices of the other pairs, but never voted.
+func (o *Oracle) SetReferencePairs(referencePairs map[types.ProviderName][]types.CurrencyPair) {
+	o.referencePairs = referencePairs
+}
+
+// SetVotePrecision sets the number of decimal places voted prices are rounded
+// to, and the mode used to round them. When the precision is zero, voted
+// prices are not rounded, and the prices which would round to zero are voted
+// unrounded.
+func (o *Oracle) SetVotePrecision(precision uint64, mode types.RoundingMode) {
+	o.votePrecision = precision
+	o.roundingMode = mode
+}
+
+// SetVoteNudgeStep sets the maximum step, as a fraction of the on-chain rate,
+// voted prices move the on-chain rates by. Prices further from the on-chain
+// rates are clamped to the step. When the step is zero, the computed prices
+// are voted as is.
+func (o *Oracle) SetVoteNudgeStep(step sdkmath.LegacyDec) {
+	o.voteNudgeStep = step
+}
+
+// SetMaxPriceChangePct sets the maximum change, in percent, of the price of an
+// asset since the previous price computation. Prices changing faster are
+// dropped instead of being voted, until the move lasted for
+// maxPriceChangeRejections ticks. When it is zero, prices are not checked.
+func (o *Oracle) SetMaxPriceChangePct(maxChangePct sdkmath.LegacyDec) {
+	o.maxPriceChangePct = maxChangePct
+}
+
+// SetMaxPairsPerTick sets the maximum number of distinct pairs collected each
+// tick, so ticks over huge pair sets stay within the voting window. The rates
+// are collected round-robin across ticks with all of their pairs, and the last
+// prices of the rates not collected are carried over. The pairs of the
+// conversion currencies are collected every tick and count towards the limit.
+// When it is zero, every pair is collected each tick.
+func (o *Oracle) SetMaxPairsPerTick(maxPairs int) {
+	o.maxPairsPerTick = maxPairs
+}
+
+// SetProviderTimeouts sets the timeouts of the providers which override the
+// provider timeout, such as slower on-chain providers.
+func (o *Oracle) SetProviderTimeouts(providerTimeouts map[types.ProviderName]time.Duration) {
+	o.providerTimeouts = providerTimeouts
+}
+
+// SetAdaptiveTimeout sets the AdaptiveTimeout used to compute the timeout of
+// each provider from its observed latencies, instead of using the fixed
+// provider timeout. The timeouts set by SetProviderTimeouts remain the floor
+// of the adaptive timeouts of their providers.
+func (o *Oracle) SetAdaptiveTimeout(adaptiveTimeout *AdaptiveTimeout) {
+	o.adaptiveTimeout = adaptiveTimeout
+}
+
+// timeoutOf returns the timeout of the ticker and candle fetch of the
+// provider, which is its adaptive timeout when enabled, but never shorter
+// than the timeout explicitly set for the provider.
+func (o *Oracle) timeoutOf(providerName types.ProviderName) time.Duration {
+	explicitTimeout, explicit := o.providerTimeouts[providerName]
+	if o.adaptiveTimeout != nil {
+		timeout := o.adaptiveTimeout.Timeout(providerName)
+		if explicit {
+			timeout = max(timeout, explicitTimeout)
+		}
+		return timeout
+	}
+	if explicit {
+		return explicitTimeout
+	}
+	return o.providerTimeout
+}
+
+// SetCircuitBreaker sets the CircuitBreaker used to skip the providers which
+// failed repeatedly for a cooldown. A provider whose circuit opens is removed
+// along with its connections, and initialized again once half-opened.
+func (o *Oracle) SetCircuitBreaker(circuitBreaker *CircuitBreaker) {
+	o.circuitBreaker = circuitBreaker
+}
+
+// SetParamsFallbackMaxAge sets the maximum age, in blocks, of the cached
+// params the oracle falls back on when fetching fresh params fails. When it is
+// zero, the tick is aborted instead.
+func (o *Oracle) SetParamsFallbackMaxAge(maxAge int64) {
+	o.paramsFallbackMaxAge = maxAge
+}
+
+// SetParamsMaxAge sets the maximum wall-clock age of the cached params, after
+// which they are refreshed even if the block height based cache interval has
+// not elapsed and no param update event was received. When it is zero, the
+// cached params only expire by block height.
+func (o *Oracle) SetParamsMaxAge(maxAge time.Duration) {
+	o.paramsMaxAge = maxAge
+}
+
+// SetMaxPriceAge sets the maximum age of the latest candle of a provider for
+// a currency pair, after which the provider's prices for that pair are not
+// used to compute prices, and of the tickers the providers created afterwards
+// return. When it is zero, prices are not checked for staleness.
+func (o *Oracle) SetMaxPriceAge(maxAge time.Duration) {
+	o.maxPriceAge = maxAge
+}
+
+// SetCandleGapFillInterval sets the interval of the filler candles inserted
+// into the gaps of the candles of each provider, so their TVWAPs are computed
+// over a continuous series. When it is zero, gaps are not filled.
+func (o *Oracle) SetCandleGapFillInterval(interval time.Duration) {
+	o.candleGapFillInterval = interval
+}
+
+// candleIntervals returns the configured candle interval of each provider
+// endpoint.
+func (o *Oracle) candleIntervals() map[types.ProviderName]time.Duration {
+	intervals := make(map[types.ProviderName]time.Duration, len(o.endpoints))
+	for providerName, endpoint := range o.endpoints {
+		intervals[providerName] = endpoint.CandleIntervalDuration()
+	}
+	return intervals
+}
+
+// SetVoteAudit sets whether the per-provider breakdown of the voted prices is
+// recorded alongside each vote for off-chain audit.
+func (o *Oracle) SetVoteAudit(enabled bool) {
+	o.voteAudit = enabled
+}
+
+// SetDryRun sets whether the oracle runs without broadcasting transactions.
+// In dry run, prices are computed and the prevotes and votes are built as
+// usual, but they are logged instead of being broadcast.
+func (o *Oracle) SetDryRun(enabled bool) {
+	o.dryRun = enabled
+}
+
+// SetVotingPaused sets whether the oracle skips its prevotes and votes, while
+// still collecting and serving prices. It is safe to call while the oracle is
+// running.
+func (o *Oracle) SetVotingPaused(paused bool) {
+	o.votingPaused.Store(paused)
+}
+
+// IsVotingPaused returns whether the oracle's prevotes and votes are paused.
+func (o *Oracle) IsVotingPaused() bool {
+	return o.votingPaused.Load()
+}
+
+// SetShutdownGracePeriod sets how long the in-flight tick is given to
+// complete its prevote or vote broadcast once the oracle's context is done,
+// before the oracle stops regardless.
+func (o *Oracle) SetShutdownGracePeriod(gracePeriod time.Duration) {
+	o.shutdownGracePeriod = gracePeriod
+}
+
+// SetConversionRawFallback sets whether the rates of the conversion
+// currencies filtered out for deviating fall back to the unfiltered VWAP of
+// their tickers, since stablecoin rates are near-constant and dropping them
+// leaves the pairs quoted in them unconverted.
+func (o *Oracle) SetConversionRawFallback(enabled bool) {
+	o.conversionRawFallback = enabled
+}
+
+// SetConversionRateOverrides sets the fixed USD rates, by currency, used
+// instead of the rates derived from providers when converting prices to USD.
+func (o *Oracle) SetConversionRateOverrides(overrides map[string]sdkmath.LegacyDec) {
+	for base, rate := range overrides {
+		o.logger.Warn().
+			Str("currency", base).
+			Str("rate", rate.String()).
+			Msg("conversion rate override is active")
+	}
+	o.conversionRateOverrides = overrides
+}
+
 // Start starts the oracle process in a blocking fashion.
 func (o *Oracle) Start(ctx context.Context) error {
 	// initialize param cache
@@ -143,17 +542,22 @@ func (o *Oracle) Start(ctx context.Context) error {
 		return err
 	}
 
+	if err := o.loadPreviousPrevote(ctx); err != nil {
+		o.logger.Err(err).Msg("failed to restore previous prevote")
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			o.closer.Close()
+			return nil
 
 		default:
 			o.logger.Debug().Msg("starting oracle tick")
 
 			startTime := time.Now()
 
-			if err := o.tick(ctx); err != nil {
+			if err := o.drainTick(ctx); err != nil {
 				telemetry.IncrCounter(1, "failure", "tick")
 				o.logger.Err(err).Msg("oracle tick failed")
 			}
@@ -163,11 +567,50 @@ func (o *Oracle) Start(ctx context.Context) error {
 			telemetry.MeasureSince(startTime, "runtime", "tick")
 			telemetry.IncrCounter(1, "new", "tick")
 
-			time.Sleep(tickerSleep)
+			select {
+			case <-ctx.Done():
+			case <-time.After(o.tickInterval):
+			}
 		}
 	}
 }
 
+// drainTick runs a tick whose vote is not interrupted by the cancellation of
+// ctx, so that a prevote or vote being broadcast when the oracle is stopped
+// still completes. Once ctx is done, the tick is given the shutdown grace
+// period to complete, after which its broadcast context is cancelled and it is
+// abandoned.
+func (o *Oracle) drainTick(ctx context.Context) error {
+	broadcastCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	o.pendingTicks.Add(1)
+	go func() {
+		defer o.pendingTicks.Done()
+		errCh <- o.tick(ctx, broadcastCtx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+
+	case <-ctx.Done():
+	}
+
+	o.logger.Info().
+		Dur("grace_period", o.shutdownGracePeriod).
+		Msg("shutting down, waiting for the in-flight tick to complete")
+
+	select {
+	case err := <-errCh:
+		return err
+
+	case <-time.After(o.shutdownGracePeriod):
+		return fmt.Errorf("tick did not complete within the shutdown grace period of %s", o.shutdownGracePeriod)
+	}
+}
+
 // Stop stops the oracle process and waits for it to gracefully exit.
 func (o *Oracle) Stop() {
 	o.closer.Close()
@@ -183,6 +626,16 @@ func (o *Oracle) GetLastPriceSyncTimestamp() time.Time {
 	return o.lastPriceSyncTS
 }
 
+// GetFirstVoteTimestamp returns when the first vote was successfully
+// broadcast, or the zero time if no vote has been broadcast yet, as is always
+// the case in dry run.
+func (o *Oracle) GetFirstVoteTimestamp() time.Time {
+	if ts := o.firstVoteTS.Load(); ts != nil {
+		return *ts
+	}
+	return time.Time{}
+}
+
 // GetPrices returns a copy of the current prices fetched from the oracle's
 // set of exchange rate providers.
 func (o *Oracle) GetPrices() types.CurrencyPairDec {
@@ -200,6 +653,60 @@ func (o *Oracle) GetPrices() types.CurrencyPairDec {
 	return prices
 }
 
+// GetPricesSnapshot returns the prices computed in the last call to SetPrices
+// without copying them. The returned map is shared between callers and must
+// not be modified.
+func (o *Oracle) GetPricesSnapshot() types.CurrencyPairDec {
+	if snapshot := o.pricesSnapshot.Load(); snapshot != nil {
+		return *snapshot
+	}
+	return types.CurrencyPairDec{}
+}
+
+// GetStalePrices returns the last known price of every currency pair that was
+// previously computed but is missing from the current prices, along with the
+// time it was last computed at. The pairs of assets removed from the currency
+// pairs are left out.
+func (o *Oracle) GetStalePrices() types.CurrencyPairTimestampedPrices {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	stalePrices := make(types.CurrencyPairTimestampedPrices)
+	for cp, lastPrice := range o.lastPrices {
+		if _, ok := o.prices[cp]; !ok {
+			stalePrices[cp] = lastPrice
+		}
+	}
+
+	return stalePrices
+}
+
+// GetConversionRoutes returns a copy of the conversion routes used to convert
+// each non-USD quoted currency pair to USD during the last price computation.
+func (o *Oracle) GetConversionRoutes() types.CurrencyPairConversionRoutes {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	routes := make(types.CurrencyPairConversionRoutes, len(o.conversionRoutes))
+	for cp, route := range o.conversionRoutes {
+		routes[cp] = append(types.ConversionRoute{}, route...)
+	}
+
+	return routes
+}
+
+// GetProviderPairs returns a copy of the currency pairs each provider is
+// subscribed to, as resolved from the config or the on-chain params and
+// including the reference pairs.
+func (o *Oracle) GetProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	allPairs := o.allProviderPairs()
+	providerPairs := make(map[types.ProviderName][]types.CurrencyPair, len(allPairs))
+	for providerName, currencyPairs := range allPairs {
+		providerPairs[providerName] = append([]types.CurrencyPair{}, currencyPairs...)
+	}
+	return providerPairs
+}
+
 // GetTvwapPrices returns a copy of the tvwapsByProvider map
 func (o *Oracle) GetTvwapPrices() types.CurrencyPairDecByProvider {
 	return o.tvwapsByProvider.GetPricesClone()
@@ -216,20 +723,52 @@ func (o *Oracle) GetVwapPrices() types.CurrencyPairDecByProvider {
 // with VWAP. Warns the the user of any missing prices, and filters out any faulty
 // providers which do not report prices or candles within 2𝜎 of the others.
 func (o *Oracle) SetPrices(ctx context.Context) error {
+	startTime := time.Now()
+	defer telemetry.MeasureSince(startTime, "runtime", "tick", "set_prices")
+
 	g := new(errgroup.Group)
 	mtx := new(sync.Mutex)
 	providerPrices := make(types.AggregatedProviderPrices)
 	providerCandles := make(types.AggregatedProviderCandles)
 	requiredRates := make(map[types.CurrencyPair]struct{})
+	openedCircuits := []types.ProviderName{}
+
+	if o.pairRevalidationInterval > 0 && time.Since(o.lastPairRevalidation) >= o.pairRevalidationInterval &&
+		o.pairRevalidationRunning.CompareAndSwap(false, true) {
+		o.lastPairRevalidation = time.Now()
+		priceProviders := make(map[types.ProviderName]provider.Provider, len(o.priceProviders))
+		for providerName, priceProvider := range o.priceProviders {
+			priceProviders[providerName] = priceProvider
+		}
+		go func() {
+			defer o.pairRevalidationRunning.Store(false)
+			o.revalidateProviderPairs(priceProviders)
+		}()
+	}
 
-	for providerName, currencyPairs := range o.providerPairs {
+	providerPairs := o.allProviderPairs()
+	tickBases := o.nextTickBases(providerPairs)
+	for providerName, currencyPairs := range providerPairs {
 		providerName := providerName
-		currencyPairs := currencyPairs
+		currencyPairs := filterPairsByBase(currencyPairs, tickBases)
+		if len(currencyPairs) == 0 {
+			continue
+		}
+
+		if o.circuitBreaker != nil && !o.circuitBreaker.Allow(providerName) {
+			o.logger.Debug().Str("provider", providerName.String()).Msg("skipping provider with open circuit")
+			continue
+		}
 
 		priceProvider, err := o.getOrSetProvider(ctx, providerName)
 		if err != nil {
 			// If initialization of one of the providers fails, do not cause an oracle tick failure.
 			o.logger.Error().Err(err).Msgf("failed to initialize %s provider", providerName)
+			if o.recordProviderResult(providerName, err) {
+				mtx.Lock()
+				openedCircuits = append(openedCircuits, providerName)
+				mtx.Unlock()
+			}
 			continue
 		}
 
@@ -240,11 +779,26 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 			}
 		}
 
-		g.Go(func() error {
+		if o.skipUnhealthyProvider(providerName, priceProvider) {
+			continue
+		}
+
+		providerTimeout := o.timeoutOf(providerName)
+
+		g.Go(func() (fetchErr error) {
+			defer func() {
+				if o.recordProviderResult(providerName, fetchErr) {
+					mtx.Lock()
+					openedCircuits = append(openedCircuits, providerName)
+					mtx.Unlock()
+				}
+			}()
+
 			prices := make(types.CurrencyPairTickers, 0)
 			candles := make(types.CurrencyPairCandles, 0)
 			ch := make(chan struct{})
 			errCh := make(chan error, 1)
+			startTime := time.Now()
 
 			go func() {
 				defer close(ch)
@@ -263,10 +817,15 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 
 			select {
 			case <-ch:
-				break
+				if o.adaptiveTimeout != nil {
+					o.adaptiveTimeout.Observe(providerName, time.Since(startTime))
+				}
 			case err := <-errCh:
 				return err
-			case <-time.After(o.providerTimeout):
+			case <-time.After(providerTimeout):
+				if o.adaptiveTimeout != nil {
+					o.adaptiveTimeout.Observe(providerName, providerTimeout)
+				}
 				telemetry.IncrCounter(1, "failure", "provider", "type", "timeout")
 				return fmt.Errorf("provider timed out")
 			}
@@ -291,29 +850,614 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		o.logger.Error().Err(err).Msg("failed to get prices from provider")
 	}
 
+	// alert once when every provider goes down, rather than every tick
+	allProvidersDown := len(providerPairs) > 0 && len(providerPrices) == 0 && len(providerCandles) == 0
+	if allProvidersDown && !o.allProvidersDown {
+		o.alert(types.AlertEvent{
+			Type:    types.AlertEventAllProvidersDown,
+			Message: "no provider returned any price",
+		})
+	}
+	o.allProvidersDown = allProvidersDown
+
+	// tear down the providers whose circuit opened, so they are initialized
+	// with new connections once their circuit is half-opened
+	for _, providerName := range openedCircuits {
+		o.removeProvider(providerName)
+	}
+
+	computeStartTime := time.Now()
 	computedPrices, err := o.GetComputedPrices(
 		providerCandles,
 		providerPrices,
 	)
+	telemetry.MeasureSince(computeStartTime, "runtime", "tick", "compute_prices")
 	if err != nil {
 		return err
 	}
 
+	o.filterPriceChanges(computedPrices)
+	carriedPrices := o.carryOverPrices(computedPrices, tickBases)
+
 	for cp := range requiredRates {
 		if _, ok := computedPrices[cp]; !ok {
 			o.logger.Error().Str("asset", cp.String()).Msg("unable to report price for expected asset")
 		}
 	}
 
+	snapshot := make(types.CurrencyPairDec, len(computedPrices))
+	for cp, price := range computedPrices {
+		snapshot[cp] = price
+	}
+
+	now := time.Now()
+	var signedPrices *types.SignedPrices
+	if o.signer != nil {
+		var err error
+		signedPrices, err = o.signPrices(snapshot, now, o.blockHeight.Load())
+		if err != nil {
+			o.logger.Error().Err(err).Msg("failed to sign prices")
+		}
+	}
+
 	o.pricesMutex.Lock()
+	if o.lastPrices == nil {
+		o.lastPrices = make(types.CurrencyPairTimestampedPrices)
+	}
+	pruneLastPrices(o.lastPrices, providerPairs)
+	for cp, price := range computedPrices {
+		if _, ok := carriedPrices[cp]; ok {
+			continue
+		}
+		o.lastPrices[cp] = types.TimestampedPrice{Price: price, Timestamp: now}
+	}
 	o.prices = computedPrices
+	o.pricesSnapshot.Store(&snapshot)
+	o.signedPrices.Store(signedPrices)
+	providerCounts := o.providerCounts
 	o.pricesMutex.Unlock()
-	return nil
+
+	// the gauges are set every tick, so their timestamps reveal stale prices
+	for cp, price := range computedPrices {
+		telemetryExchangeRate(cp, price, providerCounts[cp])
+	}
+
+	o.notifyPriceSubscribers(snapshot)
+	return o.checkRequiredRates(computedPrices)
+}
+
+// nextTickBases returns the bases whose pairs are collected this tick and
+// advances the round-robin cursor over the sorted bases, or nil if every pair
+// is collected. At most maxPairsPerTick distinct pairs are collected: the pairs
+// of the conversion currencies, which the other prices are converted with, are
+// always collected and count towards the limit, then the other bases are added
+// in turn with all of their pairs while these fit within it. The next base is
+// collected even if its pairs alone exceed the limit, so every base is
+// eventually collected.
+func (o *Oracle) nextTickBases(
+	providerPairs map[types.ProviderName][]types.CurrencyPair,
+) map[string]struct{} {
+	if o.maxPairsPerTick <= 0 {
+		return nil
+	}
+
+	conversionBases := make(map[string]struct{})
+	for _, cp := range config.SupportedConversionSlice() {
+		conversionBases[cp.Base] = struct{}{}
+	}
+
+	basePairs := make(map[string]map[types.CurrencyPair]struct{})
+	for _, currencyPairs := range providerPairs {
+		for _, cp := range currencyPairs {
+			if _, ok := basePairs[cp.Base]; !ok {
+				basePairs[cp.Base] = make(map[types.CurrencyPair]struct{})
+			}
+			basePairs[cp.Base][cp] = struct{}{}
+		}
+	}
+
+	var (
+		tickBases  = make(map[string]struct{})
+		bases      = make([]string, 0, len(basePairs))
+		tickPairs  int
+		totalPairs int
+	)
+	for base, pairs := range basePairs {
+		totalPairs += len(pairs)
+		if _, ok := conversionBases[base]; ok {
+			tickBases[base] = struct{}{}
+			tickPairs += len(pairs)
+			continue
+		}
+		bases = append(bases, base)
+	}
+	if totalPairs <= o.maxPairsPerTick || len(bases) == 0 {
+		return nil
+	}
+	sort.Strings(bases)
+
+	collected := 0
+	for ; collected < len(bases); collected++ {
+		base := bases[(o.pairCursor+collected)%len(bases)]
+		if collected > 0 && tickPairs+len(basePairs[base]) > o.maxPairsPerTick {
+			break
+		}
+		tickBases[base] = struct{}{}
+		tickPairs += len(basePairs[base])
+	}
+	o.pairCursor = (o.pairCursor + collected) % len(bases)
+
+	return tickBases
+}
+
+// filterPairsByBase returns the pairs whose base is one of the given bases, or
+// every pair if bases is nil.
+func filterPairsByBase(currencyPairs []types.CurrencyPair, bases map[string]struct{}) []types.CurrencyPair {
+	if bases == nil {
+		return currencyPairs
+	}
+
+	filteredPairs := make([]types.CurrencyPair, 0, len(currencyPairs))
+	for _, cp := range currencyPairs {
+		if _, ok := bases[cp.Base]; ok {
+			filteredPairs = append(filteredPairs, cp)
+		}
+	}
+	return filteredPairs
+}
+
+// pruneLastPrices removes the last prices of the assets which are no longer
+// among the currency pairs of any provider, so removed assets are not reported
+// as stale.
+func pruneLastPrices(
+	lastPrices types.CurrencyPairTimestampedPrices,
+	providerPairs map[types.ProviderName][]types.CurrencyPair,
+) {
+	bases := make(map[string]struct{})
+	for _, currencyPairs := range providerPairs {
+		for _, cp := range currencyPairs {
+			bases[cp.Base] = struct{}{}
+		}
+	}
+	for cp := range lastPrices {
+		if _, ok := bases[cp.Base]; !ok {
+			delete(lastPrices, cp)
+		}
+	}
+}
+
+// carryOverPrices adds the last prices of the bases not collected this tick to
+// the computed prices, and returns the prices carried over. Nothing is carried
+// over if tickBases is nil, since every base is collected.
+func (o *Oracle) carryOverPrices(
+	computedPrices types.CurrencyPairDec,
+	tickBases map[string]struct{},
+) types.CurrencyPairDec {
+	carriedPrices := make(types.CurrencyPairDec)
+	if tickBases == nil {
+		return carriedPrices
+	}
+
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	for cp, price := range o.prices {
+		if _, ok := tickBases[cp.Base]; ok {
+			continue
+		}
+		if _, ok := computedPrices[cp]; !ok {
+			computedPrices[cp] = price
+			carriedPrices[cp] = price
+		}
+	}
+	return carriedPrices
+}
+
+// checkRequiredRates returns an error if none of the required rates were
+// computed, in which case the vote of the period is aborted rather than
+// submitting empty exchange rates. Some missing rates are only logged by
+// SetPrices, since the computed rates are still voted on.
+func (o *Oracle) checkRequiredRates(computedPrices types.CurrencyPairDec) error {
+	requiredRates := o.RequiredRates()
+	if len(requiredRates) == 0 {
+		return nil
+	}
+
+	for _, cp := range requiredRates {
+		if _, ok := computedPrices[cp]; ok {
+			return nil
+		}
+	}
+
+	telemetry.IncrCounter(1, "failure", "prices", "no_required_rates")
+	return fmt.Errorf("none of the %d required rates were computed", len(requiredRates))
+}
+
+// filterPriceChanges acts as a circuit breaker against flash crashes and bad
+// data by dropping the prices which changed by more than the max price change
+// percentage since the previous price computation. The first price of an
+// asset is always kept. Dropped prices are not recorded, so the next prices
+// are still compared to the last price which passed the check, unless the
+// price of the asset was dropped for maxPriceChangeRejections consecutive
+// ticks, in which case the move is considered lasting and the price becomes
+// the new reference.
+func (o *Oracle) filterPriceChanges(prices types.CurrencyPairDec) {
+	if o.maxPriceChangePct.IsNil() || !o.maxPriceChangePct.IsPositive() {
+		return
+	}
+
+	if o.submittedPrices == nil {
+		o.submittedPrices = make(types.CurrencyPairDec)
+		o.priceChangeRejections = make(map[types.CurrencyPair]int)
+	}
+	o.pruneSubmittedPrices()
+
+	for cp, price := range prices {
+		previousPrice, ok := o.submittedPrices[cp]
+		if ok && previousPrice.IsPositive() {
+			changePct := price.Sub(previousPrice).Abs().Quo(previousPrice).MulInt64(100)
+			if changePct.GT(o.maxPriceChangePct) {
+				o.priceChangeRejections[cp]++
+				if o.priceChangeRejections[cp] < maxPriceChangeRejections {
+					o.logger.Warn().
+						Str("asset", cp.String()).
+						Str("price", price.String()).
+						Str("previous_price", previousPrice.String()).
+						Str("change_pct", changePct.String()).
+						Str("max_change_pct", o.maxPriceChangePct.String()).
+						Msg("dropping price which changed too fast since the previous tick")
+					telemetryPriceDropped(cp, "max_price_change")
+					delete(prices, cp)
+					continue
+				}
+
+				o.logger.Warn().
+					Str("asset", cp.String()).
+					Str("price", price.String()).
+					Str("previous_price", previousPrice.String()).
+					Int("rejections", o.priceChangeRejections[cp]).
+					Msg("price kept changing too fast; accepting it as the new price")
+			}
+		}
+		o.submittedPrices[cp] = price
+		delete(o.priceChangeRejections, cp)
+	}
+}
+
+// pruneSubmittedPrices removes the prices of the assets which are no longer
+// required or reference rates from the prices the max price change check
+// compares against.
+func (o *Oracle) pruneSubmittedPrices() {
+	rates := make(map[types.CurrencyPair]struct{})
+	for _, cp := range append(o.RequiredRates(), o.referenceRates()...) {
+		rates[cp] = struct{}{}
+	}
+
+	for cp := range o.submittedPrices {
+		if _, ok := rates[cp]; !ok {
+			delete(o.submittedPrices, cp)
+			delete(o.priceChangeRejections, cp)
+		}
+	}
+}
+
+// skipUnhealthyProvider returns true if the provider reports itself as
+// unhealthy and was already fetched while unhealthy within the last
+// unhealthyProviderRetryInterval, so the tick does not wait for it to time
+// out. Unhealthy providers are still fetched once per interval to retry them.
+func (o *Oracle) skipUnhealthyProvider(providerName types.ProviderName, priceProvider provider.Provider) bool {
+	healthChecker, ok := priceProvider.(provider.HealthChecker)
+	if !ok || healthChecker.IsHealthy() {
+		delete(o.unhealthyProviderRetries, providerName)
+		return false
+	}
+
+	if o.unhealthyProviderRetries == nil {
+		o.unhealthyProviderRetries = make(map[types.ProviderName]time.Time)
+	}
+	if time.Since(o.unhealthyProviderRetries[providerName]) < unhealthyProviderRetryInterval {
+		provider.TelemetryProviderUnhealthySkip(providerName)
+		o.logger.Warn().Str("provider", providerName.String()).Msg("skipping unhealthy provider")
+		return true
+	}
+
+	o.unhealthyProviderRetries[providerName] = time.Now()
+	return false
+}
+
+// SetAlerter sets the alerter notified of the critical events of the oracle,
+// such as missed votes and providers going down.
+func (o *Oracle) SetAlerter(alerter Alerter) {
+	o.alerter = alerter
+}
+
+// SetMinBalance sets the balance of the feeder account below which the low
+// balance alert is sent. The balance is checked after each vote, and is not
+// checked if the min balance is zero.
+func (o *Oracle) SetMinBalance(minBalance sdk.Coin) {
+	o.minBalance = minBalance
+}
+
+// recordProviderResult records the result of fetching a provider in the
+// circuit breaker, if any, and returns true if it opened the circuit of the
+// provider.
+func (o *Oracle) recordProviderResult(providerName types.ProviderName, err error) bool {
+	if o.circuitBreaker == nil {
+		return false
+	}
+
+	if err == nil {
+		if o.circuitBreaker.RecordSuccess(providerName) {
+			telemetryCircuitBreaker(providerName, circuitStateClosed)
+			o.logger.Info().Str("provider", providerName.String()).Msg("provider recovered; circuit closed")
+		}
+		return false
+	}
+
+	if o.circuitBreaker.RecordFailure(providerName) {
+		telemetryCircuitBreaker(providerName, circuitStateOpened)
+		o.logger.Warn().
+			Err(err).
+			Str("provider", providerName.String()).
+			Dur("cooldown", o.circuitBreaker.cooldown).
+			Msg("provider failed repeatedly; circuit opened")
+		o.alert(types.AlertEvent{
+			Type:     types.AlertEventCircuitOpened,
+			Message:  "provider failed repeatedly; circuit opened",
+			Provider: providerName,
+			Error:    err.Error(),
+		})
+		return true
+	}
+	return false
+}
+
+// removeProvider stops the connections of an initialized provider and removes
+// it, so it is initialized again the next time it is fetched.
+func (o *Oracle) removeProvider(providerName types.ProviderName) {
+	if cancel, ok := o.providerCancels[providerName]; ok {
+		cancel()
+		delete(o.providerCancels, providerName)
+	}
+	delete(o.priceProviders, providerName)
+}
+
+// revalidateProviderPairs checks the pairs of the given providers, including
+// their reference pairs and the pairs previously delisted, against the pairs
+// each provider currently has available. The delisted pairs are unsubscribed
+// and no longer queried, while the pairs which are available again are
+// restored and subscribed. It queries the providers' REST APIs, so it runs in
+// the background rather than on the tick.
+func (o *Oracle) revalidateProviderPairs(priceProviders map[types.ProviderName]provider.Provider) {
+	for providerName, priceProvider := range priceProviders {
+		o.providerPairsMtx.RLock()
+		checkedPairs := append([]types.CurrencyPair{}, o.providerPairs[providerName]...)
+		checkedPairs = append(checkedPairs, o.referencePairs[providerName]...)
+		for cp := range o.delistedPairs[providerName] {
+			checkedPairs = append(checkedPairs, cp)
+		}
+		o.providerPairsMtx.RUnlock()
+
+		delistedPairs, err := provider.DelistedPairs(priceProvider, checkedPairs...)
+		if err != nil {
+			o.logger.Warn().Err(err).Str("provider", providerName.String()).Msg("failed to revalidate available pairs")
+			continue
+		}
+
+		delisted := make(map[types.CurrencyPair]struct{}, len(delistedPairs))
+		for _, cp := range delistedPairs {
+			delisted[cp] = struct{}{}
+		}
+		newlyDelisted, relisted := o.updateDelistedPairs(providerName, delisted)
+
+		for _, cp := range newlyDelisted {
+			provider.TelemetryPairDelisted(providerName)
+			o.logger.Error().
+				Str("provider", providerName.String()).
+				Str("currency_pair", cp.String()).
+				Msg("currency pair is no longer available; removing from subscribed pairs")
+		}
+		if len(newlyDelisted) > 0 {
+			priceProvider.UnsubscribeCurrencyPairs(newlyDelisted...)
+		}
+
+		for _, cp := range relisted {
+			o.logger.Info().
+				Str("provider", providerName.String()).
+				Str("currency_pair", cp.String()).
+				Msg("currency pair is available again; restoring to subscribed pairs")
+		}
+		if len(relisted) > 0 {
+			priceProvider.SubscribeCurrencyPairs(relisted...)
+		}
+	}
+}
+
+// updateDelistedPairs records the given pairs as the delisted pairs of a
+// provider, removing them from its pairs, and restores its previously
+// delisted pairs which are available again. It returns the pairs which were
+// just delisted and the pairs which were restored.
+func (o *Oracle) updateDelistedPairs(
+	providerName types.ProviderName,
+	delisted map[types.CurrencyPair]struct{},
+) (newlyDelisted, relisted []types.CurrencyPair) {
+	o.providerPairsMtx.Lock()
+	defer o.providerPairsMtx.Unlock()
+
+	if o.delistedPairs == nil {
+		o.delistedPairs = make(map[types.ProviderName]map[types.CurrencyPair]bool)
+	}
+	previouslyDelisted := o.delistedPairs[providerName]
+
+	// the value of each delisted pair is whether it was one of the provider's
+	// pairs, rather than only a reference pair, so it can be restored
+	nowDelisted := make(map[types.CurrencyPair]bool, len(delisted))
+	subscribedPairs := make([]types.CurrencyPair, 0, len(o.providerPairs[providerName]))
+	for _, cp := range o.providerPairs[providerName] {
+		if _, ok := delisted[cp]; ok {
+			nowDelisted[cp] = true
+			continue
+		}
+		subscribedPairs = append(subscribedPairs, cp)
+	}
+	for cp := range delisted {
+		if _, ok := nowDelisted[cp]; !ok {
+			nowDelisted[cp] = previouslyDelisted[cp]
+		}
+		if _, ok := previouslyDelisted[cp]; !ok {
+			newlyDelisted = append(newlyDelisted, cp)
+		}
+	}
+
+	for cp, providerPair := range previouslyDelisted {
+		if _, ok := delisted[cp]; ok {
+			continue
+		}
+		relisted = append(relisted, cp)
+		if providerPair && !containsPair(subscribedPairs, cp) {
+			subscribedPairs = append(subscribedPairs, cp)
+		}
+	}
+
+	newProviderPairs := make(map[types.ProviderName][]types.CurrencyPair, len(o.providerPairs))
+	for name, currencyPairs := range o.providerPairs {
+		newProviderPairs[name] = currencyPairs
+	}
+	if _, ok := o.providerPairs[providerName]; ok || len(subscribedPairs) > 0 {
+		newProviderPairs[providerName] = subscribedPairs
+	}
+	o.providerPairs = newProviderPairs
+	o.delistedPairs[providerName] = nowDelisted
+
+	return newlyDelisted, relisted
+}
+
+func containsPair(currencyPairs []types.CurrencyPair, cp types.CurrencyPair) bool {
+	for _, pair := range currencyPairs {
+		if pair == cp {
+			return true
+		}
+	}
+	return false
+}
+
+// currentProviderPairs returns the currency pairs of each provider. The map is
+// replaced rather than modified, so it can be read without holding the lock.
+func (o *Oracle) currentProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	o.providerPairsMtx.RLock()
+	defer o.providerPairsMtx.RUnlock()
+	return o.providerPairs
+}
+
+// setProviderPairs replaces the currency pairs of each provider, leaving out
+// the pairs which were delisted by the provider until they are available
+// again.
+func (o *Oracle) setProviderPairs(providerPairs map[types.ProviderName][]types.CurrencyPair) {
+	o.providerPairsMtx.Lock()
+	defer o.providerPairsMtx.Unlock()
+
+	for providerName, delisted := range o.delistedPairs {
+		currencyPairs, ok := providerPairs[providerName]
+		if !ok || len(delisted) == 0 {
+			continue
+		}
+		subscribedPairs := make([]types.CurrencyPair, 0, len(currencyPairs))
+		for _, cp := range currencyPairs {
+			if _, ok := delisted[cp]; ok {
+				delisted[cp] = true
+				continue
+			}
+			subscribedPairs = append(subscribedPairs, cp)
+		}
+		providerPairs[providerName] = subscribedPairs
+	}
+	o.providerPairs = providerPairs
+}
+
+// allProviderPairs returns the currency pairs of each provider, including the
+// reference pairs which were not delisted.
+func (o *Oracle) allProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	o.providerPairsMtx.RLock()
+	defer o.providerPairsMtx.RUnlock()
+	if len(o.referencePairs) == 0 {
+		return o.providerPairs
+	}
+
+	allPairs := make(map[types.ProviderName][]types.CurrencyPair, len(o.providerPairs))
+	for providerName, currencyPairs := range o.providerPairs {
+		allPairs[providerName] = append([]types.CurrencyPair{}, currencyPairs...)
+	}
+	for providerName, currencyPairs := range o.referencePairs {
+		for _, cp := range currencyPairs {
+			if _, ok := o.delistedPairs[providerName][cp]; ok || containsPair(allPairs[providerName], cp) {
+				continue
+			}
+			allPairs[providerName] = append(allPairs[providerName], cp)
+		}
+	}
+
+	return allPairs
+}
+
+// referenceRates returns the USD pairs of the reference pairs which are not
+// also required rates, and thus must not be voted.
+func (o *Oracle) referenceRates() []types.CurrencyPair {
+	requiredRates := make(map[types.CurrencyPair]struct{})
+	for _, cp := range o.RequiredRates() {
+		requiredRates[cp] = struct{}{}
+	}
+
+	referenceRatesMap := make(map[types.CurrencyPair]struct{})
+	for _, currencyPairs := range o.referencePairs {
+		for _, pair := range currencyPairs {
+			usdPair := types.CurrencyPair{Base: pair.Base, Quote: config.DenomUSD}
+			if _, ok := requiredRates[usdPair]; !ok {
+				referenceRatesMap[usdPair] = struct{}{}
+			}
+		}
+	}
+
+	rates := make([]types.CurrencyPair, 0, len(referenceRatesMap))
+	for pair := range referenceRatesMap {
+		rates = append(rates, pair)
+	}
+	return rates
+}
+
+// votePrices returns the given prices without the prices of the reference
+// rates, rounded to the vote precision.
+func (o *Oracle) votePrices(prices types.CurrencyPairDec) types.CurrencyPairDec {
+	referenceRates := o.referenceRates()
+	if len(referenceRates) == 0 && o.votePrecision == 0 {
+		return prices
+	}
+
+	votePrices := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		if o.votePrecision > 0 {
+			// a price too small for the precision is voted as is rather than
+			// rounded to zero
+			if rounded := RoundPrice(price, o.votePrecision, o.roundingMode); !rounded.IsZero() {
+				price = rounded
+			} else {
+				o.logger.Warn().
+					Str("asset", cp.String()).
+					Str("price", price.String()).
+					Uint64("vote_precision", o.votePrecision).
+					Msg("price rounds to zero at the vote precision; voting it unrounded")
+			}
+		}
+		votePrices[cp] = price
+	}
+	for _, cp := range referenceRates {
+		delete(votePrices, cp)
+	}
+	return votePrices
 }
 
 func (o *Oracle) RequiredRates() []types.CurrencyPair {
 	requiredRatesMap := make(map[types.CurrencyPair]struct{})
-	for _, currencyPairs := range o.providerPairs {
+	for _, currencyPairs := range o.currentProviderPairs() {
 		for _, pair := range currencyPairs {
 			usdPair := types.CurrencyPair{Base: pair.Base, Quote: config.DenomUSD}
 			if _, ok := requiredRatesMap[usdPair]; !ok {
@@ -333,33 +1477,168 @@ func (o *Oracle) GetComputedPrices(
 	providerCandles types.AggregatedProviderCandles,
 	providerPrices types.AggregatedProviderPrices,
 ) (types.CurrencyPairDec, error) {
-	conversionRates, err := CalcCurrencyPairRates(
+	// the inputs are kept as they were received, along with the reasons
+	// they were filtered out, for GetPriceDebug
+	rawCandles, rawPrices := providerCandles, providerPrices
+	record := newFilterRecord()
+
+	if o.maxPriceAge > 0 {
+		candles, prices := FilterStalePrices(o.logger, providerCandles, providerPrices, o.maxPriceAge)
+		record.recordFiltered(filterReasonStale, providerCandles, candles, providerPrices, prices)
+		providerCandles, providerPrices = candles, prices
+	}
+	if o.candleGapFillInterval > 0 {
+		providerCandles = FillCandleGaps(
+			providerCandles,
+			o.tvwapLookbacks,
+			o.candleGapFillInterval,
+			o.candleIntervals(),
+		)
+	}
+	if len(o.priceBands) > 0 {
+		candles, prices := FilterPriceBands(o.logger, providerCandles, providerPrices, o.priceBands)
+		record.recordFiltered(filterReasonPriceBand, providerCandles, candles, providerPrices, prices)
+		providerCandles, providerPrices = candles, prices
+	}
+	if len(o.trustedProviders) > 0 {
+		candles, prices := FilterTrustedProviderDeviations(
+			o.logger,
+			providerCandles,
+			providerPrices,
+			o.trustedProviders,
+		)
+		record.recordFiltered(filterReasonTrustedProvider, providerCandles, candles, providerPrices, prices)
+		providerCandles, providerPrices = candles, prices
+	}
+
+	conversionRates, _, err := CalcCurrencyPairRates(
 		providerCandles,
 		providerPrices,
 		o.deviations,
+		o.deviationMultipliers,
+		o.unfilteredPairs,
 		config.SupportedConversionSlice(),
+		o.tickerVolumePolicy,
+		o.providerWeights,
+		o.aggregationMethod,
+		o.tvwapLookbacks,
+		o.tvwapMinPeriod,
+		record,
 		o.logger,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	USDRates := ConvertRatesToUSD(conversionRates)
+	if o.conversionRawFallback {
+		conversionRates = FillRawConversionRates(
+			conversionRates,
+			providerPrices,
+			config.SupportedConversionSlice(),
+			o.tickerVolumePolicy,
+			o.providerWeights,
+		)
+	}
+	if len(o.conversionRateOverrides) > 0 {
+		conversionRates = OverrideConversionRates(conversionRates, o.conversionRateOverrides)
+	}
+
+	USDRates, rateRoutes := ConvertRatesToUSDWithRoutes(conversionRates)
 
 	convertedCandles := ConvertAggregatedCandles(providerCandles, USDRates)
 	convertedTickers := ConvertAggregatedTickers(providerPrices, USDRates)
+	if len(o.priceBands) > 0 {
+		// the prices quoted in other currencies are checked once converted
+		candles, tickers := FilterPriceBands(o.logger, convertedCandles, convertedTickers, o.priceBands)
+		record.recordFiltered(filterReasonPriceBand, convertedCandles, candles, convertedTickers, tickers)
+		convertedCandles, convertedTickers = candles, tickers
+	}
 
-	prices, err := CalcCurrencyPairRates(
+	prices, providerCounts, err := CalcCurrencyPairRates(
 		convertedCandles,
 		convertedTickers,
 		o.deviations,
-		o.RequiredRates(),
+		o.deviationMultipliers,
+		o.unfilteredPairs,
+		append(o.RequiredRates(), o.referenceRates()...),
+		o.tickerVolumePolicy,
+		o.providerWeights,
+		o.aggregationMethod,
+		o.tvwapLookbacks,
+		o.tvwapMinPeriod,
+		record,
 		o.logger,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	for cp, count := range providerCounts {
+		if count < o.minProvidersPerAsset {
+			o.logger.Warn().
+				Str("asset", cp.String()).
+				Int("providers", count).
+				Int("min_providers", o.minProvidersPerAsset).
+				Msg("dropping price derived from too few providers")
+			telemetryPriceDropped(cp, "min_providers")
+			delete(prices, cp)
+		}
+	}
+
+	if len(o.minTotalVolumes) > 0 {
+		totalVolumes := ComputeTotalVolumes(convertedCandles, convertedTickers, record)
+		for cp := range prices {
+			base := strings.ToUpper(cp.Base)
+			minTotalVolume, ok := o.minTotalVolumes[base]
+			if !ok {
+				continue
+			}
+			totalVolume, ok := totalVolumes[base]
+			if !ok {
+				totalVolume = sdkmath.LegacyZeroDec()
+			}
+			if totalVolume.LT(minTotalVolume) {
+				o.logger.Warn().
+					Str("asset", cp.String()).
+					Str("total_volume", totalVolume.String()).
+					Str("min_total_volume", minTotalVolume.String()).
+					Msg("dropping price with too little total volume")
+				telemetryPriceDropped(cp, "min_total_volume")
+				delete(prices, cp)
+			}
+		}
+	}
+
+	tvwapsByProvider, err := ComputeTvwapsByProvider(convertedCandles, o.tvwapLookbacks, o.tvwapMinPeriod)
+	if err != nil {
+		return nil, err
+	}
+	vwapsByProvider := ComputeVwapsByProvider(convertedTickers, o.tickerVolumePolicy)
+	o.tvwapsByProvider.SetPrices(tvwapsByProvider)
+	o.vwapsByProvider.SetPrices(vwapsByProvider)
+
+	divergences := ComputeProviderDivergences(prices, tvwapsByProvider, vwapsByProvider)
+	for providerName, providerDivergences := range divergences {
+		for cp, divergence := range providerDivergences {
+			value, err := divergence.Float64()
+			if err != nil {
+				continue
+			}
+			provider.TelemetryProviderDivergence(providerName, cp, float32(value))
+		}
+	}
+
+	conversionRoutes := ConversionRoutes(providerCandles, providerPrices, rateRoutes)
+	o.pricesMutex.Lock()
+	o.conversionRoutes = conversionRoutes
+	o.aggregatedCandles = rawCandles
+	o.aggregatedTickers = rawPrices
+	o.usdRates = USDRates
+	o.computedPrices = prices
+	o.filterRecord = record
+	o.providerCounts = providerCounts
+	o.pricesMutex.Unlock()
+
 	return prices, nil
 }
 
@@ -402,19 +1681,35 @@ func (o *Oracle) getOrSetProvider(ctx context.Context, providerName types.Provid
 
 	priceProvider, ok = o.priceProviders[providerName]
 	if !ok {
+		// each provider gets its own context, so its connections can be
+		// stopped when it is removed
+		providerCtx, cancel := context.WithCancel(ctx)
 		newProvider, err := NewProvider(
-			ctx,
+			providerCtx,
 			providerName,
 			o.logger,
 			o.endpoints[providerName],
-			o.providerPairs[providerName]...,
+			o.allProviderPairs()[providerName]...,
 		)
 		if err != nil {
+			cancel()
 			return nil, err
 		}
+		// the tickers are skipped at the same age as the candles are
+		if limiter, ok := newProvider.(provider.TickerAgeLimiter); ok {
+			limiter.SetMaxTickerAge(o.maxPriceAge)
+		}
+		// the candles are kept for at least the TVWAP window
+		if retainer, ok := newProvider.(provider.CandleRetainer); ok {
+			retainer.SetMinCandlePeriod(o.tvwapWindow())
+		}
 		newProvider.StartConnections()
 		priceProvider = newProvider
 		o.priceProviders[providerName] = newProvider
+		if o.providerCancels == nil {
+			o.providerCancels = make(map[types.ProviderName]context.CancelFunc)
+		}
+		o.providerCancels[providerName] = cancel
 	}
 
 	return priceProvider, nil
@@ -426,6 +1721,30 @@ func NewProvider(
 	logger zerolog.Logger,
 	endpoint provider.Endpoint,
 	providerPairs ...types.CurrencyPair,
+) (provider.Provider, error) {
+	priceProvider, err := newProvider(ctx, providerName, logger, endpoint, providerPairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.CandleTimestamp != "" {
+		normalizer, ok := priceProvider.(provider.CandleTimestampNormalizer)
+		if !ok {
+			return nil, fmt.Errorf("provider %s does not support candle timestamp normalization", providerName)
+		}
+		normalizer.SetCandleTimestamp(endpoint.CandleTimestamp, endpoint.CandleIntervalDuration())
+	}
+
+	return priceProvider, nil
+}
+
+// newProvider returns the provider of the given name.
+func newProvider(
+	ctx context.Context,
+	providerName types.ProviderName,
+	logger zerolog.Logger,
+	endpoint provider.Endpoint,
+	providerPairs ...types.CurrencyPair,
 ) (provider.Provider, error) {
 	switch providerName {
 	case provider.ProviderBinance:
@@ -446,6 +1765,9 @@ func NewProvider(
 	case provider.ProviderCoinbase:
 		return provider.NewCoinbaseProvider(ctx, logger, endpoint, providerPairs...)
 
+	case provider.ProviderBitso:
+		return provider.NewBitsoProvider(ctx, logger, endpoint, providerPairs...)
+
 	case provider.ProviderOkx:
 		return provider.NewOkxProvider(ctx, logger, endpoint, providerPairs...)
 
@@ -455,6 +1777,12 @@ func NewProvider(
 	case provider.ProviderBitget:
 		return provider.NewBitgetProvider(ctx, logger, endpoint, providerPairs...)
 
+	case provider.ProviderBybit:
+		return provider.NewBybitProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderKuCoin:
+		return provider.NewKuCoinProvider(ctx, logger, endpoint, providerPairs...)
+
 	case provider.ProviderMexc:
 		return provider.NewMexcProvider(ctx, logger, endpoint, providerPairs...)
 
@@ -464,6 +1792,12 @@ func NewProvider(
 	case provider.ProviderPolygon:
 		return provider.NewPolygonProvider(ctx, logger, endpoint, providerPairs...)
 
+	case provider.ProviderDeribit:
+		return provider.NewDeribitProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderBitfinex:
+		return provider.NewBitfinexProvider(ctx, logger, endpoint, providerPairs...)
+
 	case provider.ProviderKujira:
 		return provider.NewKujiraProvider(ctx, logger, endpoint, providerPairs...)
 
@@ -487,6 +1821,12 @@ func NewProvider(
 
 	case provider.ProviderAstroport:
 		return provider.NewAstroportProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderNormalized:
+		return provider.NewNormalizedProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderCoinGecko:
+		return provider.NewCoinGeckoProvider(ctx, logger, endpoint, providerPairs...)
 	}
 
 	return nil, fmt.Errorf("provider %s not found", providerName)
@@ -495,13 +1835,23 @@ func NewProvider(
 // GetParamCache returns the last updated parameters of the x/oracle module
 // if the current ParamCache is outdated or a param update event was found, the cache is updated.
 func (o *Oracle) GetParamCache(ctx context.Context, currentBlockHeight int64) (oracletypes.Params, error) {
-	if !o.ParamCache.IsOutdated(currentBlockHeight) && !o.ParamCache.paramUpdateEvent {
+	if !o.ParamCache.IsOutdated(currentBlockHeight) &&
+		!o.ParamCache.paramUpdateEvent &&
+		!o.ParamCache.IsExpired(o.paramsMaxAge) {
 		return *o.ParamCache.params, nil
 	}
 
 	currentParams := o.ParamCache.params
 	newParams, err := o.GetParams(ctx)
 	if err != nil {
+		if params, ok := o.ParamCache.LastKnownParams(currentBlockHeight, o.paramsFallbackMaxAge); ok {
+			o.logger.Warn().
+				Err(err).
+				Int64("last_updated_block", o.ParamCache.lastUpdatedBlock).
+				Msg("failed to get oracle params; using last known params")
+			telemetry.IncrCounter(1, "params", "fallback")
+			return params, nil
+		}
 		return oracletypes.Params{}, err
 	}
 
@@ -520,29 +1870,67 @@ func (o *Oracle) GetParamCache(ctx context.Context, currentBlockHeight int64) (o
 
 // GetParams returns the current on-chain parameters of the x/oracle module.
 func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
-	//nolint: all
-	grpcConn, err := grpc.Dial(
-		o.oracleClient.GRPCEndpoint,
-		// the Cosmos SDK doesn't support any transport security mechanism
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithContextDialer(dialerFunc),
-	)
+	var queryResponse *oracletypes.QueryParamsResponse
+	err := o.queryGRPC(ctx, func(ctx context.Context, grpcConn *grpc.ClientConn) (err error) {
+		queryResponse, err = oracletypes.NewQueryClient(grpcConn).Params(ctx, &oracletypes.QueryParams{})
+		return err
+	})
 	if err != nil {
-		return oracletypes.Params{}, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+		return oracletypes.Params{}, fmt.Errorf("failed to get x/oracle params: %w", err)
 	}
 
-	defer grpcConn.Close()
-	queryClient := oracletypes.NewQueryClient(grpcConn)
+	return queryResponse.Params, nil
+}
+
+// GetExchangeRates returns the current on-chain exchange rates of the x/oracle
+// module, by symbol denom.
+func (o *Oracle) GetExchangeRates(ctx context.Context) (map[string]sdkmath.LegacyDec, error) {
+	var queryResponse *oracletypes.QueryExchangeRatesResponse
+	err := o.queryGRPC(ctx, func(ctx context.Context, grpcConn *grpc.ClientConn) (err error) {
+		queryResponse, err = oracletypes.NewQueryClient(grpcConn).ExchangeRates(ctx, &oracletypes.QueryExchangeRates{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get x/oracle exchange rates: %w", err)
+	}
 
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-	defer cancel()
+	rates := make(map[string]sdkmath.LegacyDec, len(queryResponse.ExchangeRates))
+	for _, rate := range queryResponse.ExchangeRates {
+		rates[strings.ToUpper(rate.Denom)] = rate.Amount
+	}
+	return rates, nil
+}
 
-	queryResponse, err := queryClient.Params(ctx, &oracletypes.QueryParams{})
+// GetBalance returns the balance of the given address in the given denom.
+func (o *Oracle) GetBalance(ctx context.Context, address, denom string) (sdk.Coin, error) {
+	var queryResponse *banktypes.QueryBalanceResponse
+	err := o.queryGRPC(ctx, func(ctx context.Context, grpcConn *grpc.ClientConn) (err error) {
+		queryResponse, err = banktypes.NewQueryClient(grpcConn).Balance(
+			ctx,
+			&banktypes.QueryBalanceRequest{Address: address, Denom: denom},
+		)
+		return err
+	})
 	if err != nil {
-		return oracletypes.Params{}, fmt.Errorf("failed to get x/oracle params: %w", err)
+		return sdk.Coin{}, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	return queryResponse.Params, nil
+	return *queryResponse.Balance, nil
+}
+
+// nudgePrices clamps the prices to within the vote nudge step of the on-chain
+// rates. The prices are kept as is if the on-chain rates can't be fetched.
+func (o *Oracle) nudgePrices(ctx context.Context, prices types.CurrencyPairDec) types.CurrencyPairDec {
+	if o.voteNudgeStep.IsNil() || !o.voteNudgeStep.IsPositive() {
+		return prices
+	}
+
+	onChainRates, err := o.GetExchangeRates(ctx)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to get on-chain exchange rates; voting prices without nudging")
+		return prices
+	}
+	return NudgePrices(prices, onChainRates, o.voteNudgeStep)
 }
 
 func (o *Oracle) checkAcceptList(params oracletypes.Params) {
@@ -558,7 +1946,9 @@ func (o *Oracle) checkAcceptList(params oracletypes.Params) {
 func (o *Oracle) checkCurrencyPairAndDeviations(currentParams, newParams oracletypes.Params) (err error) {
 	if currentParams.CurrencyPairProviders.String() != newParams.CurrencyPairProviders.String() {
 		o.logger.Debug().Msg("Updating Currency Pair Providers Map")
-		o.providerPairs = CreatePairProvidersFromCurrencyPairProvidersList(newParams.CurrencyPairProviders)
+		previousPairs := o.allProviderPairs()
+		o.setProviderPairs(CreatePairProvidersFromCurrencyPairProvidersList(newParams.CurrencyPairProviders))
+		o.unsubscribeDroppedPairs(previousPairs)
 	}
 	if currentParams.CurrencyDeviationThresholds.String() != newParams.CurrencyDeviationThresholds.String() {
 		o.logger.Debug().Msg("Updating Currency Deviation Thresholds Map")
@@ -571,7 +1961,45 @@ func (o *Oracle) checkCurrencyPairAndDeviations(currentParams, newParams oraclet
 	return nil
 }
 
-func (o *Oracle) tick(ctx context.Context) error {
+// unsubscribeDroppedPairs unsubscribes each provider from the currency pairs
+// it had previously which are no longer among its pairs, including the
+// reference pairs.
+func (o *Oracle) unsubscribeDroppedPairs(previousPairs map[types.ProviderName][]types.CurrencyPair) {
+	currentPairs := o.allProviderPairs()
+
+	for providerName, pairs := range previousPairs {
+		priceProvider, ok := o.priceProviders[providerName]
+		if !ok {
+			continue
+		}
+
+		droppedPairs := []types.CurrencyPair{}
+	OUTER:
+		for _, cp := range pairs {
+			for _, current := range currentPairs[providerName] {
+				if current == cp {
+					continue OUTER
+				}
+			}
+			droppedPairs = append(droppedPairs, cp)
+		}
+
+		if len(droppedPairs) == 0 {
+			continue
+		}
+		o.logger.Info().
+			Str("provider", providerName.String()).
+			Interface("pairs", droppedPairs).
+			Msg("unsubscribing from dropped currency pairs")
+		priceProvider.UnsubscribeCurrencyPairs(droppedPairs...)
+	}
+}
+
+// tick collects the prices and, when due, broadcasts the prevote or vote of the
+// current voting period. The prices are collected with ctx, which lives as
+// long as the oracle and from which the connections of the providers are
+// derived, while the vote is built and broadcast with broadcastCtx.
+func (o *Oracle) tick(ctx, broadcastCtx context.Context) error {
 	o.logger.Debug().Msg("executing oracle tick")
 
 	blockHeight, err := o.oracleClient.ChainHeight.GetChainHeight()
@@ -587,10 +2015,6 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return err
 	}
 
-	if err := o.SetPrices(ctx); err != nil {
-		return err
-	}
-
 	// Get oracle vote period, next block height, current vote period, and index
 	// in the vote period.
 	oracleVotePeriod := util.SafeUint64ToInt64(oracleParams.VotePeriod)
@@ -600,8 +2024,26 @@ func (o *Oracle) tick(ctx context.Context) error {
 
 	// Skip until new voting period. Specifically, skip when:
 	// index [0, oracleVotePeriod - 1] > oracleVotePeriod - 2 OR index is 0
-	if (o.previousVotePeriod != 0 && currentVotePeriod == o.previousVotePeriod) ||
-		oracleVotePeriod-indexInVotePeriod < 2 {
+	voteDue := (o.previousVotePeriod == 0 || currentVotePeriod != o.previousVotePeriod) &&
+		oracleVotePeriod-indexInVotePeriod >= 2
+
+	o.blockHeight.Store(blockHeight)
+	if err := o.SetPrices(ctx); err != nil {
+		if voteDue {
+			o.recordMissedVote(currentVotePeriod, blockHeight, o.priceFailureReason(), err)
+		}
+		return err
+	}
+
+	if o.IsVotingPaused() {
+		o.logger.Info().Int64("block_height", blockHeight).Msg("voting paused; skipping prevote and vote")
+		if voteDue {
+			o.recordMissedVote(currentVotePeriod, blockHeight, types.MissedVoteReasonVotingPaused, nil)
+		}
+		return nil
+	}
+
+	if !voteDue {
 		o.logger.Info().
 			Int64("vote_period", oracleVotePeriod).
 			Float64("previous_vote_period", o.previousVotePeriod).
@@ -620,9 +2062,11 @@ func (o *Oracle) tick(ctx context.Context) error {
 			Float64("current_vote_period", currentVotePeriod).
 			Msg("missing vote during voting period")
 		telemetry.IncrCounter(1, "vote", "failure", "missed")
+		o.recordMissedVote(o.previousVotePeriod+1, blockHeight, types.MissedVoteReasonVotePeriodMissed, nil)
 
 		o.previousVotePeriod = 0
 		o.previousPrevote = nil
+		o.removePreviousPrevote()
 		return nil
 	}
 
@@ -636,16 +2080,31 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return err
 	}
 
-	exchangeRatesStr := GenerateExchangeRatesString(o.prices)
+	votePrices := o.votePrices(o.nudgePrices(broadcastCtx, o.prices))
+	exchangeRatesStr := GenerateExchangeRatesString(votePrices)
+	if o.dryRun {
+		// logged as is so it can be diffed against the rates of a live feeder
+		o.logger.Info().
+			Str("exchange_rates", exchangeRatesStr).
+			Msg("dry run; computed exchange rates")
+	}
 	hash := oracletypes.GetAggregateVoteHash(salt, exchangeRatesStr, valAddr)
 	preVoteMsg := &oracletypes.MsgAggregateExchangeRatePrevote{
 		Hash:      hash.String(), // hash of prices from the oracle
-		Feeder:    o.oracleClient.OracleAddrString,
+		Feeder:    o.oracleClient.FeederAddrString(),
 		Validator: valAddr.String(),
 	}
 
 	isPrevoteOnlyTx := o.previousPrevote == nil
 	if isPrevoteOnlyTx {
+		// the audit is taken along with the voted prices, from the same
+		// price computation
+		var audit *types.VoteAudit
+		if o.voteAudit {
+			voteAudit := o.GetVoteAudit(votePrices)
+			audit = &voteAudit
+		}
+
 		// This timeout could be as small as oracleVotePeriod-indexInVotePeriod,
 		// but we give it some extra time just in case.
 		//
@@ -655,9 +2114,11 @@ func (o *Oracle) tick(ctx context.Context) error {
 			Str("validator", preVoteMsg.Validator).
 			Str("feeder", preVoteMsg.Feeder).
 			Msg("broadcasting pre-vote")
-		if err := o.oracleClient.BroadcastTx(nextBlockHeight, oracleVotePeriod*2, preVoteMsg); err != nil {
+		if err := o.broadcastTx(nextBlockHeight, oracleVotePeriod*2, preVoteMsg); err != nil {
+			o.recordMissedVote(currentVotePeriod, blockHeight, types.MissedVoteReasonBroadcastFailure, err)
 			return err
 		}
+		o.recordBroadcast()
 
 		currentHeight, err := o.oracleClient.ChainHeight.GetChainHeight()
 		if err != nil {
@@ -669,13 +2130,15 @@ func (o *Oracle) tick(ctx context.Context) error {
 			Salt:              salt,
 			ExchangeRates:     exchangeRatesStr,
 			SubmitBlockHeight: currentHeight,
+			Audit:             audit,
 		}
+		o.persistPreviousPrevote()
 	} else {
 		// otherwise, we're in the next voting period and thus we vote
 		voteMsg := &oracletypes.MsgAggregateExchangeRateVote{
 			Salt:          o.previousPrevote.Salt,
 			ExchangeRates: o.previousPrevote.ExchangeRates,
-			Feeder:        o.oracleClient.OracleAddrString,
+			Feeder:        o.oracleClient.FeederAddrString(),
 			Validator:     valAddr.String(),
 		}
 
@@ -684,21 +2147,84 @@ func (o *Oracle) tick(ctx context.Context) error {
 			Str("validator", voteMsg.Validator).
 			Str("feeder", voteMsg.Feeder).
 			Msg("broadcasting vote")
-		if err := o.oracleClient.BroadcastTx(
+		if err := o.broadcastTx(
 			nextBlockHeight,
 			oracleVotePeriod-indexInVotePeriod,
 			voteMsg,
 		); err != nil {
+			o.recordMissedVote(currentVotePeriod, blockHeight, types.MissedVoteReasonBroadcastFailure, err)
 			return err
 		}
+		o.recordBroadcast()
+		o.checkBalance(broadcastCtx)
+		// nothing was broadcast in dry run, so the feeder is not ready
+		if !o.dryRun {
+			now := time.Now()
+			o.firstVoteTS.CompareAndSwap(nil, &now)
+		}
+		if voteMsg.ExchangeRates == "" {
+			o.recordMissedVote(currentVotePeriod, blockHeight, types.MissedVoteReasonEmptyPrices, nil)
+		}
+
+		if o.previousPrevote.Audit != nil {
+			o.logger.Info().
+				Interface("audit", o.previousPrevote.Audit).
+				Str("validator", voteMsg.Validator).
+				Msg("vote audit")
+		}
 
 		o.previousPrevote = nil
 		o.previousVotePeriod = 0
+		o.removePreviousPrevote()
 	}
 
 	return nil
 }
 
+// priceFailureReason returns why the prices could not be set: because the
+// circuit of every provider was open, or because of another failure.
+func (o *Oracle) priceFailureReason() types.MissedVoteReason {
+	providerPairs := o.currentProviderPairs()
+	if o.circuitBreaker == nil || len(providerPairs) == 0 {
+		return types.MissedVoteReasonPriceFailure
+	}
+	for providerName := range providerPairs {
+		if !o.circuitBreaker.IsOpen(providerName) {
+			return types.MissedVoteReasonPriceFailure
+		}
+	}
+	return types.MissedVoteReasonCircuitOpen
+}
+
+// broadcastTx broadcasts the messages and measures the time spent doing so.
+// If the transaction was rejected, its code and raw log are logged and counted.
+// In dry run, the messages are logged instead.
+func (o *Oracle) broadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) error {
+	if o.dryRun {
+		o.logger.Info().
+			Int64("next_block_height", nextBlockHeight).
+			Interface("msgs", msgs).
+			Msg("dry run; skipping broadcast")
+		return nil
+	}
+
+	startTime := time.Now()
+	defer telemetry.MeasureSince(startTime, "runtime", "tick", "broadcast")
+
+	resp, err := o.broadcast(nextBlockHeight, timeoutHeight, msgs...)
+	if resp != nil && resp.Code != 0 {
+		telemetryTxRejected(resp.Codespace, resp.Code)
+		o.logger.Error().
+			Uint32("tx_code", resp.Code).
+			Str("tx_codespace", resp.Codespace).
+			Str("tx_hash", resp.TxHash).
+			Str("raw_log", resp.RawLog).
+			Interface("msgs", msgs).
+			Msg("tx rejected")
+	}
+	return err
+}
+
 func (o *Oracle) TickClientless(ctx context.Context) error {
 	o.logger.Debug().Msg("executing clientless oracle tick")
 
@@ -1,15 +1,27 @@
 package oracle
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	oracletypes "github.com/ojo-network/ojo/x/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/ojo-network/price-feeder/oracle/client"
 	"github.com/ojo-network/price-feeder/oracle/provider"
@@ -48,12 +60,15 @@ func (m mockProvider) GetTickerPrices(_ ...types.CurrencyPair) (types.CurrencyPa
 }
 
 func (m mockProvider) GetCandlePrices(_ ...types.CurrencyPair) (types.CurrencyPairCandles, error) {
+	// the candles are dated to the second, so the candles of the providers
+	// queried in the same tick are weighted alike
+	timestamp := time.Now().Add(-time.Minute).Truncate(time.Second).UnixMilli()
 	candles := make(types.CurrencyPairCandles)
 	for pair, price := range m.prices {
 		candles[pair] = []types.CandlePrice{
 			{
 				Price:     price.Price,
-				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+				TimeStamp: timestamp,
 				Volume:    price.Volume,
 			},
 		}
@@ -63,6 +78,8 @@ func (m mockProvider) GetCandlePrices(_ ...types.CurrencyPair) (types.CurrencyPa
 
 func (m mockProvider) SubscribeCurrencyPairs(...types.CurrencyPair) {}
 
+func (m mockProvider) UnsubscribeCurrencyPairs(...types.CurrencyPair) {}
+
 func (m mockProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return map[string]struct{}{}, nil
 }
@@ -83,10 +100,55 @@ func (m failingProvider) GetCandlePrices(_ ...types.CurrencyPair) (types.Currenc
 
 func (m failingProvider) SubscribeCurrencyPairs(...types.CurrencyPair) {}
 
+func (m failingProvider) UnsubscribeCurrencyPairs(...types.CurrencyPair) {}
+
 func (m failingProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return map[string]struct{}{}, nil
 }
 
+type delistingProvider struct {
+	mockProvider
+	availablePairs map[string]struct{}
+	subscribed     *[]types.CurrencyPair
+	unsubscribed   *[]types.CurrencyPair
+}
+
+func (m delistingProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return m.availablePairs, nil
+}
+
+func (m delistingProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	*m.subscribed = append(*m.subscribed, cps...)
+}
+
+func (m delistingProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	*m.unsubscribed = append(*m.unsubscribed, cps...)
+}
+
+type unhealthyProvider struct {
+	mockProvider
+	calls *atomic.Int32
+}
+
+func (m unhealthyProvider) IsHealthy() bool {
+	return false
+}
+
+func (m unhealthyProvider) GetTickerPrices(pairs ...types.CurrencyPair) (types.CurrencyPairTickers, error) {
+	m.calls.Add(1)
+	time.Sleep(time.Second)
+	return m.mockProvider.GetTickerPrices(pairs...)
+}
+
+type unsubscribingProvider struct {
+	mockProvider
+	unsubscribed *[]types.CurrencyPair
+}
+
+func (m unsubscribingProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	*m.unsubscribed = append(*m.unsubscribed, cps...)
+}
+
 type OracleTestSuite struct {
 	suite.Suite
 
@@ -131,6 +193,7 @@ func (ots *OracleTestSuite) SetupSuite() {
 			},
 		},
 		time.Millisecond*100,
+		defaultTickInterval,
 		make(map[string]math.LegacyDec),
 		make(map[types.ProviderName]provider.Endpoint),
 		false,
@@ -372,447 +435,1910 @@ func (ots *OracleTestSuite) TestPrices() {
 	ots.Require().Equal(math.LegacyMustNewDecFromStr("3.717"), prices[XBTUSD])
 	ots.Require().Equal(math.LegacyMustNewDecFromStr("1"), prices[USDCUSD])
 	ots.Require().Equal(math.LegacyMustNewDecFromStr("1"), prices[USDTUSD])
+	ots.Require().Equal(prices, ots.oracle.GetPricesSnapshot())
 }
 
-func TestGenerateSalt(t *testing.T) {
-	salt, err := GenerateSalt(0)
-	require.Error(t, err)
-	require.Empty(t, salt)
+func TestNewTickInterval(t *testing.T) {
+	o := New(zerolog.Nop(), client.OracleClient{}, nil, 0, 250*time.Millisecond, nil, nil, false)
+	require.Equal(t, 250*time.Millisecond, o.tickInterval)
 
-	salt, err = GenerateSalt(32)
-	require.NoError(t, err)
-	require.NotEmpty(t, salt)
+	o = New(zerolog.Nop(), client.OracleClient{}, nil, 0, 0, nil, nil, false)
+	require.Equal(t, defaultTickInterval, o.tickInterval)
 }
 
-func TestGenerateExchangeRatesString(t *testing.T) {
-	testCases := map[string]struct {
-		input    types.CurrencyPairDec
-		expected string
-	}{
-		"empty input": {
-			input:    make(types.CurrencyPairDec),
-			expected: "",
-		},
-		"single denom": {
-			input: types.CurrencyPairDec{
-				OJOUSD: math.LegacyMustNewDecFromStr("3.72"),
-			},
-			expected: "OJO:3.720000000000000000",
+func TestPricesSnapshot(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
 		},
-		"multi denom": {
-			input: types.CurrencyPairDec{
-				OJOUSD:  math.LegacyMustNewDecFromStr("3.72"),
-				ATOMUSD: math.LegacyMustNewDecFromStr("40.13"),
-				OSMOUSD: math.LegacyMustNewDecFromStr("8.69"),
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	require.Empty(t, o.GetPricesSnapshot())
+
+	setPrice := func(price string) {
+		o.priceProviders = map[types.ProviderName]provider.Provider{
+			provider.ProviderBinance: mockProvider{
+				prices: types.CurrencyPairTickers{
+					OJOUSD: {
+						Price:  math.LegacyMustNewDecFromStr(price),
+						Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+					},
+				},
 			},
-			expected: "ATOM:40.130000000000000000,OJO:3.720000000000000000,OSMO:8.690000000000000000",
-		},
+		}
+		require.NoError(t, o.SetPrices(context.TODO()))
 	}
 
-	for name, tc := range testCases {
-		tc := tc
+	setPrice("3.72")
+	snapshot := o.GetPricesSnapshot()
+	require.Equal(t, o.GetPrices(), snapshot)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), snapshot[OJOUSD])
 
-		t.Run(name, func(t *testing.T) {
-			out := GenerateExchangeRatesString(tc.input)
-			require.Equal(t, tc.expected, out)
-		})
-	}
+	// the snapshot served previously is not modified by the next tick
+	setPrice("3.80")
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), snapshot[OJOUSD])
+	require.Equal(t, o.GetPrices(), o.GetPricesSnapshot())
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.80"), o.GetPricesSnapshot()[OJOUSD])
 }
 
-func TestSuccessSetProviderTickerPricesAndCandles(t *testing.T) {
-	providerPrices := make(types.AggregatedProviderPrices, 1)
-	providerCandles := make(types.AggregatedProviderCandles, 1)
-	pair := types.CurrencyPair{
-		Base:  "ATOM",
-		Quote: "USDT",
-	}
-
-	atomPrice := math.LegacyMustNewDecFromStr("29.93")
-	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+type countingSigner struct {
+	privKey *secp256k1.PrivKey
+	calls   *int
+}
 
-	prices := make(types.CurrencyPairTickers, 1)
-	prices[pair] = types.TickerPrice{
-		Price:  atomPrice,
-		Volume: atomVolume,
-	}
+func (s countingSigner) Sign(bz []byte) ([]byte, cryptotypes.PubKey, error) {
+	*s.calls++
+	sig, err := s.privKey.Sign(bz)
+	return sig, s.privKey.PubKey(), err
+}
 
-	candles := make(types.CurrencyPairCandles, 1)
-	candles[pair] = []types.CandlePrice{
-		{
-			Price:     atomPrice,
-			Volume:    atomVolume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+func TestSignedPrices(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
 		},
-	}
-
-	success := SetProviderTickerPricesAndCandles(
-		provider.ProviderGate,
-		providerPrices,
-		providerCandles,
-		prices,
-		candles,
-		pair,
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
 	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+				},
+			},
+		},
+	}
 
-	require.True(t, success, "It should successfully set the prices")
-	require.Equal(t, atomPrice, providerPrices[provider.ProviderGate][pair].Price)
-	require.Equal(t, atomPrice, providerCandles[provider.ProviderGate][pair][0].Price)
+	// the prices are not signed without a signer
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Nil(t, o.GetSignedPrices())
+
+	privKey := secp256k1.GenPrivKey()
+	calls := 0
+	o.SetSigner(countingSigner{privKey: privKey, calls: &calls})
+	o.blockHeight.Store(42)
+	require.NoError(t, o.SetPrices(context.TODO()))
+
+	// the snapshot is signed once per price computation, however many times
+	// it is served
+	signed := o.GetSignedPrices()
+	require.NotNil(t, signed)
+	require.Equal(t, signed, o.GetSignedPrices())
+	require.Equal(t, 1, calls)
+
+	pubKey := &secp256k1.PubKey{Key: signed.PubKey}
+	require.Equal(t, privKey.PubKey().Bytes(), signed.PubKey)
+	require.True(t, pubKey.VerifySignature(signed.Snapshot, signed.Signature))
+
+	var snapshot types.PricesSnapshot
+	require.NoError(t, json.Unmarshal(signed.Snapshot, &snapshot))
+	require.Equal(t, o.GetPricesSnapshot(), snapshot.Prices)
+	require.Equal(t, int64(42), snapshot.Height)
+	require.WithinDuration(t, time.Now(), time.UnixMilli(snapshot.Timestamp), time.Minute)
+
+	// the signature doesn't cover a tampered snapshot
+	snapshot.Height = 43
+	bz, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	require.False(t, pubKey.VerifySignature(bz, signed.Signature))
 }
 
-func TestFailedSetProviderTickerPricesAndCandles(t *testing.T) {
-	success := SetProviderTickerPricesAndCandles(
-		provider.ProviderCoinbase,
-		make(types.AggregatedProviderPrices, 1),
-		make(types.AggregatedProviderCandles, 1),
-		make(types.CurrencyPairTickers, 1),
-		make(types.CurrencyPairCandles, 1),
-		types.CurrencyPair{
-			Base:  "ATOM",
-			Quote: "USDT",
+func TestQueryGRPCFailover(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{
+			GRPCEndpoint:  "primary:9090",
+			GRPCFallbacks: []string{"fallback:9090"},
 		},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
 	)
 
-	require.False(t, success, "It should failed to set the prices, prices and candle are empty")
+	// an unreachable endpoint falls back on the next one
+	var queried []string
+	err := o.queryGRPC(context.TODO(), func(_ context.Context, grpcConn *grpc.ClientConn) error {
+		queried = append(queried, grpcConn.Target())
+		if grpcConn.Target() == "primary:9090" {
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"primary:9090", "fallback:9090"}, queried)
+
+	// while an error returned by the node is returned as is
+	queried = nil
+	err = o.queryGRPC(context.TODO(), func(_ context.Context, grpcConn *grpc.ClientConn) error {
+		queried = append(queried, grpcConn.Target())
+		return status.Error(codes.NotFound, "not found")
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, []string{"primary:9090"}, queried)
 }
 
-func (ots *OracleTestSuite) TestSuccessGetComputedPricesCandles() {
-	providerCandles := make(types.AggregatedProviderCandles, 1)
-	pair := types.CurrencyPair{
-		Base:  "ATOM",
-		Quote: "USD",
+func TestSetPricesExchangeRateGauges(t *testing.T) {
+	metrics, err := telemetry.New(telemetry.Config{Enabled: true, ServiceName: "price-feeder"})
+	require.NoError(t, err)
+	defer func() {
+		_, err := telemetry.New(telemetry.Config{Enabled: false})
+		require.NoError(t, err)
+	}()
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD, ATOMUSD},
+			provider.ProviderKraken:  {ATOMUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	volume := math.LegacyMustNewDecFromStr("2396974.02000000")
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD:  {Price: math.LegacyMustNewDecFromStr("3.72"), Volume: volume},
+				ATOMUSD: {Price: math.LegacyMustNewDecFromStr("11.1"), Volume: volume},
+			},
+		},
+		provider.ProviderKraken: mockProvider{
+			prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: math.LegacyMustNewDecFromStr("11.1"), Volume: volume},
+			},
+		},
 	}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	prices := o.GetPrices()
+	require.Len(t, prices, 2)
 
-	atomPrice := math.LegacyMustNewDecFromStr("29.93")
-	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+	gr, err := metrics.Gather(telemetry.FormatDefault)
+	require.NoError(t, err)
 
-	candles := make(types.CurrencyPairCandles, 1)
-	candles[pair] = []types.CandlePrice{
-		{
-			Price:     atomPrice,
-			Volume:    atomVolume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
-		},
+	var summary struct {
+		Gauges []struct {
+			Name   string
+			Value  float32
+			Labels map[string]string
+		}
+	}
+	require.NoError(t, json.Unmarshal(gr.Metrics, &summary))
+
+	exchangeRates := make(map[string]float32)
+	providerCounts := make(map[string]float32)
+	for _, gauge := range summary.Gauges {
+		switch gauge.Name {
+		case "price-feeder.exchange_rate":
+			exchangeRates[gauge.Labels["base"]] = gauge.Value
+		case "price-feeder.exchange_rate.providers":
+			providerCounts[gauge.Labels["base"]] = gauge.Value
+		}
 	}
-	providerCandles[provider.ProviderBinance] = candles
 
-	providerPair := map[types.ProviderName][]types.CurrencyPair{
-		provider.ProviderBinance: {pair},
+	for cp, price := range prices {
+		value, err := price.Float64()
+		require.NoError(t, err)
+		require.Equal(t, float32(value), exchangeRates[cp.Base])
 	}
-	ots.oracle.providerPairs = providerPair
+	require.Equal(t, float32(1), providerCounts["OJO"])
+	require.Equal(t, float32(2), providerCounts["ATOM"])
+}
 
-	prices, err := ots.oracle.GetComputedPrices(
-		providerCandles,
-		make(types.AggregatedProviderPrices, 1),
+func TestSetPricesPhaseMetrics(t *testing.T) {
+	metrics, err := telemetry.New(telemetry.Config{Enabled: true, ServiceName: "price-feeder"})
+	require.NoError(t, err)
+	defer func() {
+		_, err := telemetry.New(telemetry.Config{Enabled: false})
+		require.NoError(t, err)
+	}()
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
 	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+				},
+			},
+		},
+	}
+	require.NoError(t, o.SetPrices(context.TODO()))
 
-	require.NoError(ots.T(), err, "It should successfully get computed candle prices")
-	require.Equal(ots.T(), prices[pair], atomPrice)
+	gr, err := metrics.Gather(telemetry.FormatDefault)
+	require.NoError(t, err)
+	require.Contains(t, string(gr.Metrics), "price-feeder.runtime.tick.set_prices")
+	require.Contains(t, string(gr.Metrics), "price-feeder.runtime.tick.compute_prices")
 }
 
-func (ots *OracleTestSuite) TestSuccessGetComputedPricesTickers() {
-	providerPrices := make(types.AggregatedProviderPrices, 1)
-	pair := types.CurrencyPair{
-		Base:  "ATOM",
-		Quote: "USD",
-	}
-
-	atomPrice := math.LegacyMustNewDecFromStr("29.93")
-	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+func TestSetPricesSkipsUnhealthyProvider(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+			provider.ProviderKraken:  {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
 
-	tickerPrices := make(types.CurrencyPairTickers, 1)
-	tickerPrices[pair] = types.TickerPrice{
-		Price:  atomPrice,
-		Volume: atomVolume,
+	prices := types.CurrencyPairTickers{
+		OJOUSD: {
+			Price:  math.LegacyMustNewDecFromStr("3.72"),
+			Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+		},
 	}
-	providerPrices[provider.ProviderBinance] = tickerPrices
-
-	providerPair := map[types.ProviderName][]types.CurrencyPair{
-		provider.ProviderBinance: {pair},
+	calls := new(atomic.Int32)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: prices},
+		provider.ProviderKraken:  unhealthyProvider{mockProvider: mockProvider{prices: prices}, calls: calls},
 	}
-	ots.oracle.providerPairs = providerPair
 
-	prices, err := ots.oracle.GetComputedPrices(
-		make(types.AggregatedProviderCandles, 1),
-		providerPrices,
-	)
+	// the unhealthy provider is retried on the first tick and times out
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Equal(t, int32(1), calls.Load())
 
-	require.NoError(ots.T(), err, "It should successfully get computed ticker prices")
-	require.Equal(ots.T(), prices[pair], atomPrice)
+	// it is then skipped without waiting for the provider timeout
+	startTime := time.Now()
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Less(t, time.Since(startTime), 100*time.Millisecond)
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
 }
 
-func (ots *OracleTestSuite) TestGetComputedPricesCandlesConversion() {
-	btcPair := types.CurrencyPair{
-		Base:  "BTC",
-		Quote: "ETH",
+func TestSetPricesCircuitBreaker(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+			provider.ProviderKraken:  {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetCircuitBreaker(NewCircuitBreaker(2, 50*time.Millisecond))
+
+	prices := types.CurrencyPairTickers{
+		OJOUSD: {
+			Price:  math.LegacyMustNewDecFromStr("3.72"),
+			Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+		},
 	}
-	btcUSDPair := types.CurrencyPair{
-		Base:  "BTC",
-		Quote: "USD",
+	providerCtx, cancel := context.WithCancel(context.Background())
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: prices},
+		provider.ProviderKraken:  failingProvider{prices: prices},
 	}
-	ethPair := types.CurrencyPair{
-		Base:  "ETH",
-		Quote: "USD",
+	o.providerCancels = map[types.ProviderName]context.CancelFunc{
+		provider.ProviderKraken: cancel,
 	}
-	btcEthPrice := math.LegacyMustNewDecFromStr("17.55")
-	btcUSDPrice := math.LegacyMustNewDecFromStr("20962.601")
-	ethUsdPrice := math.LegacyMustNewDecFromStr("1195.02")
+
+	// the failing provider is kept after its first failure
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Contains(t, o.priceProviders, provider.ProviderKraken)
+	require.NoError(t, providerCtx.Err())
+
+	// its circuit opens on the second failure, tearing it down
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.NotContains(t, o.priceProviders, provider.ProviderKraken)
+	require.NotContains(t, o.providerCancels, provider.ProviderKraken)
+	require.Error(t, providerCtx.Err())
+
+	// it is skipped rather than initialized again during the cooldown
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.NotContains(t, o.priceProviders, provider.ProviderKraken)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+
+	// once half-opened, the recovered provider closes its circuit
+	time.Sleep(50 * time.Millisecond)
+	o.priceProviders[provider.ProviderKraken] = mockProvider{prices: prices}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.True(t, o.circuitBreaker.Allow(provider.ProviderKraken))
+	require.NotContains(t, o.circuitBreaker.circuits, provider.ProviderKraken)
+}
+
+func TestSetPricesRequiredRates(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD, ATOMUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	require.ElementsMatch(t, []types.CurrencyPair{OJOUSD, ATOMUSD}, o.RequiredRates())
+
+	volume := math.LegacyMustNewDecFromStr("2396974.02000000")
+	prices := types.CurrencyPairTickers{
+		OJOUSD: {Price: math.LegacyMustNewDecFromStr("3.72"), Volume: volume},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: prices},
+	}
+
+	// some of the required rates are missing, so the computed ones proceed
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Len(t, o.GetPrices(), 1)
+
+	// none of the required rates are computed, which aborts the vote
+	delete(prices, OJOUSD)
+	err := o.SetPrices(context.TODO())
+	require.ErrorContains(t, err, "none of the 2 required rates were computed")
+	require.Empty(t, o.GetPrices())
+}
+
+func TestSetPricesMaxPairsPerTick(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD, XBTUSD, ATOMUSD},
+			provider.ProviderKraken:  {fooUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetMaxPairsPerTick(3)
+
+	volume := math.LegacyMustNewDecFromStr("2396974.02000000")
+	prices := types.CurrencyPairTickers{
+		OJOUSD:  {Price: math.LegacyMustNewDecFromStr("3.72"), Volume: volume},
+		XBTUSD:  {Price: math.LegacyMustNewDecFromStr("60000"), Volume: volume},
+		ATOMUSD: {Price: math.LegacyMustNewDecFromStr("10"), Volume: volume},
+		fooUSD:  {Price: math.LegacyMustNewDecFromStr("1.5"), Volume: volume},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: prices},
+		provider.ProviderKraken:  mockProvider{prices: prices},
+	}
+
+	// the bases FOO, OJO and XBT are collected round-robin, two per tick,
+	// while the pair of ATOM, a conversion currency, is collected every tick
+	// and counts towards the limit of three pairs
+	for _, expectedBases := range [][]string{{"FOO", "OJO"}, {"FOO", "XBT"}, {"OJO", "XBT"}} {
+		tickBases := o.nextTickBases(o.allProviderPairs())
+		require.Contains(t, tickBases, "ATOM")
+		require.Len(t, tickBases, 3)
+		collectedBases := []string{}
+		for _, base := range []string{"FOO", "OJO", "XBT"} {
+			if _, ok := tickBases[base]; ok {
+				collectedBases = append(collectedBases, base)
+			}
+		}
+		require.Equal(t, expectedBases, collectedBases)
+	}
+	require.Equal(t, 0, o.pairCursor)
+
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Len(t, o.GetPrices(), 3)
+	require.NotContains(t, o.GetPrices(), XBTUSD)
+
+	// the prices of the bases not collected are carried over
+	prices[OJOUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("4"), Volume: volume}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Len(t, o.GetPrices(), 4)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Equal(t, math.LegacyMustNewDecFromStr("4"), o.GetPrices()[OJOUSD])
+}
+
+func TestNextTickBasesCountsPairs(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	o := &Oracle{maxPairsPerTick: 3}
+	providerPairs := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {ATOMUSD, OJOUSD, ojoUSDT},
+		provider.ProviderKraken:  {ATOMUSD, fooUSD},
+	}
+
+	// the two pairs of OJO don't fit next to the pairs of ATOM and FOO, so
+	// they are collected on their own the next tick
+	require.Equal(t, map[string]struct{}{"ATOM": {}, "FOO": {}}, o.nextTickBases(providerPairs))
+	require.Equal(t, map[string]struct{}{"ATOM": {}, "OJO": {}}, o.nextTickBases(providerPairs))
+	require.Equal(t, 0, o.pairCursor)
+}
+
+func TestPruneLastPrices(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	lastPrices := types.CurrencyPairTimestampedPrices{
+		ATOMUSD: {Price: math.LegacyOneDec(), Timestamp: time.Now()},
+		fooUSD:  {Price: math.LegacyOneDec(), Timestamp: time.Now()},
+	}
+	providerPairs := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {ATOMUSD},
+	}
+
+	// FOO was removed from the pairs, so it is no longer reported as stale
+	pruneLastPrices(lastPrices, providerPairs)
+	require.Len(t, lastPrices, 1)
+	require.Contains(t, lastPrices, ATOMUSD)
+}
+
+func TestSetPricesMaxPriceChange(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD, ATOMUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetMaxPriceChangePct(math.LegacyNewDec(10))
+
+	volume := math.LegacyMustNewDecFromStr("2396974.02000000")
+	prices := types.CurrencyPairTickers{
+		OJOUSD:  {Price: math.LegacyMustNewDecFromStr("10"), Volume: volume},
+		ATOMUSD: {Price: math.LegacyMustNewDecFromStr("10"), Volume: volume},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: prices},
+	}
+
+	// the first prices of each asset are always allowed
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Len(t, o.GetPrices(), 2)
+
+	// a 60% jump is dropped while a 5% move passes
+	prices[OJOUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("16"), Volume: volume}
+	prices[ATOMUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("10.5"), Volume: volume}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.NotContains(t, o.GetPrices(), OJOUSD)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.5"), o.GetPrices()[ATOMUSD])
+
+	// the dropped price isn't recorded, so prices are compared to the last
+	// price which passed the check
+	prices[OJOUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("10.9"), Volume: volume}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.9"), o.GetPrices()[OJOUSD])
+
+	// a lasting move is accepted once it was dropped for enough ticks in a row
+	prices[OJOUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("20"), Volume: volume}
+	for i := 1; i < maxPriceChangeRejections; i++ {
+		require.NoError(t, o.SetPrices(context.TODO()))
+		require.NotContains(t, o.GetPrices(), OJOUSD)
+	}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Equal(t, math.LegacyMustNewDecFromStr("20"), o.GetPrices()[OJOUSD])
+
+	prices[OJOUSD] = types.TickerPrice{Price: math.LegacyMustNewDecFromStr("21"), Volume: volume}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.Equal(t, math.LegacyMustNewDecFromStr("21"), o.GetPrices()[OJOUSD])
+}
+
+func TestGetComputedPricesMinProviders(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {ATOMUSD, OJOUSD},
+			provider.ProviderKraken:  {ATOMUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+
+	ticker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("29.93"),
+		Volume: math.LegacyMustNewDecFromStr("894123.00"),
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {ATOMUSD: ticker, OJOUSD: ticker},
+		provider.ProviderKraken:  {ATOMUSD: ticker},
+	}
+
+	// a single provider is enough by default
+	o.SetMinProvidersPerAsset(1)
+	prices, err := o.GetComputedPrices(make(types.AggregatedProviderCandles), providerPrices)
+	require.NoError(t, err)
+	require.Contains(t, prices, ATOMUSD)
+	require.Contains(t, prices, OJOUSD)
+
+	// prices derived from a single provider are dropped and not voted
+	o.SetMinProvidersPerAsset(2)
+	prices, err = o.GetComputedPrices(make(types.AggregatedProviderCandles), providerPrices)
+	require.NoError(t, err)
+	require.Equal(t, ticker.Price, prices[ATOMUSD])
+	require.NotContains(t, prices, OJOUSD)
+	require.NotContains(t, GenerateExchangeRatesString(prices), "OJO")
+}
+
+func TestGetComputedPricesMinTotalVolumes(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {ATOMUSD, OJOUSD},
+			provider.ProviderKraken:  {ATOMUSD, OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+
+	atomTicker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("29.93"),
+		Volume: math.LegacyMustNewDecFromStr("600"),
+	}
+	ojoTicker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("0.1"),
+		Volume: math.LegacyMustNewDecFromStr("400"),
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {ATOMUSD: atomTicker, OJOUSD: ojoTicker},
+		provider.ProviderKraken:  {ATOMUSD: atomTicker, OJOUSD: ojoTicker},
+	}
+
+	// ATOM sums 1200 across its providers, OJO only 800
+	o.SetMinTotalVolumes(map[string]math.LegacyDec{
+		"ATOM": math.LegacyMustNewDecFromStr("1000"),
+		"OJO":  math.LegacyMustNewDecFromStr("1000"),
+	})
+	prices, err := o.GetComputedPrices(make(types.AggregatedProviderCandles), providerPrices)
+	require.NoError(t, err)
+	require.Equal(t, atomTicker.Price, prices[ATOMUSD])
+	require.NotContains(t, prices, OJOUSD)
+
+	// currencies without a minimum are never dropped for their volume
+	o.SetMinTotalVolumes(map[string]math.LegacyDec{"ATOM": math.LegacyMustNewDecFromStr("1000")})
+	prices, err = o.GetComputedPrices(make(types.AggregatedProviderCandles), providerPrices)
+	require.NoError(t, err)
+	require.Contains(t, prices, OJOUSD)
+
+	// the volume of a deviating provider is left out, and the candles of a
+	// provider which only supplies candles are counted
+	providerPrices[provider.ProviderOkx] = types.CurrencyPairTickers{
+		ATOMUSD: {Price: math.LegacyMustNewDecFromStr("100"), Volume: math.LegacyMustNewDecFromStr("5000")},
+	}
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderGate: {
+			OJOUSD: {{
+				Price:     ojoTicker.Price,
+				Volume:    math.LegacyMustNewDecFromStr("300"),
+				TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+			}},
+		},
+	}
+	o.SetMinTotalVolumes(map[string]math.LegacyDec{
+		"ATOM": math.LegacyMustNewDecFromStr("1300"),
+		"OJO":  math.LegacyMustNewDecFromStr("1100"),
+	})
+	prices, err = o.GetComputedPrices(providerCandles, providerPrices)
+	require.NoError(t, err)
+	require.NotContains(t, prices, ATOMUSD)
+	require.Contains(t, prices, OJOUSD)
+}
+
+func TestReferencePairs(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetReferencePairs(map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderKraken: {ATOMUSD},
+	})
+
+	ticker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("3.72"),
+		Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: types.CurrencyPairTickers{OJOUSD: ticker}},
+		provider.ProviderKraken:  mockProvider{prices: types.CurrencyPairTickers{ATOMUSD: ticker}},
+	}
+	require.NoError(t, o.SetPrices(context.TODO()))
+
+	// the reference pair is computed and served, but not required nor voted
+	prices := o.GetPrices()
+	require.Equal(t, ticker.Price, prices[ATOMUSD])
+	require.Equal(t, ticker.Price, prices[OJOUSD])
+	require.NotContains(t, o.RequiredRates(), ATOMUSD)
+	require.Equal(t, "OJO:3.720000000000000000", GenerateExchangeRatesString(o.votePrices(prices)))
+}
+
+func TestVotePricesPrecision(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetVotePrecision(6, types.RoundingModeHalfUp)
+
+	// the price rounding to zero is voted unrounded
+	votePrices := o.votePrices(types.CurrencyPairDec{
+		ATOMUSD: math.LegacyMustNewDecFromStr("9.1234567"),
+		OJOUSD:  math.LegacyMustNewDecFromStr("0.0000001"),
+	})
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.123457"), votePrices[ATOMUSD])
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.0000001"), votePrices[OJOUSD])
+}
+
+func newBenchmarkPricesOracle(numPrices int) *Oracle {
+	o := &Oracle{}
+	prices := make(types.CurrencyPairDec, numPrices)
+	for i := 0; i < numPrices; i++ {
+		prices[types.CurrencyPair{Base: fmt.Sprintf("ASSET%d", i), Quote: "USD"}] = math.LegacyNewDec(int64(i))
+	}
+	o.prices = prices
+	o.pricesSnapshot.Store(&prices)
+	return o
+}
+
+func BenchmarkGetPrices(b *testing.B) {
+	o := newBenchmarkPricesOracle(500)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = o.GetPrices()
+		}
+	})
+}
+
+func BenchmarkGetPricesSnapshot(b *testing.B) {
+	o := newBenchmarkPricesOracle(500)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = o.GetPricesSnapshot()
+		}
+	})
+}
+
+func TestTimeoutOf(t *testing.T) {
+	o := &Oracle{providerTimeout: time.Second}
+	o.SetProviderTimeouts(map[types.ProviderName]time.Duration{provider.ProviderOsmosis: 3 * time.Second})
+	require.Equal(t, time.Second, o.timeoutOf(provider.ProviderBinance))
+	require.Equal(t, 3*time.Second, o.timeoutOf(provider.ProviderOsmosis))
+
+	// the explicit timeout of a provider is the floor of its adaptive timeout
+	o.SetAdaptiveTimeout(NewAdaptiveTimeout(10, 0, 100*time.Millisecond, 2*time.Second))
+	require.Equal(t, 2*time.Second, o.timeoutOf(provider.ProviderBinance))
+	require.Equal(t, 3*time.Second, o.timeoutOf(provider.ProviderOsmosis))
+}
+
+func TestGenerateSalt(t *testing.T) {
+	salt, err := GenerateSalt(0)
+	require.Error(t, err)
+	require.Empty(t, salt)
+
+	salt, err = GenerateSalt(32)
+	require.NoError(t, err)
+	require.NotEmpty(t, salt)
+}
+
+func TestGenerateExchangeRatesString(t *testing.T) {
+	testCases := map[string]struct {
+		input    types.CurrencyPairDec
+		expected string
+	}{
+		"empty input": {
+			input:    make(types.CurrencyPairDec),
+			expected: "",
+		},
+		"single denom": {
+			input: types.CurrencyPairDec{
+				OJOUSD: math.LegacyMustNewDecFromStr("3.72"),
+			},
+			expected: "OJO:3.720000000000000000",
+		},
+		"multi denom": {
+			input: types.CurrencyPairDec{
+				OJOUSD:  math.LegacyMustNewDecFromStr("3.72"),
+				ATOMUSD: math.LegacyMustNewDecFromStr("40.13"),
+				OSMOUSD: math.LegacyMustNewDecFromStr("8.69"),
+			},
+			expected: "ATOM:40.130000000000000000,OJO:3.720000000000000000,OSMO:8.690000000000000000",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			out := GenerateExchangeRatesString(tc.input)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestSuccessSetProviderTickerPricesAndCandles(t *testing.T) {
+	providerPrices := make(types.AggregatedProviderPrices, 1)
+	providerCandles := make(types.AggregatedProviderCandles, 1)
+	pair := types.CurrencyPair{
+		Base:  "ATOM",
+		Quote: "USDT",
+	}
+
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+
+	prices := make(types.CurrencyPairTickers, 1)
+	prices[pair] = types.TickerPrice{
+		Price:  atomPrice,
+		Volume: atomVolume,
+	}
+
+	candles := make(types.CurrencyPairCandles, 1)
+	candles[pair] = []types.CandlePrice{
+		{
+			Price:     atomPrice,
+			Volume:    atomVolume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+
+	success := SetProviderTickerPricesAndCandles(
+		provider.ProviderGate,
+		providerPrices,
+		providerCandles,
+		prices,
+		candles,
+		pair,
+	)
+
+	require.True(t, success, "It should successfully set the prices")
+	require.Equal(t, atomPrice, providerPrices[provider.ProviderGate][pair].Price)
+	require.Equal(t, atomPrice, providerCandles[provider.ProviderGate][pair][0].Price)
+}
+
+func TestFailedSetProviderTickerPricesAndCandles(t *testing.T) {
+	success := SetProviderTickerPricesAndCandles(
+		provider.ProviderCoinbase,
+		make(types.AggregatedProviderPrices, 1),
+		make(types.AggregatedProviderCandles, 1),
+		make(types.CurrencyPairTickers, 1),
+		make(types.CurrencyPairCandles, 1),
+		types.CurrencyPair{
+			Base:  "ATOM",
+			Quote: "USDT",
+		},
+	)
+
+	require.False(t, success, "It should failed to set the prices, prices and candle are empty")
+}
+
+func (ots *OracleTestSuite) TestSuccessGetComputedPricesCandles() {
+	providerCandles := make(types.AggregatedProviderCandles, 1)
+	pair := types.CurrencyPair{
+		Base:  "ATOM",
+		Quote: "USD",
+	}
+
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+
+	candles := make(types.CurrencyPairCandles, 1)
+	candles[pair] = []types.CandlePrice{
+		{
+			Price:     atomPrice,
+			Volume:    atomVolume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	providerCandles[provider.ProviderBinance] = candles
+
+	providerPair := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {pair},
+	}
+	ots.oracle.providerPairs = providerPair
+
+	prices, err := ots.oracle.GetComputedPrices(
+		providerCandles,
+		make(types.AggregatedProviderPrices, 1),
+	)
+
+	require.NoError(ots.T(), err, "It should successfully get computed candle prices")
+	require.Equal(ots.T(), prices[pair], atomPrice)
+}
+
+func (ots *OracleTestSuite) TestSuccessGetComputedPricesTickers() {
+	providerPrices := make(types.AggregatedProviderPrices, 1)
+	pair := types.CurrencyPair{
+		Base:  "ATOM",
+		Quote: "USD",
+	}
+
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+
+	tickerPrices := make(types.CurrencyPairTickers, 1)
+	tickerPrices[pair] = types.TickerPrice{
+		Price:  atomPrice,
+		Volume: atomVolume,
+	}
+	providerPrices[provider.ProviderBinance] = tickerPrices
+
+	providerPair := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {pair},
+	}
+	ots.oracle.providerPairs = providerPair
+
+	prices, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles, 1),
+		providerPrices,
+	)
+
+	require.NoError(ots.T(), err, "It should successfully get computed ticker prices")
+	require.Equal(ots.T(), prices[pair], atomPrice)
+}
+
+func (ots *OracleTestSuite) TestGetComputedPricesCandlesConversion() {
+	btcPair := types.CurrencyPair{
+		Base:  "BTC",
+		Quote: "ETH",
+	}
+	btcUSDPair := types.CurrencyPair{
+		Base:  "BTC",
+		Quote: "USD",
+	}
+	ethPair := types.CurrencyPair{
+		Base:  "ETH",
+		Quote: "USD",
+	}
+	btcEthPrice := math.LegacyMustNewDecFromStr("17.55")
+	btcUSDPrice := math.LegacyMustNewDecFromStr("20962.601")
+	ethUsdPrice := math.LegacyMustNewDecFromStr("1195.02")
 	volume := math.LegacyMustNewDecFromStr("894123.00")
 	providerCandles := make(types.AggregatedProviderCandles)
 
-	// normal rates
-	binanceCandles := make(types.CurrencyPairCandles)
-	binanceCandles[btcPair] = []types.CandlePrice{
+	// normal rates
+	binanceCandles := make(types.CurrencyPairCandles)
+	binanceCandles[btcPair] = []types.CandlePrice{
+		{
+			Price:     btcEthPrice,
+			Volume:    volume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	binanceCandles[ethPair] = []types.CandlePrice{
+		{
+			Price:     ethUsdPrice,
+			Volume:    volume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	providerCandles[provider.ProviderBinance] = binanceCandles
+
+	// normal rates
+	gateCandles := make(types.CurrencyPairCandles)
+	gateCandles[ethPair] = []types.CandlePrice{
+		{
+			Price:     ethUsdPrice,
+			Volume:    volume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	providerCandles[provider.ProviderGate] = gateCandles
+
+	// abnormal eth rate
+	okxCandles := make(types.CurrencyPairCandles)
+	okxCandles[ethPair] = []types.CandlePrice{
+		{
+			Price:     math.LegacyMustNewDecFromStr("1.0"),
+			Volume:    volume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	providerCandles[provider.ProviderOkx] = okxCandles
+
+	// btc / usd rate
+	krakenCandles := make(types.CurrencyPairCandles)
+	krakenCandles[btcUSDPair] = []types.CandlePrice{
+		{
+			Price:     btcUSDPrice,
+			Volume:    volume,
+			TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
+		},
+	}
+	providerCandles[provider.ProviderKraken] = krakenCandles
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {btcPair, ethPair},
+		provider.ProviderGate:    {ethPair},
+		provider.ProviderOkx:     {ethPair},
+		provider.ProviderKraken:  {btcUSDPair},
+	}
+	ots.oracle.deviations = map[string]math.LegacyDec{
+		"BTC": math.LegacyMustNewDecFromStr("1"),
+	}
+
+	prices, err := ots.oracle.GetComputedPrices(
+		providerCandles,
+		make(types.AggregatedProviderPrices),
+	)
+
+	require.NoError(ots.T(), err,
+		"It should successfully filter out bad candles and convert everything to USD",
+	)
+
+	btcUsdRate := btcEthPrice.Mul(ethUsdPrice)
+	require.Equal(ots.T(),
+		btcUsdRate.Add(btcUSDPrice).Quo(math.LegacyMustNewDecFromStr("2")),
+		prices[BTCUSD],
+	)
+}
+
+func (ots *OracleTestSuite) TestGetComputedPricesTickersConversion() {
+	volume := math.LegacyMustNewDecFromStr("881272.00")
+	btcEthPrice := math.LegacyMustNewDecFromStr("72.55")
+	ethUsdPrice := math.LegacyMustNewDecFromStr("9989.02")
+	btcUSDPrice := math.LegacyMustNewDecFromStr("724603.401")
+	providerPrices := make(types.AggregatedProviderPrices, 1)
+
+	// normal rates
+	binanceTickerPrices := make(types.CurrencyPairTickers, 2)
+	binanceTickerPrices[BTCETH] = types.TickerPrice{
+		Price:  btcEthPrice,
+		Volume: volume,
+	}
+	binanceTickerPrices[ETHUSD] = types.TickerPrice{
+		Price:  ethUsdPrice,
+		Volume: volume,
+	}
+	providerPrices[provider.ProviderBinance] = binanceTickerPrices
+
+	// normal rates
+	gateTickerPrices := make(types.CurrencyPairTickers, 4)
+	gateTickerPrices[ETHUSD] = types.TickerPrice{
+		Price:  ethUsdPrice,
+		Volume: volume,
+	}
+	providerPrices[provider.ProviderGate] = gateTickerPrices
+
+	// abnormal eth rate
+	okxTickerPrices := make(types.CurrencyPairTickers, 1)
+	okxTickerPrices[ETHUSD] = types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("1.0"),
+		Volume: volume,
+	}
+	providerPrices[provider.ProviderOkx] = okxTickerPrices
+
+	// btc / usd rate
+	krakenTickerPrices := make(types.CurrencyPairTickers, 1)
+	krakenTickerPrices[BTCUSD] = types.TickerPrice{
+		Price:  btcUSDPrice,
+		Volume: volume,
+	}
+	providerPrices[provider.ProviderKraken] = krakenTickerPrices
+
+	providerPair := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {ETHUSD, BTCETH},
+		provider.ProviderGate:    {ETHUSD},
+		provider.ProviderOkx:     {ETHUSD},
+		provider.ProviderKraken:  {BTCUSD},
+	}
+	ots.oracle.providerPairs = providerPair
+
+	prices, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles, 1),
+		providerPrices,
+	)
+
+	require.NoError(ots.T(), err,
+		"It should successfully filter out bad tickers and convert everything to USD",
+	)
+	require.Equal(ots.T(),
+		ethUsdPrice.Mul(
+			btcEthPrice).Add(btcUSDPrice).Quo(math.LegacyMustNewDecFromStr("2")),
+		prices[BTCUSD],
+	)
+}
+
+func (ots *OracleTestSuite) TestGetComputedPricesEmptyTvwap() {
+	symbolUSDT := "USDT"
+	symbolUSD := "USD"
+	symbolDAI := "DAI"
+	symbolETH := "ETH"
+
+	pairETHtoUSDT := types.CurrencyPair{
+		Base:  symbolETH,
+		Quote: symbolUSDT,
+	}
+	pairETHtoDAI := types.CurrencyPair{
+		Base:  symbolETH,
+		Quote: symbolDAI,
+	}
+	pairETHtoUSD := types.CurrencyPair{
+		Base:  symbolETH,
+		Quote: symbolUSD,
+	}
+	basePairsETH := []types.CurrencyPair{
+		pairETHtoUSDT,
+		pairETHtoDAI,
+	}
+	krakenPairsETH := append(basePairsETH, pairETHtoUSD)
+
+	pairUSDTtoUSD := types.CurrencyPair{
+		Base:  symbolUSDT,
+		Quote: symbolUSD,
+	}
+	pairDAItoUSD := types.CurrencyPair{
+		Base:  symbolDAI,
+		Quote: symbolUSD,
+	}
+	stablecoinPairs := []types.CurrencyPair{
+		pairUSDTtoUSD,
+		pairDAItoUSD,
+	}
+
+	krakenPairs := append(krakenPairsETH, stablecoinPairs...)
+
+	volume := math.LegacyMustNewDecFromStr("881272.00")
+	ethUsdPrice := math.LegacyMustNewDecFromStr("9989.02")
+	daiUsdPrice := math.LegacyMustNewDecFromStr("999890000000000000")
+	ethTime := provider.PastUnixTimeMillis(1 * time.Minute)
+
+	ethCandle := []types.CandlePrice{
 		{
-			Price:     btcEthPrice,
+			Price:     ethUsdPrice,
 			Volume:    volume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			TimeStamp: ethTime,
 		},
-	}
-	binanceCandles[ethPair] = []types.CandlePrice{
 		{
 			Price:     ethUsdPrice,
 			Volume:    volume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			TimeStamp: ethTime,
 		},
 	}
-	providerCandles[provider.ProviderBinance] = binanceCandles
-
-	// normal rates
-	gateCandles := make(types.CurrencyPairCandles)
-	gateCandles[ethPair] = []types.CandlePrice{
+	daiCandle := []types.CandlePrice{
 		{
-			Price:     ethUsdPrice,
+			Price:     daiUsdPrice,
 			Volume:    volume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			TimeStamp: 1660829520000,
 		},
 	}
-	providerCandles[provider.ProviderGate] = gateCandles
 
-	// abnormal eth rate
-	okxCandles := make(types.CurrencyPairCandles)
-	okxCandles[ethPair] = []types.CandlePrice{
-		{
-			Price:     math.LegacyMustNewDecFromStr("1.0"),
-			Volume:    volume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+	prices := types.AggregatedProviderPrices{}
+
+	pairs := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderKraken: krakenPairs,
+	}
+
+	testCases := map[string]struct {
+		candles   types.AggregatedProviderCandles
+		prices    types.AggregatedProviderPrices
+		pairs     map[types.ProviderName][]types.CurrencyPair
+		numPrices int
+	}{
+		"Empty tvwap": {
+			candles: types.AggregatedProviderCandles{
+				provider.ProviderKraken: {
+					USDTUSD: ethCandle,
+					ETHUSD:  ethCandle,
+					DAIUSD:  daiCandle,
+				},
+			},
+			prices:    prices,
+			pairs:     pairs,
+			numPrices: 2,
+		},
+		"No valid conversion rates DAI": {
+			candles: types.AggregatedProviderCandles{
+				provider.ProviderKraken: {
+					USDTUSD: ethCandle,
+					ETHUSD:  ethCandle,
+				},
+			},
+			prices:    prices,
+			pairs:     pairs,
+			numPrices: 2,
 		},
 	}
-	providerCandles[provider.ProviderOkx] = okxCandles
 
-	// btc / usd rate
-	krakenCandles := make(types.CurrencyPairCandles)
-	krakenCandles[btcUSDPair] = []types.CandlePrice{
+	for name, tc := range testCases {
+		tc := tc
+
+		ots.Run(name, func() {
+			ots.oracle.providerPairs = tc.pairs
+			prices, _ := ots.oracle.GetComputedPrices(
+				tc.candles,
+				tc.prices,
+			)
+			require.Equal(ots.T(), tc.numPrices, len(prices))
+		})
+	}
+}
+
+func TestRevalidateProviderPairs(t *testing.T) {
+	availablePairs := map[string]struct{}{
+		"OJOUSDT": {},
+		"OJOUSDC": {},
+		"XBTUSDT": {},
+	}
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT, OJOUSDC},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.SetReferencePairs(map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {XBTUSDT},
+	})
+	var subscribed, unsubscribed []types.CurrencyPair
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: delistingProvider{
+			availablePairs: availablePairs,
+			subscribed:     &subscribed,
+			unsubscribed:   &unsubscribed,
+		},
+	}
+
+	o.revalidateProviderPairs(o.priceProviders)
+	require.Equal(t, []types.CurrencyPair{OJOUSDT, OJOUSDC}, o.currentProviderPairs()[provider.ProviderBinance])
+	require.Empty(t, unsubscribed)
+
+	// the delisted pairs are removed from the subscribed pairs and
+	// unsubscribed, including the reference pairs
+	delete(availablePairs, "OJOUSDC")
+	delete(availablePairs, "XBTUSDT")
+	o.revalidateProviderPairs(o.priceProviders)
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.currentProviderPairs()[provider.ProviderBinance])
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.allProviderPairs()[provider.ProviderBinance])
+	require.ElementsMatch(t, []types.CurrencyPair{OJOUSDC, XBTUSDT}, unsubscribed)
+
+	// reloading the pairs doesn't restore a delisted pair
+	o.setProviderPairs(map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {OJOUSDT, OJOUSDC},
+	})
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.currentProviderPairs()[provider.ProviderBinance])
+
+	// the pairs which are listed again are restored and subscribed
+	availablePairs["OJOUSDC"] = struct{}{}
+	availablePairs["XBTUSDT"] = struct{}{}
+	o.revalidateProviderPairs(o.priceProviders)
+	require.Equal(t, []types.CurrencyPair{OJOUSDT, OJOUSDC}, o.currentProviderPairs()[provider.ProviderBinance])
+	require.Equal(t, []types.CurrencyPair{OJOUSDT, OJOUSDC, XBTUSDT}, o.allProviderPairs()[provider.ProviderBinance])
+	require.ElementsMatch(t, []types.CurrencyPair{OJOUSDC, XBTUSDT}, subscribed)
+	require.Len(t, unsubscribed, 2)
+}
+
+func (ots *OracleTestSuite) TestGetPriceDebug() {
+	pair := types.CurrencyPair{
+		Base:  "ATOM",
+		Quote: "USD",
+	}
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+	atomTicker := types.TickerPrice{
+		Price:  atomPrice,
+		Volume: atomVolume,
+	}
+
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {pair: atomTicker},
+		provider.ProviderHuobi:   {pair: atomTicker},
+		provider.ProviderKraken:  {pair: atomTicker},
+		provider.ProviderCoinbase: {
+			pair: {
+				Price:  math.LegacyMustNewDecFromStr("27.1"),
+				Volume: atomVolume,
+			},
+		},
+	}
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance:  {pair},
+		provider.ProviderHuobi:    {pair},
+		provider.ProviderKraken:   {pair},
+		provider.ProviderCoinbase: {pair},
+	}
+
+	_, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles),
+		providerPrices,
+	)
+	ots.Require().NoError(err)
+
+	priceDebug, err := ots.oracle.GetPriceDebug("ATOM")
+	ots.Require().NoError(err)
+	ots.Require().Equal("ATOM", priceDebug.Base)
+	ots.Require().Len(priceDebug.Providers, 4)
+	ots.Require().Nil(priceDebug.TVWAP)
+	ots.Require().NotNil(priceDebug.VWAP)
+	ots.Require().Equal(atomPrice, *priceDebug.VWAP)
+	ots.Require().NotNil(priceDebug.Price)
+	ots.Require().Equal(atomPrice, *priceDebug.Price)
+
+	for _, p := range priceDebug.Providers {
+		ots.Require().Equal(pair, p.Pair)
+		ots.Require().NotNil(p.Ticker)
+		ots.Require().NotNil(p.ConvertedTicker)
+
+		if p.Provider == provider.ProviderCoinbase {
+			ots.Require().Equal(filterReasonDeviation, p.TickerFilterReason)
+		} else {
+			ots.Require().Empty(p.TickerFilterReason)
+		}
+	}
+}
+
+func (ots *OracleTestSuite) TestGetPriceDebugRecordedFilters() {
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+	atomTicker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("29.93"),
+		Volume: atomVolume,
+	}
+
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {ATOMUSD: atomTicker},
+		provider.ProviderHuobi:   {ATOMUSD: atomTicker},
+		provider.ProviderKraken: {
+			ATOMUSD: {Price: math.LegacyMustNewDecFromStr("27.1"), Volume: atomVolume},
+		},
+		provider.ProviderCoinbase: {
+			ATOMUSD: {Price: math.LegacyMustNewDecFromStr("100"), Volume: atomVolume},
+		},
+	}
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance:  {ATOMUSD},
+		provider.ProviderHuobi:    {ATOMUSD},
+		provider.ProviderKraken:   {ATOMUSD},
+		provider.ProviderCoinbase: {ATOMUSD},
+	}
+
+	// coinbase is outside of the price band, and kraken deviates but the pair
+	// is not filtered by deviation
+	ots.oracle.SetPriceBands(map[string]types.PriceBand{
+		"ATOM": {Min: math.LegacyMustNewDecFromStr("1"), Max: math.LegacyMustNewDecFromStr("50")},
+	})
+	ots.oracle.SetUnfilteredPairs(map[types.CurrencyPair]struct{}{ATOMUSD: {}})
+	defer func() {
+		ots.oracle.SetPriceBands(nil)
+		ots.oracle.SetUnfilteredPairs(nil)
+	}()
+
+	_, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles),
+		providerPrices,
+	)
+	ots.Require().NoError(err)
+
+	priceDebug, err := ots.oracle.GetPriceDebug("ATOM")
+	ots.Require().NoError(err)
+	ots.Require().Len(priceDebug.Providers, 4)
+
+	for _, p := range priceDebug.Providers {
+		if p.Provider == provider.ProviderCoinbase {
+			ots.Require().Equal(filterReasonPriceBand, p.TickerFilterReason)
+		} else {
+			ots.Require().Empty(p.TickerFilterReason)
+		}
+	}
+
+	deviatingProviders, err := ots.oracle.GetDeviatingProviders(ATOMUSD)
+	ots.Require().NoError(err)
+	ots.Require().Empty(deviatingProviders)
+}
+
+func TestGetDeviatingProvidersNonUSDQuote(t *testing.T) {
+	ticker := types.TickerPrice{
+		Price:  math.LegacyMustNewDecFromStr("3.72"),
+		Volume: math.LegacyOneDec(),
+	}
+
+	o := &Oracle{
+		aggregatedTickers: types.AggregatedProviderPrices{
+			provider.ProviderBinance: {OJOUSDT: ticker},
+			provider.ProviderHuobi:   {OJOUSDT: ticker},
+			provider.ProviderKraken:  {OJOUSD: ticker},
+		},
+		filterRecord: newFilterRecord(),
+	}
+	// the pairs quoted in USDT are filtered by deviation once converted
+	o.filterRecord.recordTicker(filterReasonDeviation, provider.ProviderHuobi, OJOUSD)
+	o.filterRecord.recordTicker(filterReasonDeviation, provider.ProviderKraken, OJOUSD)
+
+	deviatingProviders, err := o.GetDeviatingProviders(OJOUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.ProviderName{provider.ProviderHuobi}, deviatingProviders)
+
+	deviatingProviders, err = o.GetDeviatingProviders(OJOUSD)
+	require.NoError(t, err)
+	require.Equal(t, []types.ProviderName{provider.ProviderHuobi, provider.ProviderKraken}, deviatingProviders)
+}
+
+func (ots *OracleTestSuite) TestGetVoteAudit() {
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	volume := math.LegacyMustNewDecFromStr("894123.00")
+
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			ATOMUSD: {Price: atomPrice, Volume: volume},
+			OJOUSD:  {Price: math.LegacyMustNewDecFromStr("3.72"), Volume: volume},
+		},
+		provider.ProviderHuobi:  {ATOMUSD: {Price: atomPrice, Volume: volume}},
+		provider.ProviderKraken: {ATOMUSD: {Price: atomPrice, Volume: volume}},
+		provider.ProviderCoinbase: {
+			ATOMUSD: {Price: math.LegacyMustNewDecFromStr("27.1"), Volume: volume},
+		},
+	}
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance:  {ATOMUSD, OJOUSD},
+		provider.ProviderHuobi:    {ATOMUSD},
+		provider.ProviderKraken:   {ATOMUSD},
+		provider.ProviderCoinbase: {ATOMUSD},
+	}
+
+	prices, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles),
+		providerPrices,
+	)
+	ots.Require().NoError(err)
+
+	audit := ots.oracle.GetVoteAudit(prices)
+	ots.Require().Equal(GenerateExchangeRatesString(prices), audit.ExchangeRates)
+	ots.Require().Len(audit.Assets, 2)
+
+	// coinbase deviates from the other providers and did not contribute
+	atomAudit := audit.Assets[0]
+	ots.Require().Equal("ATOM", atomAudit.Base)
+	ots.Require().Equal(atomPrice, atomAudit.Price)
+	ots.Require().Equal([]types.ProviderPriceAudit{
+		{Provider: provider.ProviderBinance, Pair: ATOMUSD, Source: auditSourceTicker, Price: atomPrice},
+		{Provider: provider.ProviderHuobi, Pair: ATOMUSD, Source: auditSourceTicker, Price: atomPrice},
+		{Provider: provider.ProviderKraken, Pair: ATOMUSD, Source: auditSourceTicker, Price: atomPrice},
+	}, atomAudit.Providers)
+
+	ojoAudit := audit.Assets[1]
+	ots.Require().Equal("OJO", ojoAudit.Base)
+	ots.Require().Equal([]types.ProviderPriceAudit{
 		{
-			Price:     btcUSDPrice,
-			Volume:    volume,
-			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			Provider: provider.ProviderBinance,
+			Pair:     OJOUSD,
+			Source:   auditSourceTicker,
+			Price:    math.LegacyMustNewDecFromStr("3.72"),
+		},
+	}, ojoAudit.Providers)
+}
+
+func (ots *OracleTestSuite) TestGetComputedPricesConversionRateOverride() {
+	volume := math.LegacyMustNewDecFromStr("881272.00")
+	ojoUsdtPrice := math.LegacyMustNewDecFromStr("2.0")
+
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			OJOUSDT: {Price: ojoUsdtPrice, Volume: volume},
+		},
+		provider.ProviderCoinbase: {
+			USDTUSD: {Price: math.LegacyMustNewDecFromStr("0.5"), Volume: volume},
 		},
 	}
-	providerCandles[provider.ProviderKraken] = krakenCandles
 
 	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
-		provider.ProviderBinance: {btcPair, ethPair},
-		provider.ProviderGate:    {ethPair},
-		provider.ProviderOkx:     {ethPair},
-		provider.ProviderKraken:  {btcUSDPair},
+		provider.ProviderBinance:  {OJOUSDT},
+		provider.ProviderCoinbase: {USDTUSD},
+	}
+	ots.oracle.SetConversionRateOverrides(map[string]math.LegacyDec{
+		"USDT": math.LegacyOneDec(),
+	})
+	defer ots.oracle.SetConversionRateOverrides(nil)
+
+	prices, err := ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles),
+		providerPrices,
+	)
+
+	ots.Require().NoError(err)
+	ots.Require().True(ojoUsdtPrice.Equal(prices[OJOUSD]))
+	ots.Require().Equal(
+		types.ConversionRoute{{Pair: USDTUSD, Rate: math.LegacyOneDec()}},
+		ots.oracle.GetConversionRoutes()[OJOUSDT],
+	)
+}
+
+func (ots *OracleTestSuite) TestGetComputedPricesConversionRawFallback() {
+	volume := math.LegacyMustNewDecFromStr("881272.00")
+	ojoUsdtPrice := math.LegacyMustNewDecFromStr("2.0")
+
+	// every USDT rate deviates from their mean of 1 by more than the threshold
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			OJOUSDT: {Price: ojoUsdtPrice, Volume: volume},
+		},
+		provider.ProviderCoinbase: {
+			USDTUSD: {Price: math.LegacyMustNewDecFromStr("0.9"), Volume: volume},
+		},
+		provider.ProviderHuobi: {
+			USDTUSD: {Price: math.LegacyMustNewDecFromStr("0.9"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			USDTUSD: {Price: math.LegacyMustNewDecFromStr("1.2"), Volume: volume},
+		},
+	}
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance:  {OJOUSDT},
+		provider.ProviderCoinbase: {USDTUSD},
+		provider.ProviderHuobi:    {USDTUSD},
+		provider.ProviderKraken:   {USDTUSD},
 	}
 	ots.oracle.deviations = map[string]math.LegacyDec{
-		"BTC": math.LegacyMustNewDecFromStr("1"),
+		"USDT": math.LegacyMustNewDecFromStr("0.1"),
 	}
+	defer func() {
+		ots.oracle.deviations = make(map[string]math.LegacyDec)
+	}()
 
+	// the filtered conversion rate leaves OJO unconverted
 	prices, err := ots.oracle.GetComputedPrices(
-		providerCandles,
-		make(types.AggregatedProviderPrices),
+		make(types.AggregatedProviderCandles),
+		providerPrices,
 	)
+	ots.Require().NoError(err)
+	ots.Require().NotContains(prices, OJOUSD)
 
-	require.NoError(ots.T(), err,
-		"It should successfully filter out bad candles and convert everything to USD",
-	)
+	// the raw VWAP of USDT is used for the conversion instead
+	ots.oracle.SetConversionRawFallback(true)
+	defer ots.oracle.SetConversionRawFallback(false)
 
-	btcUsdRate := btcEthPrice.Mul(ethUsdPrice)
-	require.Equal(ots.T(),
-		btcUsdRate.Add(btcUSDPrice).Quo(math.LegacyMustNewDecFromStr("2")),
-		prices[BTCUSD],
+	prices, err = ots.oracle.GetComputedPrices(
+		make(types.AggregatedProviderCandles),
+		providerPrices,
 	)
+	ots.Require().NoError(err)
+	ots.Require().True(ojoUsdtPrice.Equal(prices[OJOUSD]))
 }
 
-func (ots *OracleTestSuite) TestGetComputedPricesTickersConversion() {
-	volume := math.LegacyMustNewDecFromStr("881272.00")
-	btcEthPrice := math.LegacyMustNewDecFromStr("72.55")
-	ethUsdPrice := math.LegacyMustNewDecFromStr("9989.02")
-	btcUSDPrice := math.LegacyMustNewDecFromStr("724603.401")
-	providerPrices := make(types.AggregatedProviderPrices, 1)
+func TestSubscribePrices(t *testing.T) {
+	o := &Oracle{}
 
-	// normal rates
-	binanceTickerPrices := make(types.CurrencyPairTickers, 2)
-	binanceTickerPrices[BTCETH] = types.TickerPrice{
-		Price:  btcEthPrice,
-		Volume: volume,
-	}
-	binanceTickerPrices[ETHUSD] = types.TickerPrice{
-		Price:  ethUsdPrice,
-		Volume: volume,
-	}
-	providerPrices[provider.ProviderBinance] = binanceTickerPrices
+	prices, unsubscribe := o.SubscribePrices()
 
-	// normal rates
-	gateTickerPrices := make(types.CurrencyPairTickers, 4)
-	gateTickerPrices[ETHUSD] = types.TickerPrice{
-		Price:  ethUsdPrice,
-		Volume: volume,
-	}
-	providerPrices[provider.ProviderGate] = gateTickerPrices
+	first := types.CurrencyPairDec{OJOUSD: math.LegacyOneDec()}
+	latest := types.CurrencyPairDec{OJOUSD: math.LegacyNewDec(2)}
+	o.notifyPriceSubscribers(first)
+	o.notifyPriceSubscribers(latest)
 
-	// abnormal eth rate
-	okxTickerPrices := make(types.CurrencyPairTickers, 1)
-	okxTickerPrices[ETHUSD] = types.TickerPrice{
-		Price:  math.LegacyMustNewDecFromStr("1.0"),
-		Volume: volume,
+	// a subscriber which fell behind only receives the latest prices
+	require.Equal(t, latest, <-prices)
+	select {
+	case <-prices:
+		t.Fatal("unexpected prices")
+	default:
 	}
-	providerPrices[provider.ProviderOkx] = okxTickerPrices
 
-	// btc / usd rate
-	krakenTickerPrices := make(types.CurrencyPairTickers, 1)
-	krakenTickerPrices[BTCUSD] = types.TickerPrice{
-		Price:  btcUSDPrice,
-		Volume: volume,
+	unsubscribe()
+	o.notifyPriceSubscribers(first)
+	select {
+	case <-prices:
+		t.Fatal("unexpected prices after unsubscribing")
+	default:
 	}
-	providerPrices[provider.ProviderKraken] = krakenTickerPrices
+}
 
-	providerPair := map[types.ProviderName][]types.CurrencyPair{
-		provider.ProviderBinance: {ETHUSD, BTCETH},
-		provider.ProviderGate:    {ETHUSD},
-		provider.ProviderOkx:     {ETHUSD},
-		provider.ProviderKraken:  {BTCUSD},
+func TestCheckCurrencyPairAndDeviationsUnsubscribesDroppedPairs(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	currentParams := oracletypes.Params{
+		CurrencyPairProviders: oracletypes.CurrencyPairProvidersList{
+			{BaseDenom: "OJO", QuoteDenom: "USDT", Providers: []string{provider.ProviderBinance.String()}},
+			{BaseDenom: "ATOM", QuoteDenom: "USDT", Providers: []string{provider.ProviderBinance.String()}},
+		},
+	}
+	newParams := oracletypes.Params{
+		CurrencyPairProviders: oracletypes.CurrencyPairProvidersList{
+			{BaseDenom: "OJO", QuoteDenom: "USDT", Providers: []string{provider.ProviderBinance.String()}},
+		},
 	}
-	ots.oracle.providerPairs = providerPair
 
-	prices, err := ots.oracle.GetComputedPrices(
-		make(types.AggregatedProviderCandles, 1),
-		providerPrices,
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		CreatePairProvidersFromCurrencyPairProvidersList(currentParams.CurrencyPairProviders),
+		100*time.Millisecond,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
 	)
+	unsubscribed := []types.CurrencyPair{}
+	o.priceProviders[provider.ProviderBinance] = unsubscribingProvider{unsubscribed: &unsubscribed}
 
-	require.NoError(ots.T(), err,
-		"It should successfully filter out bad tickers and convert everything to USD",
-	)
-	require.Equal(ots.T(),
-		ethUsdPrice.Mul(
-			btcEthPrice).Add(btcUSDPrice).Quo(math.LegacyMustNewDecFromStr("2")),
-		prices[BTCUSD],
-	)
+	require.NoError(t, o.checkCurrencyPairAndDeviations(currentParams, newParams))
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, unsubscribed)
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.providerPairs[provider.ProviderBinance])
 }
 
-func (ots *OracleTestSuite) TestGetComputedPricesEmptyTvwap() {
-	symbolUSDT := "USDT"
-	symbolUSD := "USD"
-	symbolDAI := "DAI"
-	symbolETH := "ETH"
-
-	pairETHtoUSDT := types.CurrencyPair{
-		Base:  symbolETH,
-		Quote: symbolUSDT,
-	}
-	pairETHtoDAI := types.CurrencyPair{
-		Base:  symbolETH,
-		Quote: symbolDAI,
+func TestTickDryRun(t *testing.T) {
+	logs := new(bytes.Buffer)
+	o := New(
+		zerolog.New(logs),
+		client.OracleClient{
+			ChainHeight:         client.NewStaticChainHeight(zerolog.Nop(), 10),
+			ValidatorAddrString: sdk.ValAddress([]byte("validator-address-01")).String(),
+		},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+				},
+			},
+		},
 	}
-	pairETHtoUSD := types.CurrencyPair{
-		Base:  symbolETH,
-		Quote: symbolUSD,
+	o.ParamCache.UpdateParamCache(10, oracletypes.Params{VotePeriod: 5}, nil)
+	o.SetDryRun(true)
+
+	// the prevote file of a live price feeder sharing the config is neither
+	// restored nor modified
+	prevoteFile := filepath.Join(t.TempDir(), PrevoteFileName)
+	livePrevote := &PreviousPrevote{
+		ExchangeRates:     "OJO:3.5",
+		Salt:              "5f9ae2fe1fa3b5bf0f5d0f4fd6c4b4b2",
+		SubmitBlockHeight: 10,
 	}
-	basePairsETH := []types.CurrencyPair{
-		pairETHtoUSDT,
-		pairETHtoDAI,
+	require.NoError(t, writePreviousPrevote(prevoteFile, livePrevote))
+	o.SetPrevoteFile(prevoteFile)
+	require.NoError(t, o.restorePreviousPrevote(10, 5))
+	require.Nil(t, o.previousPrevote)
+
+	var broadcasts int
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		broadcasts++
+		return nil, nil
 	}
-	krakenPairsETH := append(basePairsETH, pairETHtoUSD)
 
-	pairUSDTtoUSD := types.CurrencyPair{
-		Base:  symbolUSDT,
-		Quote: symbolUSD,
-	}
-	pairDAItoUSD := types.CurrencyPair{
-		Base:  symbolDAI,
-		Quote: symbolUSD,
-	}
-	stablecoinPairs := []types.CurrencyPair{
-		pairUSDTtoUSD,
-		pairDAItoUSD,
+	// the prevote is built from the computed prices, but not broadcast
+	require.NoError(t, o.tick(context.TODO(), context.TODO()))
+	require.Equal(t, 0, broadcasts)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+	require.NotNil(t, o.previousPrevote)
+	require.Equal(t, "OJO:3.720000000000000000", o.previousPrevote.ExchangeRates)
+	require.Contains(t, logs.String(), `"exchange_rates":"`+GenerateExchangeRatesString(o.GetPrices())+`"`)
+	require.Equal(t, float64(2), o.previousVotePeriod)
+
+	// the vote is built in the next vote period, but not broadcast
+	o.oracleClient.ChainHeight = client.NewStaticChainHeight(zerolog.Nop(), 15)
+	require.NoError(t, o.tick(context.TODO(), context.TODO()))
+	require.Equal(t, 0, broadcasts)
+	require.Nil(t, o.previousPrevote)
+	require.Equal(t, float64(0), o.previousVotePeriod)
+	require.True(t, o.GetFirstVoteTimestamp().IsZero())
+
+	prevote, err := readPreviousPrevote(prevoteFile)
+	require.NoError(t, err)
+	require.Equal(t, livePrevote, prevote)
+}
+
+func TestTickVotingPaused(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{
+			ChainHeight:         client.NewStaticChainHeight(zerolog.Nop(), 10),
+			ValidatorAddrString: sdk.ValAddress([]byte("validator-address-01")).String(),
+		},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+				},
+			},
+		},
 	}
+	o.ParamCache.UpdateParamCache(10, oracletypes.Params{VotePeriod: 5}, nil)
 
-	krakenPairs := append(krakenPairsETH, stablecoinPairs...)
+	var broadcasts int
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		broadcasts++
+		return &sdk.TxResponse{}, nil
+	}
 
-	volume := math.LegacyMustNewDecFromStr("881272.00")
-	ethUsdPrice := math.LegacyMustNewDecFromStr("9989.02")
-	daiUsdPrice := math.LegacyMustNewDecFromStr("999890000000000000")
-	ethTime := provider.PastUnixTime(1 * time.Minute)
+	// prices are still updated while voting is paused
+	o.SetVotingPaused(true)
+	require.NoError(t, o.tick(context.TODO(), context.TODO()))
+	require.Equal(t, 0, broadcasts)
+	require.Nil(t, o.previousPrevote)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+
+	o.SetVotingPaused(false)
+	require.NoError(t, o.tick(context.TODO(), context.TODO()))
+	require.Equal(t, 1, broadcasts)
+	require.NotNil(t, o.previousPrevote)
+}
 
-	ethCandle := []types.CandlePrice{
-		{
-			Price:     ethUsdPrice,
-			Volume:    volume,
-			TimeStamp: ethTime,
+func TestTickMissedVotes(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{
+			ChainHeight:         client.NewStaticChainHeight(zerolog.Nop(), 10),
+			ValidatorAddrString: sdk.ValAddress([]byte("validator-address-01")).String(),
 		},
-		{
-			Price:     ethUsdPrice,
-			Volume:    volume,
-			TimeStamp: ethTime,
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
 		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{prices: types.CurrencyPairTickers{}},
 	}
-	daiCandle := []types.CandlePrice{
-		{
-			Price:     daiUsdPrice,
-			Volume:    volume,
-			TimeStamp: 1660829520000,
-		},
+	o.ParamCache.UpdateParamCache(10, oracletypes.Params{VotePeriod: 5}, nil)
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		return &sdk.TxResponse{}, nil
 	}
 
-	prices := types.AggregatedProviderPrices{}
+	tickAt := func(height int64) error {
+		o.oracleClient.ChainHeight = client.NewStaticChainHeight(zerolog.Nop(), height)
+		return o.tick(context.TODO(), context.TODO())
+	}
 
-	pairs := map[types.ProviderName][]types.CurrencyPair{
-		provider.ProviderKraken: krakenPairs,
+	// a price failure is recorded once per vote period in which a vote is due
+	require.Error(t, tickAt(10))
+	require.Error(t, tickAt(11))
+	require.Error(t, tickAt(18))
+	require.Len(t, o.GetMissedVotes(), 1)
+	require.Equal(t, types.MissedVoteReasonPriceFailure, o.GetMissedVotes()[0].Reason)
+	require.Equal(t, int64(10), o.GetMissedVotes()[0].Height)
+
+	// pausing voting misses the votes which are due
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
+				},
+			},
+		},
 	}
+	o.SetVotingPaused(true)
+	require.NoError(t, tickAt(19))
+	require.NoError(t, tickAt(20))
+	require.Len(t, o.GetMissedVotes(), 2)
+	require.Equal(t, types.MissedVoteReasonVotingPaused, o.GetMissedVotes()[1].Reason)
+}
 
-	testCases := map[string]struct {
-		candles   types.AggregatedProviderCandles
-		prices    types.AggregatedProviderPrices
-		pairs     map[types.ProviderName][]types.CurrencyPair
-		numPrices int
-	}{
-		"Empty tvwap": {
-			candles: types.AggregatedProviderCandles{
-				provider.ProviderKraken: {
-					USDTUSD: ethCandle,
-					ETHUSD:  ethCandle,
-					DAIUSD:  daiCandle,
+func TestDrainTick(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{
+			ChainHeight:         client.NewStaticChainHeight(zerolog.Nop(), 10),
+			ValidatorAddrString: sdk.ValAddress([]byte("validator-address-01")).String(),
+		},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
 				},
 			},
-			prices:    prices,
-			pairs:     pairs,
-			numPrices: 2,
 		},
-		"No valid conversion rates DAI": {
-			candles: types.AggregatedProviderCandles{
-				provider.ProviderKraken: {
-					USDTUSD: ethCandle,
-					ETHUSD:  ethCandle,
+	}
+	o.ParamCache.UpdateParamCache(10, oracletypes.Params{VotePeriod: 5}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the prevote broadcast only completes once the context is cancelled
+	var broadcasts atomic.Int32
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		broadcasts.Add(1)
+		return &sdk.TxResponse{}, nil
+	}
+
+	require.NoError(t, o.drainTick(ctx))
+	require.Equal(t, int32(1), broadcasts.Load())
+	require.NotNil(t, o.previousPrevote)
+
+	// the vote broadcast is abandoned once the grace period elapses
+	o.SetShutdownGracePeriod(10 * time.Millisecond)
+	o.oracleClient.ChainHeight = client.NewStaticChainHeight(zerolog.Nop(), 15)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		cancel()
+		<-release
+		return &sdk.TxResponse{}, nil
+	}
+
+	require.ErrorContains(t, o.drainTick(ctx), "shutdown grace period")
+
+	// the abandoned tick must complete before the test returns, so it does not
+	// outlive it
+	close(release)
+	o.pendingTicks.Wait()
+}
+
+func TestTickRejectedTx(t *testing.T) {
+	metrics, err := telemetry.New(telemetry.Config{Enabled: true, ServiceName: "price-feeder"})
+	require.NoError(t, err)
+	defer func() {
+		_, err := telemetry.New(telemetry.Config{Enabled: false})
+		require.NoError(t, err)
+	}()
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{
+			ChainHeight:         client.NewStaticChainHeight(zerolog.Nop(), 10),
+			ValidatorAddrString: sdk.ValAddress([]byte("validator-address-01")).String(),
+		},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSD},
+		},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				OJOUSD: {
+					Price:  math.LegacyMustNewDecFromStr("3.72"),
+					Volume: math.LegacyMustNewDecFromStr("2396974.02000000"),
 				},
 			},
-			prices:    prices,
-			pairs:     pairs,
-			numPrices: 2,
 		},
 	}
+	o.ParamCache.UpdateParamCache(10, oracletypes.Params{VotePeriod: 5}, nil)
+	o.broadcast = func(int64, int64, ...sdk.Msg) (*sdk.TxResponse, error) {
+		return &sdk.TxResponse{Code: 5, Codespace: "sdk", RawLog: "insufficient funds"},
+			fmt.Errorf("broadcasting tx timed out")
+	}
 
-	for name, tc := range testCases {
-		tc := tc
+	require.Error(t, o.tick(context.TODO(), context.TODO()))
+	require.Nil(t, o.previousPrevote)
 
-		ots.Run(name, func() {
-			ots.oracle.providerPairs = tc.pairs
-			prices, _ := ots.oracle.GetComputedPrices(
-				tc.candles,
-				tc.prices,
-			)
-			require.Equal(ots.T(), tc.numPrices, len(prices))
-		})
+	missedVotes := o.GetMissedVotes()
+	require.Len(t, missedVotes, 1)
+	require.Equal(t, types.MissedVoteReasonBroadcastFailure, missedVotes[0].Reason)
+
+	gr, err := metrics.Gather(telemetry.FormatDefault)
+	require.NoError(t, err)
+
+	var summary struct {
+		Counters []struct {
+			Name   string
+			Count  int
+			Labels map[string]string
+		}
+	}
+	require.NoError(t, json.Unmarshal(gr.Metrics, &summary))
+
+	var rejected int
+	for _, counter := range summary.Counters {
+		if counter.Name == "price-feeder.tx.rejected" {
+			require.Equal(t, "sdk", counter.Labels["codespace"])
+			require.Equal(t, "5", counter.Labels["code"])
+			rejected += counter.Count
+		}
 	}
+	require.Equal(t, 1, rejected)
 }
@@ -3,6 +3,7 @@ package oracle
 import (
 	"context"
 	"sync"
+	"time"
 
 	tmrpcclient "github.com/cometbft/cometbft/rpc/client"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
@@ -31,6 +32,7 @@ type ParamCache struct {
 	errGetParams     error
 	params           *oracletypes.Params
 	lastUpdatedBlock int64
+	lastUpdatedTime  time.Time
 	paramUpdateEvent bool
 }
 
@@ -70,6 +72,7 @@ func (paramCache *ParamCache) UpdateParamCache(currentBlockHeight int64, params
 	defer paramCache.mtx.Unlock()
 
 	paramCache.lastUpdatedBlock = currentBlockHeight
+	paramCache.lastUpdatedTime = time.Now()
 	paramCache.params = &params
 	paramCache.errGetParams = err
 	paramCache.paramUpdateEvent = false
@@ -97,6 +100,40 @@ func (paramCache *ParamCache) IsOutdated(currentBlockHeight int64) bool {
 	return (currentBlockHeight - paramCache.lastUpdatedBlock) > paramsCacheInterval
 }
 
+// IsExpired checks whether or not the current param data was fetched more
+// than maxAge ago, regardless of the block height. It is a safety net for
+// param updates missed by both the update events and the height based
+// IsOutdated check, e.g. when the chain is slow. A maxAge of zero disables it.
+func (paramCache *ParamCache) IsExpired(maxAge time.Duration) bool {
+	paramCache.mtx.RLock()
+	defer paramCache.mtx.RUnlock()
+
+	if paramCache.params == nil || maxAge <= 0 {
+		return false
+	}
+
+	return time.Since(paramCache.lastUpdatedTime) > maxAge
+}
+
+// LastKnownParams returns the cached params if they were fetched at most
+// maxAge blocks before the current block height. It is used to fall back on
+// the previously cached params when fetching fresh params fails.
+func (paramCache *ParamCache) LastKnownParams(currentBlockHeight, maxAge int64) (oracletypes.Params, bool) {
+	paramCache.mtx.RLock()
+	defer paramCache.mtx.RUnlock()
+
+	if paramCache.params == nil || maxAge <= 0 {
+		return oracletypes.Params{}, false
+	}
+
+	age := currentBlockHeight - paramCache.lastUpdatedBlock
+	if age < 0 || age > maxAge {
+		return oracletypes.Params{}, false
+	}
+
+	return *paramCache.params, true
+}
+
 // subscribe listens to param update events.
 func (paramCache *ParamCache) subscribe(
 	ctx context.Context,
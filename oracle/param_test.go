@@ -1,10 +1,18 @@
 package oracle
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"cosmossdk.io/math"
 	oracletypes "github.com/ojo-network/ojo/x/oracle/types"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
 func TestParamCacheIsOutdated(t *testing.T) {
@@ -63,3 +71,171 @@ func TestParamCacheIsOutdated(t *testing.T) {
 		})
 	}
 }
+
+func TestParamCacheLastKnownParams(t *testing.T) {
+	testCases := map[string]struct {
+		paramCache         *ParamCache
+		currentBlockHeight int64
+		maxAge             int64
+		expected           bool
+	}{
+		"Params Nil": {
+			paramCache: &ParamCache{
+				params:           nil,
+				lastUpdatedBlock: 0,
+			},
+			currentBlockHeight: 10,
+			maxAge:             1000,
+			expected:           false,
+		},
+		"Fallback disabled": {
+			paramCache: &ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 200,
+			},
+			currentBlockHeight: 401,
+			maxAge:             0,
+			expected:           false,
+		},
+		"Within max age": {
+			paramCache: &ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 200,
+			},
+			currentBlockHeight: 700,
+			maxAge:             500,
+			expected:           true,
+		},
+		"Exceeds max age": {
+			paramCache: &ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 200,
+			},
+			currentBlockHeight: 701,
+			maxAge:             500,
+			expected:           false,
+		},
+		"currentBlockHeight < lastUpdatedBlock": {
+			paramCache: &ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 205,
+			},
+			currentBlockHeight: 203,
+			maxAge:             500,
+			expected:           false,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			_, ok := tc.paramCache.LastKnownParams(tc.currentBlockHeight, tc.maxAge)
+			require.Equal(t, tc.expected, ok)
+		})
+	}
+}
+
+func TestGetParamCacheFallback(t *testing.T) {
+	cachedParams := oracletypes.DefaultParams()
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{GRPCEndpoint: "unix:///nonexistent/grpc.sock"},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.ParamCache.UpdateParamCache(200, cachedParams, nil)
+
+	// fetching fresh params fails and no fallback is configured
+	_, err := o.GetParamCache(context.Background(), 401)
+	require.Error(t, err)
+
+	// fetching fresh params fails and the cached params are used
+	o.SetParamsFallbackMaxAge(500)
+	params, err := o.GetParamCache(context.Background(), 401)
+	require.NoError(t, err)
+	require.Equal(t, cachedParams.VotePeriod, params.VotePeriod)
+	require.Equal(t, cachedParams.AcceptList.String(), params.AcceptList.String())
+
+	// the cached params are too old to be used
+	_, err = o.GetParamCache(context.Background(), 701)
+	require.Error(t, err)
+}
+
+func TestParamCacheIsExpired(t *testing.T) {
+	testCases := map[string]struct {
+		paramCache *ParamCache
+		maxAge     time.Duration
+		expected   bool
+	}{
+		"Params Nil": {
+			paramCache: &ParamCache{
+				params: nil,
+			},
+			maxAge:   time.Minute,
+			expected: false,
+		},
+		"Max age disabled": {
+			paramCache: &ParamCache{
+				params:          &oracletypes.Params{},
+				lastUpdatedTime: time.Now().Add(-time.Hour),
+			},
+			maxAge:   0,
+			expected: false,
+		},
+		"Within max age": {
+			paramCache: &ParamCache{
+				params:          &oracletypes.Params{},
+				lastUpdatedTime: time.Now(),
+			},
+			maxAge:   time.Minute,
+			expected: false,
+		},
+		"Exceeds max age": {
+			paramCache: &ParamCache{
+				params:          &oracletypes.Params{},
+				lastUpdatedTime: time.Now().Add(-2 * time.Minute),
+			},
+			maxAge:   time.Minute,
+			expected: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.paramCache.IsExpired(tc.maxAge))
+		})
+	}
+}
+
+func TestGetParamCacheMaxAge(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{GRPCEndpoint: "unix:///nonexistent/grpc.sock"},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		defaultTickInterval,
+		make(map[string]math.LegacyDec),
+		make(map[types.ProviderName]provider.Endpoint),
+		false,
+	)
+	o.ParamCache.UpdateParamCache(200, oracletypes.DefaultParams(), nil)
+	o.SetParamsMaxAge(time.Minute)
+
+	// the cached params are neither outdated nor expired and are not refetched
+	_, err := o.GetParamCache(context.Background(), 201)
+	require.NoError(t, err)
+
+	// the max age elapsed without a height change or update event, so the
+	// params are refetched, which fails without a gRPC endpoint
+	o.ParamCache.lastUpdatedTime = time.Now().Add(-2 * time.Minute)
+	_, err = o.GetParamCache(context.Background(), 201)
+	require.Error(t, err)
+}
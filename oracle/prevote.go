@@ -0,0 +1,151 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/ojo-network/ojo/util"
+)
+
+// PrevoteFileName is the name of the file the previous prevote is persisted
+// to, so it survives restarts of the price feeder.
+const PrevoteFileName = "previous_prevote.json"
+
+// SetPrevoteFile sets the file the previous prevote is persisted to after each
+// prevote broadcast, and restored from on Start. When it is empty, or in dry
+// run, the previous prevote is only kept in memory.
+func (o *Oracle) SetPrevoteFile(path string) {
+	o.prevoteFile = path
+}
+
+// usesPrevoteFile returns whether the previous prevote is kept in the prevote
+// file. The prevotes of a dry run are never broadcast, and the file may belong
+// to a live price feeder sharing the config, so a dry run never reads, writes
+// or removes it.
+func (o *Oracle) usesPrevoteFile() bool {
+	return o.prevoteFile != "" && !o.dryRun
+}
+
+// persistPreviousPrevote writes the previous prevote to the prevote file.
+func (o *Oracle) persistPreviousPrevote() {
+	if !o.usesPrevoteFile() {
+		return
+	}
+	if err := writePreviousPrevote(o.prevoteFile, o.previousPrevote); err != nil {
+		o.logger.Err(err).Str("file", o.prevoteFile).Msg("failed to persist previous prevote")
+	}
+}
+
+// removePreviousPrevote removes the prevote file once its prevote has been
+// voted on or can no longer be.
+func (o *Oracle) removePreviousPrevote() {
+	if !o.usesPrevoteFile() {
+		return
+	}
+	if err := os.Remove(o.prevoteFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		o.logger.Err(err).Str("file", o.prevoteFile).Msg("failed to remove previous prevote")
+	}
+}
+
+// loadPreviousPrevote restores the previous prevote from the prevote file
+// using the current block height and vote period.
+func (o *Oracle) loadPreviousPrevote(ctx context.Context) error {
+	if !o.usesPrevoteFile() {
+		return nil
+	}
+
+	blockHeight, err := o.oracleClient.ChainHeight.GetChainHeight()
+	if err != nil {
+		return err
+	}
+	oracleParams, err := o.GetParamCache(ctx, blockHeight)
+	if err != nil {
+		return err
+	}
+
+	return o.restorePreviousPrevote(blockHeight, util.SafeUint64ToInt64(oracleParams.VotePeriod))
+}
+
+// restorePreviousPrevote restores the previous prevote from the prevote file
+// if it can still be voted on at the given block height, so a restarted price
+// feeder submits its vote instead of missing it. A prevote which can no longer
+// be voted on is removed.
+func (o *Oracle) restorePreviousPrevote(blockHeight, votePeriod int64) error {
+	if !o.usesPrevoteFile() {
+		return nil
+	}
+
+	prevote, err := readPreviousPrevote(o.prevoteFile)
+	if err != nil || prevote == nil {
+		return err
+	}
+
+	previousVotePeriod := math.Floor(float64(prevote.SubmitBlockHeight) / float64(votePeriod))
+	currentVotePeriod := math.Floor(float64(blockHeight+1) / float64(votePeriod))
+	if currentVotePeriod < previousVotePeriod || currentVotePeriod-previousVotePeriod > 1 {
+		o.logger.Info().
+			Int64("submit_block_height", prevote.SubmitBlockHeight).
+			Int64("block_height", blockHeight).
+			Msg("discarding expired previous prevote")
+		o.removePreviousPrevote()
+		return nil
+	}
+
+	o.previousPrevote = prevote
+	o.previousVotePeriod = previousVotePeriod
+	o.logger.Info().
+		Int64("submit_block_height", prevote.SubmitBlockHeight).
+		Msg("restored previous prevote")
+	return nil
+}
+
+// writePreviousPrevote atomically writes the prevote as JSON to the given
+// path, by writing it to a temporary file which then replaces the file.
+func writePreviousPrevote(path string, prevote *PreviousPrevote) error {
+	bz, err := json.Marshal(prevote)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(bz); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// readPreviousPrevote reads the prevote from the given path. It returns nil
+// without an error when the file does not exist.
+func readPreviousPrevote(path string) (*PreviousPrevote, error) {
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var prevote PreviousPrevote
+	if err := json.Unmarshal(bz, &prevote); err != nil {
+		return nil, fmt.Errorf("failed to decode previous prevote: %w", err)
+	}
+	return &prevote, nil
+}
@@ -0,0 +1,99 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPreviousPrevoteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), PrevoteFileName)
+
+	// a missing file holds no prevote
+	prevote, err := readPreviousPrevote(path)
+	require.NoError(t, err)
+	require.Nil(t, prevote)
+
+	expected := &PreviousPrevote{
+		ExchangeRates:     "ATOM:11.1,OJO:1.1",
+		Salt:              "5f9ae2fe1fa3b5bf0f5d0f4fd6c4b4b2",
+		SubmitBlockHeight: 42,
+		Audit: &types.VoteAudit{
+			ExchangeRates: "ATOM:11.1,OJO:1.1",
+			Assets: []types.AssetAudit{
+				{
+					Base:  "ATOM",
+					Price: math.LegacyMustNewDecFromStr("11.1"),
+					Providers: []types.ProviderPriceAudit{
+						{
+							Provider: provider.ProviderBinance,
+							Pair:     ATOMUSD,
+							Source:   "ticker",
+							Price:    math.LegacyMustNewDecFromStr("11.1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, writePreviousPrevote(path, expected))
+
+	prevote, err = readPreviousPrevote(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, prevote)
+
+	// the temporary file was renamed over the prevote file
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestRestorePreviousPrevote(t *testing.T) {
+	const votePeriod = 5
+
+	testCases := []struct {
+		name        string
+		blockHeight int64
+		restored    bool
+	}{
+		// the restarted feeder waits for the next vote period to vote
+		{"restart within the prevote period", 11, true},
+		// the restarted feeder votes on its next tick
+		{"restart within the vote period", 16, true},
+		{"restart after the vote period", 19, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), PrevoteFileName)
+			prevote := &PreviousPrevote{
+				ExchangeRates:     "ATOM:11.1",
+				Salt:              "5f9ae2fe1fa3b5bf0f5d0f4fd6c4b4b2",
+				SubmitBlockHeight: 10,
+			}
+			require.NoError(t, writePreviousPrevote(path, prevote))
+
+			o := New(zerolog.Nop(), client.OracleClient{}, nil, 0, 0, nil, nil, false)
+			o.SetPrevoteFile(path)
+			require.NoError(t, o.restorePreviousPrevote(tc.blockHeight, votePeriod))
+
+			if !tc.restored {
+				require.Nil(t, o.previousPrevote)
+				_, err := os.Stat(path)
+				require.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+
+			require.Equal(t, prevote, o.previousPrevote)
+			require.Equal(t, float64(2), o.previousVotePeriod)
+		})
+	}
+}
@@ -82,14 +82,14 @@ func NewAstroportProvider(
 	provider := &AstroportProvider{
 		logger:     astroLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(astroLogger),
+		priceStore: newPriceStore(astroLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 		client:     &http.Client{},
 		ctx:        ctx,
 	}
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -132,7 +132,7 @@ func (p *AstroportProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -165,6 +165,9 @@ type AstroportTickerPairs struct {
 // toTickerPrice converts the AstroportTickerPairs to a TickerPrice.
 // It satisfies the TickerPrice interface.
 func (atr AstroportTickersResponse) toTickerPrice() (types.TickerPrice, error) {
+	if err := checkFinite(atr.LastPrice, atr.BaseVolume); err != nil {
+		return types.TickerPrice{}, err
+	}
 	lp, err := decmath.NewDecFromFloat(atr.LastPrice)
 	if err != nil {
 		return types.TickerPrice{}, err
@@ -201,7 +204,7 @@ func (p *AstroportProvider) getAvailableAssets() (map[string]types.CurrencyPair,
 	}
 	defer res.Body.Close()
 
-	bz, err := io.ReadAll(res.Body)
+	bz, err := io.ReadAll(p.endpoints.limitResponseBody(res.Body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -242,7 +245,7 @@ func (p *AstroportProvider) queryTickers() ([]AstroportTickerPairs, error) {
 	}
 	defer res.Body.Close()
 
-	bz, err := io.ReadAll(res.Body)
+	bz, err := io.ReadAll(p.endpoints.limitResponseBody(res.Body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
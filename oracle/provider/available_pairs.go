@@ -3,24 +3,182 @@ package provider
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
+// defaultAvailablePairsCacheTTL is how long the available pairs fetched
+// from a provider are reused before being fetched again.
+const defaultAvailablePairsCacheTTL = 5 * time.Minute
+
+// availablePairsStore holds the statically configured pairs available on a
+// provider, used instead of fetching them over REST, and when they were last
+// refreshed. Otherwise, it caches the available pairs last fetched from the
+// provider, so subscribing again within the cache TTL does not hit its REST
+// endpoints.
+type availablePairsStore struct {
+	mtx         sync.Mutex
+	loaded      bool
+	static      map[string]struct{}
+	lastRefresh time.Time
+	cached      map[string]struct{}
+	fetchedAt   time.Time
+}
+
+// availablePairsHolder defines the providers which keep their available
+// pairs between subscriptions, which all providers embedding a priceStore do.
+type availablePairsHolder interface {
+	getAvailablePairsStore() *availablePairsStore
+}
+
+// availablePairsCacheTTL returns how long the available pairs fetched from
+// the endpoint are reused, defaulting to defaultAvailablePairsCacheTTL, and
+// zero if the cache is disabled.
+func (e Endpoint) availablePairsCacheTTL() time.Duration {
+	if e.AvailablePairsCacheTTL == "" {
+		return defaultAvailablePairsCacheTTL
+	}
+	ttl, err := time.ParseDuration(e.AvailablePairsCacheTTL)
+	if err != nil || ttl < 0 {
+		return defaultAvailablePairsCacheTTL
+	}
+	return ttl
+}
+
+// fetchAvailablePairs returns the cached available pairs of the store if they
+// were fetched within the cache TTL, and otherwise uses the provider's
+// GetAvailablePairs method and caches its result. The lock of the store must
+// be held, so concurrent lookups wait for a single request instead of each
+// making their own.
+func (s *availablePairsStore) fetchAvailablePairs(p Provider, ttl time.Duration) (map[string]struct{}, error) {
+	if ttl <= 0 {
+		return p.GetAvailablePairs()
+	}
+	if s.cached != nil && time.Since(s.fetchedAt) < ttl {
+		return s.cached, nil
+	}
+
+	availablePairs, err := p.GetAvailablePairs()
+	if err != nil {
+		return nil, err
+	}
+	s.cached = availablePairs
+	s.fetchedAt = time.Now()
+
+	return availablePairs, nil
+}
+
+// invalidate drops the cached available pairs of the store, so they are
+// fetched again on the next lookup.
+func (s *availablePairsStore) invalidate() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.cached = nil
+}
+
+// availablePairsRefresh returns the interval at which the static available
+// pairs of the endpoint are refreshed over REST, and zero if they are never
+// refreshed.
+func (e Endpoint) availablePairsRefresh() time.Duration {
+	if e.AvailablePairsRefresh == "" {
+		return 0
+	}
+	refresh, err := time.ParseDuration(e.AvailablePairsRefresh)
+	if err != nil || refresh <= 0 {
+		return 0
+	}
+	return refresh
+}
+
+// staticAvailablePairs returns the statically configured pairs available on
+// the endpoint, ex. "ATOM/USDT", and nil if none are configured.
+func (e Endpoint) staticAvailablePairs() (map[string]struct{}, error) {
+	if len(e.AvailablePairs) == 0 {
+		return nil, nil
+	}
+
+	availablePairs := make(map[string]struct{}, len(e.AvailablePairs))
+	for _, pair := range e.AvailablePairs {
+		cp, err := types.ParseCurrencyPair(pair)
+		if err != nil {
+			return nil, err
+		}
+		availablePairs[cp.String()] = struct{}{}
+	}
+	return availablePairs, nil
+}
+
+// getAvailablePairsStore returns the available pairs kept by the price store
+// between subscriptions.
+func (ps *priceStore) getAvailablePairsStore() *availablePairsStore {
+	return &ps.availablePairs
+}
+
+// getAvailablePairs returns the static available pairs of the endpoint if
+// they are configured, refreshing them once the refresh interval has elapsed
+// and keeping them as is if refreshing fails, and otherwise uses the
+// provider's GetAvailablePairs method through the available pairs cache.
+func getAvailablePairs(
+	p Provider,
+	store *availablePairsStore,
+	endpoints Endpoint,
+	logger zerolog.Logger,
+) (map[string]struct{}, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	if !store.loaded {
+		static, err := endpoints.staticAvailablePairs()
+		if err != nil {
+			return nil, err
+		}
+		store.static = static
+		store.lastRefresh = time.Now()
+		store.loaded = true
+	}
+	if store.static == nil {
+		return store.fetchAvailablePairs(p, endpoints.availablePairsCacheTTL())
+	}
+
+	refresh := endpoints.availablePairsRefresh()
+	if refresh > 0 && time.Since(store.lastRefresh) >= refresh {
+		availablePairs, err := p.GetAvailablePairs()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to refresh available pairs; using static available pairs")
+		} else {
+			store.static = availablePairs
+		}
+		store.lastRefresh = time.Now()
+	}
+
+	return store.static, nil
+}
+
 // ConfirmPairAvailability takes a list of pairs that are meant to be subscribed
-// to, and uses the given provider's GetAvailablePairs method to check that the
-// given pairs can be subscribed to. It will return an updated list of pairs that
+// to, and uses the given provider's GetAvailablePairs method, or the static
+// available pairs of its endpoint if configured, to check that the given pairs can be
+// subscribed to. It will return an updated list of pairs that
 // can be subsribed to, and send a warning log about any pairs passed in that
-// cannot be subsribed to.
+// cannot be subsribed to. The cached available pairs of the provider are
+// invalidated when a pair is not found, so they are fetched again on the next
+// subscription.
 func ConfirmPairAvailability(
 	p Provider,
-	providerName types.ProviderName,
+	endpoints Endpoint,
 	logger zerolog.Logger,
 	cps ...types.CurrencyPair,
 ) ([]types.CurrencyPair, error) {
-	availablePairs, err := p.GetAvailablePairs()
+	store := &availablePairsStore{}
+	if holder, ok := p.(availablePairsHolder); ok {
+		store = holder.getAvailablePairsStore()
+	}
+
+	availablePairs, err := getAvailablePairs(p, store, endpoints, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -32,13 +190,37 @@ func ConfirmPairAvailability(
 			logger.Error().Msg(fmt.Sprintf(
 				"%s not an available pair to be subscribed to in %v, %v ignoring pair",
 				cp.String(),
-				providerName,
-				providerName,
+				endpoints.Name,
+				endpoints.Name,
 			))
 			continue
 		}
 		confirmedPairs = append(confirmedPairs, cp)
 	}
 
+	if len(confirmedPairs) < len(cps) {
+		store.invalidate()
+	}
+
 	return confirmedPairs, nil
 }
+
+// DelistedPairs uses the given provider's GetAvailablePairs method to check
+// whether the given pairs can still be subscribed to. It returns the pairs
+// passed in which are no longer available, e.g. because the provider delisted
+// them.
+func DelistedPairs(p Provider, cps ...types.CurrencyPair) ([]types.CurrencyPair, error) {
+	availablePairs, err := p.GetAvailablePairs()
+	if err != nil {
+		return nil, err
+	}
+
+	delistedPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := availablePairs[strings.ToUpper(cp.String())]; !ok {
+			delistedPairs = append(delistedPairs, cp)
+		}
+	}
+
+	return delistedPairs, nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// restPairsProvider is a Provider whose GetAvailablePairs method counts its
+// calls, standing in for a REST request.
+type restPairsProvider struct {
+	Provider
+
+	store availablePairsStore
+	calls int
+	pairs map[string]struct{}
+	err   error
+}
+
+func (p *restPairsProvider) getAvailablePairsStore() *availablePairsStore {
+	return &p.store
+}
+
+func (p *restPairsProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	p.calls++
+	return p.pairs, p.err
+}
+
+func TestConfirmPairAvailability_StaticAvailablePairs(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	osmoUSDT := types.CurrencyPair{Base: "OSMO", Quote: "USDT"}
+
+	// without static available pairs, the provider is queried
+	p := &restPairsProvider{pairs: map[string]struct{}{"OSMOUSDT": {}}}
+	endpoints := Endpoint{Name: "static-test"}
+	confirmedPairs, err := ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{osmoUSDT}, confirmedPairs)
+	require.Equal(t, 1, p.calls)
+
+	// with static available pairs, the provider is not queried
+	p = &restPairsProvider{pairs: map[string]struct{}{"OSMOUSDT": {}}}
+	endpoints.AvailablePairs = []string{"ATOM/USDT"}
+	confirmedPairs, err = ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, confirmedPairs)
+	require.Equal(t, 0, p.calls)
+}
+
+func TestConfirmPairAvailability_StaticAvailablePairsRefresh(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	osmoUSDT := types.CurrencyPair{Base: "OSMO", Quote: "USDT"}
+
+	p := &restPairsProvider{err: fmt.Errorf("rest endpoint unavailable")}
+	endpoints := Endpoint{
+		Name:                  "static-refresh-test",
+		AvailablePairs:        []string{"ATOM/USDT"},
+		AvailablePairsRefresh: "1ns",
+	}
+
+	// refreshing fails and the static available pairs are kept
+	confirmedPairs, err := ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, confirmedPairs)
+	require.Equal(t, 1, p.calls)
+
+	// refreshing succeeds and replaces the static available pairs
+	p.err = nil
+	p.pairs = map[string]struct{}{"OSMOUSDT": {}}
+	confirmedPairs, err = ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{osmoUSDT}, confirmedPairs)
+	require.Equal(t, 2, p.calls)
+}
+
+func TestConfirmPairAvailability_InvalidStaticAvailablePair(t *testing.T) {
+	p := &restPairsProvider{}
+	endpoints := Endpoint{Name: "static-invalid-test", AvailablePairs: []string{"ATOMUSDT"}}
+	_, err := ConfirmPairAvailability(p, endpoints, zerolog.Nop(), types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.Error(t, err)
+}
+
+func TestConfirmPairAvailability_AvailablePairsCache(t *testing.T) {
+	endpoints := Endpoint{Name: "cache-test"}
+
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	osmoUSDT := types.CurrencyPair{Base: "OSMO", Quote: "USDT"}
+
+	p := &restPairsProvider{pairs: map[string]struct{}{"ATOMUSDT": {}}}
+
+	// concurrent lookups share a single request
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			confirmedPairs, err := ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT)
+			require.NoError(t, err)
+			require.Equal(t, []types.CurrencyPair{atomUSDT}, confirmedPairs)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, p.calls)
+
+	// a pair which is not found invalidates the cache
+	p.pairs = map[string]struct{}{"ATOMUSDT": {}, "OSMOUSDT": {}}
+	confirmedPairs, err := ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, confirmedPairs)
+	require.Equal(t, 1, p.calls)
+
+	confirmedPairs, err = ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT, osmoUSDT}, confirmedPairs)
+	require.Equal(t, 2, p.calls)
+
+	// without a TTL, the provider is queried on every lookup
+	endpoints.AvailablePairsCacheTTL = "0s"
+	_, err = ConfirmPairAvailability(p, endpoints, zerolog.Nop(), atomUSDT)
+	require.NoError(t, err)
+	require.Equal(t, 3, p.calls)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -37,6 +36,7 @@ type (
 		logger    zerolog.Logger
 		mtx       sync.RWMutex
 		endpoints Endpoint
+		ctx       context.Context
 
 		priceStore
 	}
@@ -91,13 +91,14 @@ func NewBalancerProvider(
 		wsURL:      wsURL,
 		logger:     balancerLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(balancerLogger),
+		ctx:        ctx,
+		priceStore: newPriceStore(balancerLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToBalancerPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -115,16 +116,25 @@ func NewBalancerProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		balancerLogger,
 	)
 
 	return provider, nil
 }
 
+// StartConnections starts the websocket connection, and checks that data
+// arrives for each subscribed pair since the server pushes every pair
+// without per-pair subscriptions.
 func (p *BalancerProvider) StartConnections() {
+	p.startPairCoverageCheck(p.ctx, p.wsc, p.endpoints.pairCoverageWindow())
 	p.wsc.StartConnections()
 }
 
+func (p *BalancerProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 // SubscribeCurrencyPairs sends the new subscription messages to the websocket
 // and adds them to the providers subscribedPairs array
 func (p *BalancerProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
@@ -133,7 +143,7 @@ func (p *BalancerProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		cps...,
 	)
@@ -193,7 +203,6 @@ func (p *BalancerProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 
 			// candle response
 			case []interface{}:
-				// use latest candlestick in list if there is one
 				if len(v) == 0 {
 					continue
 				}
@@ -263,14 +272,14 @@ func (p *BalancerProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *BalancerProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + balancerRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + balancerRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary []BalancerPairData
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
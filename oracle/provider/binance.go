@@ -3,7 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strings"
 	"sync"
@@ -21,6 +21,11 @@ const (
 	binanceRestHost   = "https://api1.binance.com"
 	binanceRestUSHost = "https://api.binance.us"
 	binanceRestPath   = "/api/v3/ticker/price"
+	binanceRestTicker = "/api/v3/ticker/24hr"
+
+	// binanceCandleInterval defines the interval of the klines always
+	// subscribed to.
+	binanceCandleInterval = "1m"
 )
 
 var _ Provider = (*BinanceProvider)(nil)
@@ -46,10 +51,15 @@ type (
 	// case-insensitive match. C field which is Statistics close time is not used, but
 	// it avoids to implement specific UnmarshalJSON.
 	BinanceTicker struct {
-		Symbol    string `json:"s"` // Symbol ex.: BTCUSDT
-		LastPrice string `json:"c"` // Last price ex.: 0.0025
-		Volume    string `json:"v"` // Total traded base asset volume ex.: 1000
-		C         uint64 `json:"C"` // Statistics close time
+		Symbol      string      `json:"s"` // Symbol ex.: BTCUSDT
+		LastPrice   string      `json:"c"` // Last price ex.: 0.0025
+		Volume      string      `json:"v"` // Total traded base asset volume ex.: 1000
+		C           uint64      `json:"C"` // Statistics close time
+		BidPrice    string      `json:"b"` // Best bid price ex.: 0.0024
+		BidQty      string      `json:"B"` // Best bid quantity ex.: 10
+		AskPrice    string      `json:"a"` // Best ask price ex.: 0.0026
+		AskQty      string      `json:"A"` // Best ask quantity ex.: 100
+		PriceSource PriceSource `json:"-"` // Price used as the ticker price
 	}
 
 	// BinanceCandleMetadata candle metadata used to compute tvwap price.
@@ -57,6 +67,7 @@ type (
 		Close     string `json:"c"` // Price at close
 		TimeStamp int64  `json:"T"` // Close time in unix epoch ex.: 1645756200000
 		Volume    string `json:"v"` // Volume during period
+		Interval  string `json:"i"` // Interval ex.: 1m
 	}
 
 	// BinanceCandle candle binance websocket channel "kline_1m" response.
@@ -78,6 +89,18 @@ type (
 		ID     uint16 `json:"id"`
 	}
 
+	// BinanceRESTTicker defines the response structure of a Binance 24hr ticker
+	// REST request, used while the websocket is disconnected.
+	BinanceRESTTicker struct {
+		Symbol    string `json:"symbol"`    // Symbol ex.: BTCUSDT
+		LastPrice string `json:"lastPrice"` // Last price ex.: 0.0025
+		Volume    string `json:"volume"`    // Total traded base asset volume ex.: 1000
+		BidPrice  string `json:"bidPrice"`  // Best bid price ex.: 0.0024
+		BidQty    string `json:"bidQty"`    // Best bid quantity ex.: 10
+		AskPrice  string `json:"askPrice"`  // Best ask price ex.: 0.0026
+		AskQty    string `json:"askQty"`    // Best ask quantity ex.: 100
+	}
+
 	// BinancePairSummary defines the response structure for a Binance pair
 	// summary.
 	BinancePairSummary struct {
@@ -119,12 +142,12 @@ func NewBinanceProvider(
 	provider := &BinanceProvider{
 		logger:     binanceLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(binanceLogger),
+		priceStore: newPriceStore(binanceLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -142,6 +165,7 @@ func NewBinanceProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		binanceLogger,
 	)
 
@@ -150,6 +174,13 @@ func NewBinanceProvider(
 
 func (p *BinanceProvider) StartConnections() {
 	p.wsc.StartConnections()
+	if p.endpoints.restFallbackEnabled() {
+		go startRESTFallback(p.wsc, p.logger, p.pollRESTTickers)
+	}
+}
+
+func (p *BinanceProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
 }
 
 func (p *BinanceProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
@@ -158,8 +189,13 @@ func (p *BinanceProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []inter
 		binanceTickerPair := currencyPairToBinanceTickerPair(cp)
 		subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceTickerPair))
 
-		binanceCandlePair := currencyPairToBinanceCandlePair(cp)
+		binanceCandlePair := currencyPairToBinanceCandlePair(cp, binanceCandleInterval)
 		subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceCandlePair))
+
+		if interval, _, ok := p.endpoints.secondaryCandleInterval(); ok {
+			binanceCandlePair := currencyPairToBinanceCandlePair(cp, interval)
+			subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceCandlePair))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -179,7 +215,7 @@ func (p *BinanceProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -197,6 +233,33 @@ func (p *BinanceProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 	p.setSubscribedPairs(confirmedPairs...)
 }
 
+// UnsubscribeCurrencyPairs sends the unsubscription messages for the currency
+// pairs to the websocket, closes their connections and removes them and their
+// prices from the providers subscribedPairs array
+func (p *BinanceProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	subscribedPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if p.isSubscribed(cp.String()) {
+			subscribedPairs = append(subscribedPairs, cp)
+		}
+	}
+
+	subscriptionMsgs := p.getSubscriptionMsgs(subscribedPairs...)
+	unsubscriptionMsgs := make([]interface{}, 0, len(subscriptionMsgs))
+	for _, msg := range subscriptionMsgs {
+		unsubscriptionMsgs = append(
+			unsubscriptionMsgs,
+			newBinanceUnsubscriptionMsg(msg.(BinanceSubscriptionMsg).Params...),
+		)
+	}
+
+	p.wsc.RemoveWebsocketConnections(subscriptionMsgs, unsubscriptionMsgs)
+	p.removeSubscribedPairs(subscribedPairs...)
+}
+
 func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
 	var (
 		tickerResp       BinanceTicker
@@ -209,6 +272,7 @@ func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	if len(tickerResp.LastPrice) != 0 {
+		tickerResp.PriceSource = p.endpoints.PriceSource
 		p.setTickerPair(tickerResp, tickerResp.Symbol)
 		telemetryWebsocketMessage(ProviderBinance, MessageTypeTicker)
 		return
@@ -216,7 +280,12 @@ func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 
 	candleErr = json.Unmarshal(bz, &candleResp)
 	if len(candleResp.Metadata.Close) != 0 {
-		p.setCandlePair(candleResp, candleResp.Symbol)
+		if interval, duration, ok := p.endpoints.secondaryCandleInterval(); ok &&
+			candleResp.Metadata.Interval == interval {
+			p.setSecondaryCandlePair(candleResp, candleResp.Symbol, duration)
+		} else {
+			p.setCandlePair(candleResp, candleResp.Symbol)
+		}
 		telemetryWebsocketMessage(ProviderBinance, MessageTypeCandle)
 		return
 	}
@@ -235,24 +304,83 @@ func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 }
 
 func (ticker BinanceTicker) toTickerPrice() (types.TickerPrice, error) {
-	return types.NewTickerPrice(ticker.LastPrice, ticker.Volume)
+	tickerPrice, err := types.NewTickerPrice(ticker.LastPrice, ticker.Volume)
+	if err != nil || ticker.PriceSource == "" || ticker.PriceSource == PriceSourceLast {
+		return tickerPrice, err
+	}
+
+	tickerPrice.Price, err = bookPrice(
+		ticker.PriceSource,
+		ticker.BidPrice,
+		ticker.BidQty,
+		ticker.AskPrice,
+		ticker.AskQty,
+	)
+	if err != nil {
+		return types.TickerPrice{}, fmt.Errorf("binance: %w", err)
+	}
+	return tickerPrice, nil
 }
 
 func (candle BinanceCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(candle.Metadata.Close, candle.Metadata.Volume, candle.Metadata.TimeStamp)
 }
 
+// pollRESTTickers fetches the 24hr tickers of the subscribed pairs from the
+// REST API and sets them in the price store.
+func (p *BinanceProvider) pollRESTTickers() error {
+	p.subscribedPairsMtx.RLock()
+	symbols := make([]string, 0, len(p.subscribedPairs))
+	for _, cp := range p.subscribedPairs {
+		symbols = append(symbols, cp.String())
+	}
+	p.subscribedPairsMtx.RUnlock()
+
+	bz, err := json.Marshal(symbols)
+	if err != nil {
+		return err
+	}
+
+	reqURL := p.endpoints.Rest + binanceRestTicker + "?symbols=" + url.QueryEscape(string(bz))
+	resp, err := p.endpoints.httpGetWithRetries(reqURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var tickers []BinanceRESTTicker
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&tickers); err != nil {
+		return err
+	}
+
+	for _, ticker := range tickers {
+		p.setTickerPair(BinanceTicker{
+			Symbol:      ticker.Symbol,
+			LastPrice:   ticker.LastPrice,
+			Volume:      ticker.Volume,
+			BidPrice:    ticker.BidPrice,
+			BidQty:      ticker.BidQty,
+			AskPrice:    ticker.AskPrice,
+			AskQty:      ticker.AskQty,
+			PriceSource: p.endpoints.PriceSource,
+		}, ticker.Symbol)
+		telemetryRESTFallbackMessage(ProviderBinance, MessageTypeTicker)
+	}
+
+	return nil
+}
+
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *BinanceProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + binanceRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + binanceRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary []BinancePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -270,10 +398,10 @@ func currencyPairToBinanceTickerPair(cp types.CurrencyPair) string {
 	return strings.ToLower(cp.String() + "@ticker")
 }
 
-// currencyPairToBinanceCandlePair receives a currency pair and return binance
-// candle symbol atomusdt@kline_1m.
-func currencyPairToBinanceCandlePair(cp types.CurrencyPair) string {
-	return strings.ToLower(cp.String() + "@kline_1m")
+// currencyPairToBinanceCandlePair receives a currency pair and a kline
+// interval and return binance candle symbol atomusdt@kline_1m.
+func currencyPairToBinanceCandlePair(cp types.CurrencyPair, interval string) string {
+	return strings.ToLower(cp.String() + "@kline_" + interval)
 }
 
 // newBinanceSubscriptionMsg returns a new subscription Msg.
@@ -284,3 +412,12 @@ func newBinanceSubscriptionMsg(params ...string) BinanceSubscriptionMsg {
 		ID:     1,
 	}
 }
+
+// newBinanceUnsubscriptionMsg returns a new unsubscription Msg.
+func newBinanceUnsubscriptionMsg(params ...string) BinanceSubscriptionMsg {
+	return BinanceSubscriptionMsg{
+		Method: "UNSUBSCRIBE",
+		Params: params,
+		ID:     1,
+	}
+}
@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"cosmossdk.io/math"
@@ -90,7 +92,7 @@ func TestBinanceCurrencyPairToBinancePair(t *testing.T) {
 
 func TestBinanceProvider_getSubscriptionMsgs(t *testing.T) {
 	provider := &BinanceProvider{
-		priceStore: newPriceStore(zerolog.Nop()),
+		priceStore: newPriceStore(zerolog.Nop(), defaultCandlePeriod),
 	}
 	cps := []types.CurrencyPair{
 		{Base: "ATOM", Quote: "USDT"},
@@ -103,4 +105,44 @@ func TestBinanceProvider_getSubscriptionMsgs(t *testing.T) {
 
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"atomusdt@kline_1m\"],\"id\":1}", string(msg))
+
+	provider.endpoints = Endpoint{Name: ProviderBinance, SecondaryCandleInterval: "5m"}
+	subMsgs = provider.getSubscriptionMsgs(cps...)
+	require.Len(t, subMsgs, 3)
+
+	msg, _ = json.Marshal(subMsgs[2])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"atomusdt@kline_5m\"],\"id\":1}", string(msg))
+}
+
+func TestBinanceProvider_pollRESTTickers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case binanceRestPath:
+			_, err = w.Write([]byte(`[{"symbol":"ATOMUSDT","price":"34.69000000"}]`))
+		case binanceRestTicker:
+			require.Equal(t, `["ATOMUSDT"]`, r.URL.Query().Get("symbols"))
+			_, err = w.Write([]byte(`[{"symbol":"ATOMUSDT","lastPrice":"34.69000000","volume":"2396974.02000000"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewBinanceProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderBinance, Rest: server.URL, Websocket: binanceWSHost},
+		false,
+		ATOMUSDT,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, p.pollRESTTickers())
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("34.69"), prices[ATOMUSDT].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
 }
@@ -0,0 +1,506 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	bitfinexWSHost          = "api-pub.bitfinex.com"
+	bitfinexWSPath          = "/ws/2"
+	bitfinexRestHost        = "https://api-pub.bitfinex.com"
+	bitfinexPairsPath       = "/v2/conf/pub:list:pair:exchange"
+	bitfinexTickerChannel   = "ticker"
+	bitfinexCandleChannel   = "candles"
+	bitfinexCandleKeyPrefix = "trade:1m:t"
+	bitfinexSymbolPrefix    = "t"
+	bitfinexEventInfo       = "info"
+	bitfinexEventSubscribed = "subscribed"
+	bitfinexEventError      = "error"
+	bitfinexHeartbeat       = `"hb"`
+
+	// bitfinexTickerLength and bitfinexCandleLength define the number of
+	// fields of the ticker and candle arrays.
+	bitfinexTickerLength = 10
+	bitfinexCandleLength = 6
+)
+
+var (
+	_ Provider = (*BitfinexProvider)(nil)
+
+	// bitfinexCurrencies maps the symbols whose Bitfinex ticker differs from
+	// the one used by the price feeder.
+	bitfinexCurrencies = map[string]string{
+		"USDT": "UST",
+		"USDC": "UDC",
+	}
+)
+
+type (
+	// BitfinexProvider defines an Oracle provider implemented by the Bitfinex
+	// public websocket v2 API. Bitfinex identifies the data messages of each
+	// subscription by a numeric channel id only, so the ids announced by the
+	// subscription acks are tracked to map the messages back to their pairs.
+	// The ids are only unique within a connection, so they are tracked per
+	// connection and reset each time it (re)connects.
+	//
+	// REF: https://docs.bitfinex.com/docs/ws-public
+	BitfinexProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		channelsMtx sync.RWMutex
+		channels    map[*WebsocketConnection]map[int64]bitfinexChannel // connection => chanId => subscribed channel
+
+		priceStore
+	}
+
+	// BitfinexSubscriptionMsg defines a ticker or candle subscription message.
+	BitfinexSubscriptionMsg struct {
+		Event   string `json:"event"`            // ex.: subscribe
+		Channel string `json:"channel"`          // ex.: ticker
+		Symbol  string `json:"symbol,omitempty"` // ex.: tBTCUSD
+		Key     string `json:"key,omitempty"`    // ex.: trade:1m:tBTCUSD
+	}
+
+	// BitfinexEvent defines an event message received over the websocket,
+	// such as a subscription ack or an error.
+	BitfinexEvent struct {
+		Event   string `json:"event"`   // ex.: subscribed
+		Channel string `json:"channel"` // ex.: ticker
+		ChanID  int64  `json:"chanId"`  // ex.: 224555
+		Symbol  string `json:"symbol"`  // ex.: tBTCUSD
+		Key     string `json:"key"`     // ex.: trade:1m:tBTCUSD
+		Msg     string `json:"msg"`     // ex.: symbol: invalid
+		Code    int64  `json:"code"`    // ex.: 10300
+	}
+
+	// BitfinexTicker defines the fields used from a ticker array:
+	// [BID, BID_SIZE, ASK, ASK_SIZE, DAILY_CHANGE, DAILY_CHANGE_RELATIVE,
+	// LAST_PRICE, VOLUME, HIGH, LOW].
+	BitfinexTicker struct {
+		LastPrice float64
+		Volume    float64
+	}
+
+	// BitfinexCandle defines the fields used from a candle array:
+	// [MTS, OPEN, CLOSE, HIGH, LOW, VOLUME].
+	BitfinexCandle struct {
+		TimeStamp int64 // Unix milliseconds of the open of the candle
+		Close     float64
+		Volume    float64
+	}
+
+	// bitfinexChannel defines the channel and the Bitfinex pair of a
+	// subscription, ex.: {candles, BTCUSD}.
+	bitfinexChannel struct {
+		channel string
+		pair    string
+	}
+)
+
+func NewBitfinexProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*BitfinexProvider, error) {
+	if endpoints.Name != ProviderBitfinex {
+		endpoints = Endpoint{
+			Name:      ProviderBitfinex,
+			Rest:      bitfinexRestHost,
+			Websocket: bitfinexWSHost,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   bitfinexWSPath,
+	}
+
+	bitfinexLogger := logger.With().Str("provider", string(ProviderBitfinex)).Logger()
+
+	provider := &BitfinexProvider{
+		logger:     bitfinexLogger,
+		endpoints:  endpoints,
+		channels:   map[*WebsocketConnection]map[int64]bitfinexChannel{},
+		priceStore: newPriceStore(bitfinexLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToBitfinexPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
+		bitfinexLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *BitfinexProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *BitfinexProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
+// getSubscriptionMsgs returns a ticker and a candle subscription message for
+// each currency pair.
+func (p *BitfinexProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	for _, cp := range cps {
+		bitfinexPair := currencyPairToBitfinexPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, BitfinexSubscriptionMsg{
+			Event:   "subscribe",
+			Channel: bitfinexTickerChannel,
+			Symbol:  bitfinexSymbolPrefix + bitfinexPair,
+		})
+		subscriptionMsgs = append(subscriptionMsgs, BitfinexSubscriptionMsg{
+			Event:   "subscribe",
+			Channel: bitfinexCandleChannel,
+			Key:     bitfinexCandleKeyPrefix + bitfinexPair,
+		})
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BitfinexProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// messageReceived handles both the event messages, which are JSON objects,
+// and the data messages, which are arrays starting with the channel id of
+// their subscription. The subscription acks are used to record the channel
+// and pair of each channel id, so the following data messages can be stored.
+func (p *BitfinexProvider) messageReceived(messageType int, conn *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	bz = bytes.TrimSpace(bz)
+	if len(bz) == 0 {
+		return
+	}
+
+	if bz[0] == '{' {
+		p.eventReceived(conn, bz)
+		return
+	}
+
+	var msg []json.RawMessage
+	if err := json.Unmarshal(bz, &msg); err != nil || len(msg) < 2 {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("response", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	if string(msg[1]) == bitfinexHeartbeat {
+		return
+	}
+
+	var chanID int64
+	if err := json.Unmarshal(msg[0], &chanID); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("chanId", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	channel, ok := p.getChannel(conn, chanID)
+	if !ok {
+		p.logger.Debug().Int64("chanId", chanID).Msg("message for unknown bitfinex channel")
+		return
+	}
+
+	var err error
+	switch channel.channel {
+	case bitfinexTickerChannel:
+		err = p.tickerReceived(msg[1], channel.pair)
+	case bitfinexCandleChannel:
+		err = p.candlesReceived(msg[1], channel.pair)
+	}
+	if err != nil {
+		p.logger.Error().
+			Int64("chanId", chanID).
+			Str("channel", channel.channel).
+			Str("pair", channel.pair).
+			AnErr("response", err).
+			Msg("Error on receive message")
+	}
+}
+
+// eventReceived handles an event message of the connection, resetting its
+// channel ids on the info message sent when it connects, recording the channel
+// id of the subscription acks and logging the errors.
+func (p *BitfinexProvider) eventReceived(conn *WebsocketConnection, bz []byte) {
+	var event BitfinexEvent
+	if err := json.Unmarshal(bz, &event); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("event", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	switch event.Event {
+	case bitfinexEventInfo:
+		p.resetChannels(conn)
+
+	case bitfinexEventSubscribed:
+		var pair string
+		switch event.Channel {
+		case bitfinexTickerChannel:
+			pair = strings.TrimPrefix(event.Symbol, bitfinexSymbolPrefix)
+		case bitfinexCandleChannel:
+			pair = strings.TrimPrefix(event.Key, bitfinexCandleKeyPrefix)
+		default:
+			return
+		}
+		p.setChannel(conn, event.ChanID, bitfinexChannel{channel: event.Channel, pair: pair})
+
+	case bitfinexEventError:
+		p.logger.Error().
+			Int64("code", event.Code).
+			Str("message", event.Msg).
+			Msg("bitfinex subscription failed")
+
+	default:
+		p.logger.Debug().Str("event", event.Event).Msg("bitfinex event received")
+	}
+}
+
+// tickerReceived stores the ticker array of the pair.
+func (p *BitfinexProvider) tickerReceived(bz json.RawMessage, pair string) error {
+	var fields []float64
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		return err
+	}
+	if len(fields) < bitfinexTickerLength {
+		return fmt.Errorf("invalid bitfinex ticker length: %d", len(fields))
+	}
+
+	p.setTickerPair(BitfinexTicker{LastPrice: fields[6], Volume: fields[7]}, pair)
+	telemetryWebsocketMessage(ProviderBitfinex, MessageTypeTicker)
+	return nil
+}
+
+// candlesReceived stores either a single candle array of the pair or the
+// snapshot of candle arrays sent right after the subscription.
+func (p *BitfinexProvider) candlesReceived(bz json.RawMessage, pair string) error {
+	var snapshot [][]float64
+	if err := json.Unmarshal(bz, &snapshot); err != nil {
+		var fields []float64
+		if err := json.Unmarshal(bz, &fields); err != nil {
+			return err
+		}
+		snapshot = [][]float64{fields}
+	}
+
+	for _, fields := range snapshot {
+		if len(fields) < bitfinexCandleLength {
+			return fmt.Errorf("invalid bitfinex candle length: %d", len(fields))
+		}
+		p.setCandlePair(BitfinexCandle{
+			TimeStamp: int64(fields[0]),
+			Close:     fields[2],
+			Volume:    fields[5],
+		}, pair)
+	}
+	telemetryWebsocketMessage(ProviderBitfinex, MessageTypeCandle)
+	return nil
+}
+
+func (p *BitfinexProvider) setChannel(conn *WebsocketConnection, chanID int64, channel bitfinexChannel) {
+	p.channelsMtx.Lock()
+	defer p.channelsMtx.Unlock()
+	if _, ok := p.channels[conn]; !ok {
+		p.channels[conn] = map[int64]bitfinexChannel{}
+	}
+	p.channels[conn][chanID] = channel
+}
+
+func (p *BitfinexProvider) getChannel(conn *WebsocketConnection, chanID int64) (bitfinexChannel, bool) {
+	p.channelsMtx.RLock()
+	defer p.channelsMtx.RUnlock()
+	channel, ok := p.channels[conn][chanID]
+	return channel, ok
+}
+
+// resetChannels forgets the channel ids of the connection, which are reassigned
+// when it reconnects.
+func (p *BitfinexProvider) resetChannels(conn *WebsocketConnection) {
+	p.channelsMtx.Lock()
+	defer p.channelsMtx.Unlock()
+	delete(p.channels, conn)
+}
+
+func (bt BitfinexTicker) toTickerPrice() (types.TickerPrice, error) {
+	if err := checkFinite(bt.LastPrice, bt.Volume); err != nil {
+		return types.TickerPrice{}, err
+	}
+	return types.NewTickerPrice(
+		strconv.FormatFloat(bt.LastPrice, 'f', -1, 64),
+		strconv.FormatFloat(bt.Volume, 'f', -1, 64),
+	)
+}
+
+func (bc BitfinexCandle) toCandlePrice() (types.CandlePrice, error) {
+	if err := checkFinite(bc.Close, bc.Volume); err != nil {
+		return types.CandlePrice{}, err
+	}
+	return types.NewCandlePrice(
+		strconv.FormatFloat(bc.Close, 'f', -1, 64),
+		strconv.FormatFloat(bc.Volume, 'f', -1, 64),
+		bc.TimeStamp,
+	)
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *BitfinexProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs of all exchange markets of Bitfinex.
+// ex.: map["BTCUSD" => {}, "ETHUSDT" => {}].
+func (p *BitfinexProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + bitfinexPairsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the response holds a single list of pairs, ex.: [["BTCUSD","AVAX:USD"]]
+	var pairsSummary [][]string
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{})
+	for _, pairs := range pairsSummary {
+		for _, pair := range pairs {
+			cp, ok := bitfinexPairToCurrencyPair(pair)
+			if !ok {
+				continue
+			}
+			availablePairs[cp.String()] = struct{}{}
+		}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToBitfinexPair receives a currency pair and returns the Bitfinex
+// pair, separating the symbols by a colon when one of them is longer than
+// three characters i.e: BTCUST, AVAX:USD
+func currencyPairToBitfinexPair(cp types.CurrencyPair) string {
+	base := bitfinexCurrency(cp.Base)
+	quote := bitfinexCurrency(cp.Quote)
+	if len(base) > 3 || len(quote) > 3 {
+		return base + ":" + quote
+	}
+	return base + quote
+}
+
+// bitfinexPairToCurrencyPair receives a Bitfinex pair and returns its currency
+// pair, or false if the pair can't be split into its symbols.
+func bitfinexPairToCurrencyPair(pair string) (types.CurrencyPair, bool) {
+	var base, quote string
+	if splitPair := strings.Split(pair, ":"); len(splitPair) == 2 {
+		base, quote = splitPair[0], splitPair[1]
+	} else if len(pair) == 6 {
+		base, quote = pair[:3], pair[3:]
+	} else {
+		return types.CurrencyPair{}, false
+	}
+
+	return types.CurrencyPair{
+		Base:  currencyFromBitfinex(base),
+		Quote: currencyFromBitfinex(quote),
+	}, true
+}
+
+// bitfinexCurrency returns the Bitfinex symbol of a currency.
+func bitfinexCurrency(currency string) string {
+	currency = strings.ToUpper(currency)
+	if bitfinexSymbol, ok := bitfinexCurrencies[currency]; ok {
+		return bitfinexSymbol
+	}
+	return currency
+}
+
+// currencyFromBitfinex returns the currency of a Bitfinex symbol.
+func currencyFromBitfinex(bitfinexSymbol string) string {
+	for currency, symbol := range bitfinexCurrencies {
+		if symbol == bitfinexSymbol {
+			return currency
+		}
+	}
+	return bitfinexSymbol
+}
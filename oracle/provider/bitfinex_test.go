@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBitfinexProvider(t *testing.T, pairs ...types.CurrencyPair) *BitfinexProvider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, bitfinexPairsPath, r.URL.Path)
+		_, err := w.Write([]byte(`[["BTCUSD","BTCUST","AVAX:UDC","TESTBTC:TESTUSD","INVALID"]]`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewBitfinexProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderBitfinex, Rest: server.URL, Websocket: "localhost"},
+		pairs...,
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestBitfinexProvider_GetAvailablePairs(t *testing.T) {
+	p := newTestBitfinexProvider(t, BTCUSD)
+
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Len(t, availablePairs, 4)
+	require.Contains(t, availablePairs, "BTCUSD")
+	require.Contains(t, availablePairs, "BTCUSDT")
+	require.Contains(t, availablePairs, "AVAXUSDC")
+	require.Contains(t, availablePairs, "TESTBTCTESTUSD")
+}
+
+func TestBitfinexProvider_MessageReceived(t *testing.T) {
+	p := newTestBitfinexProvider(t, BTCUSD, BTCUSDT)
+
+	t.Run("unknown_channel", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(
+			`[17470,[62000,1,62001,1,100,0.01,62000.5,1200,63000,61000]]`,
+		))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	p.messageReceived(websocket.TextMessage, nil, []byte(
+		`{"event":"subscribed","channel":"ticker","chanId":17470,"symbol":"tBTCUSD","pair":"BTCUSD"}`,
+	))
+	p.messageReceived(websocket.TextMessage, nil, []byte(
+		`{"event":"subscribed","channel":"candles","chanId":17471,"key":"trade:1m:tBTCUST"}`,
+	))
+
+	t.Run("heartbeat", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(`[17470,"hb"]`))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	t.Run("ticker", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(
+			`[17470,[62000,1,62001,1,100,0.01,62000.5,1200,63000,61000]]`,
+		))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62000.5"), prices[BTCUSD].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1200"), prices[BTCUSD].Volume)
+	})
+
+	t.Run("candles", func(t *testing.T) {
+		timeStamp := PastUnixTimeMillis(0)
+		p.messageReceived(websocket.TextMessage, nil, []byte(fmt.Sprintf(
+			`[17471,[[%d,10,10.5,11,9.5,300],[%d,10.5,10.25,10.75,10,200]]]`,
+			timeStamp-60000, timeStamp,
+		)))
+		prices, err := p.GetCandlePrices(BTCUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices[BTCUSDT], 2)
+
+		p.messageReceived(websocket.TextMessage, nil, []byte(fmt.Sprintf(
+			`[17471,[%d,10.5,10.75,10.75,10,250]]`,
+			timeStamp,
+		)))
+		prices, err = p.GetCandlePrices(BTCUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices[BTCUSDT], 3)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10.75"), prices[BTCUSDT][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("250"), prices[BTCUSDT][0].Volume)
+		require.Equal(t, timeStamp, prices[BTCUSDT][0].TimeStamp)
+	})
+
+	t.Run("low_price", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(
+			`[17470,[0.0000001,1,0.0000002,1,0,0,0.00000012345,1200,0.0000002,0.0000001]]`,
+		))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.00000012345"), prices[BTCUSD].Price)
+	})
+
+	t.Run("channel_ids_per_connection", func(t *testing.T) {
+		// the channel ids of another connection are unknown
+		_, ok := p.getChannel(nil, 17470)
+		require.True(t, ok)
+		_, ok = p.getChannel(&WebsocketConnection{}, 17470)
+		require.False(t, ok)
+
+		// a reconnection resets the channel ids of the connection
+		p.messageReceived(websocket.TextMessage, nil, []byte(`{"event":"info","version":2}`))
+		_, ok = p.getChannel(nil, 17470)
+		require.False(t, ok)
+	})
+}
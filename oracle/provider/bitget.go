@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -131,12 +130,12 @@ func NewBitgetProvider(
 	provider := &BitgetProvider{
 		logger:     bitgetLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(bitgetLogger),
+		priceStore: newPriceStore(bitgetLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -154,6 +153,7 @@ func NewBitgetProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.TextMessage,
+		endpoints.reconnectMaxInterval(),
 		bitgetLogger,
 	)
 	return provider, nil
@@ -163,6 +163,10 @@ func (p *BitgetProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *BitgetProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *BitgetProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, 1)
 	bitgetTickerSubscriptionMsg := newBitgetTickerSubscriptionMsg(cps)
@@ -181,7 +185,7 @@ func (p *BitgetProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -281,14 +285,14 @@ func (bcr BitgetCandleResponse) ToBitgetCandle() (BitgetCandle, error) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *BitgetProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + bitgetRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + bitgetRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary BitgetPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 	if pairsSummary.RespCode != "00000" {
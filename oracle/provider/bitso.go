@@ -0,0 +1,669 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	bitsoWSHost        = "ws.bitso.com"
+	bitsoRestHost      = "https://api.bitso.com"
+	bitsoRestPath      = "/v3/available_books"
+	bitsoOrderBookPath = "/v3/order_book/?aggregate=false&book="
+	bitsoTradesType    = "trades"
+	bitsoDiffOrderType = "diff-orders"
+	bitsoOrderOpen     = "open"
+	bitsoBuySide       = 0
+	bitsoVolumeWindow  = 24 * time.Hour
+
+	// bitsoOrderBookRetryInterval is the minimum interval between two
+	// attempts to fetch the order book snapshot of a book.
+	bitsoOrderBookRetryInterval = 5 * time.Second
+)
+
+var (
+	_ Provider = (*BitsoProvider)(nil)
+
+	// bitsoQuotes defines the quote currencies of the Bitso markets the
+	// provider subscribes to.
+	bitsoQuotes = map[string]struct{}{
+		"MXN": {},
+		"ARS": {},
+	}
+)
+
+type (
+	// BitsoProvider defines an Oracle provider implemented by the Bitso public
+	// API. Candles are built from the trades channel, and tickers use the
+	// mid-price of the order book, falling back to the last trade price while
+	// the book has no best bid and ask. The order book is fetched from the REST
+	// API and kept up to date with the diff-orders channel, whose updates must
+	// follow the sequence of the snapshot; the book is fetched again on a gap.
+	//
+	// REF: https://docs.bitso.com/bitso-api/docs/websocket-api
+	BitsoProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		books     map[string]*bitsoBook // book ex.: "btc_mxn" -> order book and trade volume
+
+		priceStore
+	}
+
+	// BitsoSubscriptionMsg Msg to subscribe to a channel of a book.
+	BitsoSubscriptionMsg struct {
+		Action string `json:"action"` // ex. "subscribe"
+		Book   string `json:"book"`   // ex. "btc_mxn"
+		Type   string `json:"type"`   // ex. "trades" or "diff-orders"
+	}
+
+	// BitsoSubscriptionResponse defines the response body for subscriptions.
+	BitsoSubscriptionResponse struct {
+		Action   string `json:"action"`   // ex. "subscribe"
+		Response string `json:"response"` // ex. "ok"
+		Type     string `json:"type"`     // ex. "trades"
+	}
+
+	// BitsoTradesResponse defines the response body for Bitso trades. The
+	// trades carry no time of their own, so they are dated when the message
+	// was sent.
+	BitsoTradesResponse struct {
+		Type    string       `json:"type"` // "trades"
+		Book    string       `json:"book"` // ex. "btc_mxn"
+		Payload []BitsoTrade `json:"payload"`
+		Sent    int64        `json:"sent"` // Time in unix epoch milliseconds ex.: 1715623040000
+	}
+
+	// BitsoTrade defines a single trade of a trades response.
+	BitsoTrade struct {
+		ID     int64  `json:"i"` // ex.: 77777
+		Amount string `json:"a"` // Size of the trade ex.: 0.0025
+		Rate   string `json:"r"` // Price of the trade ex.: 1145000.50
+	}
+
+	// BitsoDiffOrdersResponse defines the response body for Bitso diff-orders.
+	BitsoDiffOrdersResponse struct {
+		Type     string           `json:"type"`     // "diff-orders"
+		Book     string           `json:"book"`     // ex. "btc_mxn"
+		Sequence int64            `json:"sequence"` // ex. 27214
+		Payload  []BitsoDiffOrder `json:"payload"`
+	}
+
+	// BitsoDiffOrder defines a single order update of a diff-orders response.
+	BitsoDiffOrder struct {
+		OrderID string `json:"o"` // ex.: "ZMYv8rB1qQfgdBT5"
+		Rate    string `json:"r"` // Price of the order ex.: 1145000.50
+		Side    int    `json:"t"` // 0 for buy orders, 1 for sell orders
+		Status  string `json:"s"` // "open", "cancelled" or "completed"
+	}
+
+	// BitsoOrderBookResponse defines the response body for the Bitso order
+	// book.
+	BitsoOrderBookResponse struct {
+		Payload BitsoOrderBook `json:"payload"`
+	}
+
+	// BitsoOrderBook defines a snapshot of the order book of a Bitso book.
+	BitsoOrderBook struct {
+		Asks     []BitsoBookOrder `json:"asks"`
+		Bids     []BitsoBookOrder `json:"bids"`
+		Sequence string           `json:"sequence"` // ex. "27214"
+	}
+
+	// BitsoBookOrder defines a single order of an order book snapshot.
+	BitsoBookOrder struct {
+		Price   string `json:"price"` // ex.: 1145000.50
+		OrderID string `json:"oid"`   // ex.: "ZMYv8rB1qQfgdBT5"
+	}
+
+	// BitsoTicker defines the ticker info we'd like to save.
+	BitsoTicker struct {
+		Book   string // ex.: "btc_mxn"
+		Price  string // ex.: 1145000.50
+		Volume string // 24-hour volume of the trades received
+	}
+
+	// BitsoPairsSummary defines the response structure for the Bitso
+	// available books.
+	BitsoPairsSummary struct {
+		Payload []BitsoPairData `json:"payload"`
+	}
+
+	// BitsoPairData defines the data response structure for a Bitso book.
+	BitsoPairData struct {
+		Book string `json:"book"` // ex. "btc_mxn"
+	}
+
+	// bitsoBook defines the order book and recent trades of a Bitso book.
+	bitsoBook struct {
+		bids      map[string]math.LegacyDec // order id -> price
+		asks      map[string]math.LegacyDec // order id -> price
+		trades    []types.Trade
+		lastPrice string
+
+		synced   bool                      // whether the order book was built from a snapshot
+		sequence int64                     // sequence of the last order update applied
+		fetching bool                      // whether a snapshot is being fetched
+		failedAt time.Time                 // time of the last failure to fetch a snapshot
+		pending  []BitsoDiffOrdersResponse // order updates received while not synced
+	}
+)
+
+// NewBitsoProvider creates a new BitsoProvider.
+func NewBitsoProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*BitsoProvider, error) {
+	if endpoints.Name != ProviderBitso {
+		endpoints = Endpoint{
+			Name:      ProviderBitso,
+			Rest:      bitsoRestHost,
+			Websocket: bitsoWSHost,
+		}
+	}
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+	}
+
+	bitsoLogger := logger.With().Str("provider", string(ProviderBitso)).Logger()
+
+	provider := &BitsoProvider{
+		logger:     bitsoLogger,
+		endpoints:  endpoints,
+		books:      map[string]*bitsoBook{},
+		priceStore: newPriceStore(bitsoLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToBitsoPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
+		bitsoLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *BitsoProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *BitsoProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
+func (p *BitsoProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	for _, cp := range cps {
+		bitsoPair := currencyPairToBitsoPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newBitsoSubscriptionMsg(bitsoPair, bitsoTradesType))
+		subscriptionMsgs = append(subscriptionMsgs, newBitsoSubscriptionMsg(bitsoPair, bitsoDiffOrderType))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BitsoProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// GetAvailablePairs returns all MXN and ARS pairs to which the provider can
+// subscribe.
+func (p *BitsoProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + bitsoRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary BitsoPairsSummary
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Payload))
+	for _, pair := range pairsSummary.Payload {
+		cp, err := bitsoPairToCurrencyPair(pair.Book)
+		if err != nil {
+			continue
+		}
+		if _, ok := bitsoQuotes[cp.Quote]; !ok {
+			continue
+		}
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+func (p *BitsoProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
+	var (
+		subscriptionResp BitsoSubscriptionResponse
+		tradesResp       BitsoTradesResponse
+		diffOrdersResp   BitsoDiffOrdersResponse
+	)
+
+	if err := json.Unmarshal(bz, &subscriptionResp); err != nil {
+		p.logger.Error().Err(err).Msg("unable to unmarshal response")
+		return
+	}
+
+	if subscriptionResp.Action == "subscribe" {
+		if subscriptionResp.Response != "ok" {
+			p.logger.Error().Str("type", subscriptionResp.Type).Msg("failed to subscribe to channel")
+		}
+		return
+	}
+
+	switch subscriptionResp.Type {
+	case bitsoTradesType:
+		if err := json.Unmarshal(bz, &tradesResp); err != nil {
+			p.logger.Error().Err(err).Msg("unable to unmarshal trades response")
+			return
+		}
+		telemetryWebsocketMessage(ProviderBitso, MessageTypeTrade)
+		p.setTrades(tradesResp)
+
+	case bitsoDiffOrderType:
+		if err := json.Unmarshal(bz, &diffOrdersResp); err != nil {
+			p.logger.Error().Err(err).Msg("unable to unmarshal diff-orders response")
+			return
+		}
+		telemetryWebsocketMessage(ProviderBitso, MessageTypeTicker)
+		p.setDiffOrders(diffOrdersResp)
+	}
+}
+
+// setTrades adds the trades to the candles of the book and updates its ticker.
+func (p *BitsoProvider) setTrades(tradesResp BitsoTradesResponse) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	sent := tradesResp.Sent
+	if sent == 0 {
+		sent = time.Now().UnixMilli()
+	}
+
+	book := p.getBook(tradesResp.Book)
+	for _, trade := range tradesResp.Payload {
+		t := trade.toTrade(sent)
+		p.addTradeToCandles(t, tradesResp.Book)
+		book.trades = append(book.trades, t)
+		book.lastPrice = trade.Rate
+	}
+
+	p.setTickerPair(book.toTicker(tradesResp.Book), tradesResp.Book)
+}
+
+// setDiffOrders applies the order updates to the order book of the book and
+// updates its ticker. The updates received before the order book snapshot,
+// or after a gap in their sequence, are kept until the snapshot is fetched.
+func (p *BitsoProvider) setDiffOrders(diffOrdersResp BitsoDiffOrdersResponse) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	book := p.getBook(diffOrdersResp.Book)
+	switch {
+	case !book.synced:
+		book.pending = append(book.pending, diffOrdersResp)
+		p.fetchOrderBook(book, diffOrdersResp.Book)
+		return
+
+	case diffOrdersResp.Sequence <= book.sequence:
+		return
+
+	case diffOrdersResp.Sequence != book.sequence+1:
+		p.logger.Warn().
+			Str("book", diffOrdersResp.Book).
+			Int64("sequence", diffOrdersResp.Sequence).
+			Int64("expected_sequence", book.sequence+1).
+			Msg("order update out of sequence; fetching the order book again")
+		book.reset()
+		book.pending = append(book.pending, diffOrdersResp)
+		p.fetchOrderBook(book, diffOrdersResp.Book)
+		return
+	}
+
+	p.applyDiffOrders(book, diffOrdersResp)
+	p.setBookTicker(book, diffOrdersResp.Book)
+}
+
+// applyDiffOrders applies the order updates to the order book.
+// Does not acquire lock - must be called from parent function.
+func (p *BitsoProvider) applyDiffOrders(book *bitsoBook, diffOrdersResp BitsoDiffOrdersResponse) {
+	for _, order := range diffOrdersResp.Payload {
+		if err := book.applyDiffOrder(order); err != nil {
+			p.logger.Error().Err(err).Str("book", diffOrdersResp.Book).Msg("failed to apply order update")
+		}
+	}
+	book.sequence = diffOrdersResp.Sequence
+}
+
+// setBookTicker updates the ticker of the book once it has a price.
+// Does not acquire lock - must be called from parent function.
+func (p *BitsoProvider) setBookTicker(book *bitsoBook, bookName string) {
+	if book.lastPrice == "" && !book.hasSpread() {
+		return
+	}
+	p.setTickerPair(book.toTicker(bookName), bookName)
+}
+
+// fetchOrderBook fetches the order book snapshot of the book in the
+// background, unless it is already being fetched or failed to be fetched too
+// recently.
+// Does not acquire lock - must be called from parent function.
+func (p *BitsoProvider) fetchOrderBook(book *bitsoBook, bookName string) {
+	if book.fetching || time.Since(book.failedAt) < bitsoOrderBookRetryInterval {
+		return
+	}
+	book.fetching = true
+
+	go p.syncOrderBook(bookName)
+}
+
+// syncOrderBook fetches the order book snapshot of the book, and then
+// applies the order updates received since, in sequence.
+func (p *BitsoProvider) syncOrderBook(bookName string) {
+	snapshot, err := p.getOrderBook(bookName)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	book := p.getBook(bookName)
+	book.fetching = false
+	if err != nil {
+		p.logger.Error().Err(err).Str("book", bookName).Msg("failed to fetch order book")
+		book.failedAt = time.Now()
+		book.pending = nil
+		return
+	}
+	if err := book.setSnapshot(snapshot); err != nil {
+		p.logger.Error().Err(err).Str("book", bookName).Msg("failed to parse order book")
+		book.failedAt = time.Now()
+		book.pending = nil
+		return
+	}
+
+	pending := book.pending
+	book.pending = nil
+	for i, diffOrdersResp := range pending {
+		if diffOrdersResp.Sequence <= book.sequence {
+			continue
+		}
+		if diffOrdersResp.Sequence != book.sequence+1 {
+			p.logger.Warn().
+				Str("book", bookName).
+				Int64("sequence", diffOrdersResp.Sequence).
+				Int64("expected_sequence", book.sequence+1).
+				Msg("order update out of sequence; fetching the order book again")
+			book.reset()
+			book.pending = pending[i:]
+			p.fetchOrderBook(book, bookName)
+			return
+		}
+		p.applyDiffOrders(book, diffOrdersResp)
+	}
+
+	p.setBookTicker(book, bookName)
+}
+
+// getOrderBook returns the order book snapshot of the book from the REST API.
+func (p *BitsoProvider) getOrderBook(bookName string) (BitsoOrderBook, error) {
+	resp, err := p.endpoints.httpGetWithRetries(p.endpoints.Rest + bitsoOrderBookPath + bookName)
+	if err != nil {
+		return BitsoOrderBook{}, err
+	}
+	defer resp.Body.Close()
+
+	var orderBookResp BitsoOrderBookResponse
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&orderBookResp); err != nil {
+		return BitsoOrderBook{}, err
+	}
+	return orderBookResp.Payload, nil
+}
+
+// getBook returns the order book of the given book, creating it if needed.
+// Does not acquire lock - must be called from parent function.
+func (p *BitsoProvider) getBook(bookName string) *bitsoBook {
+	book, ok := p.books[bookName]
+	if !ok {
+		book = &bitsoBook{
+			bids: map[string]math.LegacyDec{},
+			asks: map[string]math.LegacyDec{},
+		}
+		p.books[bookName] = book
+	}
+	return book
+}
+
+// reset clears the order book until a new snapshot is fetched.
+func (b *bitsoBook) reset() {
+	b.bids = map[string]math.LegacyDec{}
+	b.asks = map[string]math.LegacyDec{}
+	b.synced = false
+	b.sequence = 0
+}
+
+// setSnapshot replaces the order book with the snapshot.
+func (b *bitsoBook) setSnapshot(snapshot BitsoOrderBook) error {
+	sequence, err := strconv.ParseInt(snapshot.Sequence, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse order book sequence (%s): %w", snapshot.Sequence, err)
+	}
+
+	bids := make(map[string]math.LegacyDec, len(snapshot.Bids))
+	asks := make(map[string]math.LegacyDec, len(snapshot.Asks))
+	for _, side := range []struct {
+		orders []BitsoBookOrder
+		prices map[string]math.LegacyDec
+	}{
+		{snapshot.Bids, bids},
+		{snapshot.Asks, asks},
+	} {
+		for _, order := range side.orders {
+			price, err := math.LegacyNewDecFromStr(order.Price)
+			if err != nil {
+				return fmt.Errorf("failed to parse order price (%s): %w", order.Price, err)
+			}
+			side.prices[order.OrderID] = price
+		}
+	}
+
+	b.bids = bids
+	b.asks = asks
+	b.sequence = sequence
+	b.synced = true
+	return nil
+}
+
+// applyDiffOrder adds an open order to the book or removes a cancelled or
+// completed order from it.
+func (b *bitsoBook) applyDiffOrder(order BitsoDiffOrder) error {
+	side := b.asks
+	if order.Side == bitsoBuySide {
+		side = b.bids
+	}
+
+	if order.Status != bitsoOrderOpen {
+		delete(side, order.OrderID)
+		return nil
+	}
+
+	price, err := math.LegacyNewDecFromStr(order.Rate)
+	if err != nil {
+		return fmt.Errorf("failed to parse order price (%s): %w", order.Rate, err)
+	}
+	side[order.OrderID] = price
+	return nil
+}
+
+// bestBidAndAsk returns the highest bid and lowest ask of the book.
+func (b *bitsoBook) bestBidAndAsk() (bid, ask math.LegacyDec, ok bool) {
+	if len(b.bids) == 0 || len(b.asks) == 0 {
+		return math.LegacyDec{}, math.LegacyDec{}, false
+	}
+
+	first := true
+	for _, price := range b.bids {
+		if first || price.GT(bid) {
+			bid = price
+			first = false
+		}
+	}
+	first = true
+	for _, price := range b.asks {
+		if first || price.LT(ask) {
+			ask = price
+			first = false
+		}
+	}
+	return bid, ask, true
+}
+
+// hasSpread returns true if the book has a best bid and ask which are not
+// crossed.
+func (b *bitsoBook) hasSpread() bool {
+	bid, ask, ok := b.bestBidAndAsk()
+	return ok && bid.LT(ask)
+}
+
+// toTicker returns the ticker of the book, using the mid-price of the order
+// book if it has a spread and the last trade price otherwise. The volume is
+// the sum of the trades received within the last 24 hours.
+func (b *bitsoBook) toTicker(bookName string) BitsoTicker {
+	price := b.lastPrice
+	if b.hasSpread() {
+		bid, ask, _ := b.bestBidAndAsk()
+		price = bid.Add(ask).QuoInt64(2).String()
+	}
+
+	staleTime := PastUnixTimeMillis(bitsoVolumeWindow)
+	recentTrades := make([]types.Trade, 0, len(b.trades))
+	volume := math.LegacyZeroDec()
+	for _, trade := range b.trades {
+		if trade.Time < staleTime {
+			continue
+		}
+		amount, err := math.LegacyNewDecFromStr(trade.Size)
+		if err != nil {
+			continue
+		}
+		recentTrades = append(recentTrades, trade)
+		volume = volume.Add(amount)
+	}
+	b.trades = recentTrades
+
+	return BitsoTicker{
+		Book:   bookName,
+		Price:  price,
+		Volume: volume.String(),
+	}
+}
+
+// toTrade returns the trade dated at the given time in unix epoch
+// milliseconds.
+func (trade BitsoTrade) toTrade(timestamp int64) types.Trade {
+	return types.Trade{
+		Time:  timestamp,
+		Price: trade.Rate,
+		Size:  trade.Amount,
+	}
+}
+
+func (ticker BitsoTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(
+		ticker.Price,
+		ticker.Volume,
+	)
+}
+
+// currencyPairToBitsoPair returns the expected pair for Bitso
+// ex.: "btc_mxn".
+func currencyPairToBitsoPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + "_" + cp.Quote)
+}
+
+// bitsoPairToCurrencyPair returns the currency pair of a Bitso book
+// ex.: "btc_mxn" -> BTC/MXN.
+func bitsoPairToCurrencyPair(book string) (types.CurrencyPair, error) {
+	base, quote, ok := strings.Cut(book, "_")
+	if !ok || base == "" || quote == "" {
+		return types.CurrencyPair{}, fmt.Errorf("invalid bitso book: %s", book)
+	}
+	return types.CurrencyPair{
+		Base:  strings.ToUpper(base),
+		Quote: strings.ToUpper(quote),
+	}, nil
+}
+
+// newBitsoSubscriptionMsg returns a new subscription Msg for a channel of a
+// book.
+func newBitsoSubscriptionMsg(book, channel string) BitsoSubscriptionMsg {
+	return BitsoSubscriptionMsg{
+		Action: "subscribe",
+		Book:   book,
+		Type:   channel,
+	}
+}
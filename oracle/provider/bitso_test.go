@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitsoProvider_MessageReceived(t *testing.T) {
+	btcMXN := types.CurrencyPair{Base: "BTC", Quote: "MXN"}
+
+	var orderBook atomic.Value
+	orderBook.Store(`{"success":true,"payload":{"sequence":"4",` +
+		`"bids":[{"book":"btc_mxn","price":"1144000.00","amount":"0.1","oid":"bid1"}],` +
+		`"asks":[{"book":"btc_mxn","price":"1146500.00","amount":"0.1","oid":"ask2"}]}}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case bitsoRestPath:
+			_, _ = w.Write([]byte(`{"success":true,"payload":[{"book":"btc_mxn"}]}`))
+		default:
+			require.Equal(t, "btc_mxn", r.URL.Query().Get("book"))
+			_, _ = w.Write([]byte(orderBook.Load().(string)))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewBitsoProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderBitso, Rest: server.URL, Websocket: "localhost"},
+		btcMXN,
+	)
+	require.NoError(t, err)
+
+	tickerPrice := func() math.LegacyDec {
+		prices, err := p.GetTickerPrices(btcMXN)
+		require.NoError(t, err)
+		return prices[btcMXN].Price
+	}
+
+	t.Run("subscription_response", func(t *testing.T) {
+		p.messageReceived(0, nil, []byte(`{"action":"subscribe","response":"ok","time":1715623040000,"type":"trades"}`))
+
+		prices, err := p.GetTickerPrices(btcMXN)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	t.Run("trades", func(t *testing.T) {
+		now := time.Now().UnixMilli()
+		p.messageReceived(0, nil, []byte(fmt.Sprintf(
+			`{"type":"trades","book":"btc_mxn","payload":[`+
+				`{"i":1,"a":"0.5","r":"1145000.00","v":"572500.00","t":0,"mo":"o1","to":"o2"},`+
+				`{"i":2,"a":"0.25","r":"1146000.00","v":"286500.00","t":1,"mo":"o3","to":"o4"}],"sent":%d}`,
+			now,
+		)))
+
+		prices, err := p.GetTickerPrices(btcMXN)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1146000.00"), prices[btcMXN].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.75"), prices[btcMXN].Volume)
+
+		candles, err := p.GetCandlePrices(btcMXN)
+		require.NoError(t, err)
+		require.Len(t, candles[btcMXN], 1)
+		// the candle is stamped with the close of the minute the trades were
+		// sent in
+		require.Equal(t, time.UnixMilli(now).Truncate(time.Minute).Add(time.Minute).UnixMilli(), candles[btcMXN][0].TimeStamp)
+	})
+
+	t.Run("diff_orders", func(t *testing.T) {
+		// the updates are applied on top of the order book snapshot, once
+		// fetched, and the ones already in the snapshot are skipped
+		p.messageReceived(0, nil, []byte(
+			`{"type":"diff-orders","book":"btc_mxn","sequence":4,"payload":[`+
+				`{"d":1715623040000,"r":"1140000.00","t":0,"a":"0.1","v":"114000.00","o":"bid0","s":"open"}]}`,
+		))
+		p.messageReceived(0, nil, []byte(
+			`{"type":"diff-orders","book":"btc_mxn","sequence":5,"payload":[`+
+				`{"d":1715623040000,"r":"1144500.00","t":0,"a":"0.1","v":"114450.00","o":"bid2","s":"open"},`+
+				`{"d":1715623040000,"r":"1145500.00","t":1,"a":"0.1","v":"114550.00","o":"ask1","s":"open"}]}`,
+		))
+
+		require.Eventually(t, func() bool {
+			return tickerPrice().Equal(math.LegacyMustNewDecFromStr("1145000.00"))
+		}, time.Second, 10*time.Millisecond)
+
+		prices, err := p.GetTickerPrices(btcMXN)
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.75"), prices[btcMXN].Volume)
+
+		// the best bid is cancelled
+		p.messageReceived(0, nil, []byte(
+			`{"type":"diff-orders","book":"btc_mxn","sequence":6,"payload":[`+
+				`{"d":1715623041000,"r":"1144500.00","t":0,"o":"bid2","s":"cancelled"}]}`,
+		))
+		require.Equal(t, math.LegacyMustNewDecFromStr("1144750.00"), tickerPrice())
+	})
+
+	t.Run("diff_orders_sequence_gap", func(t *testing.T) {
+		orderBook.Store(`{"success":true,"payload":{"sequence":"7",` +
+			`"bids":[{"book":"btc_mxn","price":"1144000.00","amount":"0.1","oid":"bid1"}],` +
+			`"asks":[{"book":"btc_mxn","price":"1145500.00","amount":"0.1","oid":"ask1"}]}}`)
+
+		// the update of sequence 7 is missed, so the order book is fetched
+		// again before the update of sequence 8 is applied
+		p.messageReceived(0, nil, []byte(
+			`{"type":"diff-orders","book":"btc_mxn","sequence":8,"payload":[`+
+				`{"d":1715623042000,"r":"1145000.00","t":0,"a":"0.1","v":"114500.00","o":"bid3","s":"open"}]}`,
+		))
+
+		require.Eventually(t, func() bool {
+			return tickerPrice().Equal(math.LegacyMustNewDecFromStr("1145250.00"))
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, _ := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.Empty(t, prices)
+	})
+}
+
+func TestBitsoCurrencyPairToBitsoPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "BTC", Quote: "MXN"}
+	require.Equal(t, "btc_mxn", currencyPairToBitsoPair(cp))
+
+	bitsoCP, err := bitsoPairToCurrencyPair("btc_mxn")
+	require.NoError(t, err)
+	require.Equal(t, cp, bitsoCP)
+
+	bitsoCP, err = bitsoPairToCurrencyPair("usdt_ars")
+	require.NoError(t, err)
+	require.Equal(t, types.CurrencyPair{Base: "USDT", Quote: "ARS"}, bitsoCP)
+
+	_, err = bitsoPairToCurrencyPair("btcmxn")
+	require.Error(t, err)
+}
@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	bybitWSHost           = "stream.bybit.com"
+	bybitWSPath           = "/v5/public/spot"
+	bybitRestHost         = "https://api.bybit.com"
+	bybitRestPath         = "/v5/market/instruments-info?category=spot"
+	bybitTickerTopic      = "tickers"
+	bybitCandleTopic      = "kline.1"
+	bybitInstrumentStatus = "Trading"
+)
+
+var _ Provider = (*BybitProvider)(nil)
+
+type (
+	// BybitProvider defines an Oracle provider implemented by the Bybit v5
+	// public API.
+	//
+	// REF: https://bybit-exchange.github.io/docs/v5/websocket/public/ticker
+	// REF: https://bybit-exchange.github.io/docs/v5/websocket/public/kline
+	BybitProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		priceStore
+	}
+
+	// BybitSubscriptionMsg Msg to subscribe/unsubscribe to the topics of a pair.
+	BybitSubscriptionMsg struct {
+		Op   string   `json:"op"`   // Operation ex.: subscribe
+		Args []string `json:"args"` // Topics ex.: tickers.BTCUSDT
+	}
+
+	// BybitSubscriptionResponse defines the response of a subscription request.
+	BybitSubscriptionResponse struct {
+		Success bool   `json:"success"` // ex.: true
+		RetMsg  string `json:"ret_msg"` // ex.: "subscribe" or the error message
+		Op      string `json:"op"`      // ex.: "subscribe"
+	}
+
+	// BybitTickerResponse defines the response structure of a Bybit ticker
+	// message.
+	BybitTickerResponse struct {
+		Topic string      `json:"topic"` // ex.: tickers.BTCUSDT
+		Data  BybitTicker `json:"data"`
+	}
+
+	// BybitTicker defines a ticker of Bybit.
+	BybitTicker struct {
+		Symbol    string `json:"symbol"`    // Symbol ex.: BTCUSDT
+		LastPrice string `json:"lastPrice"` // Last traded price ex.: 21109.77
+		Volume24h string `json:"volume24h"` // 24h volume in the base coin ex.: 6780.866843
+	}
+
+	// BybitCandleResponse defines the response structure of a Bybit kline
+	// message.
+	BybitCandleResponse struct {
+		Topic string        `json:"topic"` // ex.: kline.1.BTCUSDT
+		Data  []BybitCandle `json:"data"`
+	}
+
+	// BybitCandle defines a candle of Bybit.
+	BybitCandle struct {
+		Start  int64  `json:"start"`  // Start timestamp in milliseconds
+		Close  string `json:"close"`  // Close price for this time period
+		Volume string `json:"volume"` // Volume in the base coin for this time period
+	}
+
+	// BybitPairsSummary defines the response structure for the Bybit spot
+	// instruments info.
+	BybitPairsSummary struct {
+		RetCode int `json:"retCode"`
+		Result  struct {
+			List []BybitPairData `json:"list"`
+		} `json:"result"`
+	}
+
+	// BybitPairData defines a spot instrument of Bybit.
+	BybitPairData struct {
+		Symbol    string `json:"symbol"`    // ex.: BTCUSDT
+		BaseCoin  string `json:"baseCoin"`  // ex.: BTC
+		QuoteCoin string `json:"quoteCoin"` // ex.: USDT
+		Status    string `json:"status"`    // ex.: Trading
+	}
+)
+
+// NewBybitProvider creates a new BybitProvider.
+func NewBybitProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*BybitProvider, error) {
+	if endpoints.Name != ProviderBybit {
+		endpoints = Endpoint{
+			Name:      ProviderBybit,
+			Rest:      bybitRestHost,
+			Websocket: bybitWSHost,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   bybitWSPath,
+	}
+
+	bybitLogger := logger.With().Str("provider", string(ProviderBybit)).Logger()
+
+	provider := &BybitProvider{
+		logger:     bybitLogger,
+		endpoints:  endpoints,
+		priceStore: newPriceStore(bybitLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
+		bybitLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *BybitProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *BybitProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
+func (p *BybitProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps))
+	for _, cp := range cps {
+		subscriptionMsgs = append(subscriptionMsgs, newBybitSubscriptionMsg(bybitPairTopics(cp)...))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BybitProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// UnsubscribeCurrencyPairs sends the unsubscription messages for the currency
+// pairs to the websocket, closes their connections and removes them and their
+// prices from the providers subscribedPairs array
+func (p *BybitProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	subscribedPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if p.isSubscribed(cp.String()) {
+			subscribedPairs = append(subscribedPairs, cp)
+		}
+	}
+
+	subscriptionMsgs := p.getSubscriptionMsgs(subscribedPairs...)
+	unsubscriptionMsgs := make([]interface{}, 0, len(subscribedPairs))
+	for _, cp := range subscribedPairs {
+		unsubscriptionMsgs = append(unsubscriptionMsgs, newBybitUnsubscriptionMsg(bybitPairTopics(cp)...))
+	}
+
+	p.wsc.RemoveWebsocketConnections(subscriptionMsgs, unsubscriptionMsgs)
+	p.removeSubscribedPairs(subscribedPairs...)
+}
+
+func (p *BybitProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
+	var (
+		subscriptionResp BybitSubscriptionResponse
+		tickerResp       BybitTickerResponse
+		tickerErr        error
+		candleResp       BybitCandleResponse
+		candleErr        error
+	)
+
+	err := json.Unmarshal(bz, &subscriptionResp)
+	if err == nil && subscriptionResp.Op != "" {
+		if !subscriptionResp.Success {
+			p.logger.Error().
+				Str("op", subscriptionResp.Op).
+				Str("msg", subscriptionResp.RetMsg).
+				Msg("Bybit subscription failed")
+		}
+		return
+	}
+
+	tickerErr = json.Unmarshal(bz, &tickerResp)
+	if strings.HasPrefix(tickerResp.Topic, bybitTickerTopic+".") {
+		p.setTickerPair(tickerResp.Data, tickerResp.Data.Symbol)
+		telemetryWebsocketMessage(ProviderBybit, MessageTypeTicker)
+		return
+	}
+
+	candleErr = json.Unmarshal(bz, &candleResp)
+	if strings.HasPrefix(candleResp.Topic, bybitCandleTopic+".") {
+		symbol := strings.TrimPrefix(candleResp.Topic, bybitCandleTopic+".")
+		for _, candle := range candleResp.Data {
+			p.setCandlePair(candle, symbol)
+			telemetryWebsocketMessage(ProviderBybit, MessageTypeCandle)
+		}
+		return
+	}
+
+	p.logger.Error().
+		Int("length", len(bz)).
+		AnErr("ticker", tickerErr).
+		AnErr("candle", candleErr).
+		Msg("Error on receive message")
+}
+
+// GetAvailablePairs returns all spot pairs currently trading on Bybit.
+func (p *BybitProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + bybitRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary BybitPairsSummary
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+	if pairsSummary.RetCode != 0 {
+		return nil, fmt.Errorf("unable to get bybit available pairs")
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Result.List))
+	for _, pair := range pairsSummary.Result.List {
+		if pair.Status != bybitInstrumentStatus {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  pair.BaseCoin,
+			Quote: pair.QuoteCoin,
+		}
+		availablePairs[strings.ToUpper(cp.String())] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+func (ticker BybitTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.LastPrice, ticker.Volume24h)
+}
+
+func (candle BybitCandle) toCandlePrice() (types.CandlePrice, error) {
+	return types.NewCandlePrice(candle.Close, candle.Volume, candle.Start)
+}
+
+// bybitPairTopics returns the ticker and candle topics of a pair
+// ex.: "tickers.BTCUSDT" and "kline.1.BTCUSDT".
+func bybitPairTopics(cp types.CurrencyPair) []string {
+	return []string{
+		bybitTickerTopic + "." + cp.String(),
+		bybitCandleTopic + "." + cp.String(),
+	}
+}
+
+// newBybitSubscriptionMsg returns a new subscription Msg for Bybit.
+func newBybitSubscriptionMsg(topics ...string) BybitSubscriptionMsg {
+	return BybitSubscriptionMsg{
+		Op:   "subscribe",
+		Args: topics,
+	}
+}
+
+// newBybitUnsubscriptionMsg returns a new unsubscription Msg for Bybit.
+func newBybitUnsubscriptionMsg(topics ...string) BybitSubscriptionMsg {
+	return BybitSubscriptionMsg{
+		Op:   "unsubscribe",
+		Args: topics,
+	}
+}
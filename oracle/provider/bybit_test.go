@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// newBybitTestServer returns a server serving the spot instruments of Bybit.
+func newBybitTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path+"?"+r.URL.RawQuery != bybitRestPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(`{"retCode":0,"result":{"list":[
+			{"symbol":"ATOMUSDT","baseCoin":"ATOM","quoteCoin":"USDT","status":"Trading"},
+			{"symbol":"BTCUSDT","baseCoin":"BTC","quoteCoin":"USDT","status":"Trading"},
+			{"symbol":"FOOBAR","baseCoin":"FOO","quoteCoin":"BAR","status":"Closed"}
+		]}}`))
+		require.NoError(t, err)
+	}))
+}
+
+func newBybitTestProvider(t *testing.T, pairs ...types.CurrencyPair) *BybitProvider {
+	server := newBybitTestServer(t)
+	t.Cleanup(server.Close)
+
+	p, err := NewBybitProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderBybit, Rest: server.URL, Websocket: bybitWSHost},
+		pairs...,
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestBybitProvider_GetTickerPrices(t *testing.T) {
+	p := newBybitTestProvider(t, types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+
+	t.Run("valid_request_single_ticker", func(t *testing.T) {
+		lastPrice := "34.69000000"
+		volume := "2396974.02000000"
+		symbol := "ATOMUSDT"
+
+		bybitTicker := BybitTicker{
+			Symbol:    symbol,
+			LastPrice: lastPrice,
+			Volume24h: volume,
+		}
+		p.setTickerPair(bybitTicker, symbol)
+
+		prices, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr(lastPrice), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[ATOMUSDT].Volume)
+	})
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		atomLastPrice := "34.69000000"
+		lunaLastPrice := "41.35000000"
+		volume := "2396974.02000000"
+
+		tickers := []BybitTicker{
+			{
+				Symbol:    "ATOMUSDT",
+				LastPrice: atomLastPrice,
+				Volume24h: volume,
+			},
+			{
+				Symbol:    "LUNAUSDT",
+				LastPrice: lunaLastPrice,
+				Volume24h: volume,
+			},
+		}
+
+		for _, bybitTicker := range tickers {
+			p.setTickerPair(bybitTicker, bybitTicker.Symbol)
+		}
+
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "ATOM", Quote: "USDT"},
+			types.CurrencyPair{Base: "LUNA", Quote: "USDT"},
+		)
+
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr(atomLastPrice), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[ATOMUSDT].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr(lunaLastPrice), prices[LUNAUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[LUNAUSDT].Volume)
+	})
+
+	t.Run("valid_ticker_message", func(t *testing.T) {
+		p.messageReceived(0, nil, []byte(`{
+			"topic":"tickers.ATOMUSDT",
+			"type":"snapshot",
+			"data":{"symbol":"ATOMUSDT","lastPrice":"10.5","volume24h":"1000"}
+		}`))
+
+		prices, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10.5"), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1000"), prices[ATOMUSDT].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, _ := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.Empty(t, prices)
+	})
+}
+
+func TestBybitProvider_GetCandlePrices(t *testing.T) {
+	p := newBybitTestProvider(t, ATOMUSDT)
+
+	t.Run("valid_request_single_candle", func(t *testing.T) {
+		price := "34.689998626708984000"
+		volume := "2396974.000000000000000000"
+		timeStamp := int64(1000000)
+
+		bybitCandle := BybitCandle{
+			Start:  timeStamp,
+			Close:  price,
+			Volume: volume,
+		}
+		p.setCandlePair(bybitCandle, "ATOMUSDT")
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr(price), prices[ATOMUSDT][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[ATOMUSDT][0].Volume)
+		require.Equal(t, timeStamp, prices[ATOMUSDT][0].TimeStamp)
+	})
+
+	t.Run("valid_candle_message", func(t *testing.T) {
+		timeStamp := PastUnixTimeMillis(0)
+		p.messageReceived(0, nil, []byte(fmt.Sprintf(`{
+			"topic":"kline.1.ATOMUSDT",
+			"type":"snapshot",
+			"data":[{"start":%d,"end":%d,"interval":"1","close":"10.5","volume":"20","confirm":false}]
+		}`, timeStamp, timeStamp+59999)))
+
+		prices, err := p.GetCandlePrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10.5"), prices[ATOMUSDT][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("20"), prices[ATOMUSDT][0].Volume)
+		require.Equal(t, timeStamp, prices[ATOMUSDT][0].TimeStamp)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, _ := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.Empty(t, prices)
+	})
+}
+
+func TestBybitProvider_AvailablePairs(t *testing.T) {
+	p := newBybitTestProvider(t)
+
+	// the instruments which are not trading are left out
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"ATOMUSDT": {}, "BTCUSDT": {}}, pairs)
+}
+
+func TestBybitProvider_NewSubscriptionMsg(t *testing.T) {
+	cps := []types.CurrencyPair{
+		{
+			Base: "ATOM", Quote: "USDT",
+		},
+		{
+			Base: "FOO", Quote: "BAR",
+		},
+	}
+	p := &BybitProvider{}
+	msgs := p.getSubscriptionMsgs(cps...)
+
+	require.Len(t, msgs, len(cps))
+	sub := msgs[0].(BybitSubscriptionMsg)
+	require.Equal(t, "subscribe", sub.Op)
+	require.Equal(t, []string{"tickers.ATOMUSDT", "kline.1.ATOMUSDT"}, sub.Args)
+	sub = msgs[1].(BybitSubscriptionMsg)
+	require.Equal(t, []string{"tickers.FOOBAR", "kline.1.FOOBAR"}, sub.Args)
+
+	unsub := newBybitUnsubscriptionMsg(bybitPairTopics(cps[0])...)
+	require.Equal(t, "unsubscribe", unsub.Op)
+	require.Equal(t, []string{"tickers.ATOMUSDT", "kline.1.ATOMUSDT"}, unsub.Args)
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"time"
+)
+
+const (
+	// CandleTimestampOpen means the provider timestamps its candles at the
+	// open of their interval.
+	CandleTimestampOpen CandleTimestamp = "open"
+	// CandleTimestampMid means the provider timestamps its candles at the
+	// midpoint of their interval.
+	CandleTimestampMid CandleTimestamp = "mid"
+	// CandleTimestampClose means the provider timestamps its candles at the
+	// close of their interval.
+	CandleTimestampClose CandleTimestamp = "close"
+
+	// defaultCandleInterval is the interval of the candles of most providers,
+	// used to normalize their timestamps when none is configured.
+	defaultCandleInterval = time.Minute
+)
+
+// CandleTimestamp defines the point of their interval a provider timestamps
+// its candles at.
+type CandleTimestamp string
+
+// SupportedCandleTimestamps defines the candle timestamp conventions which can
+// be configured for a provider.
+var SupportedCandleTimestamps = map[CandleTimestamp]struct{}{
+	CandleTimestampOpen:  {},
+	CandleTimestampMid:   {},
+	CandleTimestampClose: {},
+}
+
+// CandleIntervalDuration returns the configured candle interval of the
+// endpoint, or defaultCandleInterval if it is empty or invalid.
+func (e Endpoint) CandleIntervalDuration() time.Duration {
+	if e.CandleInterval == "" {
+		return defaultCandleInterval
+	}
+	interval, err := time.ParseDuration(e.CandleInterval)
+	if err != nil || interval <= 0 {
+		return defaultCandleInterval
+	}
+	return interval
+}
+
+// normalizeCandleTimestamp returns the unix millisecond timestamp of the close
+// of the interval of a candle timestamped following the given convention, so
+// the candles of the same interval align across providers. The timestamp is
+// rounded to the nearest interval boundary, absorbing the jitter of providers
+// timestamping their candles when they are emitted. Only closed candles are
+// normalized: a candle whose interval closes after now is still in progress,
+// and keeps its timestamp, at most now, rather than being dated in the future.
+func normalizeCandleTimestamp(timestamp int64, convention CandleTimestamp, interval time.Duration, now int64) int64 {
+	intervalMillis := interval.Milliseconds()
+	if intervalMillis <= 0 {
+		return timestamp
+	}
+
+	closeTimestamp := timestamp
+	switch convention {
+	case CandleTimestampOpen:
+		closeTimestamp += intervalMillis
+	case CandleTimestampMid:
+		closeTimestamp += intervalMillis / 2
+	}
+	closeTimestamp = (closeTimestamp + intervalMillis/2) / intervalMillis * intervalMillis
+
+	if closeTimestamp > now {
+		if timestamp > now {
+			return now
+		}
+		return timestamp
+	}
+	return closeTimestamp
+}
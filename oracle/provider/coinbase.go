@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -110,13 +109,13 @@ func NewCoinbaseProvider(
 		logger:         coinbaseLogger,
 		reconnectTimer: time.NewTicker(coinbasePingCheck),
 		endpoints:      endpoints,
-		priceStore:     newPriceStore(coinbaseLogger),
+		priceStore:     newPriceStore(coinbaseLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToCoinbasePair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -134,6 +133,7 @@ func NewCoinbaseProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		coinbaseLogger,
 	)
 
@@ -144,6 +144,10 @@ func (p *CoinbaseProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *CoinbaseProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *CoinbaseProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, 1)
 
@@ -174,7 +178,7 @@ func (p *CoinbaseProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -194,14 +198,14 @@ func (p *CoinbaseProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *CoinbaseProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + coinbaseRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + coinbaseRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary []CoinbasePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,405 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/ojo/util/decmath"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+var _ Provider = (*CoinGeckoProvider)(nil)
+
+const (
+	coinGeckoRestHost     = "https://api.coingecko.com"
+	coinGeckoCoinsPath    = "/api/v3/coins/list"
+	coinGeckoPricePath    = "/api/v3/simple/price"
+	coinGeckoAPIKeyHeader = "x-cg-pro-api-key"
+	coinGeckoPollInterval = 30 * time.Second
+
+	// coinGeckoQuote is the only quote currency the CoinGecko provider
+	// supports, since every price is eventually converted to USD.
+	coinGeckoQuote = "USD"
+)
+
+var (
+	// coinGeckoVolume is the volume of every CoinGecko ticker and candle. The
+	// volume CoinGecko serves is the global 24h volume of the coin, which
+	// already includes the volume of the exchanges, so the prices are given
+	// a negligible volume to sanity-check the exchanges without outweighing
+	// them.
+	coinGeckoVolume = math.LegacySmallestDec()
+
+	// coinGeckoCoinIDs defines the CoinGecko coin id of the bases whose symbol
+	// is shared by several coins listed on CoinGecko.
+	coinGeckoCoinIDs = map[string]string{
+		"ATOM": "cosmos",
+		"BTC":  "bitcoin",
+		"DAI":  "dai",
+		"ETH":  "ethereum",
+		"INJ":  "injective-protocol",
+		"OJO":  "ojo-network",
+		"OSMO": "osmosis",
+		"TIA":  "celestia",
+		"USDC": "usd-coin",
+		"USDT": "tether",
+	}
+)
+
+type (
+	// CoinGeckoProvider defines an oracle provider that polls the CoinGecko
+	// simple price API, used as a slow reference feed to sanity-check the
+	// prices of the exchanges. Only USD quoted pairs are supported, and the
+	// prices are queried by coin id, resolved from the symbol of each base.
+	// The pro API is used by setting the rest endpoint to
+	// "https://pro-api.coingecko.com" along with an API key.
+	//
+	// REF: https://docs.coingecko.com/reference/simple-price
+	CoinGeckoProvider struct {
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+		priceStore
+		ctx context.Context
+
+		// lastTimestamps holds the last updated timestamp of the latest candle
+		// synthesized for each pair, so repeated polls don't store it twice.
+		lastTimestamps map[string]int64
+
+		coinIDsMtx sync.RWMutex
+		coinIDs    map[string]string // base ex. "ATOM" -> coin id ex. "cosmos"
+	}
+
+	// CoinGeckoCoin defines a single entry of the CoinGecko coins list.
+	CoinGeckoCoin struct {
+		ID     string `json:"id"`
+		Symbol string `json:"symbol"`
+	}
+
+	// CoinGeckoPrice defines the USD price of a coin and the unix timestamp
+	// in seconds of its last update.
+	CoinGeckoPrice struct {
+		Price         float64 `json:"usd"`
+		LastUpdatedAt int64   `json:"last_updated_at"`
+	}
+)
+
+// NewCoinGeckoProvider returns a new CoinGeckoProvider.
+func NewCoinGeckoProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*CoinGeckoProvider, error) {
+	if endpoints.Name != ProviderCoinGecko {
+		endpoints = Endpoint{
+			Name: ProviderCoinGecko,
+			Rest: coinGeckoRestHost,
+		}
+	}
+
+	coinGeckoLogger := logger.With().Str("provider", string(ProviderCoinGecko)).Logger()
+
+	provider := &CoinGeckoProvider{
+		logger:         coinGeckoLogger,
+		endpoints:      endpoints,
+		client:         newDefaultHTTPClient(endpoints.restTimeout()),
+		priceStore:     newPriceStore(coinGeckoLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+		ctx:            ctx,
+		lastTimestamps: map[string]int64{},
+		coinIDs:        map[string]string{},
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// GetAvailablePairs returns the USD pair of every coin listed on CoinGecko
+// whose coin id can be resolved from its symbol, and stores the resolved coin
+// ids. The symbols shared by several coins are only resolved for the bases
+// of coinGeckoCoinIDs.
+func (p *CoinGeckoProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	bz, err := p.get(coinGeckoCoinsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var coins []CoinGeckoCoin
+	if err := json.Unmarshal(bz, &coins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	idsBySymbol := make(map[string][]string, len(coins))
+	for _, coin := range coins {
+		base := strings.ToUpper(coin.Symbol)
+		idsBySymbol[base] = append(idsBySymbol[base], coin.ID)
+	}
+
+	coinIDs := make(map[string]string, len(idsBySymbol))
+	for base, ids := range idsBySymbol {
+		if id, ok := coinGeckoCoinIDs[base]; ok {
+			for _, listedID := range ids {
+				if listedID == id {
+					coinIDs[base] = id
+				}
+			}
+			continue
+		}
+		if len(ids) == 1 {
+			coinIDs[base] = ids[0]
+		}
+	}
+
+	p.coinIDsMtx.Lock()
+	p.coinIDs = coinIDs
+	p.coinIDsMtx.Unlock()
+
+	availablePairs := make(map[string]struct{}, len(coinIDs))
+	for base := range coinIDs {
+		cp := types.CurrencyPair{
+			Base:  base,
+			Quote: coinGeckoQuote,
+		}
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// SubscribeCurrencyPairs adds the new currency pairs to the providers
+// subscribedPairs array.
+func (p *CoinGeckoProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// StartConnections begins the polling process for the CoinGecko provider.
+func (p *CoinGeckoProvider) StartConnections() {
+	go p.poll()
+}
+
+// poll periodically calls setPrices to update the priceStore until the
+// context is done.
+func (p *CoinGeckoProvider) poll() {
+	ticker := time.NewTicker(coinGeckoPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.setPrices(); err != nil {
+			p.logger.Err(err).Msg("failed to poll coingecko prices")
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setPrices queries the prices of the subscribed pairs and updates the
+// priceStore. CoinGecko only serves spot prices, so each new price is also
+// stored as a single-point candle for the TVWAP.
+func (p *CoinGeckoProvider) setPrices() error {
+	p.subscribedPairsMtx.RLock()
+	bases := make([]string, 0, len(p.subscribedPairs))
+	for _, cp := range p.subscribedPairs {
+		bases = append(bases, cp.Base)
+	}
+	p.subscribedPairsMtx.RUnlock()
+
+	p.coinIDsMtx.RLock()
+	basesByID := make(map[string]string, len(bases))
+	for _, base := range bases {
+		if id, ok := p.coinIDs[base]; ok {
+			basesByID[id] = base
+		}
+	}
+	p.coinIDsMtx.RUnlock()
+
+	if len(basesByID) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(basesByID))
+	for id := range basesByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	prices, err := p.queryPrices(ids)
+	if err != nil {
+		return err
+	}
+
+	for id, price := range prices {
+		base, ok := basesByID[id]
+		if !ok {
+			continue
+		}
+		cp := types.CurrencyPair{Base: base, Quote: coinGeckoQuote}.String()
+		if !p.isSubscribed(cp) || price.Price <= 0 {
+			continue
+		}
+
+		p.setTickerPair(price, cp)
+		p.setCandle(price, cp)
+	}
+
+	return nil
+}
+
+// setCandle stores the price as a candle of the pair unless a candle with the
+// same or a newer last updated timestamp was already stored.
+func (p *CoinGeckoProvider) setCandle(price CoinGeckoPrice, cp string) {
+	candle, err := price.toCandlePrice()
+	if err != nil {
+		p.logger.Error().Err(err).Str("pair", cp).Msg("failed to parse coingecko candle")
+		return
+	}
+
+	p.candleMtx.Lock()
+	defer p.candleMtx.Unlock()
+
+	if candle.TimeStamp <= p.lastTimestamps[cp] {
+		return
+	}
+	p.lastTimestamps[cp] = candle.TimeStamp
+
+	p.appendAndFilterCandles(candle, cp)
+	p.setLastUpdate(cp, MessageTypeCandle)
+}
+
+// queryPrices returns the USD prices of the given coin ids, keyed by coin id.
+func (p *CoinGeckoProvider) queryPrices(ids []string) (map[string]CoinGeckoPrice, error) {
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", strings.ToLower(coinGeckoQuote))
+	query.Set("include_last_updated_at", "true")
+
+	bz, err := p.get(coinGeckoPricePath, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices map[string]CoinGeckoPrice
+	if err := json.Unmarshal(bz, &prices); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return prices, nil
+}
+
+// get sends a GET request to the given path of the rest endpoint, with the
+// API key header if an API key is configured, and returns the response body.
+func (p *CoinGeckoProvider) get(path string, query url.Values) ([]byte, error) {
+	reqURL := p.endpoints.Rest + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.endpoints.APIKey != "" {
+		req.Header.Set(coinGeckoAPIKeyHeader, p.endpoints.APIKey)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	bz, err := io.ReadAll(p.endpoints.limitResponseBody(res.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return bz, nil
+}
+
+// price returns the price of the coin.
+func (cgp CoinGeckoPrice) price() (math.LegacyDec, error) {
+	price, err := decmath.NewDecFromFloat(cgp.Price)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	if !price.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("invalid coingecko price: %f", cgp.Price)
+	}
+	return price, nil
+}
+
+func (cgp CoinGeckoPrice) toTickerPrice() (types.TickerPrice, error) {
+	price, err := cgp.price()
+	if err != nil {
+		return types.TickerPrice{}, err
+	}
+	return types.TickerPrice{Price: price, Volume: coinGeckoVolume}, nil
+}
+
+func (cgp CoinGeckoPrice) toCandlePrice() (types.CandlePrice, error) {
+	price, err := cgp.price()
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+
+	timestamp := PastUnixTimeMillis(0)
+	if cgp.LastUpdatedAt > 0 {
+		timestamp = SecondsToMilli(cgp.LastUpdatedAt)
+	}
+
+	return types.CandlePrice{
+		Price:     price,
+		Volume:    coinGeckoVolume,
+		TimeStamp: timestamp,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinGeckoProvider_SetPrices(t *testing.T) {
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	prices := fmt.Sprintf(
+		`{"cosmos":{"usd":10,"last_updated_at":%d},"osmosis":{"usd":1,"last_updated_at":%d}}`,
+		time.Now().Unix(),
+		time.Now().Unix(),
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get(coinGeckoAPIKeyHeader))
+
+		var body string
+		switch r.URL.Path {
+		case coinGeckoCoinsPath:
+			body = `[{"id":"cosmos","symbol":"atom"},{"id":"cosmos-bridged","symbol":"atom"},` +
+				`{"id":"osmosis","symbol":"osmo"},{"id":"foo-1","symbol":"foo"},{"id":"foo-2","symbol":"foo"}]`
+		case coinGeckoPricePath:
+			require.Equal(t, "cosmos", r.URL.Query().Get("ids"))
+			body = prices
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewCoinGeckoProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderCoinGecko, Rest: server.URL, APIKey: "key"},
+		atomUSD,
+	)
+	require.NoError(t, err)
+
+	// the symbols shared by several coins are only resolved for known bases
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"ATOMUSD": {}, "OSMOUSD": {}}, availablePairs)
+
+	// polling the same price twice must not store its candle twice
+	require.NoError(t, p.setPrices())
+	require.NoError(t, p.setPrices())
+
+	tickers, err := p.GetTickerPrices(atomUSD)
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), tickers[atomUSD].Price)
+	require.Equal(t, coinGeckoVolume, tickers[atomUSD].Volume)
+
+	candles, err := p.GetCandlePrices(atomUSD)
+	require.NoError(t, err)
+	require.Len(t, candles[atomUSD], 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), candles[atomUSD][0].Price)
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -128,14 +127,13 @@ func NewCryptoProvider(
 	provider := &CryptoProvider{
 		logger:     cryptoLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(cryptoLogger),
+		priceStore: newPriceStore(cryptoLogger, endpoints.candlePeriod(cryptoCandlePeriod)),
 	}
-	provider.candlePeriod = cryptoCandlePeriod
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToCryptoPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -153,6 +151,7 @@ func NewCryptoProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		cryptoLogger,
 	)
 
@@ -163,6 +162,10 @@ func (p *CryptoProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *CryptoProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *CryptoProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
 	for _, cp := range cps {
@@ -194,7 +197,7 @@ func (p *CryptoProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -300,14 +303,14 @@ func (p *CryptoProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *CryptoProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + cryptoRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + cryptoRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary CryptoPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -15,6 +15,7 @@ var (
 
 	KUJIATOM = types.CurrencyPair{Base: "KUJI", Quote: "ATOM"}
 
+	BTCUSD = types.CurrencyPair{Base: "BTC", Quote: "USD"}
 	EURUSD = types.CurrencyPair{Base: "EUR", Quote: "USD"}
 	JPYUSD = types.CurrencyPair{Base: "JPY", Quote: "USD"}
 )
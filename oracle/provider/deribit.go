@@ -0,0 +1,330 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/ojo/util/decmath"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	deribitWSHost             = "www.deribit.com"
+	deribitWSPath             = "/ws/api/v2"
+	deribitRestHost           = "https://www.deribit.com"
+	deribitIndexNamesPath     = "/api/v2/public/get_index_price_names"
+	deribitJSONRPCVersion     = "2.0"
+	deribitSubscribeMethod    = "public/subscribe"
+	deribitSubscriptionMethod = "subscription"
+	deribitIndexChannelPrefix = "deribit_price_index."
+)
+
+var _ Provider = (*DeribitProvider)(nil)
+
+type (
+	// DeribitProvider defines an Oracle provider implemented by the Deribit
+	// public API, sourcing the index prices of assets such as BTC and ETH.
+	// Deribit doesn't provide spot volume, so each index price is stored as a
+	// ticker with the synthetic volume of the endpoint, defaulting to one.
+	//
+	// REF: https://docs.deribit.com/#deribit_price_index-index_name
+	DeribitProvider struct {
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoints       Endpoint
+		syntheticVolume math.LegacyDec
+		requestID       atomic.Int64
+
+		priceStore
+	}
+
+	// DeribitRequest defines a JSON-RPC request sent over the websocket.
+	DeribitRequest struct {
+		JSONRPC string                    `json:"jsonrpc"`
+		ID      int64                     `json:"id"`
+		Method  string                    `json:"method"` // ex.: public/subscribe
+		Params  DeribitSubscriptionParams `json:"params"`
+	}
+	DeribitSubscriptionParams struct {
+		Channels []string `json:"channels"` // ex.: deribit_price_index.btc_usd
+	}
+
+	// DeribitResponse defines a JSON-RPC message received over the websocket,
+	// either the response to a request, holding its id along with a result
+	// or an error, or a subscription notification.
+	DeribitResponse struct {
+		JSONRPC string                    `json:"jsonrpc"`
+		ID      *int64                    `json:"id"`
+		Method  string                    `json:"method"` // ex.: subscription
+		Params  DeribitNotificationParams `json:"params"`
+		Result  json.RawMessage           `json:"result"`
+		Error   *DeribitError             `json:"error"`
+	}
+	DeribitError struct {
+		Code    int64  `json:"code"`
+		Message string `json:"message"`
+	}
+	DeribitNotificationParams struct {
+		Channel string            `json:"channel"` // ex.: deribit_price_index.btc_usd
+		Data    DeribitIndexPrice `json:"data"`
+	}
+	DeribitIndexPrice struct {
+		IndexName string  `json:"index_name"` // ex.: btc_usd
+		Price     float64 `json:"price"`
+		Timestamp int64   `json:"timestamp"` // Unix milliseconds
+	}
+
+	// DeribitIndexNamesResponse defines the response of the index price names
+	// REST method.
+	DeribitIndexNamesResponse struct {
+		Result []string `json:"result"` // ex.: ["btc_usd", "eth_usd"]
+	}
+
+	// deribitTicker defines an index price along with the synthetic volume it
+	// is stored with.
+	deribitTicker struct {
+		indexPrice DeribitIndexPrice
+		volume     math.LegacyDec
+	}
+)
+
+func NewDeribitProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*DeribitProvider, error) {
+	if endpoints.Name != ProviderDeribit {
+		endpoints = Endpoint{
+			Name:      ProviderDeribit,
+			Rest:      deribitRestHost,
+			Websocket: deribitWSHost,
+		}
+	}
+
+	syntheticVolume := math.LegacyOneDec()
+	if endpoints.SyntheticVolume != "" {
+		var err error
+		syntheticVolume, err = math.LegacyNewDecFromStr(endpoints.SyntheticVolume)
+		if err != nil || !syntheticVolume.IsPositive() {
+			return nil, fmt.Errorf("invalid deribit synthetic volume: %s", endpoints.SyntheticVolume)
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   deribitWSPath,
+	}
+
+	deribitLogger := logger.With().Str("provider", string(ProviderDeribit)).Logger()
+
+	provider := &DeribitProvider{
+		logger:          deribitLogger,
+		endpoints:       endpoints,
+		syntheticVolume: syntheticVolume,
+		priceStore:      newPriceStore(deribitLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToDeribitPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
+		deribitLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *DeribitProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *DeribitProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
+func (p *DeribitProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	channels := make([]string, 0, len(cps))
+	for _, cp := range cps {
+		channels = append(channels, deribitIndexChannelPrefix+currencyPairToDeribitPair(cp))
+	}
+
+	return []interface{}{p.newSubscriptionMsg(channels)}
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *DeribitProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// messageReceived handles the JSON-RPC messages of the websocket. Responses
+// to the subscription requests are matched by their id and only logged, while
+// the index price notifications are stored as tickers.
+func (p *DeribitProvider) messageReceived(messageType int, _ *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var resp DeribitResponse
+	if err := json.Unmarshal(bz, &resp); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("response", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	switch {
+	case resp.Error != nil:
+		p.logger.Error().
+			Interface("id", resp.ID).
+			Int64("code", resp.Error.Code).
+			Str("message", resp.Error.Message).
+			Msg("deribit request failed")
+
+	case resp.Method == deribitSubscriptionMethod &&
+		strings.HasPrefix(resp.Params.Channel, deribitIndexChannelPrefix):
+		p.setTickerPair(
+			deribitTicker{indexPrice: resp.Params.Data, volume: p.syntheticVolume},
+			resp.Params.Data.IndexName,
+		)
+		telemetryWebsocketMessage(ProviderDeribit, MessageTypeTicker)
+
+	case resp.ID != nil:
+		p.logger.Debug().
+			Int64("id", *resp.ID).
+			Str("result", string(resp.Result)).
+			Msg("deribit request succeeded")
+
+	default:
+		p.logger.Error().
+			Int("length", len(bz)).
+			Str("method", resp.Method).
+			Msg("Error on receive message")
+	}
+}
+
+func (dt deribitTicker) toTickerPrice() (types.TickerPrice, error) {
+	if err := checkFinite(dt.indexPrice.Price); err != nil {
+		return types.TickerPrice{}, err
+	}
+	price, err := decmath.NewDecFromFloat(dt.indexPrice.Price)
+	if err != nil {
+		return types.TickerPrice{}, err
+	}
+	return types.TickerPrice{Price: price, Volume: dt.volume}, nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *DeribitProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs of all index prices of Deribit.
+// ex.: map["BTCUSD" => {}, "ETHUSDC" => {}].
+func (p *DeribitProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + deribitIndexNamesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var indexNames DeribitIndexNamesResponse
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&indexNames); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(indexNames.Result))
+	for _, indexName := range indexNames.Result {
+		splitIndexName := strings.Split(indexName, "_")
+		if len(splitIndexName) != 2 {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(splitIndexName[0]),
+			Quote: strings.ToUpper(splitIndexName[1]),
+		}
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// newSubscriptionMsg returns a new JSON-RPC subscription request with the
+// next request id.
+func (p *DeribitProvider) newSubscriptionMsg(channels []string) DeribitRequest {
+	return DeribitRequest{
+		JSONRPC: deribitJSONRPCVersion,
+		ID:      p.requestID.Add(1),
+		Method:  deribitSubscribeMethod,
+		Params: DeribitSubscriptionParams{
+			Channels: channels,
+		},
+	}
+}
+
+// currencyPairToDeribitPair receives a currency pair and returns the Deribit
+// index name i.e: btc_usd
+func currencyPairToDeribitPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + "_" + cp.Quote)
+}
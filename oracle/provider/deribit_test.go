@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeribitProvider(t *testing.T, endpoints Endpoint, pairs ...types.CurrencyPair) *DeribitProvider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, deribitIndexNamesPath, r.URL.Path)
+		_, err := w.Write([]byte(`{"jsonrpc":"2.0","result":["btc_usd","eth_usdc","btcdvol_usdc_old"]}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoints.Name = ProviderDeribit
+	endpoints.Rest = server.URL
+	endpoints.Websocket = "localhost"
+
+	p, err := NewDeribitProvider(context.TODO(), zerolog.Nop(), endpoints, pairs...)
+	require.NoError(t, err)
+	return p
+}
+
+func TestDeribitProvider_GetAvailablePairs(t *testing.T) {
+	p := newTestDeribitProvider(t, Endpoint{}, BTCUSD)
+
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Len(t, availablePairs, 2)
+	require.Contains(t, availablePairs, "BTCUSD")
+	require.Contains(t, availablePairs, "ETHUSDC")
+}
+
+func TestDeribitProvider_MessageReceived(t *testing.T) {
+	p := newTestDeribitProvider(t, Endpoint{SyntheticVolume: "1000"}, BTCUSD)
+
+	t.Run("subscription_response", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(
+			`{"jsonrpc":"2.0","id":1,"result":["deribit_price_index.btc_usd"]}`,
+		))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(
+			`{"jsonrpc":"2.0","id":2,"error":{"code":11050,"message":"bad_request"}}`,
+		))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	t.Run("index_price_notification", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, nil, []byte(`{
+			"jsonrpc":"2.0",
+			"method":"subscription",
+			"params":{
+				"channel":"deribit_price_index.btc_usd",
+				"data":{"timestamp":1715623040000,"price":62000.5,"index_name":"btc_usd"}
+			}
+		}`))
+		prices, err := p.GetTickerPrices(BTCUSD)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62000.5"), prices[BTCUSD].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1000"), prices[BTCUSD].Volume)
+	})
+}
+
+func TestDeribitProvider_GetSubscriptionMsgs(t *testing.T) {
+	p := newTestDeribitProvider(t, Endpoint{}, BTCUSD)
+
+	msgs := p.getSubscriptionMsgs(BTCUSD, types.CurrencyPair{Base: "ETH", Quote: "USDC"})
+	require.Len(t, msgs, 1)
+
+	bz, err := json.Marshal(msgs[0])
+	require.NoError(t, err)
+
+	var req DeribitRequest
+	require.NoError(t, json.Unmarshal(bz, &req))
+	require.Equal(t, deribitJSONRPCVersion, req.JSONRPC)
+	require.Equal(t, deribitSubscribeMethod, req.Method)
+	require.Positive(t, req.ID)
+	require.Equal(t, []string{"deribit_price_index.btc_usd", "deribit_price_index.eth_usdc"}, req.Params.Channels)
+}
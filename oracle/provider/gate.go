@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +22,14 @@ const (
 	gatePingCheck = time.Second * 28 // should be < 30
 	gateRestHost  = "https://api.gateio.ws"
 	gateRestPath  = "/api/v4/spot/currency_pairs"
+
+	// gateRestCandlePath defines the REST path the recent candles are
+	// backfilled from on connection.
+	gateRestCandlePath = "/api/v4/spot/candlesticks"
+
+	// gateBackfillCandles defines how many 1m candles are backfilled, covering
+	// the TVWAP window.
+	gateBackfillCandles = 10
 )
 
 var _ Provider = (*GateProvider)(nil)
@@ -49,7 +57,7 @@ type (
 
 	GateCandle struct {
 		Close     string // Closing price
-		TimeStamp int64  // Unix timestamp
+		TimeStamp int64  // Start time in unix epoch seconds
 		Volume    string // Total candle volume
 		Symbol    string // Total symbol
 	}
@@ -93,6 +101,13 @@ type (
 		Status string `json:"status"` // ex. "successful"
 	}
 
+	// GateRESTCandle defines a candle of the Gate candlesticks REST response,
+	// an array of the open time in unix epoch seconds, quote volume, close,
+	// high, low, open, base volume and whether the candle is closed.
+	//
+	// REF: https://www.gate.io/docs/developers/apiv4/#market-candlesticks
+	GateRESTCandle []string
+
 	// GatePairSummary defines the response structure for a Gate pair summary.
 	GatePairSummary struct {
 		Base  string `json:"base"`
@@ -127,13 +142,13 @@ func NewGateProvider(
 		logger:         gateLogger,
 		reconnectTimer: time.NewTicker(gatePingCheck),
 		endpoints:      endpoints,
-		priceStore:     newPriceStore(gateLogger),
+		priceStore:     newPriceStore(gateLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToGatePair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -151,16 +166,26 @@ func NewGateProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		gateLogger,
 	)
+	provider.wsc.SetReconnectHandler(provider.candleReconnected)
 
 	return provider, nil
 }
 
+// StartConnections starts the websocket connections and backfills the recent
+// candles of the subscribed pairs in the background, so the TVWAP is not thin
+// until enough candles are received.
 func (p *GateProvider) StartConnections() {
+	go p.backfillCandles(p.subscribedPairsList()...)
 	p.wsc.StartConnections()
 }
 
+func (p *GateProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *GateProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
 	for _, cp := range cps {
@@ -186,7 +211,7 @@ func (p *GateProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -194,6 +219,7 @@ func (p *GateProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 		return
 	}
 
+	go p.backfillCandles(confirmedPairs...)
 	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
 	p.wsc.AddWebsocketConnection(
 		newSubscriptionMsgs,
@@ -345,6 +371,90 @@ func (p *GateProvider) messageReceivedCandle(bz []byte) error {
 	return nil
 }
 
+// candleReconnected backfills the candles of the pair of a candle connection
+// in the background once it is subscribed again, since its candles stopped
+// while disconnected.
+func (p *GateProvider) candleReconnected(subscriptionMsg interface{}) {
+	msg, ok := subscriptionMsg.(GateCandleSubscriptionMsg)
+	if !ok || len(msg.Params) == 0 {
+		return
+	}
+	gatePair, ok := msg.Params[0].(string)
+	if !ok {
+		return
+	}
+	go func() {
+		if err := p.backfillPairCandles(gatePair); err != nil {
+			p.logger.Warn().Err(err).Str("pair", gatePair).Msg("failed to backfill candles")
+		}
+	}()
+}
+
+// backfillCandles seeds the candles of the currency pairs with their recent
+// 1m candles from the REST API, logging the pairs which failed.
+func (p *GateProvider) backfillCandles(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		gatePair := currencyPairToGatePair(cp)
+		if err := p.backfillPairCandles(gatePair); err != nil {
+			p.logger.Warn().Err(err).Str("pair", gatePair).Msg("failed to backfill candles")
+		}
+	}
+}
+
+// backfillPairCandles requests the recent 1m candles of the Gate pair and
+// stores the closed ones.
+func (p *GateProvider) backfillPairCandles(gatePair string) error {
+	resp, err := p.endpoints.httpGetWithRetries(fmt.Sprintf(
+		"%s%s?currency_pair=%s&interval=1m&limit=%d",
+		p.endpoints.Rest,
+		gateRestCandlePath,
+		gatePair,
+		gateBackfillCandles,
+	))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var restCandles []GateRESTCandle
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&restCandles); err != nil {
+		return err
+	}
+
+	for _, restCandle := range restCandles {
+		if len(restCandle) < 8 {
+			return fmt.Errorf("wrong number of fields in candle")
+		}
+		if restCandle[7] != "true" {
+			continue
+		}
+		timeStamp, err := strconv.ParseInt(restCandle[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse candle time: %w", err)
+		}
+
+		p.setCandlePair(GateCandle{
+			Close:     restCandle[2],
+			TimeStamp: timeStamp,
+			Volume:    restCandle[6],
+			Symbol:    gatePair,
+		}, gatePair)
+	}
+	return nil
+}
+
+// subscribedPairsList returns the subscribed currency pairs.
+func (p *GateProvider) subscribedPairsList() []types.CurrencyPair {
+	p.subscribedPairsMtx.RLock()
+	defer p.subscribedPairsMtx.RUnlock()
+
+	cps := make([]types.CurrencyPair, 0, len(p.subscribedPairs))
+	for _, cp := range p.subscribedPairs {
+		cps = append(cps, cp)
+	}
+	return cps
+}
+
 // setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
 func (p *GateProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 	for _, cp := range cps {
@@ -354,14 +464,14 @@ func (p *GateProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *GateProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + gateRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + gateRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary []GatePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -385,7 +495,7 @@ func (candle GateCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
 		candle.Close,
 		candle.Volume,
-		candle.TimeStamp,
+		SecondsToMilli(candle.TimeStamp),
 	)
 }
 
@@ -3,7 +3,11 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cosmossdk.io/math"
 	"github.com/ojo-network/price-feeder/oracle/types"
@@ -101,3 +105,56 @@ func TestGateProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"method\":\"kline.subscribe\",\"params\":[\"ATOM_USDT\",60],\"id\":2}", string(msg))
 }
+
+func TestGateProvider_BackfillCandles(t *testing.T) {
+	now := time.Now().Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case gateRestPath:
+			body = `[{"base":"ATOM","quote":"USDT"}]`
+		case gateRestCandlePath:
+			require.Equal(t, "ATOM_USDT", r.URL.Query().Get("currency_pair"))
+			require.Equal(t, "1m", r.URL.Query().Get("interval"))
+			body = fmt.Sprintf(
+				`[["%d","100","10.1","10.2","10","10","10","true"],`+
+					`["%d","100","10.3","10.4","10","10","10","true"],`+
+					`["%d","100","10.5","10.6","10","10","10","false"]]`,
+				now-120, now-60, now,
+			)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := NewGateProvider(
+		ctx,
+		zerolog.Nop(),
+		Endpoint{Name: ProviderGate, Rest: server.URL, Websocket: "localhost:0"},
+		ATOMUSDT,
+	)
+	require.NoError(t, err)
+	p.StartConnections()
+
+	// only the closed candles are backfilled, in the background
+	var candles types.CurrencyPairCandles
+	require.Eventually(t, func() bool {
+		candles, err = p.GetCandlePrices(ATOMUSDT)
+		return err == nil && len(candles[ATOMUSDT]) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	prices := map[int64]math.LegacyDec{}
+	for _, candle := range candles[ATOMUSDT] {
+		prices[candle.TimeStamp] = candle.Price
+	}
+	require.Equal(t, map[int64]math.LegacyDec{
+		SecondsToMilli(now - 120): math.LegacyMustNewDecFromStr("10.1"),
+		SecondsToMilli(now - 60):  math.LegacyMustNewDecFromStr("10.3"),
+	}, prices)
+}
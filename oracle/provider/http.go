@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// restMaxRetries defines how many times a REST request failing with a
+	// network error, a server error or a rate limit is retried.
+	restMaxRetries = 2
+
+	// restRetryBackoff defines the backoff before the first retry of a REST
+	// request, doubled on each retry.
+	restRetryBackoff = 250 * time.Millisecond
+
+	// restRateLimitBackoff defines the minimum backoff before retrying a rate
+	// limited REST request, unless its Retry-After is longer.
+	restRateLimitBackoff = 1 * time.Second
+
+	// restMaxRetryAfter caps the Retry-After of a rate limited REST request
+	// waited for before retrying it.
+	restMaxRetryAfter = 5 * time.Second
+)
+
+// newDefaultHTTPClient returns the HTTP client of the provider REST calls,
+// which gives up after the timeout instead of blocking on a hung endpoint.
+func newDefaultHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
+// restTimeout returns the parsed rest_timeout of the endpoint, or
+// defaultTimeout when it is empty or invalid.
+func (e Endpoint) restTimeout() time.Duration {
+	if e.RestTimeout == "" {
+		return defaultTimeout
+	}
+	timeout, err := time.ParseDuration(e.RestTimeout)
+	if err != nil || timeout <= 0 {
+		return defaultTimeout
+	}
+	return timeout
+}
+
+// httpGet issues a GET request to the url with a client timing out after the
+// rest timeout of the endpoint. It is not retried, so it returns within the
+// timeout of the callers on the tick path.
+func (e Endpoint) httpGet(url string) (*http.Response, error) {
+	return newDefaultHTTPClient(e.restTimeout()).Get(url)
+}
+
+// httpGetWithRetries issues a GET request like httpGet, retrying transient
+// failures up to restMaxRetries times with an exponential backoff, and returns
+// the response or error of the last attempt. The backoff can outlast the
+// timeout of a tick, so it is only used by the background pollers.
+func (e Endpoint) httpGetWithRetries(url string) (*http.Response, error) {
+	client := newDefaultHTTPClient(e.restTimeout())
+	backoff := restRetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Get(url)
+		delay, retry := restRetryDelay(resp, err, backoff)
+		if !retry || attempt == restMaxRetries {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, e.limitResponseBody(resp.Body))
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+		backoff *= 2
+	}
+}
+
+// restRetryDelay returns whether a REST request which returned the response
+// and error should be retried, and the delay to wait before retrying it. Rate
+// limited requests are backed off for longer, honoring their Retry-After.
+func restRetryDelay(resp *http.Response, err error, backoff time.Duration) (time.Duration, bool) {
+	switch {
+	case err != nil:
+		return backoff, true
+
+	case resp.StatusCode == http.StatusTooManyRequests:
+		delay := max(backoff, restRateLimitBackoff)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			delay = max(delay, min(time.Duration(seconds)*time.Second, restMaxRetryAfter))
+		}
+		return delay, true
+
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return backoff, true
+
+	default:
+		return 0, false
+	}
+}
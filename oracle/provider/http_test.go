@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint_RestTimeout(t *testing.T) {
+	require.Equal(t, defaultTimeout, Endpoint{}.restTimeout())
+	require.Equal(t, defaultTimeout, Endpoint{RestTimeout: "foo"}.restTimeout())
+	require.Equal(t, 3*time.Second, Endpoint{RestTimeout: "3s"}.restTimeout())
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	p := &HuobiProvider{
+		endpoints: Endpoint{Name: ProviderHuobi, Rest: server.URL, RestTimeout: "100ms"},
+	}
+
+	// the hung endpoint errors out at the rest timeout
+	startTime := time.Now()
+	_, err := p.GetAvailablePairs()
+	require.Error(t, err)
+	require.Less(t, time.Since(startTime), time.Second)
+}
+
+func TestEndpoint_HTTPGetWithRetries(t *testing.T) {
+	newServer := func(statusCodes ...int) (*httptest.Server, *atomic.Int32) {
+		calls := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			call := int(calls.Add(1)) - 1
+			if call < len(statusCodes) {
+				if statusCodes[call] == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "2")
+				}
+				w.WriteHeader(statusCodes[call])
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	t.Run("server_error_retried", func(t *testing.T) {
+		server, calls := newServer(http.StatusServiceUnavailable)
+		resp, err := Endpoint{}.httpGetWithRetries(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("bounded_retries", func(t *testing.T) {
+		server, calls := newServer(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		resp, err := Endpoint{}.httpGetWithRetries(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.Equal(t, int32(restMaxRetries+1), calls.Load())
+	})
+
+	t.Run("client_error_not_retried", func(t *testing.T) {
+		server, calls := newServer(http.StatusNotFound)
+		resp, err := Endpoint{}.httpGetWithRetries(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("tick_path_not_retried", func(t *testing.T) {
+		server, calls := newServer(http.StatusServiceUnavailable)
+		resp, err := Endpoint{}.httpGet(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("rate_limit_backed_off", func(t *testing.T) {
+		server, calls := newServer(http.StatusTooManyRequests)
+		startTime := time.Now()
+		resp, err := Endpoint{}.httpGetWithRetries(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), calls.Load())
+		require.GreaterOrEqual(t, time.Since(startTime), 2*time.Second)
+	})
+}
+
+func TestRestRetryDelay(t *testing.T) {
+	rateLimited := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+
+	delay, retry := restRetryDelay(nil, errors.New("connection reset"), restRetryBackoff)
+	require.True(t, retry)
+	require.Equal(t, restRetryBackoff, delay)
+
+	// a rate limit is backed off for longer than the retry backoff
+	delay, retry = restRetryDelay(rateLimited, nil, restRetryBackoff)
+	require.True(t, retry)
+	require.Equal(t, restRateLimitBackoff, delay)
+
+	// the Retry-After is honored up to its cap
+	rateLimited.Header.Set("Retry-After", "3600")
+	delay, retry = restRetryDelay(rateLimited, nil, restRetryBackoff)
+	require.True(t, retry)
+	require.Equal(t, restMaxRetryAfter, delay)
+
+	_, retry = restRetryDelay(&http.Response{StatusCode: http.StatusOK}, nil, restRetryBackoff)
+	require.False(t, retry)
+}
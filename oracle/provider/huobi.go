@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"io"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -67,7 +66,7 @@ type (
 	// HuobiCandleTick defines the response type for the candle.
 	HuobiCandleTick struct {
 		Close     float64 `json:"close"` // Closing price during this period
-		TimeStamp int64   `json:"id"`    // TimeStamp for this as an ID
+		TimeStamp int64   `json:"id"`    // Start time in unix epoch seconds, used as an ID
 		Volume    float64 `json:"vol"`   // Volume during this period
 	}
 
@@ -119,14 +118,14 @@ func NewHuobiProvider(
 	provider := &HuobiProvider{
 		logger:     huobiLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(huobiLogger),
+		priceStore: newPriceStore(huobiLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.currencyPairToTickerPair = currencyPairToHuobiTickerPair
 	provider.curencyPairToCandlePair = currencyPairToHuobiCandlePair
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -144,6 +143,7 @@ func NewHuobiProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		huobiLogger,
 	)
 
@@ -154,6 +154,10 @@ func (p *HuobiProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *HuobiProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *HuobiProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
 	for _, cp := range cps {
@@ -178,7 +182,7 @@ func (p *HuobiProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -283,14 +287,14 @@ func (p *HuobiProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *HuobiProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + huobiRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + huobiRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary HuobiPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -315,6 +319,9 @@ func decompressGzip(bz []byte) ([]byte, error) {
 
 // toTickerPrice converts current HuobiTicker to TickerPrice.
 func (ticker HuobiTicker) toTickerPrice() (types.TickerPrice, error) {
+	if err := checkFinite(ticker.Tick.LastPrice, ticker.Tick.Vol); err != nil {
+		return types.TickerPrice{}, err
+	}
 	return types.NewTickerPrice(
 		strconv.FormatFloat(ticker.Tick.LastPrice, 'f', -1, 64),
 		strconv.FormatFloat(ticker.Tick.Vol, 'f', -1, 64),
@@ -322,10 +329,13 @@ func (ticker HuobiTicker) toTickerPrice() (types.TickerPrice, error) {
 }
 
 func (candle HuobiCandle) toCandlePrice() (types.CandlePrice, error) {
+	if err := checkFinite(candle.Tick.Close, candle.Tick.Volume); err != nil {
+		return types.CandlePrice{}, err
+	}
 	return types.NewCandlePrice(
 		strconv.FormatFloat(candle.Tick.Close, 'f', -1, 64),
 		strconv.FormatFloat(candle.Tick.Volume, 'f', -1, 64),
-		candle.Tick.TimeStamp,
+		SecondsToMilli(candle.Tick.TimeStamp),
 	)
 }
 
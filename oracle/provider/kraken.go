@@ -3,14 +3,15 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"cosmossdk.io/math"
 	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
@@ -20,15 +21,19 @@ const (
 	krakenWSHost                  = "ws.kraken.com"
 	KrakenRestHost                = "https://api.kraken.com"
 	KrakenRestPath                = "/0/public/AssetPairs"
+	krakenRestTickerPath          = "/0/public/Ticker"
 	krakenEventSystemStatus       = "systemStatus"
 	krakenEventSubscriptionStatus = "subscriptionStatus"
+	krakenBookDepth               = 100
 )
 
 var _ Provider = (*KrakenProvider)(nil)
 
 type (
 	// KrakenProvider defines an Oracle provider implemented by the Kraken public
-	// API.
+	// API. With the depth price source, it also subscribes to the book
+	// channel of each pair and uses the depth price of the book for the
+	// notional of the pair's quote currency as the ticker price.
 	//
 	// REF: https://docs.kraken.com/websockets/#overview
 	KrakenProvider struct {
@@ -37,6 +42,11 @@ type (
 		mtx       sync.RWMutex
 		endpoints Endpoint
 
+		depthNotionals map[string]math.LegacyDec // quote currency => notional
+		booksMtx       sync.Mutex
+		books          map[string]*orderBook // currency pair symbol => book
+		depthFallbacks map[string]struct{}   // currency pair symbols using the last price
+
 		priceStore
 	}
 
@@ -51,7 +61,7 @@ type (
 	// REF: https://docs.kraken.com/websockets/#message-ohlc
 	KrakenCandle struct {
 		Close     string // Close price during this period
-		TimeStamp int64  // Linux epoch timestamp
+		TimeStamp int64  // End time in unix epoch seconds
 		Volume    string // Volume during this period
 		Symbol    string // Symbol for this candle
 	}
@@ -65,7 +75,19 @@ type (
 
 	// KrakenSubscriptionChannel Msg with the channel name to be subscribed.
 	KrakenSubscriptionChannel struct {
-		Name string `json:"name"` // channel to be subscribed ex.: ticker
+		Name  string `json:"name"`            // channel to be subscribed ex.: ticker
+		Depth int    `json:"depth,omitempty"` // depth of the book channel ex.: 100
+	}
+
+	// KrakenBook defines a book snapshot, with the "as" and "bs" levels, or
+	// a book update, with the "a" and "b" levels, of the book channel. Each
+	// level is an array of [price, volume, timestamp].
+	// REF: https://docs.kraken.com/websockets/#message-book
+	KrakenBook struct {
+		AsksSnapshot [][]string `json:"as"`
+		BidsSnapshot [][]string `json:"bs"`
+		Asks         [][]string `json:"a"`
+		Bids         [][]string `json:"b"`
 	}
 
 	// KrakenEvent wraps the possible events from the provider.
@@ -80,6 +102,14 @@ type (
 		ErrorMessage string `json:"errorMessage"` // error description
 	}
 
+	// KrakenTickerResponse defines the response structure of the Kraken REST
+	// ticker request, whose result is keyed by the Kraken pair name.
+	// REF: https://docs.kraken.com/api/docs/rest-api/get-ticker-information
+	KrakenTickerResponse struct {
+		Error  []string                `json:"error"`
+		Result map[string]KrakenTicker `json:"result"`
+	}
+
 	// KrakenPairsSummary defines the response structure for an Kraken pairs summary.
 	KrakenPairsSummary struct {
 		Result map[string]KrakenPairData `json:"result"`
@@ -114,14 +144,24 @@ func NewKrakenProvider(
 	krakenLogger := logger.With().Str("provider", string(ProviderKraken)).Logger()
 
 	provider := &KrakenProvider{
-		logger:     krakenLogger,
-		endpoints:  endpoints,
-		priceStore: newPriceStore(krakenLogger),
+		logger:         krakenLogger,
+		endpoints:      endpoints,
+		books:          map[string]*orderBook{},
+		depthFallbacks: map[string]struct{}{},
+		priceStore:     newPriceStore(krakenLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+
+	if endpoints.PriceSource == PriceSourceDepth {
+		depthNotionals, err := endpoints.depthNotionals()
+		if err != nil {
+			return nil, fmt.Errorf("invalid kraken depth notionals: %w", err)
+		}
+		provider.depthNotionals = depthNotionals
 	}
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -139,6 +179,7 @@ func NewKrakenProvider(
 		provider.messageReceived,
 		time.Duration(0),
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		krakenLogger,
 	)
 
@@ -147,6 +188,13 @@ func NewKrakenProvider(
 
 func (p *KrakenProvider) StartConnections() {
 	p.wsc.StartConnections()
+	if p.endpoints.restFallbackEnabled() {
+		go startStaleTickerRESTFallback(p.wsc, &p.priceStore, p.logger, p.pollRESTTickers)
+	}
+}
+
+func (p *KrakenProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
 }
 
 func (p *KrakenProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
@@ -155,10 +203,19 @@ func (p *KrakenProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interf
 		krakenPair := currencyPairToKrakenPair(cp)
 		subscriptionMsgs = append(subscriptionMsgs, newKrakenTickerSubscriptionMsg(krakenPair))
 		subscriptionMsgs = append(subscriptionMsgs, newKrakenCandleSubscriptionMsg(krakenPair))
+		if p.isDepthPriceSource() {
+			subscriptionMsgs = append(subscriptionMsgs, newKrakenBookSubscriptionMsg(krakenPair))
+		}
 	}
 	return subscriptionMsgs
 }
 
+// isDepthPriceSource returns true if the depth price of the book is used as
+// the ticker price.
+func (p *KrakenProvider) isDepthPriceSource() bool {
+	return len(p.depthNotionals) > 0
+}
+
 // SubscribeCurrencyPairs sends the new subscription messages to the websocket
 // and adds them to the providers subscribedPairs array
 func (p *KrakenProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
@@ -174,7 +231,7 @@ func (p *KrakenProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -196,7 +253,7 @@ func (candle KrakenCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
 		candle.Close,
 		candle.Volume,
-		candle.TimeStamp,
+		SecondsToMilli(candle.TimeStamp),
 	)
 }
 
@@ -211,6 +268,7 @@ func (p *KrakenProvider) messageReceived(messageType int, _ *WebsocketConnection
 		krakenErr   error
 		tickerErr   error
 		candleErr   error
+		bookErr     error
 	)
 
 	krakenErr = json.Unmarshal(bz, &krakenEvent)
@@ -235,10 +293,16 @@ func (p *KrakenProvider) messageReceived(messageType int, _ *WebsocketConnection
 		return
 	}
 
+	bookErr = p.messageReceivedBook(bz)
+	if bookErr == nil {
+		return
+	}
+
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("book", bookErr).
 		AnErr("event", krakenErr).
 		Msg("Error on receive message")
 }
@@ -282,8 +346,135 @@ func (p *KrakenProvider) messageReceivedTickerPrice(bz []byte) error {
 	krakenPair = normalizeKrakenBTCPair(krakenPair)
 	currencyPairSymbol := krakenPairToCurrencyPairSymbol(krakenPair)
 
-	p.setTickerPair(krakenTicker, currencyPairSymbol)
 	telemetryWebsocketMessage(ProviderKraken, MessageTypeTicker)
+	p.setTickerPair(p.priceSourceTicker(krakenTicker, currencyPairSymbol), currencyPairSymbol)
+	return nil
+}
+
+// priceSourceTicker returns the ticker of the pair with the depth price of its
+// book with the depth price source, or the ticker with its last price
+// otherwise. Pairs whose depth price is unavailable, such as pairs whose book
+// can't fill the notional, fall back to their last price with a warning.
+func (p *KrakenProvider) priceSourceTicker(krakenTicker KrakenTicker, currencyPairSymbol string) providerTicker {
+	if !p.isDepthPriceSource() {
+		return krakenTicker
+	}
+
+	depthPrice, err := p.depthPrice(currencyPairSymbol)
+
+	p.booksMtx.Lock()
+	defer p.booksMtx.Unlock()
+
+	if err != nil {
+		// only warned once until the depth price is available again, since
+		// tickers are received continuously
+		if _, ok := p.depthFallbacks[currencyPairSymbol]; !ok {
+			p.depthFallbacks[currencyPairSymbol] = struct{}{}
+			p.logger.Warn().Err(err).Str("pair", currencyPairSymbol).Msg("depth price unavailable; using the last price")
+		}
+		return krakenTicker
+	}
+	delete(p.depthFallbacks, currencyPairSymbol)
+	return depthTicker{ticker: krakenTicker, price: depthPrice}
+}
+
+// messageReceivedBook handles the book snapshot and update msgs, which hold
+// either one object with the asks or bids, or one object for each side.
+func (p *KrakenProvider) messageReceivedBook(bz []byte) error {
+	// the provider response is an array with different types at each index
+	// kraken documentation https://docs.kraken.com/websockets/#message-book
+	var bookMessage []json.RawMessage
+	if err := json.Unmarshal(bz, &bookMessage); err != nil {
+		return err
+	}
+
+	if len(bookMessage) != 4 && len(bookMessage) != 5 {
+		return fmt.Errorf("received something different than book")
+	}
+
+	var channelName, krakenPair string
+	if err := json.Unmarshal(bookMessage[len(bookMessage)-2], &channelName); err != nil ||
+		!strings.HasPrefix(channelName, "book-") {
+		return fmt.Errorf("received an unexpected channel name")
+	}
+	if err := json.Unmarshal(bookMessage[len(bookMessage)-1], &krakenPair); err != nil {
+		return fmt.Errorf("received an unexpected pair")
+	}
+
+	currencyPairSymbol := krakenPairToCurrencyPairSymbol(normalizeKrakenBTCPair(krakenPair))
+
+	p.booksMtx.Lock()
+	defer p.booksMtx.Unlock()
+
+	book, ok := p.books[currencyPairSymbol]
+	if !ok {
+		book = newOrderBook(krakenBookDepth)
+		p.books[currencyPairSymbol] = book
+	}
+
+	for _, bookBz := range bookMessage[1 : len(bookMessage)-2] {
+		var krakenBook KrakenBook
+		if err := json.Unmarshal(bookBz, &krakenBook); err != nil {
+			return err
+		}
+		if err := krakenBook.apply(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// depthPrice returns the depth price of the book of the pair for the depth
+// notional of its quote currency.
+func (p *KrakenProvider) depthPrice(currencyPairSymbol string) (math.LegacyDec, error) {
+	p.subscribedPairsMtx.RLock()
+	cp, ok := p.subscribedPairs[currencyPairSymbol]
+	p.subscribedPairsMtx.RUnlock()
+	if !ok {
+		return math.LegacyDec{}, fmt.Errorf("%s is not subscribed", currencyPairSymbol)
+	}
+	notional, ok := p.depthNotionals[cp.Quote]
+	if !ok {
+		return math.LegacyDec{}, fmt.Errorf("no depth notional for quote %s", cp.Quote)
+	}
+
+	p.booksMtx.Lock()
+	defer p.booksMtx.Unlock()
+
+	book, ok := p.books[currencyPairSymbol]
+	if !ok {
+		return math.LegacyDec{}, fmt.Errorf("no order book for %s", currencyPairSymbol)
+	}
+	return book.depthPrice(notional)
+}
+
+// apply applies the snapshot or update levels to the order book, resetting
+// the book first on a snapshot.
+func (kb KrakenBook) apply(book *orderBook) error {
+	if kb.AsksSnapshot != nil || kb.BidsSnapshot != nil {
+		book.reset()
+	}
+
+	for _, levels := range [][][]string{kb.AsksSnapshot, kb.Asks} {
+		for _, level := range levels {
+			if len(level) < 2 {
+				return fmt.Errorf("wrong number of fields in book level")
+			}
+			if err := book.setAsk(level[0], level[1]); err != nil {
+				return err
+			}
+		}
+	}
+	for _, levels := range [][][]string{kb.BidsSnapshot, kb.Bids} {
+		for _, level := range levels {
+			if len(level) < 2 {
+				return fmt.Errorf("wrong number of fields in book level")
+			}
+			if err := book.setBid(level[0], level[1]); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -385,33 +576,72 @@ func (p *KrakenProvider) messageReceivedSubscriptionStatus(bz []byte) {
 	}
 }
 
-// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
-func (p *KrakenProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
-	for _, cp := range cps {
-		p.subscribedPairs[cp.String()] = cp
-	}
-}
-
 // removeSubscribedTickers delete N pairs from the subscribed map.
 func (p *KrakenProvider) removeSubscribedTickers(tickerSymbols ...string) {
-	p.mtx.Lock()
-	defer p.mtx.Unlock()
+	p.subscribedPairsMtx.Lock()
+	defer p.subscribedPairsMtx.Unlock()
 
 	for _, tickerSymbol := range tickerSymbols {
 		delete(p.subscribedPairs, tickerSymbol)
 	}
 }
 
+// pollRESTTickers fetches the tickers of the given pairs from the REST API and
+// sets them in the price store, with the depth price of their book when it is
+// the price source. Kraken keys the result by its own pair names, ex.:
+// XXBTZUSD, so each pair is requested on its own, and the pairs which fail
+// don't prevent the others from being set.
+func (p *KrakenProvider) pollRESTTickers(cps ...types.CurrencyPair) error {
+	var errs []error
+	for _, cp := range cps {
+		ticker, err := p.getRESTTicker(cp)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.setTickerPair(p.priceSourceTicker(ticker, cp.String()), cp.String())
+		telemetryRESTFallbackMessage(ProviderKraken, MessageTypeTicker)
+	}
+
+	return errors.Join(errs...)
+}
+
+// getRESTTicker fetches the ticker of a single pair from the REST API.
+func (p *KrakenProvider) getRESTTicker(cp types.CurrencyPair) (KrakenTicker, error) {
+	reqURL := p.endpoints.Rest + krakenRestTickerPath + "?pair=" + url.QueryEscape(cp.String())
+	resp, err := p.endpoints.httpGetWithRetries(reqURL)
+	if err != nil {
+		return KrakenTicker{}, err
+	}
+	defer resp.Body.Close()
+
+	var tickerResp KrakenTickerResponse
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&tickerResp); err != nil {
+		return KrakenTicker{}, err
+	}
+	if len(tickerResp.Error) > 0 {
+		return KrakenTicker{}, fmt.Errorf("kraken ticker request for %s failed: %s", cp, strings.Join(tickerResp.Error, ", "))
+	}
+	if len(tickerResp.Result) != 1 {
+		return KrakenTicker{}, fmt.Errorf("unexpected kraken ticker result for %s", cp)
+	}
+
+	for _, ticker := range tickerResp.Result {
+		return ticker, nil
+	}
+	return KrakenTicker{}, nil
+}
+
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *KrakenProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + KrakenRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + KrakenRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary KrakenPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -464,6 +694,18 @@ func newKrakenCandleSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
 	}
 }
 
+// newKrakenBookSubscriptionMsg returns a new book subscription Msg.
+func newKrakenBookSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
+	return KrakenSubscriptionMsg{
+		Event: "subscribe",
+		Pair:  pairs,
+		Subscription: KrakenSubscriptionChannel{
+			Name:  "book",
+			Depth: krakenBookDepth,
+		},
+	}
+}
+
 // krakenPairToCurrencyPairSymbol receives a kraken pair formated
 // ex.: ATOM/USDT and return currencyPair Symbol ATOMUSDT.
 func krakenPairToCurrencyPairSymbol(krakenPair string) string {
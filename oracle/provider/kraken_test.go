@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -107,3 +110,108 @@ func TestKrakenProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ohlc\"}}", string(msg))
 }
+
+func TestKrakenProvider_DepthPrice(t *testing.T) {
+	p := &KrakenProvider{
+		logger:         zerolog.Nop(),
+		depthNotionals: map[string]math.LegacyDec{"USD": math.LegacyNewDec(49)},
+		books:          map[string]*orderBook{},
+		depthFallbacks: map[string]struct{}{},
+		priceStore:     newPriceStore(zerolog.Nop(), defaultCandlePeriod),
+	}
+	p.setSubscribedPairs(BTCUSD, ATOMUSDT)
+
+	subMsgs := p.getSubscriptionMsgs(BTCUSD)
+	require.Len(t, subMsgs, 3)
+	msg, _ := json.Marshal(subMsgs[2])
+	require.Equal(t,
+		`{"event":"subscribe","pair":["BTC/USD"],"subscription":{"name":"book","depth":100}}`,
+		string(msg),
+	)
+
+	p.messageReceived(websocket.TextMessage, nil, []byte(`[0,{
+		"as":[["100.00000","1.00000000","1534614057.321597"],["140.00000","2.00000000","1534614057.321597"]],
+		"bs":[["98.00000","1.00000000","1534614057.321597"],["70.00000","2.00000000","1534614057.321597"]]
+	},"book-100","XBT/USD"]`))
+
+	ticker := []byte(`[0,{"c":["110.00000","0.1"],"v":["10.0","20.0"]},"ticker","XBT/USD"]`)
+	p.messageReceived(websocket.TextMessage, nil, ticker)
+	prices, err := p.GetTickerPrices(BTCUSD)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(99), prices[BTCUSD].Price)
+	require.Equal(t, math.LegacyNewDec(20), prices[BTCUSD].Volume)
+
+	// removing the best ask and bid moves the depth price to the next levels
+	p.messageReceived(websocket.TextMessage, nil, []byte(`[0,
+		{"a":[["100.00000","0.00000000","1534614335.345903"]]},
+		{"b":[["98.00000","0.00000000","1534614335.345903"]],"c":"974942666"},
+		"book-100","XBT/USD"]`))
+	p.messageReceived(websocket.TextMessage, nil, ticker)
+	prices, err = p.GetTickerPrices(BTCUSD)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(105), prices[BTCUSD].Price)
+
+	// a book which can't fill the notional falls back to the last price
+	p.messageReceived(websocket.TextMessage, nil, []byte(`[0,
+		{"a":[["140.00000","0.00000000","1534614335.345903"]]},
+		"book-100","XBT/USD"]`))
+	p.messageReceived(websocket.TextMessage, nil, ticker)
+	prices, err = p.GetTickerPrices(BTCUSD)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(110), prices[BTCUSD].Price)
+
+	// a pair quoted in a currency without a notional uses its last price
+	p.messageReceived(websocket.TextMessage, nil, []byte(`[0,
+		{"c":["0.05","0.1"],"v":["10.0","20.0"]},
+		"ticker","ATOM/USDT"]`))
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.05"), prices[ATOMUSDT].Price)
+}
+
+func TestKrakenProvider_pollRESTTickers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case KrakenRestPath:
+			_, err = w.Write([]byte(`{"result":{"ATOMUSDT":{"wsname":"ATOM/USDT"},"ATOMUSDC":{"wsname":"ATOM/USDC"}}}`))
+		case krakenRestTickerPath:
+			if r.URL.Query().Get("pair") != "ATOMUSDT" {
+				_, err = w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+				break
+			}
+			_, err = w.Write([]byte(`{"error":[],"result":{"ATOMUSDT":{
+				"c":["34.69000000","1.5"],
+				"v":["1000.00000000","2396974.02000000"]
+			}}}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewKrakenProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderKraken, Rest: server.URL, Websocket: "localhost"},
+		ATOMUSDT, ATOMUSDC,
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.CurrencyPair{ATOMUSDT, ATOMUSDC}, p.staleTickerPairs(restFallbackThreshold))
+
+	// the failing pair doesn't prevent the other one from being set
+	require.Error(t, p.pollRESTTickers(ATOMUSDT, ATOMUSDC))
+
+	prices, err := p.GetTickerPrices(ATOMUSDT, ATOMUSDC)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("34.69"), prices[ATOMUSDT].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
+	require.Equal(t, []types.CurrencyPair{ATOMUSDC}, p.staleTickerPairs(restFallbackThreshold))
+}
+
+func TestEndpoint_restFallbackEnabled(t *testing.T) {
+	disabled := false
+	require.True(t, Endpoint{Name: ProviderKraken}.restFallbackEnabled())
+	require.False(t, Endpoint{Name: ProviderKraken, EnableRESTFallback: &disabled}.restFallbackEnabled())
+	require.False(t, Endpoint{Name: ProviderCoinbase}.restFallbackEnabled())
+}
@@ -0,0 +1,445 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	kucoinRestHost        = "https://api.kucoin.com"
+	kucoinRestPath        = "/api/v2/symbols"
+	kucoinBulletPath      = "/api/v1/bullet-public"
+	kucoinSuccessCode     = "200000"
+	kucoinTickerTopic     = "/market/ticker:"
+	kucoinSnapshotTopic   = "/market/snapshot:"
+	kucoinCandleTopic     = "/market/candles:"
+	kucoinCandleInterval  = "_1min"
+	kucoinBulletRefresh   = time.Hour
+	kucoinMessageType     = "message"
+	kucoinPingMessageJSON = `{"id":"ping","type":"ping"}`
+)
+
+var _ Provider = (*KuCoinProvider)(nil)
+
+type (
+	// KuCoinProvider defines an Oracle provider implemented by the KuCoin
+	// public API. KuCoin serves its websocket on an instance server returned
+	// along with a connection token by its bullet endpoint, so both are
+	// requested over REST before connecting, and again once the token is older
+	// than kucoinBulletRefresh. The ticker channel carries no volume, so the
+	// 24h volume of each pair is taken from its snapshot channel.
+	//
+	// REF: https://www.kucoin.com/docs/websocket/basic-info/apply-connect-token/public-token-no-authentication-required-
+	// REF: https://www.kucoin.com/docs/websocket/spot-trading/public-channels/ticker
+	// REF: https://www.kucoin.com/docs/websocket/spot-trading/public-channels/klines
+	KuCoinProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+		volumes   map[string]string // symbol -> 24h volume
+		volumeMtx sync.RWMutex
+
+		bulletMtx  sync.Mutex
+		bullet     KuCoinBullet
+		bulletTime time.Time
+
+		priceStore
+	}
+
+	// KuCoinBulletResponse defines the response structure of a KuCoin bullet
+	// request.
+	KuCoinBulletResponse struct {
+		Code string       `json:"code"` // ex.: "200000"
+		Data KuCoinBullet `json:"data"`
+	}
+
+	// KuCoinBullet defines the token and the instance servers to connect to.
+	KuCoinBullet struct {
+		Token           string                 `json:"token"`
+		InstanceServers []KuCoinInstanceServer `json:"instanceServers"`
+	}
+
+	// KuCoinInstanceServer defines a websocket server of KuCoin.
+	KuCoinInstanceServer struct {
+		Endpoint string `json:"endpoint"` // ex.: wss://ws-api-spot.kucoin.com/
+		Protocol string `json:"protocol"` // ex.: websocket
+	}
+
+	// KuCoinSubscriptionMsg Msg to subscribe/unsubscribe to a topic.
+	KuCoinSubscriptionMsg struct {
+		ID             string `json:"id"`             // Unique ID of the request
+		Type           string `json:"type"`           // ex.: subscribe
+		Topic          string `json:"topic"`          // ex.: /market/ticker:BTC-USDT,ETH-USDT
+		PrivateChannel bool   `json:"privateChannel"` // always false
+		Response       bool   `json:"response"`       // whether the server acks the request
+	}
+
+	// KuCoinMessage defines a message received from the KuCoin websocket.
+	KuCoinMessage struct {
+		Type  string          `json:"type"`  // ex.: message, welcome, ack or pong
+		Topic string          `json:"topic"` // ex.: /market/ticker:BTC-USDT
+		Data  json.RawMessage `json:"data"`
+	}
+
+	// KuCoinTicker defines a ticker of KuCoin.
+	KuCoinTicker struct {
+		Price  string `json:"price"` // Last traded price ex.: 0.08
+		Volume string `json:"-"`     // 24h volume of the snapshot channel
+	}
+
+	// KuCoinSnapshot defines a snapshot of a KuCoin market.
+	KuCoinSnapshot struct {
+		Data struct {
+			Symbol string      `json:"symbol"` // ex.: BTC-USDT
+			Vol    json.Number `json:"vol"`    // 24h volume in the base currency
+		} `json:"data"`
+	}
+
+	// KuCoinCandle defines a candle of KuCoin.
+	KuCoinCandle struct {
+		Symbol  string   `json:"symbol"`  // ex.: BTC-USDT
+		Candles []string `json:"candles"` // [start (seconds), open, close, high, low, volume, turnover]
+	}
+
+	// KuCoinPairsSummary defines the response structure for the KuCoin
+	// symbols.
+	KuCoinPairsSummary struct {
+		Code string           `json:"code"`
+		Data []KuCoinPairData `json:"data"`
+	}
+
+	// KuCoinPairData defines a symbol of KuCoin.
+	KuCoinPairData struct {
+		BaseCurrency  string `json:"baseCurrency"`  // ex.: BTC
+		QuoteCurrency string `json:"quoteCurrency"` // ex.: USDT
+		EnableTrading bool   `json:"enableTrading"`
+	}
+)
+
+// NewKuCoinProvider creates a new KuCoinProvider. It returns an error if the
+// bullet request for the websocket server and token fails.
+func NewKuCoinProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*KuCoinProvider, error) {
+	if endpoints.Name != ProviderKuCoin {
+		endpoints = Endpoint{
+			Name: ProviderKuCoin,
+			Rest: kucoinRestHost,
+		}
+	}
+
+	kucoinLogger := logger.With().Str("provider", string(ProviderKuCoin)).Logger()
+
+	provider := &KuCoinProvider{
+		logger:     kucoinLogger,
+		endpoints:  endpoints,
+		client:     newDefaultHTTPClient(endpoints.restTimeout()),
+		volumes:    map[string]string{},
+		priceStore: newPriceStore(kucoinLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToKuCoinPair)
+
+	wsURL, err := provider.websocketURL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s websocket bullet: %w", ProviderKuCoin, err)
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+		endpoints.reconnectMaxInterval(),
+		kucoinLogger,
+	)
+	provider.wsc.SetPingMessage([]byte(kucoinPingMessageJSON))
+	provider.wsc.SetURLResolver(provider.websocketURL)
+
+	return provider, nil
+}
+
+func (p *KuCoinProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *KuCoinProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
+// getSubscriptionMsgs returns the ticker, snapshot and candle subscription
+// messages of the pairs, each topic subscribing to all of them at once.
+func (p *KuCoinProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	if len(cps) == 0 {
+		return []interface{}{}
+	}
+
+	symbols := make([]string, 0, len(cps))
+	candleSymbols := make([]string, 0, len(cps))
+	for _, cp := range cps {
+		symbol := currencyPairToKuCoinPair(cp)
+		symbols = append(symbols, symbol)
+		candleSymbols = append(candleSymbols, symbol+kucoinCandleInterval)
+	}
+
+	return []interface{}{
+		newKuCoinSubscriptionMsg(kucoinTickerTopic + strings.Join(symbols, ",")),
+		newKuCoinSubscriptionMsg(kucoinSnapshotTopic + strings.Join(symbols, ",")),
+		newKuCoinSubscriptionMsg(kucoinCandleTopic + strings.Join(candleSymbols, ",")),
+	}
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *KuCoinProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+func (p *KuCoinProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
+	var msg KuCoinMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("message", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	// welcome, ack and pong messages carry no prices
+	if msg.Type != kucoinMessageType {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(msg.Topic, kucoinTickerTopic):
+		var ticker KuCoinTicker
+		if err := json.Unmarshal(msg.Data, &ticker); err != nil {
+			p.logger.Error().Err(err).Msg("Unable to parse kucoin ticker")
+			return
+		}
+		symbol := strings.TrimPrefix(msg.Topic, kucoinTickerTopic)
+		ticker.Volume = p.getVolume(symbol)
+		p.setTickerPair(ticker, symbol)
+		telemetryWebsocketMessage(ProviderKuCoin, MessageTypeTicker)
+
+	case strings.HasPrefix(msg.Topic, kucoinSnapshotTopic):
+		var snapshot KuCoinSnapshot
+		if err := json.Unmarshal(msg.Data, &snapshot); err != nil {
+			p.logger.Error().Err(err).Msg("Unable to parse kucoin snapshot")
+			return
+		}
+		p.setVolume(snapshot.Data.Symbol, snapshot.Data.Vol.String())
+
+	case strings.HasPrefix(msg.Topic, kucoinCandleTopic):
+		var candle KuCoinCandle
+		if err := json.Unmarshal(msg.Data, &candle); err != nil {
+			p.logger.Error().Err(err).Msg("Unable to parse kucoin candle")
+			return
+		}
+		p.setCandlePair(candle, candle.Symbol)
+		telemetryWebsocketMessage(ProviderKuCoin, MessageTypeCandle)
+	}
+}
+
+// websocketURL returns the url of a new websocket connection, with the token
+// and instance server of the last bullet, which is requested again once it is
+// older than kucoinBulletRefresh. Each connection gets its own connect id.
+func (p *KuCoinProvider) websocketURL() (url.URL, error) {
+	p.bulletMtx.Lock()
+	defer p.bulletMtx.Unlock()
+
+	if time.Since(p.bulletTime) >= kucoinBulletRefresh {
+		bullet, err := p.requestBullet()
+		if err != nil {
+			return url.URL{}, err
+		}
+		p.bullet = bullet
+		p.bulletTime = time.Now()
+	}
+
+	wsURL, err := url.Parse(p.bullet.InstanceServers[0].Endpoint)
+	if err != nil {
+		return url.URL{}, err
+	}
+	query := wsURL.Query()
+	query.Set("token", p.bullet.Token)
+	query.Set("connectId", strconv.FormatInt(time.Now().UnixNano(), 10))
+	wsURL.RawQuery = query.Encode()
+
+	return *wsURL, nil
+}
+
+// requestBullet requests a public token and the instance servers of the
+// websocket.
+func (p *KuCoinProvider) requestBullet() (KuCoinBullet, error) {
+	resp, err := p.client.Post(p.endpoints.Rest+kucoinBulletPath, "application/json", nil)
+	if err != nil {
+		return KuCoinBullet{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return KuCoinBullet{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var bulletResp KuCoinBulletResponse
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&bulletResp); err != nil {
+		return KuCoinBullet{}, err
+	}
+	if bulletResp.Code != kucoinSuccessCode {
+		return KuCoinBullet{}, fmt.Errorf("unexpected response code: %s", bulletResp.Code)
+	}
+	if bulletResp.Data.Token == "" || len(bulletResp.Data.InstanceServers) == 0 {
+		return KuCoinBullet{}, fmt.Errorf("bullet has no token or instance server")
+	}
+
+	return bulletResp.Data, nil
+}
+
+// GetAvailablePairs returns all pairs currently trading on KuCoin.
+func (p *KuCoinProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.client.Get(p.endpoints.Rest + kucoinRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary KuCoinPairsSummary
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+	if pairsSummary.Code != kucoinSuccessCode {
+		return nil, fmt.Errorf("unable to get kucoin available pairs")
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Data))
+	for _, pair := range pairsSummary.Data {
+		if !pair.EnableTrading {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  pair.BaseCurrency,
+			Quote: pair.QuoteCurrency,
+		}
+		availablePairs[strings.ToUpper(cp.String())] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// setVolume stores the 24h volume of the snapshot of a symbol.
+func (p *KuCoinProvider) setVolume(symbol, volume string) {
+	p.volumeMtx.Lock()
+	defer p.volumeMtx.Unlock()
+
+	p.volumes[symbol] = volume
+}
+
+// getVolume returns the last 24h volume of a symbol, or zero if no snapshot
+// was received yet.
+func (p *KuCoinProvider) getVolume(symbol string) string {
+	p.volumeMtx.RLock()
+	defer p.volumeMtx.RUnlock()
+
+	volume, ok := p.volumes[symbol]
+	if !ok {
+		return "0"
+	}
+	return volume
+}
+
+func (ticker KuCoinTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.Price, ticker.Volume)
+}
+
+// toCandlePrice converts the candle, whose close and volume are at the 2 and
+// 5 indexes of its candles, to a CandlePrice.
+func (candle KuCoinCandle) toCandlePrice() (types.CandlePrice, error) {
+	if len(candle.Candles) < 6 {
+		return types.CandlePrice{}, fmt.Errorf("invalid kucoin candle")
+	}
+
+	ts, err := strconv.ParseInt(candle.Candles[0], 10, 64)
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+
+	return types.NewCandlePrice(candle.Candles[2], candle.Candles[5], SecondsToMilli(ts))
+}
+
+// currencyPairToKuCoinPair returns the expected pair symbol for KuCoin
+// ex.: "BTC-USDT".
+func currencyPairToKuCoinPair(pair types.CurrencyPair) string {
+	return pair.Base + "-" + pair.Quote
+}
+
+// newKuCoinSubscriptionMsg returns a new subscription Msg for KuCoin, using
+// the topic as its ID so the same pairs always produce the same Msg.
+func newKuCoinSubscriptionMsg(topic string) KuCoinSubscriptionMsg {
+	return KuCoinSubscriptionMsg{
+		ID:             topic,
+		Type:           "subscribe",
+		Topic:          topic,
+		PrivateChannel: false,
+		Response:       true,
+	}
+}
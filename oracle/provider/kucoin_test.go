@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// newKuCoinTestServer returns a server serving the bullet and symbols of
+// KuCoin, counting the bullet requests.
+func newKuCoinTestServer(t *testing.T, bulletRequests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch {
+		case r.URL.Path == kucoinBulletPath && r.Method == http.MethodPost:
+			*bulletRequests++
+			body = `{"code":"200000","data":{"token":"token","instanceServers":[
+				{"endpoint":"wss://ws-api-spot.kucoin.com/","protocol":"websocket"}
+			]}}`
+		case r.URL.Path == kucoinRestPath:
+			body = `{"code":"200000","data":[
+				{"baseCurrency":"ATOM","quoteCurrency":"USDT","enableTrading":true},
+				{"baseCurrency":"LUNA","quoteCurrency":"USDT","enableTrading":true},
+				{"baseCurrency":"FOO","quoteCurrency":"BAR","enableTrading":false}
+			]}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+}
+
+func newKuCoinTestProvider(t *testing.T) *KuCoinProvider {
+	bulletRequests := 0
+	server := newKuCoinTestServer(t, &bulletRequests)
+	t.Cleanup(server.Close)
+
+	p, err := NewKuCoinProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderKuCoin, Rest: server.URL},
+		ATOMUSDT,
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewKuCoinProvider_Bullet(t *testing.T) {
+	t.Run("bullet_failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := NewKuCoinProvider(
+			context.TODO(),
+			zerolog.Nop(),
+			Endpoint{Name: ProviderKuCoin, Rest: server.URL},
+			ATOMUSDT,
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("bullet_without_token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{"code":"200000","data":{"token":"","instanceServers":[]}}`))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		_, err := NewKuCoinProvider(
+			context.TODO(),
+			zerolog.Nop(),
+			Endpoint{Name: ProviderKuCoin, Rest: server.URL},
+			ATOMUSDT,
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("websocket_url", func(t *testing.T) {
+		bulletRequests := 0
+		server := newKuCoinTestServer(t, &bulletRequests)
+		defer server.Close()
+
+		p, err := NewKuCoinProvider(
+			context.TODO(),
+			zerolog.Nop(),
+			Endpoint{Name: ProviderKuCoin, Rest: server.URL},
+			ATOMUSDT,
+		)
+		require.NoError(t, err)
+		require.Equal(t, 1, bulletRequests)
+
+		// the bullet is reused until it is refreshed, with a new connect id
+		// for each connection
+		first, err := p.websocketURL()
+		require.NoError(t, err)
+		second, err := p.websocketURL()
+		require.NoError(t, err)
+		require.Equal(t, 1, bulletRequests)
+		require.Equal(t, "ws-api-spot.kucoin.com", first.Host)
+		require.Equal(t, "token", first.Query().Get("token"))
+		require.NotEqual(t, first.Query().Get("connectId"), second.Query().Get("connectId"))
+	})
+}
+
+func TestKuCoinProvider_GetTickerPrices(t *testing.T) {
+	p := newKuCoinTestProvider(t)
+
+	t.Run("valid_request_single_ticker", func(t *testing.T) {
+		lastPrice := "34.69000000"
+		volume := "2396974.02000000"
+
+		p.setTickerPair(KuCoinTicker{Price: lastPrice, Volume: volume}, "ATOM-USDT")
+
+		prices, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr(lastPrice), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[ATOMUSDT].Volume)
+	})
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		atomLastPrice := "34.69000000"
+		lunaLastPrice := "41.35000000"
+		volume := "2396974.02000000"
+
+		p.setTickerPair(KuCoinTicker{Price: atomLastPrice, Volume: volume}, "ATOM-USDT")
+		p.setTickerPair(KuCoinTicker{Price: lunaLastPrice, Volume: volume}, "LUNA-USDT")
+
+		prices, err := p.GetTickerPrices(ATOMUSDT, LUNAUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr(atomLastPrice), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[ATOMUSDT].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr(lunaLastPrice), prices[LUNAUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices[LUNAUSDT].Volume)
+	})
+
+	t.Run("valid_ticker_and_snapshot_messages", func(t *testing.T) {
+		p.messageReceived(0, nil, []byte(`{
+			"type":"message",
+			"topic":"/market/snapshot:ATOM-USDT",
+			"subject":"trade.snapshot",
+			"data":{"sequence":"1","data":{"symbol":"ATOM-USDT","lastTradedPrice":10.4,"vol":1000.5}}
+		}`))
+		p.messageReceived(0, nil, []byte(`{
+			"type":"message",
+			"topic":"/market/ticker:ATOM-USDT",
+			"subject":"trade.ticker",
+			"data":{"sequence":"2","price":"10.5","size":"0.1","time":1545914149935}
+		}`))
+
+		prices, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10.5"), prices[ATOMUSDT].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1000.5"), prices[ATOMUSDT].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, _ := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.Empty(t, prices)
+	})
+}
+
+func TestKuCoinProvider_GetCandlePrices(t *testing.T) {
+	p := newKuCoinTestProvider(t)
+
+	t.Run("valid_request_single_candle", func(t *testing.T) {
+		timeStamp := PastUnixTimeMillis(0) / 1000
+		p.messageReceived(0, nil, []byte(fmt.Sprintf(`{
+			"type":"message",
+			"topic":"/market/candles:ATOM-USDT_1min",
+			"subject":"trade.candles.update",
+			"data":{"symbol":"ATOM-USDT","candles":["%d","10.1","10.5","10.6","10","20","210"],"time":1}
+		}`, timeStamp)))
+
+		prices, err := p.GetCandlePrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10.5"), prices[ATOMUSDT][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("20"), prices[ATOMUSDT][0].Volume)
+		require.Equal(t, SecondsToMilli(timeStamp), prices[ATOMUSDT][0].TimeStamp)
+	})
+
+	t.Run("invalid_candle", func(t *testing.T) {
+		_, err := KuCoinCandle{Candles: []string{"1", "2"}}.toCandlePrice()
+		require.Error(t, err)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, _ := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.Empty(t, prices)
+	})
+}
+
+func TestKuCoinProvider_AvailablePairs(t *testing.T) {
+	p := newKuCoinTestProvider(t)
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+	require.Contains(t, pairs, "ATOMUSDT")
+	require.NotContains(t, pairs, "FOOBAR")
+}
+
+func TestKuCoinProvider_NewSubscriptionMsg(t *testing.T) {
+	p := &KuCoinProvider{}
+	msgs := p.getSubscriptionMsgs(ATOMUSDT, LUNAUSDT)
+
+	require.Len(t, msgs, 3)
+	require.Equal(t, "/market/ticker:ATOM-USDT,LUNA-USDT", msgs[0].(KuCoinSubscriptionMsg).Topic)
+	require.Equal(t, "/market/snapshot:ATOM-USDT,LUNA-USDT", msgs[1].(KuCoinSubscriptionMsg).Topic)
+	require.Equal(t, "/market/candles:ATOM-USDT_1min,LUNA-USDT_1min", msgs[2].(KuCoinSubscriptionMsg).Topic)
+	require.Equal(t, "subscribe", msgs[0].(KuCoinSubscriptionMsg).Type)
+	require.Empty(t, p.getSubscriptionMsgs())
+}
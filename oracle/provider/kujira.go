@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -36,6 +35,7 @@ type (
 		logger    zerolog.Logger
 		mtx       sync.RWMutex
 		endpoints Endpoint
+		ctx       context.Context
 
 		priceStore
 	}
@@ -90,13 +90,14 @@ func NewKujiraProvider(
 		wsURL:      wsURL,
 		logger:     kujiraLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(kujiraLogger),
+		ctx:        ctx,
+		priceStore: newPriceStore(kujiraLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToKujiraPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -114,16 +115,25 @@ func NewKujiraProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		kujiraLogger,
 	)
 
 	return provider, nil
 }
 
+// StartConnections starts the websocket connection, and checks that data
+// arrives for each subscribed pair since the server pushes every pair
+// without per-pair subscriptions.
 func (p *KujiraProvider) StartConnections() {
+	p.startPairCoverageCheck(p.ctx, p.wsc, p.endpoints.pairCoverageWindow())
 	p.wsc.StartConnections()
 }
 
+func (p *KujiraProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 // SubscribeCurrencyPairs sends the new subscription messages to the websocket
 // and adds them to the providers subscribedPairs array
 func (p *KujiraProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
@@ -132,7 +142,7 @@ func (p *KujiraProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		cps...,
 	)
@@ -192,7 +202,6 @@ func (p *KujiraProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 
 			// candle response
 			case []interface{}:
-				// use latest candlestick in list if there is one
 				if len(v) == 0 {
 					continue
 				}
@@ -262,14 +271,14 @@ func (p *KujiraProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *KujiraProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + kujiraRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + kujiraRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary []KujiraPairData
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"testing"
+	"time"
 
 	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -114,3 +116,26 @@ func TestKujiraCurrencyPairToKujiraPair(t *testing.T) {
 	kujiraSymbol := currencyPairToKujiraPair(cp)
 	require.Equal(t, kujiraSymbol, "ATOM/USDT")
 }
+
+func TestKujiraProvider_ReportMissingPairs(t *testing.T) {
+	p := &KujiraProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop(), defaultCandlePeriod),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToKujiraPair)
+	p.setSubscribedPairs(KUJIATOM, ATOMUSDT)
+
+	// no data arrived yet for any subscribed pair
+	require.Equal(t, []types.CurrencyPair{ATOMUSDT, KUJIATOM}, p.reportMissingPairs(time.Time{}))
+
+	// the server only pushes the kujira pair, so the misspelled or missing
+	// pair keeps being reported
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"KUJI/ATOM":{"Price":"1.5","Volume":"100"}}`))
+	require.Equal(t, []types.CurrencyPair{ATOMUSDT}, p.reportMissingPairs(time.Time{}))
+
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"ATOM/USDT":{"Price":"10","Volume":"1000"}}`))
+	require.Empty(t, p.reportMissingPairs(time.Time{}))
+
+	// after a reconnect only the data received since reconnecting counts
+	require.Equal(t, []types.CurrencyPair{ATOMUSDT, KUJIATOM}, p.reportMissingPairs(time.Now().Add(time.Minute)))
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -46,8 +45,11 @@ type (
 		Metadata MexcTicker `json:"d"` // Metadata for ticker
 	}
 	MexcTicker struct {
-		LastPrice string `json:"b"` // Best bid price ex.: 0.0025
-		Volume    string `json:"B"` // Best bid qty ex.: 1000
+		LastPrice   string      `json:"b"` // Best bid price ex.: 0.0025
+		Volume      string      `json:"B"` // Best bid qty ex.: 1000
+		AskPrice    string      `json:"a"` // Best ask price ex.: 0.0026
+		AskQty      string      `json:"A"` // Best ask qty ex.: 100
+		PriceSource PriceSource `json:"-"` // Price used as the ticker price
 	}
 
 	// MexcCandle is the candle websocket response object.
@@ -111,13 +113,13 @@ func NewMexcProvider(
 	provider := &MexcProvider{
 		logger:     mexcLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(mexcLogger),
+		priceStore: newPriceStore(mexcLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToMexcPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -135,6 +137,7 @@ func NewMexcProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		mexcLogger,
 	)
 
@@ -145,6 +148,10 @@ func (p *MexcProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *MexcProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *MexcProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
 	mexcPairs := make([]string, 0, len(cps))
@@ -171,7 +178,7 @@ func (p *MexcProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -199,6 +206,7 @@ func (p *MexcProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	if tickerResp.Metadata.LastPrice != "" {
+		tickerResp.Metadata.PriceSource = p.endpoints.PriceSource
 		p.setTickerPair(tickerResp.Metadata, tickerResp.Symbol)
 		telemetryWebsocketMessage(ProviderMexc, MessageTypeTicker)
 		return
@@ -230,6 +238,13 @@ func (mt MexcTicker) toTickerPrice() (types.TickerPrice, error) {
 		return types.TickerPrice{}, err
 	}
 
+	if mt.PriceSource != "" && mt.PriceSource != PriceSourceLast {
+		price, err = bookPrice(mt.PriceSource, mt.LastPrice, mt.Volume, mt.AskPrice, mt.AskQty)
+		if err != nil {
+			return types.TickerPrice{}, fmt.Errorf("mexc: %w", err)
+		}
+	}
+
 	ticker := types.TickerPrice{
 		Price:  price,
 		Volume: volume,
@@ -266,14 +281,14 @@ func (p *MexcProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *MexcProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + mexcRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + mexcRestPath)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var pairsSummary MexcPairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -3,8 +3,12 @@ package provider
 import (
 	"encoding/csv"
 	"fmt"
+	gomath "math"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"cosmossdk.io/math"
@@ -24,13 +28,66 @@ type (
 	// MockProvider defines a mocked exchange rate provider using a published
 	// Google sheets document to fetch mocked/fake exchange rates.
 	MockProvider struct {
-		baseURL string
-		client  *http.Client
+		baseURL   string
+		client    *http.Client
+		generator MockPriceGenerator
+	}
+
+	// MockPriceGenerator defines an interface for generating successive mocked
+	// ticker prices. When set on the MockProvider it is used instead of the
+	// published Google sheets document, which allows for deterministic load
+	// testing without relying on any external service.
+	MockPriceGenerator interface {
+		// Pairs returns the currency pairs the generator is able to price.
+		Pairs() []types.CurrencyPair
+
+		// Next returns the next ticker price for the given currency pair.
+		Next(types.CurrencyPair) (types.TickerPrice, error)
+	}
+
+	// MockPairParams defines the parameters used to generate prices for a single
+	// currency pair.
+	MockPairParams struct {
+		// Price is the starting price of the pair, and the center of the wave
+		// for the SineWaveGenerator.
+		Price float64
+
+		// Volume is the constant volume reported for the pair.
+		Volume float64
+
+		// Volatility is the amplitude of the wave relative to Price for the
+		// SineWaveGenerator, and the maximum relative step size of each read
+		// for the RandomWalkGenerator.
+		Volatility float64
+
+		// Period is the amount of reads it takes the SineWaveGenerator to
+		// complete a full wave. It is unused by the RandomWalkGenerator.
+		Period int
+	}
+
+	// SineWaveGenerator generates prices following a sine wave around the
+	// configured price of each pair.
+	SineWaveGenerator struct {
+		mtx    sync.Mutex
+		params map[types.CurrencyPair]MockPairParams
+		steps  map[types.CurrencyPair]int
+	}
+
+	// RandomWalkGenerator generates prices following a seeded random walk
+	// starting from the configured price of each pair.
+	RandomWalkGenerator struct {
+		mtx    sync.Mutex
+		rand   *rand.Rand
+		params map[types.CurrencyPair]MockPairParams
+		prices map[types.CurrencyPair]float64
 	}
 )
 
-func NewMockProvider() *MockProvider {
-	return &MockProvider{
+// NewMockProvider returns a new MockProvider. If a MockPriceGenerator is given,
+// prices are generated by it instead of being fetched from the published
+// Google sheets document.
+func NewMockProvider(generator ...MockPriceGenerator) *MockProvider {
+	p := &MockProvider{
 		baseURL: mockBaseURL,
 		client: &http.Client{
 			Timeout: defaultTimeout,
@@ -38,6 +95,100 @@ func NewMockProvider() *MockProvider {
 			// because it gets prices from a google spreadsheet, which redirects
 		},
 	}
+	if len(generator) > 0 {
+		p.generator = generator[0]
+	}
+	return p
+}
+
+// NewSineWaveGenerator returns a SineWaveGenerator for the given pair params.
+func NewSineWaveGenerator(params map[types.CurrencyPair]MockPairParams) (*SineWaveGenerator, error) {
+	for cp, param := range params {
+		if param.Period <= 0 {
+			return nil, fmt.Errorf("sine wave period must be positive for %s", cp)
+		}
+	}
+
+	return &SineWaveGenerator{
+		params: params,
+		steps:  make(map[types.CurrencyPair]int, len(params)),
+	}, nil
+}
+
+// Pairs returns the currency pairs the generator is able to price.
+func (g *SineWaveGenerator) Pairs() []types.CurrencyPair {
+	return mockParamPairs(g.params)
+}
+
+// Next returns the next price of the sine wave for the given currency pair.
+func (g *SineWaveGenerator) Next(cp types.CurrencyPair) (types.TickerPrice, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	param, ok := g.params[cp]
+	if !ok {
+		return types.TickerPrice{}, fmt.Errorf(types.ErrMissingExchangeRate.Error(), cp)
+	}
+
+	step := g.steps[cp]
+	g.steps[cp] = (step + 1) % param.Period
+
+	angle := 2 * gomath.Pi * float64(step) / float64(param.Period)
+	price := param.Price * (1 + param.Volatility*gomath.Sin(angle))
+
+	return newMockTickerPrice(price, param.Volume)
+}
+
+// NewRandomWalkGenerator returns a RandomWalkGenerator for the given pair
+// params. The same seed always produces the same sequence of prices.
+func NewRandomWalkGenerator(seed int64, params map[types.CurrencyPair]MockPairParams) *RandomWalkGenerator {
+	prices := make(map[types.CurrencyPair]float64, len(params))
+	for cp, param := range params {
+		prices[cp] = param.Price
+	}
+
+	return &RandomWalkGenerator{
+		rand:   rand.New(rand.NewSource(seed)),
+		params: params,
+		prices: prices,
+	}
+}
+
+// Pairs returns the currency pairs the generator is able to price.
+func (g *RandomWalkGenerator) Pairs() []types.CurrencyPair {
+	return mockParamPairs(g.params)
+}
+
+// Next returns the current price of the random walk for the given currency
+// pair and moves it by a random step within the configured volatility.
+func (g *RandomWalkGenerator) Next(cp types.CurrencyPair) (types.TickerPrice, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	param, ok := g.params[cp]
+	if !ok {
+		return types.TickerPrice{}, fmt.Errorf(types.ErrMissingExchangeRate.Error(), cp)
+	}
+
+	price := g.prices[cp]
+	g.prices[cp] = price * (1 + param.Volatility*(2*g.rand.Float64()-1))
+
+	return newMockTickerPrice(price, param.Volume)
+}
+
+func mockParamPairs(params map[types.CurrencyPair]MockPairParams) []types.CurrencyPair {
+	pairs := make([]types.CurrencyPair, 0, len(params))
+	for cp := range params {
+		pairs = append(pairs, cp)
+	}
+	return pairs
+}
+
+func newMockTickerPrice(price, volume float64) (types.TickerPrice, error) {
+	return types.NewTickerPrice(
+		strconv.FormatFloat(price, 'f', 8, 64),
+		strconv.FormatFloat(volume, 'f', 8, 64),
+	)
 }
 
 func (p *MockProvider) StartConnections() {
@@ -47,7 +198,14 @@ func (p *MockProvider) StartConnections() {
 // SubscribeCurrencyPairs performs a no-op since mock does not use websockets
 func (p MockProvider) SubscribeCurrencyPairs(...types.CurrencyPair) {}
 
+// UnsubscribeCurrencyPairs performs a no-op since mock does not use websockets
+func (p MockProvider) UnsubscribeCurrencyPairs(...types.CurrencyPair) {}
+
 func (p MockProvider) GetTickerPrices(pairs ...types.CurrencyPair) (types.CurrencyPairTickers, error) {
+	if p.generator != nil {
+		return p.getGeneratedTickerPrices(pairs...)
+	}
+
 	tickerPrices := make(types.CurrencyPairTickers, len(pairs))
 
 	resp, err := p.client.Get(p.baseURL)
@@ -57,7 +215,7 @@ func (p MockProvider) GetTickerPrices(pairs ...types.CurrencyPair) (types.Curren
 
 	defer resp.Body.Close()
 
-	csvReader := csv.NewReader(resp.Body)
+	csvReader := csv.NewReader(newLimitedBody(resp.Body, defaultMaxResponseSize))
 	records, err := csvReader.ReadAll()
 	if err != nil {
 		return nil, err
@@ -104,6 +262,19 @@ func (p MockProvider) GetTickerPrices(pairs ...types.CurrencyPair) (types.Curren
 	return tickerPrices, nil
 }
 
+// getGeneratedTickerPrices returns the next generated price for each pair.
+func (p MockProvider) getGeneratedTickerPrices(pairs ...types.CurrencyPair) (types.CurrencyPairTickers, error) {
+	tickerPrices := make(types.CurrencyPairTickers, len(pairs))
+	for _, cp := range pairs {
+		tp, err := p.generator.Next(cp)
+		if err != nil {
+			return nil, err
+		}
+		tickerPrices[cp] = tp
+	}
+	return tickerPrices, nil
+}
+
 func (p MockProvider) GetCandlePrices(pairs ...types.CurrencyPair) (types.CurrencyPairCandles, error) {
 	price, err := p.GetTickerPrices(pairs...)
 	if err != nil {
@@ -115,7 +286,7 @@ func (p MockProvider) GetCandlePrices(pairs ...types.CurrencyPair) (types.Curren
 			{
 				Price:     price.Price,
 				Volume:    price.Volume,
-				TimeStamp: PastUnixTime(1 * time.Minute),
+				TimeStamp: PastUnixTimeMillis(1 * time.Minute),
 			},
 		}
 	}
@@ -124,13 +295,22 @@ func (p MockProvider) GetCandlePrices(pairs ...types.CurrencyPair) (types.Curren
 
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p MockProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.baseURL)
+	if p.generator != nil {
+		pairs := p.generator.Pairs()
+		availablePairs := make(map[string]struct{}, len(pairs))
+		for _, cp := range pairs {
+			availablePairs[strings.ToUpper(cp.String())] = struct{}{}
+		}
+		return availablePairs, nil
+	}
+
+	resp, err := p.client.Get(p.baseURL)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	csvReader := csv.NewReader(resp.Body)
+	csvReader := csv.NewReader(newLimitedBody(resp.Body, defaultMaxResponseSize))
 	records, err := csvReader.ReadAll()
 	if err != nil {
 		return nil, err
@@ -75,3 +75,62 @@ ATOM,USDC,21.84,1827884.77
 		require.Nil(t, prices)
 	})
 }
+
+func TestMockProvider_GeneratedPrices(t *testing.T) {
+	t.Run("sine_wave", func(t *testing.T) {
+		generator, err := NewSineWaveGenerator(map[types.CurrencyPair]MockPairParams{
+			OJOUSDT: {Price: 100, Volume: 1000, Volatility: 0.1, Period: 4},
+		})
+		require.NoError(t, err)
+		mp := NewMockProvider(generator)
+
+		// a period of 4 reads yields center, peak, center, trough, and repeats
+		expected := []string{"100", "110", "100", "90", "100", "110"}
+		for _, price := range expected {
+			prices, err := mp.GetTickerPrices(OJOUSDT)
+			require.NoError(t, err)
+			require.Equal(t, math.LegacyMustNewDecFromStr(price), prices[OJOUSDT].Price)
+			require.Equal(t, math.LegacyMustNewDecFromStr("1000"), prices[OJOUSDT].Volume)
+		}
+	})
+
+	t.Run("random_walk", func(t *testing.T) {
+		params := map[types.CurrencyPair]MockPairParams{
+			OJOUSDT:  {Price: 100, Volume: 1000, Volatility: 0.05},
+			ATOMUSDC: {Price: 10, Volume: 500, Volatility: 0.01},
+		}
+		mp := NewMockProvider(NewRandomWalkGenerator(1, params))
+		replay := NewMockProvider(NewRandomWalkGenerator(1, params))
+
+		previous := math.LegacyMustNewDecFromStr("100")
+		for i := 0; i < 10; i++ {
+			prices, err := mp.GetTickerPrices(OJOUSDT, ATOMUSDC)
+			require.NoError(t, err)
+
+			// the same seed always produces the same walk
+			replayed, err := replay.GetTickerPrices(OJOUSDT, ATOMUSDC)
+			require.NoError(t, err)
+			require.Equal(t, replayed, prices)
+
+			// each step stays within the configured volatility
+			price := prices[OJOUSDT].Price
+			maxStep := previous.Mul(math.LegacyMustNewDecFromStr("0.05"))
+			require.True(t, price.Sub(previous).Abs().LTE(maxStep))
+			previous = price
+		}
+	})
+
+	t.Run("unknown_pair", func(t *testing.T) {
+		mp := NewMockProvider(NewRandomWalkGenerator(1, map[types.CurrencyPair]MockPairParams{
+			OJOUSDT: {Price: 100, Volume: 1000, Volatility: 0.05},
+		}))
+
+		prices, err := mp.GetTickerPrices(ATOMUSDC)
+		require.Error(t, err)
+		require.Nil(t, prices)
+
+		availablePairs, err := mp.GetAvailablePairs()
+		require.NoError(t, err)
+		require.Equal(t, map[string]struct{}{"OJOUSDT": {}}, availablePairs)
+	})
+}
@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+var _ Provider = (*NormalizedProvider)(nil)
+
+const (
+	normalizedPollInterval = 5 * time.Second
+)
+
+type (
+	// NormalizedProvider defines an oracle provider that polls a REST
+	// aggregator serving the prices of several exchanges in a common schema,
+	// so many venues can be added through a single integration. The venues
+	// are aggregated into a single price per pair, so the aggregator counts as
+	// one source rather than one per venue. The endpoint has no default and
+	// must be set in the provider_endpoints config.
+	NormalizedProvider struct {
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+		priceStore
+		ctx context.Context
+
+		// lastTimestamps holds the timestamp of the latest candle stored for
+		// each pair, so repeated polls don't store it twice.
+		lastTimestamps map[string]int64
+	}
+
+	// NormalizedTicker defines a single entry of the normalized schema, the
+	// latest price and volume of a pair on one exchange. The price and volume
+	// can be either JSON numbers or strings, a missing volume is zero, and ts
+	// is a unix timestamp in either seconds or milliseconds.
+	NormalizedTicker struct {
+		Exchange  string      `json:"exchange"`
+		Base      string      `json:"base"`
+		Quote     string      `json:"quote"`
+		Price     json.Number `json:"price"`
+		Volume    json.Number `json:"volume"`
+		Timestamp int64       `json:"ts"`
+	}
+)
+
+// NewNormalizedProvider returns a new NormalizedProvider.
+func NewNormalizedProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*NormalizedProvider, error) {
+	if endpoints.Name != ProviderNormalized || endpoints.Rest == "" {
+		return nil, fmt.Errorf("provider %s requires a rest endpoint", ProviderNormalized)
+	}
+
+	normalizedLogger := logger.With().Str("provider", string(ProviderNormalized)).Logger()
+
+	provider := &NormalizedProvider{
+		logger:         normalizedLogger,
+		endpoints:      endpoints,
+		client:         newDefaultHTTPClient(endpoints.restTimeout()),
+		priceStore:     newPriceStore(normalizedLogger, endpoints.candlePeriod(defaultCandlePeriod)),
+		ctx:            ctx,
+		lastTimestamps: map[string]int64{},
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// GetAvailablePairs returns all pairs served by at least one exchange of the
+// aggregator.
+func (p *NormalizedProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	tickers, err := p.queryTickers()
+	if err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(tickers))
+	for _, ticker := range tickers {
+		availablePairs[ticker.currencyPair().String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// SubscribeCurrencyPairs adds the new currency pairs to the providers
+// subscribedPairs array.
+func (p *NormalizedProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// StartConnections begins the polling process for the normalized provider.
+func (p *NormalizedProvider) StartConnections() {
+	go p.poll()
+}
+
+// poll periodically calls setPrices to update the priceStore until the
+// context is done.
+func (p *NormalizedProvider) poll() {
+	ticker := time.NewTicker(normalizedPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.setPrices(); err != nil {
+			p.logger.Err(err).Msg("failed to poll normalized prices")
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setPrices queries the aggregator and updates the priceStore. The ticker of
+// a subscribed pair is the volume weighted price across all of its exchanges,
+// which is also stored as a candle dated at the latest entry of the pair.
+func (p *NormalizedProvider) setPrices() error {
+	tickers, err := p.queryTickers()
+	if err != nil {
+		return err
+	}
+
+	tickersByPair := map[string][]NormalizedTicker{}
+	for _, ticker := range tickers {
+		cp := ticker.currencyPair().String()
+		if !p.isSubscribed(cp) {
+			continue
+		}
+		tickersByPair[cp] = append(tickersByPair[cp], ticker)
+	}
+
+	for cp, pairTickers := range tickersByPair {
+		p.setTickerPair(normalizedPairTickers(pairTickers), cp)
+		p.setCandle(normalizedPairTickers(pairTickers), cp)
+	}
+
+	return nil
+}
+
+// setCandle stores the aggregated entries of the pair as a candle unless a
+// candle with the same or a newer timestamp was already stored for it.
+func (p *NormalizedProvider) setCandle(pairTickers normalizedPairTickers, cp string) {
+	candle, err := pairTickers.toCandlePrice()
+	if err != nil {
+		p.logger.Error().Err(err).Str("pair", cp).Msg("failed to parse normalized candle")
+		return
+	}
+
+	p.candleMtx.Lock()
+	defer p.candleMtx.Unlock()
+
+	if candle.TimeStamp <= p.lastTimestamps[cp] {
+		return
+	}
+	p.lastTimestamps[cp] = candle.TimeStamp
+
+	p.appendAndFilterCandles(candle, cp)
+	p.setLastUpdate(cp, MessageTypeCandle)
+}
+
+// queryTickers returns all entries served by the aggregator.
+func (p *NormalizedProvider) queryTickers() ([]NormalizedTicker, error) {
+	res, err := p.client.Get(p.endpoints.Rest)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	bz, err := io.ReadAll(p.endpoints.limitResponseBody(res.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return parseNormalizedTickers(bz)
+}
+
+// parseNormalizedTickers unmarshals a response of the normalized schema,
+// skipping the entries missing an exchange, base, quote or price.
+func parseNormalizedTickers(bz []byte) ([]NormalizedTicker, error) {
+	var entries []NormalizedTicker
+	if err := json.Unmarshal(bz, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	tickers := make([]NormalizedTicker, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Exchange == "" || entry.Base == "" || entry.Quote == "" || entry.Price == "" {
+			continue
+		}
+		tickers = append(tickers, entry)
+	}
+
+	return tickers, nil
+}
+
+// currencyPair returns the currency pair of the entry.
+func (nt NormalizedTicker) currencyPair() types.CurrencyPair {
+	return types.CurrencyPair{
+		Base:  strings.ToUpper(nt.Base),
+		Quote: strings.ToUpper(nt.Quote),
+	}
+}
+
+// timestampMillis returns the timestamp of the entry in milliseconds.
+func (nt NormalizedTicker) timestampMillis() int64 {
+	// unix timestamps in milliseconds have had 13 digits since 2001
+	if nt.Timestamp < 1e12 {
+		return SecondsToMilli(nt.Timestamp)
+	}
+	return nt.Timestamp
+}
+
+// volume returns the volume of the entry, which is zero when it is missing.
+func (nt NormalizedTicker) volume() string {
+	if nt.Volume == "" {
+		return "0"
+	}
+	return nt.Volume.String()
+}
+
+func (nt NormalizedTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(nt.Price.String(), nt.volume())
+}
+
+// normalizedPairTickers defines the entries of a single pair across exchanges.
+// It satisfies the providerTicker interface.
+type normalizedPairTickers []NormalizedTicker
+
+// toTickerPrice returns the volume weighted price and the total volume of the
+// pair across its exchanges. The plain average price is used when none of
+// them reports any volume.
+func (npt normalizedPairTickers) toTickerPrice() (types.TickerPrice, error) {
+	if len(npt) == 0 {
+		return types.TickerPrice{}, fmt.Errorf("no normalized tickers")
+	}
+
+	weightedPrice := math.LegacyZeroDec()
+	priceSum := math.LegacyZeroDec()
+	volume := math.LegacyZeroDec()
+	for _, ticker := range npt {
+		tp, err := ticker.toTickerPrice()
+		if err != nil {
+			return types.TickerPrice{}, fmt.Errorf("exchange %s: %w", ticker.Exchange, err)
+		}
+		weightedPrice = weightedPrice.Add(tp.Price.Mul(tp.Volume))
+		priceSum = priceSum.Add(tp.Price)
+		volume = volume.Add(tp.Volume)
+	}
+
+	if volume.IsZero() {
+		return types.TickerPrice{
+			Price:  priceSum.QuoInt64(int64(len(npt))),
+			Volume: volume,
+		}, nil
+	}
+
+	return types.TickerPrice{
+		Price:  weightedPrice.Quo(volume),
+		Volume: volume,
+	}, nil
+}
+
+// toCandlePrice returns the volume weighted price and the total volume of the
+// pair across its exchanges as a candle dated at the latest of their entries.
+func (npt normalizedPairTickers) toCandlePrice() (types.CandlePrice, error) {
+	tp, err := npt.toTickerPrice()
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+
+	var timestamp int64
+	for _, ticker := range npt {
+		if ts := ticker.timestampMillis(); ts > timestamp {
+			timestamp = ts
+		}
+	}
+
+	return types.CandlePrice{
+		Price:     tp.Price,
+		Volume:    tp.Volume,
+		TimeStamp: timestamp,
+	}, nil
+}
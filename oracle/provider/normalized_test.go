@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNormalizedTickers(t *testing.T) {
+	bz := []byte(`[
+		{"exchange":"binance","base":"atom","quote":"usdt","price":"10.5","volume":"100","ts":1700000000000},
+		{"exchange":"kraken","base":"ATOM","quote":"USDT","price":13,"volume":200.25,"ts":1700000000},
+		{"exchange":"gate","base":"ATOM","quote":"USDT","price":"11"},
+		{"exchange":"","base":"ATOM","quote":"USDT","price":"12","volume":"1","ts":1700000000000},
+		{"exchange":"okx","base":"ATOM","quote":"USDT","volume":"1","ts":1700000000000}
+	]`)
+
+	tickers, err := parseNormalizedTickers(bz)
+	require.NoError(t, err)
+	require.Len(t, tickers, 3)
+
+	require.Equal(t, "binance", tickers[0].Exchange)
+	require.Equal(t, ATOMUSDT, tickers[0].currencyPair())
+	require.Equal(t, int64(1700000000000), tickers[0].timestampMillis())
+
+	candle, err := normalizedPairTickers{tickers[1]}.toCandlePrice()
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("13"), candle.Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("200.25"), candle.Volume)
+	require.Equal(t, int64(1700000000000), candle.TimeStamp)
+
+	ticker, err := tickers[2].toTickerPrice()
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("11"), ticker.Price)
+	require.True(t, ticker.Volume.IsZero())
+
+	_, err = parseNormalizedTickers([]byte(`{"exchange":"binance"}`))
+	require.Error(t, err)
+}
+
+func TestNormalizedPairTickers_ToTickerPrice(t *testing.T) {
+	t.Run("volume_weighted", func(t *testing.T) {
+		ticker, err := normalizedPairTickers{
+			{Exchange: "binance", Price: "10", Volume: "100"},
+			{Exchange: "kraken", Price: "13", Volume: "200"},
+		}.toTickerPrice()
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("12"), ticker.Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("300"), ticker.Volume)
+	})
+
+	t.Run("no_volume", func(t *testing.T) {
+		ticker, err := normalizedPairTickers{
+			{Exchange: "binance", Price: "10"},
+			{Exchange: "kraken", Price: "13"},
+		}.toTickerPrice()
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("11.5"), ticker.Price)
+		require.True(t, ticker.Volume.IsZero())
+	})
+
+	t.Run("invalid_price", func(t *testing.T) {
+		_, err := normalizedPairTickers{
+			{Exchange: "binance", Price: "1e5", Volume: "100"},
+		}.toTickerPrice()
+		require.Error(t, err)
+	})
+}
+
+func TestNormalizedProvider_SetPrices(t *testing.T) {
+	now := time.Now()
+	body := fmt.Sprintf(`[
+		{"exchange":"binance","base":"ATOM","quote":"USDT","price":"10","volume":"100","ts":%d},
+		{"exchange":"kraken","base":"ATOM","quote":"USDT","price":"13","volume":"200","ts":%d},
+		{"exchange":"binance","base":"OSMO","quote":"USDT","price":"1","volume":"100","ts":%d}
+	]`, now.UnixMilli(), now.Unix(), now.UnixMilli())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	_, err := NewNormalizedProvider(context.TODO(), zerolog.Nop(), Endpoint{Name: ProviderNormalized}, ATOMUSDT)
+	require.Error(t, err)
+
+	p, err := NewNormalizedProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderNormalized, Rest: server.URL},
+		ATOMUSDT,
+	)
+	require.NoError(t, err)
+
+	// polling the same entries twice must not store their candles twice
+	require.NoError(t, p.setPrices())
+	require.NoError(t, p.setPrices())
+
+	tickers, err := p.GetTickerPrices(ATOMUSDT, types.CurrencyPair{Base: "OSMO", Quote: "USDT"})
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("12"), tickers[ATOMUSDT].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("300"), tickers[ATOMUSDT].Volume)
+
+	// the exchanges of the pair are aggregated into a single candle
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("12"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, now.UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+}
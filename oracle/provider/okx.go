@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -21,20 +20,28 @@ const (
 	okxWSPathBusiness = "/ws/v5/business"
 	okxRestHost       = "https://www.okx.com"
 	okxRestPath       = "/api/v5/market/tickers?instType=SPOT"
+	okxTickerChannel  = "tickers"
+	okxIndexChannel   = "index-tickers"
+	okxCandleChannel  = "candle1m"
 )
 
 var _ Provider = (*OkxProvider)(nil)
 
 type (
 	// OkxProvider defines an Oracle provider implemented by the Okx public
-	// API.
+	// API. Pairs configured as index ticker pairs use the index price of the
+	// index-tickers channel as their ticker price, along with the 24h volume
+	// of their spot ticker.
 	//
 	// REF: https://www.okx.com/docs-v5/en/#websocket-api-public-channel-tickers-channel
 	OkxProvider struct {
-		wsc       *WebsocketController
-		logger    zerolog.Logger
-		mtx       sync.RWMutex
-		endpoints Endpoint
+		wsc         *WebsocketController
+		logger      zerolog.Logger
+		mtx         sync.RWMutex
+		endpoints   Endpoint
+		indexPairs  map[string]struct{} // instrument ID ex.: "BTC-USDT"
+		spotVolumes map[string]string   // instrument ID -> spot 24h volume
+		volumeMtx   sync.RWMutex
 
 		priceStore
 	}
@@ -51,6 +58,20 @@ type (
 		Vol24h string `json:"vol24h"` // 24h trading volume ex.: 11159.87127845
 	}
 
+	// OkxIndexTicker defines an index ticker of Okx.
+	OkxIndexTicker struct {
+		OkxInstID
+		IdxPx  string `json:"idxPx"` // Latest index price ex.: 43350.1
+		Vol24h string `json:"-"`     // 24h trading volume of the spot ticker
+	}
+
+	// OkxIndexTickerResponse defines the response structure of a Okx index
+	// ticker request.
+	OkxIndexTickerResponse struct {
+		Data []OkxIndexTicker `json:"data"`
+		ID   OkxID            `json:"arg"`
+	}
+
 	// OkxInst defines the structure containing ID information for the OkxResponses.
 	OkxID struct {
 		OkxInstID
@@ -118,16 +139,23 @@ func NewOkxProvider(
 
 	okxLogger := logger.With().Str("provider", string(ProviderOkx)).Logger()
 
+	indexPairs := make(map[string]struct{}, len(endpoints.IndexTickerPairs))
+	for _, instID := range endpoints.IndexTickerPairs {
+		indexPairs[strings.ToUpper(instID)] = struct{}{}
+	}
+
 	provider := &OkxProvider{
-		logger:     okxLogger,
-		endpoints:  endpoints,
-		priceStore: newPriceStore(okxLogger),
+		logger:      okxLogger,
+		endpoints:   endpoints,
+		indexPairs:  indexPairs,
+		spotVolumes: map[string]string{},
+		priceStore:  newPriceStore(okxLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -145,6 +173,7 @@ func NewOkxProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		okxLogger,
 	)
 
@@ -153,10 +182,17 @@ func NewOkxProvider(
 
 func (p *OkxProvider) StartConnections() {
 	p.wsc.StartConnections()
+	if p.endpoints.restFallbackEnabled() {
+		go startRESTFallback(p.wsc, p.logger, p.pollRESTTickers)
+	}
+}
+
+func (p *OkxProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
 }
 
 func (p *OkxProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
-	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*3)
 	for _, cp := range cps {
 		okxPair := currencyPairToOkxPair(cp)
 		okxTopic := newOkxCandleSubscriptionTopic(okxPair)
@@ -164,6 +200,11 @@ func (p *OkxProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface
 
 		okxTopic = newOkxTickerSubscriptionTopic(okxPair)
 		subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+
+		if p.isIndexPair(okxPair) {
+			okxTopic = newOkxIndexTickerSubscriptionTopic(okxPair)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -183,7 +224,7 @@ func (p *OkxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -201,26 +242,73 @@ func (p *OkxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 	p.setSubscribedPairs(confirmedPairs...)
 }
 
+// UnsubscribeCurrencyPairs sends the unsubscription messages for the currency
+// pairs to the websocket, closes their connections and removes them and their
+// prices from the providers subscribedPairs array
+func (p *OkxProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	subscribedPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if p.isSubscribed(cp.String()) {
+			subscribedPairs = append(subscribedPairs, cp)
+		}
+	}
+
+	subscriptionMsgs := p.getSubscriptionMsgs(subscribedPairs...)
+	unsubscriptionMsgs := make([]interface{}, 0, len(subscriptionMsgs))
+	for _, msg := range subscriptionMsgs {
+		unsubscriptionMsgs = append(
+			unsubscriptionMsgs,
+			newOkxUnsubscriptionMsg(msg.(OkxSubscriptionMsg).Args...),
+		)
+	}
+
+	p.wsc.RemoveWebsocketConnections(subscriptionMsgs, unsubscriptionMsgs)
+	p.removeSubscribedPairs(subscribedPairs...)
+}
+
 func (p *OkxProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
 	var (
-		tickerResp OkxTickerResponse
-		tickerErr  error
-		candleResp OkxCandleResponse
-		candleErr  error
+		tickerResp      OkxTickerResponse
+		tickerErr       error
+		indexTickerResp OkxIndexTickerResponse
+		indexTickerErr  error
+		candleResp      OkxCandleResponse
+		candleErr       error
 	)
 
 	// sometimes the message received is not a ticker or a candle response.
 	tickerErr = json.Unmarshal(bz, &tickerResp)
-	if tickerResp.ID.Channel == "tickers" {
+	if tickerResp.ID.Channel == okxTickerChannel {
 		for _, tickerPair := range tickerResp.Data {
+			if p.isIndexPair(tickerPair.InstID) {
+				// only the volume of the spot ticker is used for index pairs
+				p.setSpotVolume(tickerPair.InstID, tickerPair.Vol24h)
+				continue
+			}
 			p.setTickerPair(tickerPair, tickerPair.InstID)
 			telemetryWebsocketMessage(ProviderOkx, MessageTypeTicker)
 		}
 		return
 	}
 
+	indexTickerErr = json.Unmarshal(bz, &indexTickerResp)
+	if indexTickerResp.ID.Channel == okxIndexChannel {
+		for _, indexTicker := range indexTickerResp.Data {
+			if !p.isIndexPair(indexTicker.InstID) {
+				continue
+			}
+			indexTicker.Vol24h = p.getSpotVolume(indexTicker.InstID)
+			p.setTickerPair(indexTicker, indexTicker.InstID)
+			telemetryWebsocketMessage(ProviderOkx, MessageTypeTicker)
+		}
+		return
+	}
+
 	candleErr = json.Unmarshal(bz, &candleResp)
-	if candleResp.ID.Channel == "candle1m" {
+	if candleResp.ID.Channel == okxCandleChannel {
 		currencyPairString := candleResp.ID.InstID
 		for _, pairData := range candleResp.Data {
 			ts, err := strconv.ParseInt(pairData[0], 10, 64)
@@ -243,13 +331,53 @@ func (p *OkxProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
+		AnErr("indexTicker", indexTickerErr).
 		AnErr("candle", candleErr).
 		Msg("Error on receive message")
 }
 
+// pollRESTTickers fetches the spot tickers from the REST API and sets the
+// tickers of the subscribed pairs in the price store. Index pairs only take
+// the volume of their spot ticker, as their index price is not polled.
+func (p *OkxProvider) pollRESTTickers() error {
+	resp, err := p.endpoints.httpGetWithRetries(p.endpoints.Rest + okxRestPath)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var tickers struct {
+		Data []OkxTickerPair `json:"data"`
+	}
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&tickers); err != nil {
+		return err
+	}
+
+	subscribed := make(map[string]struct{})
+	p.subscribedPairsMtx.RLock()
+	for _, cp := range p.subscribedPairs {
+		subscribed[p.currencyPairToTickerPair(cp)] = struct{}{}
+	}
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, tickerPair := range tickers.Data {
+		if _, ok := subscribed[tickerPair.InstID]; !ok {
+			continue
+		}
+		if p.isIndexPair(tickerPair.InstID) {
+			p.setSpotVolume(tickerPair.InstID, tickerPair.Vol24h)
+			continue
+		}
+		p.setTickerPair(tickerPair, tickerPair.InstID)
+		telemetryRESTFallbackMessage(ProviderOkx, MessageTypeTicker)
+	}
+
+	return nil
+}
+
 // GetAvailablePairs return all available pairs symbol to subscribe.
 func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + okxRestPath)
+	resp, err := p.endpoints.httpGet(p.endpoints.Rest + okxRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +387,7 @@ func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
 		Data []OkxInstID `json:"data"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -284,6 +412,38 @@ func (ticker OkxTickerPair) toTickerPrice() (types.TickerPrice, error) {
 	return types.NewTickerPrice(ticker.Last, ticker.Vol24h)
 }
 
+// isIndexPair returns true if the ticker price of the instrument is taken
+// from its index price.
+func (p *OkxProvider) isIndexPair(instID string) bool {
+	_, ok := p.indexPairs[instID]
+	return ok
+}
+
+// setSpotVolume stores the 24h volume of the spot ticker of an index pair.
+func (p *OkxProvider) setSpotVolume(instID, volume string) {
+	p.volumeMtx.Lock()
+	defer p.volumeMtx.Unlock()
+
+	p.spotVolumes[instID] = volume
+}
+
+// getSpotVolume returns the last 24h volume of the spot ticker of an index
+// pair, or zero if no spot ticker was received yet.
+func (p *OkxProvider) getSpotVolume(instID string) string {
+	p.volumeMtx.RLock()
+	defer p.volumeMtx.RUnlock()
+
+	volume, ok := p.spotVolumes[instID]
+	if !ok {
+		return "0"
+	}
+	return volume
+}
+
+func (ticker OkxIndexTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.IdxPx, ticker.Vol24h)
+}
+
 func (candle OkxCandlePair) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(candle.Close, candle.Volume, candle.TimeStamp)
 }
@@ -297,7 +457,16 @@ func currencyPairToOkxPair(pair types.CurrencyPair) string {
 // newOkxTickerSubscriptionTopic returns a new subscription topic.
 func newOkxTickerSubscriptionTopic(instID string) OkxSubscriptionTopic {
 	return OkxSubscriptionTopic{
-		Channel: "tickers",
+		Channel: okxTickerChannel,
+		InstID:  instID,
+	}
+}
+
+// newOkxIndexTickerSubscriptionTopic returns a new index ticker subscription
+// topic.
+func newOkxIndexTickerSubscriptionTopic(instID string) OkxSubscriptionTopic {
+	return OkxSubscriptionTopic{
+		Channel: okxIndexChannel,
 		InstID:  instID,
 	}
 }
@@ -305,7 +474,7 @@ func newOkxTickerSubscriptionTopic(instID string) OkxSubscriptionTopic {
 // newOkxSubscriptionTopic returns a new subscription topic.
 func newOkxCandleSubscriptionTopic(instID string) OkxSubscriptionTopic {
 	return OkxSubscriptionTopic{
-		Channel: "candle1m",
+		Channel: okxCandleChannel,
 		InstID:  instID,
 	}
 }
@@ -317,3 +486,11 @@ func newOkxSubscriptionMsg(args ...OkxSubscriptionTopic) OkxSubscriptionMsg {
 		Args: args,
 	}
 }
+
+// newOkxUnsubscriptionMsg returns a new unsubscription Msg for Okx.
+func newOkxUnsubscriptionMsg(args ...OkxSubscriptionTopic) OkxSubscriptionMsg {
+	return OkxSubscriptionMsg{
+		Op:   "unsubscribe",
+		Args: args,
+	}
+}
@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"cosmossdk.io/math"
@@ -107,3 +109,81 @@ func TestOkxProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"tickers\",\"instId\":\"ATOM-USDT\"}]}", string(msg))
 }
+
+func TestOkxProvider_IndexTicker(t *testing.T) {
+	btcUSDT := types.CurrencyPair{Base: "BTC", Quote: "USDT"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`{"data":[{"instId":"BTC-USDT","last":"43508.9","vol24h":"11159.87127845"}]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewOkxProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name:             ProviderOkx,
+			Rest:             server.URL,
+			Websocket:        okxWSHost,
+			IndexTickerPairs: []string{"BTC-USDT"},
+		},
+		btcUSDT,
+	)
+	require.NoError(t, err)
+
+	subMsgs := p.getSubscriptionMsgs(btcUSDT)
+	require.Len(t, subMsgs, 3)
+	msg, _ := json.Marshal(subMsgs[2])
+	require.Equal(t, `{"op":"subscribe","args":[{"channel":"index-tickers","instId":"BTC-USDT"}]}`, string(msg))
+
+	// the spot ticker of an index pair only provides its volume
+	p.messageReceived(0, nil, []byte(
+		`{"arg":{"channel":"tickers","instId":"BTC-USDT"},`+
+			`"data":[{"instId":"BTC-USDT","last":"43508.9","vol24h":"11159.87127845"}]}`,
+	))
+	prices, err := p.GetTickerPrices(btcUSDT)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	p.messageReceived(0, nil, []byte(
+		`{"arg":{"channel":"index-tickers","instId":"BTC-USDT"},`+
+			`"data":[{"instId":"BTC-USDT","idxPx":"43350.1","high24h":"43800","low24h":"42900",`+
+			`"open24h":"43000","sodUtc0":"43100","sodUtc8":"43200","ts":"1597026383085"}]}`,
+	))
+	prices, err = p.GetTickerPrices(btcUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("43350.1"), prices[btcUSDT].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("11159.87127845"), prices[btcUSDT].Volume)
+}
+
+func TestOkxProvider_pollRESTTickers(t *testing.T) {
+	btcUSDT := types.CurrencyPair{Base: "BTC", Quote: "USDT"}
+
+	// the tickers are also the available pairs of OKX
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`{"data":[` +
+			`{"instId":"BTC-USDT","last":"43508.9","vol24h":"11159.87"},` +
+			`{"instId":"ETH-USDT","last":"2300.1","vol24h":"5000"}]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewOkxProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderOkx, Rest: server.URL, Websocket: okxWSHost},
+		btcUSDT,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, p.pollRESTTickers())
+
+	// only the subscribed pairs are set
+	prices, err := p.GetTickerPrices(btcUSDT, types.CurrencyPair{Base: "ETH", Quote: "USDT"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("43508.9"), prices[btcUSDT].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("11159.87"), prices[btcUSDT].Volume)
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/math"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type (
+	// orderBook defines a snapshot of the order book of a pair, kept up to
+	// date from the provider's book updates and truncated to its depth.
+	orderBook struct {
+		depth int
+		bids  map[string]orderBookLevel
+		asks  map[string]orderBookLevel
+	}
+
+	// orderBookLevel defines the size resting at a price of an order book.
+	orderBookLevel struct {
+		price math.LegacyDec
+		size  math.LegacyDec
+	}
+
+	// depthTicker defines a ticker whose price is replaced by the depth price
+	// of the order book.
+	depthTicker struct {
+		ticker providerTicker
+		price  math.LegacyDec
+	}
+)
+
+func newOrderBook(depth int) *orderBook {
+	return &orderBook{
+		depth: depth,
+		bids:  map[string]orderBookLevel{},
+		asks:  map[string]orderBookLevel{},
+	}
+}
+
+// reset removes every level of the order book, before applying a snapshot.
+func (ob *orderBook) reset() {
+	ob.bids = map[string]orderBookLevel{}
+	ob.asks = map[string]orderBookLevel{}
+}
+
+// setBid sets the size of a bid level, removing the level if the size is zero.
+func (ob *orderBook) setBid(price, size string) error {
+	return ob.setLevel(ob.bids, true, price, size)
+}
+
+// setAsk sets the size of an ask level, removing the level if the size is zero.
+func (ob *orderBook) setAsk(price, size string) error {
+	return ob.setLevel(ob.asks, false, price, size)
+}
+
+func (ob *orderBook) setLevel(levels map[string]orderBookLevel, descending bool, price, size string) error {
+	priceDec, err := math.LegacyNewDecFromStr(price)
+	if err != nil {
+		return fmt.Errorf("failed to parse order book price (%s): %w", price, err)
+	}
+	sizeDec, err := math.LegacyNewDecFromStr(size)
+	if err != nil {
+		return fmt.Errorf("failed to parse order book size (%s): %w", size, err)
+	}
+
+	key := priceDec.String()
+	if !sizeDec.IsPositive() {
+		delete(levels, key)
+		return nil
+	}
+	levels[key] = orderBookLevel{price: priceDec, size: sizeDec}
+
+	// drop the levels pushed out of the depth of the book
+	if ob.depth > 0 && len(levels) > ob.depth {
+		for _, level := range sortedLevels(levels, descending)[ob.depth:] {
+			delete(levels, level.price.String())
+		}
+	}
+	return nil
+}
+
+// depthPrice returns the depth-weighted mid price of the order book for the
+// given notional, in the quote currency: the average of the prices paid to
+// buy and received to sell the notional by sweeping the book.
+func (ob *orderBook) depthPrice(notional math.LegacyDec) (math.LegacyDec, error) {
+	buyPrice, err := fillPrice(sortedLevels(ob.asks, false), notional)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("asks: %w", err)
+	}
+	sellPrice, err := fillPrice(sortedLevels(ob.bids, true), notional)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("bids: %w", err)
+	}
+	return buyPrice.Add(sellPrice).QuoInt64(2), nil
+}
+
+// fillPrice returns the average price of filling the notional against the
+// levels, ordered from the best price.
+func fillPrice(levels []orderBookLevel, notional math.LegacyDec) (math.LegacyDec, error) {
+	if !notional.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("invalid depth notional: %s", notional)
+	}
+
+	remaining := notional
+	filled := math.LegacyZeroDec()
+	for _, level := range levels {
+		levelNotional := level.price.Mul(level.size)
+		if levelNotional.GTE(remaining) {
+			filled = filled.Add(remaining.Quo(level.price))
+			remaining = math.LegacyZeroDec()
+			break
+		}
+		filled = filled.Add(level.size)
+		remaining = remaining.Sub(levelNotional)
+	}
+
+	if remaining.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("insufficient depth to fill %s", notional)
+	}
+	return notional.Quo(filled), nil
+}
+
+// sortedLevels returns the levels ordered by price, from the highest price if
+// descending.
+func sortedLevels(levels map[string]orderBookLevel, descending bool) []orderBookLevel {
+	sorted := make([]orderBookLevel, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].price.GT(sorted[j].price)
+		}
+		return sorted[i].price.LT(sorted[j].price)
+	})
+	return sorted
+}
+
+func (dt depthTicker) toTickerPrice() (types.TickerPrice, error) {
+	tickerPrice, err := dt.ticker.toTickerPrice()
+	if err != nil {
+		return types.TickerPrice{}, err
+	}
+	tickerPrice.Price = dt.price
+	return tickerPrice, nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderBook_DepthPrice(t *testing.T) {
+	book := newOrderBook(3)
+	for _, level := range [][2]string{{"100", "1"}, {"102", "2"}, {"104", "10"}} {
+		require.NoError(t, book.setAsk(level[0], level[1]))
+	}
+	for _, level := range [][2]string{{"98", "1"}, {"96", "2"}, {"95", "10"}} {
+		require.NoError(t, book.setBid(level[0], level[1]))
+	}
+
+	t.Run("best_level", func(t *testing.T) {
+		// 49 is filled by the best ask at 100 and the best bid at 98
+		price, err := book.depthPrice(math.LegacyNewDec(49))
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyNewDec(99), price)
+	})
+
+	t.Run("multiple_levels", func(t *testing.T) {
+		// buying 304 fills 1 at 100 and 2 at 102, while selling 304 fills
+		// 1 at 98, 2 at 96 and the remaining 14 at 95
+		price, err := book.depthPrice(math.LegacyNewDec(304))
+		require.NoError(t, err)
+		buyPrice := math.LegacyNewDec(304).Quo(math.LegacyNewDec(3))
+		sellPrice := math.LegacyNewDec(304).Quo(
+			math.LegacyNewDec(3).Add(math.LegacyNewDec(14).Quo(math.LegacyNewDec(95))),
+		)
+		require.Equal(t, buyPrice.Add(sellPrice).QuoInt64(2), price)
+	})
+
+	t.Run("insufficient_depth", func(t *testing.T) {
+		_, err := book.depthPrice(math.LegacyNewDec(10000))
+		require.Error(t, err)
+	})
+
+	t.Run("update_and_truncate", func(t *testing.T) {
+		// removing the best ask and adding a fourth ask pushes out the worst one
+		require.NoError(t, book.setAsk("100", "0"))
+		require.NoError(t, book.setAsk("103", "1"))
+		require.NoError(t, book.setAsk("101", "1"))
+		require.Len(t, book.asks, 3)
+		require.NotContains(t, book.asks, math.LegacyNewDec(104).String())
+		require.Equal(t, math.LegacyNewDec(101), sortedLevels(book.asks, false)[0].price)
+	})
+}
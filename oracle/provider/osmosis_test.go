@@ -2,9 +2,12 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"cosmossdk.io/math"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -109,6 +112,36 @@ func TestOsmosisProvider_GetCandlePrices(t *testing.T) {
 	})
 }
 
+func TestOsmosisProvider_ReversedCandleOrder(t *testing.T) {
+	p, err := NewOsmosisProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{},
+		OSMOATOM,
+	)
+	require.NoError(t, err)
+
+	newestTime := PastUnixTimeMillis(1 * time.Minute)
+	olderTime := PastUnixTimeMillis(2 * time.Minute)
+
+	// candles are sent newest-first instead of oldest-first
+	msg := fmt.Sprintf(
+		`{"OSMO/ATOM":[{"Close":"2.5","Volume":"100","EndTime":%d},{"Close":"1.5","Volume":"100","EndTime":%d}]}`,
+		newestTime,
+		olderTime,
+	)
+	p.messageReceived(websocket.TextMessage, nil, []byte(msg))
+
+	prices, err := p.GetCandlePrices(OSMOATOM)
+	require.NoError(t, err)
+	require.Len(t, prices[OSMOATOM], 2)
+
+	latest, ok := types.LatestCandle(prices[OSMOATOM])
+	require.True(t, ok)
+	require.Equal(t, newestTime, latest.TimeStamp)
+	require.Equal(t, math.LegacyMustNewDecFromStr("2.5"), latest.Price)
+}
+
 func TestOsmosisCurrencyPairToOsmosisPair(t *testing.T) {
 	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
 	osmosisSymbol := currencyPairToOsmosisPair(cp)
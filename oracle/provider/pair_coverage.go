@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// defaultPairCoverageWindow defines how long after connecting a provider
+	// pushing every pair is given to send data for each subscribed pair.
+	defaultPairCoverageWindow = 1 * time.Minute
+
+	// pairCoveragePollInterval defines how often the connection is checked
+	// while waiting for it to be established.
+	pairCoveragePollInterval = 1 * time.Second
+)
+
+// PairCoverageProviders defines the providers which connect without per-pair
+// subscriptions and rely on the server pushing every pair, so whether data
+// arrives for each subscribed pair is checked after connecting.
+var PairCoverageProviders = map[types.ProviderName]struct{}{
+	ProviderEthBalancer: {},
+	ProviderEthCamelot:  {},
+	ProviderEthCurve:    {},
+	ProviderKujira:      {},
+	ProviderOsmosis:     {},
+	ProviderEthPancake:  {},
+	ProviderEthUniswap:  {},
+}
+
+// pairCoverageWindow returns the pair coverage window of the endpoint, or the
+// default window if it is not set or invalid.
+func (e Endpoint) pairCoverageWindow() time.Duration {
+	if e.PairCoverageWindow == "" {
+		return defaultPairCoverageWindow
+	}
+	window, err := time.ParseDuration(e.PairCoverageWindow)
+	if err != nil || window <= 0 {
+		return defaultPairCoverageWindow
+	}
+	return window
+}
+
+// startPairCoverageCheck checks the pair coverage once the connections of wsc
+// start, and again after every reconnect. It must be called before the
+// connections start.
+func (ps *priceStore) startPairCoverageCheck(ctx context.Context, wsc *WebsocketController, window time.Duration) {
+	wsc.SetReconnectHandler(func(interface{}) {
+		ps.checkPairCoverage(ctx, wsc.IsHealthy, window)
+	})
+	go ps.checkPairCoverage(ctx, wsc.IsHealthy, window)
+}
+
+// checkPairCoverage waits until the provider is connected and the window
+// elapsed, then reports the subscribed pairs for which no data arrived since
+// connecting. It returns early if ctx is done.
+func (ps *priceStore) checkPairCoverage(ctx context.Context, isConnected func() bool, window time.Duration) {
+	ticker := time.NewTicker(pairCoveragePollInterval)
+	defer ticker.Stop()
+
+	for !isConnected() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	since := time.Now()
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(window):
+	}
+
+	ps.reportMissingPairs(since)
+}
+
+// reportMissingPairs logs the subscribed pairs for which no ticker or candle
+// was received since the given time, which usually means the pair is
+// misspelled or not pushed by the server, and returns them.
+func (ps *priceStore) reportMissingPairs(since time.Time) []types.CurrencyPair {
+	ps.subscribedPairsMtx.RLock()
+	subscribedPairs := make([]types.CurrencyPair, 0, len(ps.subscribedPairs))
+	for _, cp := range ps.subscribedPairs {
+		subscribedPairs = append(subscribedPairs, cp)
+	}
+	ps.subscribedPairsMtx.RUnlock()
+
+	missingPairs := []types.CurrencyPair{}
+	for _, cp := range subscribedPairs {
+		if lastUpdate, ok := ps.GetLastUpdate(cp); !ok || lastUpdate.Before(since) {
+			missingPairs = append(missingPairs, cp)
+		}
+	}
+	sort.Slice(missingPairs, func(i, j int) bool {
+		return missingPairs[i].String() < missingPairs[j].String()
+	})
+
+	if len(missingPairs) > 0 {
+		ps.logger.Warn().
+			Interface("currency_pairs", missingPairs).
+			Msg("no data received for subscribed pairs since connecting")
+	}
+	return missingPairs
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -98,13 +97,13 @@ func NewPolygonProvider(
 	provider := &PolygonProvider{
 		logger:     polygonLogger,
 		endpoints:  endpoints,
-		priceStore: newPriceStore(polygonLogger),
+		priceStore: newPriceStore(polygonLogger, endpoints.candlePeriod(defaultCandlePeriod)),
 	}
 	provider.priceStore.setCurrencyPairToTickerAndCandlePair(currencyPairToPolygonPair)
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
-		provider.endpoints.Name,
+		provider.endpoints,
 		provider.logger,
 		pairs...,
 	)
@@ -122,6 +121,7 @@ func NewPolygonProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.reconnectMaxInterval(),
 		polygonLogger,
 	)
 
@@ -132,6 +132,10 @@ func (p *PolygonProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+func (p *PolygonProvider) IsHealthy() bool {
+	return p.wsc.IsHealthy()
+}
+
 func (p *PolygonProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2+1)
 
@@ -165,7 +169,7 @@ func (p *PolygonProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 	confirmedPairs, err := ConfirmPairAvailability(
 		p,
-		p.endpoints.Name,
+		p.endpoints,
 		p.logger,
 		newPairs...,
 	)
@@ -186,23 +190,25 @@ func (p *PolygonProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p *PolygonProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	// request for first 1000 tickers (request limit)
-	resp, err := http.Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderOne + polygonLimitOne)
+	reqURL := p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderOne + polygonLimitOne
+	resp, err := p.endpoints.httpGet(reqURL)
 	if err != nil {
 		return nil, err
 	}
 	var tickers PolygonTickersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&tickers); err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// request for rest of the tickers
-	resp, err = http.Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderTwo + polygonLimitTwo)
+	reqURL = p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderTwo + polygonLimitTwo
+	resp, err = p.endpoints.httpGet(reqURL)
 	if err != nil {
 		return nil, err
 	}
 	var tickersLeftover PolygonTickersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tickersLeftover); err != nil {
+	if err := json.NewDecoder(p.endpoints.limitResponseBody(resp.Body)).Decode(&tickersLeftover); err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -259,6 +265,9 @@ func (p *PolygonProvider) messageReceived(messageType int, _ *WebsocketConnectio
 }
 
 func (par PolygonAggregatesResponse) toTickerPrice() (types.TickerPrice, error) {
+	if err := checkFinite(par.Close, par.Volume); err != nil {
+		return types.TickerPrice{}, err
+	}
 	return types.NewTickerPrice(
 		fmt.Sprintf("%f", par.Close),
 		fmt.Sprintf("%f", par.Volume),
@@ -266,6 +275,9 @@ func (par PolygonAggregatesResponse) toTickerPrice() (types.TickerPrice, error)
 }
 
 func (par PolygonAggregatesResponse) toCandlePrice() (types.CandlePrice, error) {
+	if err := checkFinite(par.Close, par.Volume); err != nil {
+		return types.CandlePrice{}, err
+	}
 	return types.NewCandlePrice(
 		fmt.Sprintf("%f", par.Close),
 		fmt.Sprintf("%f", par.Volume),
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/math"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// PriceSourceLast uses the last trade price as the ticker price.
+	PriceSourceLast PriceSource = "last"
+	// PriceSourceMid uses the mid-price of the best bid and ask as the ticker
+	// price.
+	PriceSourceMid PriceSource = "mid"
+	// PriceSourceMicro uses the size-weighted micro-price of the best bid and
+	// ask as the ticker price.
+	PriceSourceMicro PriceSource = "micro"
+	// PriceSourceDepth uses the depth-weighted mid price of the order book
+	// for the notional of the pair's quote currency as the ticker price.
+	PriceSourceDepth PriceSource = "depth"
+)
+
+// PriceSource defines which price of a provider's ticker is used as the
+// ticker price.
+type PriceSource string
+
+var (
+	// SupportedPriceSources defines the price sources which can be configured
+	// for a provider.
+	SupportedPriceSources = map[PriceSource]struct{}{
+		PriceSourceLast:  {},
+		PriceSourceMid:   {},
+		PriceSourceMicro: {},
+		PriceSourceDepth: {},
+	}
+
+	// BookPriceProviders defines the providers which expose their best bid and
+	// ask, and can therefore use the mid or micro price sources.
+	BookPriceProviders = map[types.ProviderName]struct{}{
+		ProviderBinance: {},
+		ProviderMexc:    {},
+	}
+
+	// DepthPriceProviders defines the providers which maintain a snapshot of
+	// their order book, and can therefore use the depth price source.
+	DepthPriceProviders = map[types.ProviderName]struct{}{
+		ProviderKraken: {},
+	}
+)
+
+// depthNotionals returns the depth notionals of the endpoint keyed by their
+// uppercased quote currency, since the config keys are lowercased when
+// parsed.
+func (e Endpoint) depthNotionals() (map[string]math.LegacyDec, error) {
+	if len(e.DepthNotionals) == 0 {
+		return nil, fmt.Errorf("no depth notional")
+	}
+
+	notionals := make(map[string]math.LegacyDec, len(e.DepthNotionals))
+	for quote, notional := range e.DepthNotionals {
+		notionalDec, err := math.LegacyNewDecFromStr(notional)
+		if err != nil || !notionalDec.IsPositive() {
+			return nil, fmt.Errorf("invalid depth notional of %s: %s", quote, notional)
+		}
+		notionals[strings.ToUpper(quote)] = notionalDec
+	}
+	return notionals, nil
+}
+
+// bookPrice computes the price of the given order book price source from the
+// best bid and ask prices and sizes.
+//
+// The mid-price is (bid + ask) / 2, and the micro-price weights each side by
+// the size of the opposite side: (bid * askSize + ask * bidSize) / (bidSize + askSize).
+func bookPrice(source PriceSource, bid, bidSize, ask, askSize string) (math.LegacyDec, error) {
+	bidDec, err := math.LegacyNewDecFromStr(bid)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("failed to parse bid price (%s): %w", bid, err)
+	}
+	askDec, err := math.LegacyNewDecFromStr(ask)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("failed to parse ask price (%s): %w", ask, err)
+	}
+
+	mid := bidDec.Add(askDec).QuoInt64(2)
+
+	switch source {
+	case PriceSourceMid:
+		return mid, nil
+
+	case PriceSourceMicro:
+		bidSizeDec, err := math.LegacyNewDecFromStr(bidSize)
+		if err != nil {
+			return math.LegacyDec{}, fmt.Errorf("failed to parse bid size (%s): %w", bidSize, err)
+		}
+		askSizeDec, err := math.LegacyNewDecFromStr(askSize)
+		if err != nil {
+			return math.LegacyDec{}, fmt.Errorf("failed to parse ask size (%s): %w", askSize, err)
+		}
+
+		totalSize := bidSizeDec.Add(askSizeDec)
+		if !totalSize.IsPositive() {
+			return mid, nil
+		}
+		return bidDec.Mul(askSizeDec).Add(askDec.Mul(bidSizeDec)).Quo(totalSize), nil
+
+	default:
+		return math.LegacyDec{}, fmt.Errorf("unsupported order book price source: %s", source)
+	}
+}
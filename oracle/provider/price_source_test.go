@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookPrice(t *testing.T) {
+	testCases := map[string]struct {
+		source   PriceSource
+		bid      string
+		bidSize  string
+		ask      string
+		askSize  string
+		expected math.LegacyDec
+		err      bool
+	}{
+		"mid": {
+			source:   PriceSourceMid,
+			bid:      "10.0",
+			bidSize:  "1",
+			ask:      "12.0",
+			askSize:  "3",
+			expected: math.LegacyMustNewDecFromStr("11.0"),
+		},
+		"micro weighted towards the ask": {
+			source:  PriceSourceMicro,
+			bid:     "10.0",
+			bidSize: "3",
+			ask:     "12.0",
+			askSize: "1",
+			// (10 * 1 + 12 * 3) / 4
+			expected: math.LegacyMustNewDecFromStr("11.5"),
+		},
+		"micro weighted towards the bid": {
+			source:  PriceSourceMicro,
+			bid:     "10.0",
+			bidSize: "1",
+			ask:     "12.0",
+			askSize: "3",
+			// (10 * 3 + 12 * 1) / 4
+			expected: math.LegacyMustNewDecFromStr("10.5"),
+		},
+		"micro without sizes falls back to mid": {
+			source:   PriceSourceMicro,
+			bid:      "10.0",
+			bidSize:  "0",
+			ask:      "12.0",
+			askSize:  "0",
+			expected: math.LegacyMustNewDecFromStr("11.0"),
+		},
+		"invalid bid": {
+			source: PriceSourceMid,
+			bid:    "foo",
+			ask:    "12.0",
+			err:    true,
+		},
+		"unsupported source": {
+			source: PriceSourceLast,
+			bid:    "10.0",
+			ask:    "12.0",
+			err:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			price, err := bookPrice(tc.source, tc.bid, tc.bidSize, tc.ask, tc.askSize)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tc.expected.Equal(price), "expected %s, got %s", tc.expected, price)
+		})
+	}
+}
+
+func TestBinanceTickerPriceSource(t *testing.T) {
+	ticker := BinanceTicker{
+		Symbol:    "ATOMUSDT",
+		LastPrice: "9.0",
+		Volume:    "1000",
+		BidPrice:  "10.0",
+		BidQty:    "3",
+		AskPrice:  "12.0",
+		AskQty:    "1",
+	}
+
+	tickerPrice, err := ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.0"), tickerPrice.Price)
+
+	ticker.PriceSource = PriceSourceMid
+	tickerPrice, err = ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.True(t, math.LegacyMustNewDecFromStr("11.0").Equal(tickerPrice.Price))
+	require.Equal(t, math.LegacyMustNewDecFromStr("1000"), tickerPrice.Volume)
+
+	ticker.PriceSource = PriceSourceMicro
+	tickerPrice, err = ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.True(t, math.LegacyMustNewDecFromStr("11.5").Equal(tickerPrice.Price))
+}
+
+func TestMexcTickerPriceSource(t *testing.T) {
+	ticker := MexcTicker{
+		LastPrice: "10.0",
+		Volume:    "1",
+		AskPrice:  "12.0",
+		AskQty:    "3",
+	}
+
+	tickerPrice, err := ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.0"), tickerPrice.Price)
+
+	ticker.PriceSource = PriceSourceMid
+	tickerPrice, err = ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.True(t, math.LegacyMustNewDecFromStr("11.0").Equal(tickerPrice.Price))
+
+	ticker.PriceSource = PriceSourceMicro
+	tickerPrice, err = ticker.toTickerPrice()
+	require.NoError(t, err)
+	require.True(t, math.LegacyMustNewDecFromStr("10.5").Equal(tickerPrice.Price))
+}
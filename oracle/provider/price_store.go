@@ -1,18 +1,18 @@
 package provider
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"time"
 
+	"cosmossdk.io/math"
 	"github.com/rs/zerolog"
 
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
-const (
-	defaultCandlePeriod = 5 * time.Minute
-)
+const defaultCandlePeriod = 5 * time.Minute
 
 // PriceStore is an embedded struct in each provider that manages the in memory
 // store of subscribed currency pairs, candles prices, and ticker prices. It also
@@ -23,9 +23,34 @@ type priceStore struct {
 	subscribedPairs map[string]types.CurrencyPair
 	candlePeriod    time.Duration
 
+	// maxTickerAge defines how long a ticker is returned for after it was last
+	// received, so the last ticker of a frozen connection is not used forever.
+	// A max age of zero disables the check.
+	maxTickerAge time.Duration
+
+	// candleTimestamp and candleInterval define how the timestamps of the
+	// candles are normalized to the close of their interval, and are not
+	// normalized when candleTimestamp is empty.
+	candleTimestamp CandleTimestamp
+	candleInterval  time.Duration
+
+	// secondaryCandles holds the candles of the coarser secondary interval
+	// of the provider, if any, returned when no candle of the primary
+	// interval is within the candle period.
+	secondaryCandles map[string][]types.CandlePrice
+
+	// lastUpdate holds when a ticker and a candle were last received for each
+	// currency pair string key specific to the provider.
+	lastUpdate map[string]priceUpdate
+
+	// availablePairs holds the pairs available on the provider, kept between
+	// subscriptions.
+	availablePairs availablePairsStore
+
 	subscribedPairsMtx sync.RWMutex
 	tickerMtx          sync.RWMutex
 	candleMtx          sync.RWMutex
+	lastUpdateMtx      sync.RWMutex
 
 	// currencyPairToTickerPair translates CurrencyPair the provider specific string map index
 	currencyPairToTickerPair func(types.CurrencyPair) string
@@ -34,6 +59,16 @@ type priceStore struct {
 	curencyPairToCandlePair func(types.CurrencyPair) string
 
 	logger zerolog.Logger
+
+	// now returns the current time, defaulting to time.Now.
+	now func() time.Time
+}
+
+// priceUpdate defines when a ticker and a candle of a currency pair were last
+// received, and is zero for the ones not received yet.
+type priceUpdate struct {
+	ticker time.Time
+	candle time.Time
 }
 
 // providerTicker is an interface that all provider tickers must implement to be
@@ -52,18 +87,65 @@ func defaultCurrencyPairTranslation(cp types.CurrencyPair) string {
 	return cp.String()
 }
 
-func newPriceStore(logger zerolog.Logger) priceStore {
+// candlePeriod returns the parsed candle_period of the endpoint, or
+// defaultPeriod when it is empty or invalid.
+func (e Endpoint) candlePeriod(defaultPeriod time.Duration) time.Duration {
+	if e.CandlePeriod == "" {
+		return defaultPeriod
+	}
+	period, err := time.ParseDuration(e.CandlePeriod)
+	if err != nil || period <= 0 {
+		return defaultPeriod
+	}
+	return period
+}
+
+// newPriceStore returns a priceStore which prunes candles older than
+// candlePeriod.
+func newPriceStore(logger zerolog.Logger, candlePeriod time.Duration) priceStore {
 	return priceStore{
 		tickers:                  map[string]types.TickerPrice{},
 		candles:                  map[string][]types.CandlePrice{},
+		secondaryCandles:         map[string][]types.CandlePrice{},
 		subscribedPairs:          map[string]types.CurrencyPair{},
-		candlePeriod:             defaultCandlePeriod,
+		candlePeriod:             candlePeriod,
+		lastUpdate:               map[string]priceUpdate{},
 		logger:                   logger,
 		currencyPairToTickerPair: defaultCurrencyPairTranslation,
 		curencyPairToCandlePair:  defaultCurrencyPairTranslation,
+		now:                      time.Now,
 	}
 }
 
+// SetMaxTickerAge sets how long the tickers are returned for after they were
+// last received. A max age of zero disables the check.
+func (ps *priceStore) SetMaxTickerAge(maxAge time.Duration) {
+	ps.tickerMtx.Lock()
+	defer ps.tickerMtx.Unlock()
+
+	ps.maxTickerAge = maxAge
+}
+
+// SetMinCandlePeriod lengthens the candle period to the given minimum if it is
+// shorter, so the candles are kept for at least that long.
+func (ps *priceStore) SetMinCandlePeriod(minPeriod time.Duration) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	ps.candlePeriod = max(ps.candlePeriod, minPeriod)
+}
+
+// SetCandleTimestamp sets the convention the provider timestamps its candles
+// of the given interval with, so setCandlePair normalizes their timestamps to
+// the close of their interval.
+func (ps *priceStore) SetCandleTimestamp(convention CandleTimestamp, interval time.Duration) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	ps.candleTimestamp = convention
+	ps.candleInterval = interval
+}
+
 func (ps *priceStore) setCurrencyPairToTickerAndCandlePair(f func(types.CurrencyPair) string) {
 	ps.currencyPairToTickerPair = f
 	ps.curencyPairToCandlePair = f
@@ -92,6 +174,43 @@ func (ps *priceStore) addSubscribedPairs(cps ...types.CurrencyPair) []types.Curr
 	return newPairs
 }
 
+// UnsubscribeCurrencyPairs removes the currency pairs and their stored prices
+// from the price store. Providers which can stop receiving prices for a pair
+// wrap it to unsubscribe from the pair first.
+func (ps *priceStore) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	ps.removeSubscribedPairs(cps...)
+}
+
+// removeSubscribedPairs removes the currency pairs from the map of subscribed
+// pairs and prunes their tickers, candles and last update timestamps.
+func (ps *priceStore) removeSubscribedPairs(cps ...types.CurrencyPair) {
+	ps.subscribedPairsMtx.Lock()
+	for _, cp := range cps {
+		delete(ps.subscribedPairs, cp.String())
+	}
+	ps.subscribedPairsMtx.Unlock()
+
+	ps.tickerMtx.Lock()
+	for _, cp := range cps {
+		delete(ps.tickers, ps.currencyPairToTickerPair(cp))
+	}
+	ps.tickerMtx.Unlock()
+
+	ps.candleMtx.Lock()
+	for _, cp := range cps {
+		delete(ps.candles, ps.curencyPairToCandlePair(cp))
+		delete(ps.secondaryCandles, ps.curencyPairToCandlePair(cp))
+	}
+	ps.candleMtx.Unlock()
+
+	ps.lastUpdateMtx.Lock()
+	for _, cp := range cps {
+		delete(ps.lastUpdate, ps.currencyPairToTickerPair(cp))
+		delete(ps.lastUpdate, ps.curencyPairToCandlePair(cp))
+	}
+	ps.lastUpdateMtx.Unlock()
+}
+
 // isSubscribed returns true if the provider is subscribed to the currency pair.
 func (ps *priceStore) isSubscribed(currencyPair string) bool {
 	ps.subscribedPairsMtx.RLock()
@@ -104,7 +223,8 @@ func (ps *priceStore) isSubscribed(currencyPair string) bool {
 }
 
 // GetTickerPrices returns the tickerPrices based on the provided pairs. Logs a
-// warning for each currency pair that is not available.
+// warning for each currency pair that is not available, and skips the tickers
+// last received longer than the max ticker age ago.
 func (ps *priceStore) GetTickerPrices(pairs ...types.CurrencyPair) (types.CurrencyPairTickers, error) {
 	ps.tickerMtx.RLock()
 	defer ps.tickerMtx.RUnlock()
@@ -117,13 +237,40 @@ func (ps *priceStore) GetTickerPrices(pairs ...types.CurrencyPair) (types.Curren
 			ps.logger.Debug().Msgf("failed to get ticker price for %s", key)
 			continue
 		}
+		if updated, ok := ps.tickerUpdate(key); ok && ps.maxTickerAge > 0 {
+			if age := ps.now().Sub(updated); age > ps.maxTickerAge {
+				ps.logger.Warn().
+					Str("currency_pair", key).
+					Dur("age", age).
+					Msg("skipping stale ticker price")
+				continue
+			}
+		}
 		tickerPrices[cp] = ticker
 	}
 	return tickerPrices, nil
 }
 
+// staleTickerPairs returns the subscribed currency pairs whose ticker was not
+// received within maxAge, including the ones without any ticker yet.
+func (ps *priceStore) staleTickerPairs(maxAge time.Duration) []types.CurrencyPair {
+	ps.subscribedPairsMtx.RLock()
+	defer ps.subscribedPairsMtx.RUnlock()
+
+	stalePairs := []types.CurrencyPair{}
+	for _, cp := range ps.subscribedPairs {
+		updated, ok := ps.tickerUpdate(ps.currencyPairToTickerPair(cp))
+		if !ok || ps.now().Sub(updated) > maxAge {
+			stalePairs = append(stalePairs, cp)
+		}
+	}
+	return stalePairs
+}
+
 // GetCandlePrices returns a copy of the the candlePrices based on the provided pairs.
-// Logs a warning for each currency pair that is not available.
+// The candles of the secondary interval are returned instead for the pairs
+// without any candle of the primary interval within the candle period. Logs a
+// warning for each currency pair that is not available.
 func (ps *priceStore) GetCandlePrices(pairs ...types.CurrencyPair) (types.CurrencyPairCandles, error) {
 	ps.candleMtx.RLock()
 	defer ps.candleMtx.RUnlock()
@@ -132,6 +279,11 @@ func (ps *priceStore) GetCandlePrices(pairs ...types.CurrencyPair) (types.Curren
 	for _, cp := range pairs {
 		key := ps.curencyPairToCandlePair(cp)
 		candles, ok := ps.candles[key]
+		if !ps.hasRecentCandles(candles) {
+			if secondaryCandles := ps.secondaryCandles[key]; ps.hasRecentCandles(secondaryCandles) {
+				candles, ok = secondaryCandles, true
+			}
+		}
 		if !ok {
 			ps.logger.Debug().Msgf("failed to get candle prices for %s", key)
 			continue
@@ -144,7 +296,8 @@ func (ps *priceStore) GetCandlePrices(pairs ...types.CurrencyPair) (types.Curren
 }
 
 // setTickerPair sets the ticker price for a currency pair string key specific to the provider.
-// Logs an error and returns early if the providerTicker fails conversion to a TickerPrice.
+// Logs an error and returns early if the providerTicker fails conversion to a TickerPrice,
+// and logs a warning and skips the ticker if its price or volume is invalid.
 func (ps *priceStore) setTickerPair(ticker providerTicker, currencyPair string) {
 	ps.tickerMtx.Lock()
 	defer ps.tickerMtx.Unlock()
@@ -154,35 +307,158 @@ func (ps *priceStore) setTickerPair(ticker providerTicker, currencyPair string)
 		ps.logger.Error().Err(err).Msg("failed to convert providerTicker to TickerPrice")
 		return
 	}
+	if err := validatePrice(oracleTicker.Price, oracleTicker.Volume); err != nil {
+		ps.logger.Warn().Err(err).Str("pair", currencyPair).Msg("skipping invalid ticker")
+		return
+	}
 	ps.tickers[currencyPair] = oracleTicker
+	ps.setLastUpdate(currencyPair, MessageTypeTicker)
 }
 
 // setCandlePair sets the candle price for a currency pair string key specific to the provider.
-// Logs an error and returns early if the providerCandle fails conversion to a CandlePrice.
+// Logs an error and returns early if the providerCandle fails conversion to a CandlePrice,
+// and logs a warning and skips the candle if its price or volume is invalid.
 func (ps *priceStore) setCandlePair(candle providerCandle, currencyPair string) {
 	ps.candleMtx.Lock()
 	defer ps.candleMtx.Unlock()
 
+	oracleCandle, ok := ps.toValidCandle(candle, currencyPair, ps.candleInterval)
+	if !ok {
+		return
+	}
+
+	ps.appendAndFilterCandles(oracleCandle, currencyPair)
+	ps.setLastUpdate(currencyPair, MessageTypeCandle)
+}
+
+// setSecondaryCandlePair sets the candle price of the secondary interval for a
+// currency pair string key specific to the provider, kept separately from the
+// candles of the primary interval.
+func (ps *priceStore) setSecondaryCandlePair(candle providerCandle, currencyPair string, interval time.Duration) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	oracleCandle, ok := ps.toValidCandle(candle, currencyPair, interval)
+	if !ok {
+		return
+	}
+
+	ps.secondaryCandles[currencyPair] = ps.appendRecentCandles(ps.secondaryCandles[currencyPair], oracleCandle)
+	ps.setLastUpdate(currencyPair, MessageTypeCandle)
+}
+
+// toValidCandle converts the providerCandle to a CandlePrice, normalizing its
+// timestamp to the close of its interval. Logs and returns false if the
+// conversion fails or its price or volume is invalid.
+func (ps *priceStore) toValidCandle(
+	candle providerCandle,
+	currencyPair string,
+	interval time.Duration,
+) (types.CandlePrice, bool) {
 	oracleCandle, err := candle.toCandlePrice()
 	if err != nil {
 		ps.logger.Error().Err(err).Msg("failed to convert providerCandle to CandlePrice")
-		return
+		return types.CandlePrice{}, false
+	}
+	if err := validatePrice(oracleCandle.Price, oracleCandle.Volume); err != nil {
+		ps.logger.Warn().Err(err).Str("pair", currencyPair).Msg("skipping invalid candle")
+		return types.CandlePrice{}, false
 	}
+	if ps.candleTimestamp != "" {
+		oracleCandle.TimeStamp = normalizeCandleTimestamp(
+			oracleCandle.TimeStamp,
+			ps.candleTimestamp,
+			interval,
+			ps.now().UnixMilli(),
+		)
+	}
+	return oracleCandle, true
+}
 
-	ps.appendAndFilterCandles(oracleCandle, currencyPair)
+// validatePrice returns an error if a price converted from a provider is not
+// positive or its volume is negative.
+func validatePrice(price, volume math.LegacyDec) error {
+	if price.IsNil() || !price.IsPositive() {
+		return fmt.Errorf("price must be positive: %s", price)
+	}
+	if volume.IsNil() || volume.IsNegative() {
+		return fmt.Errorf("volume must not be negative: %s", volume)
+	}
+	return nil
+}
+
+// setLastUpdate records that a ticker, or a candle for any other message
+// type, was just received for a currency pair string key specific to the
+// provider.
+func (ps *priceStore) setLastUpdate(currencyPair string, messageType MessageType) {
+	ps.lastUpdateMtx.Lock()
+	defer ps.lastUpdateMtx.Unlock()
+
+	update := ps.lastUpdate[currencyPair]
+	if messageType == MessageTypeTicker {
+		update.ticker = ps.now()
+	} else {
+		update.candle = ps.now()
+	}
+	ps.lastUpdate[currencyPair] = update
+}
+
+// tickerUpdate returns when a ticker was last received for a currency pair
+// string key specific to the provider, and false if none was received yet.
+func (ps *priceStore) tickerUpdate(currencyPair string) (time.Time, bool) {
+	ps.lastUpdateMtx.RLock()
+	defer ps.lastUpdateMtx.RUnlock()
+
+	updated := ps.lastUpdate[currencyPair].ticker
+	return updated, !updated.IsZero()
+}
+
+// GetLastUpdate returns when a ticker or candle was last received for the
+// currency pair, and false if none was received yet.
+func (ps *priceStore) GetLastUpdate(cp types.CurrencyPair) (time.Time, bool) {
+	ps.lastUpdateMtx.RLock()
+	defer ps.lastUpdateMtx.RUnlock()
+
+	tickerUpdate := ps.lastUpdate[ps.currencyPairToTickerPair(cp)].ticker
+	candleUpdate := ps.lastUpdate[ps.curencyPairToCandlePair(cp)].candle
+	if candleUpdate.After(tickerUpdate) {
+		return candleUpdate, true
+	}
+	return tickerUpdate, !tickerUpdate.IsZero()
 }
 
 // Does not acquire lock - must be called from parent function
 func (ps *priceStore) appendAndFilterCandles(newCandle types.CandlePrice, currencyPair string) {
-	staleTime := PastUnixTime(ps.candlePeriod)
+	ps.candles[currencyPair] = ps.appendRecentCandles(ps.candles[currencyPair], newCandle)
+}
+
+// appendRecentCandles returns the new candle followed by the candles within
+// the candle period, pruning the older ones.
+func (ps *priceStore) appendRecentCandles(
+	candles []types.CandlePrice,
+	newCandle types.CandlePrice,
+) []types.CandlePrice {
+	staleTime := PastUnixTimeMillis(ps.candlePeriod)
 	newCandles := []types.CandlePrice{newCandle}
 
-	for _, c := range ps.candles[currencyPair] {
+	for _, c := range candles {
 		if staleTime < c.TimeStamp {
 			newCandles = append(newCandles, c)
 		}
 	}
-	ps.candles[currencyPair] = newCandles
+	return newCandles
+}
+
+// hasRecentCandles returns whether any of the candles is within the candle
+// period.
+func (ps *priceStore) hasRecentCandles(candles []types.CandlePrice) bool {
+	staleTime := PastUnixTimeMillis(ps.candlePeriod)
+	for _, c := range candles {
+		if staleTime < c.TimeStamp {
+			return true
+		}
+	}
+	return false
 }
 
 // All candles are in one min intervals where each candle starts exactly on the minute
@@ -190,12 +466,13 @@ func (ps *priceStore) addTradeToCandles(trade types.Trade, currencyPair string)
 	ps.candleMtx.Lock()
 	defer ps.candleMtx.Unlock()
 
-	tradeCandleStamp := time.Unix(trade.Time, 0).Truncate(time.Minute).Unix() + 60
+	tradeCandleStamp := time.UnixMilli(trade.Time).Truncate(time.Minute).Add(time.Minute).UnixMilli()
 	newCandle, err := types.NewCandlePrice(trade.Price, trade.Size, tradeCandleStamp)
 	if err != nil {
 		ps.logger.Error().Err(err).Msg("failed to parse trade values")
 		return
 	}
+	ps.setLastUpdate(currencyPair, MessageTypeTrade)
 
 	if len(ps.candles[currencyPair]) == 0 {
 		ps.candles[currencyPair] = []types.CandlePrice{newCandle}
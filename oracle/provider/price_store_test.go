@@ -0,0 +1,266 @@
+package provider
+
+import (
+	gomath "math"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+var _ LastUpdateReporter = (*BinanceProvider)(nil)
+
+type testTicker struct{}
+
+func (testTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{Price: math.LegacyOneDec(), Volume: math.LegacyOneDec()}, nil
+}
+
+type testCandle struct{}
+
+// timestampedCandle defines a test candle with the given unix millisecond
+// timestamp.
+type timestampedCandle int64
+
+func (c timestampedCandle) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     math.LegacyOneDec(),
+		Volume:    math.LegacyOneDec(),
+		TimeStamp: int64(c),
+	}, nil
+}
+
+func (testCandle) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     math.LegacyOneDec(),
+		Volume:    math.LegacyOneDec(),
+		TimeStamp: PastUnixTimeMillis(0),
+	}, nil
+}
+
+func TestPriceStore_GetLastUpdate(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+
+	_, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.False(t, ok)
+
+	ps.setTickerPair(testTicker{}, ATOMUSDT.String())
+	tickerUpdate, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.True(t, ok)
+
+	time.Sleep(time.Millisecond)
+	ps.setCandlePair(testCandle{}, ATOMUSDT.String())
+	candleUpdate, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.True(t, ok)
+	require.True(t, candleUpdate.After(tickerUpdate))
+
+	time.Sleep(time.Millisecond)
+	ps.setTickerPair(testTicker{}, ATOMUSDT.String())
+	secondTickerUpdate, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.True(t, ok)
+	require.True(t, secondTickerUpdate.After(candleUpdate))
+
+	time.Sleep(time.Millisecond)
+	ps.addTradeToCandles(types.Trade{Price: "1", Size: "1", Time: time.Now().UnixMilli()}, ATOMUSDT.String())
+	tradeUpdate, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.True(t, ok)
+	require.True(t, tradeUpdate.After(secondTickerUpdate))
+
+	_, ok = ps.GetLastUpdate(types.CurrencyPair{Base: "OSMO", Quote: "USDT"})
+	require.False(t, ok)
+}
+
+func TestPriceStore_GetLastUpdateTranslatedPairs(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	ps.setCurrencyPairToTickerAndCandlePair(currencyPairToBinanceTickerPair)
+
+	ps.setTickerPair(testTicker{}, currencyPairToBinanceTickerPair(ATOMUSDT))
+	_, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.True(t, ok)
+}
+
+func TestPriceStore_UnsubscribeCurrencyPairs(t *testing.T) {
+	osmoUSDT := types.CurrencyPair{Base: "OSMO", Quote: "USDT"}
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	ps.setSubscribedPairs(ATOMUSDT, osmoUSDT)
+	for _, cp := range []types.CurrencyPair{ATOMUSDT, osmoUSDT} {
+		ps.setTickerPair(testTicker{}, cp.String())
+		ps.setCandlePair(testCandle{}, cp.String())
+	}
+
+	ps.UnsubscribeCurrencyPairs(ATOMUSDT)
+
+	require.False(t, ps.isSubscribed(ATOMUSDT.String()))
+	require.True(t, ps.isSubscribed(osmoUSDT.String()))
+
+	tickers, err := ps.GetTickerPrices(ATOMUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	require.Contains(t, tickers, osmoUSDT)
+
+	candles, err := ps.GetCandlePrices(ATOMUSDT, osmoUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	require.Contains(t, candles, osmoUSDT)
+
+	_, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.False(t, ok)
+}
+
+func TestPriceStore_CandlePeriod(t *testing.T) {
+	oldCandle := types.CandlePrice{
+		Price:     math.LegacyOneDec(),
+		Volume:    math.LegacyOneDec(),
+		TimeStamp: PastUnixTimeMillis(10 * time.Minute),
+	}
+	newCandle := types.CandlePrice{
+		Price:     math.LegacyOneDec(),
+		Volume:    math.LegacyOneDec(),
+		TimeStamp: PastUnixTimeMillis(0),
+	}
+
+	testCases := []struct {
+		name      string
+		endpoint  Endpoint
+		minPeriod time.Duration
+		expected  int
+	}{
+		{name: "default period", endpoint: Endpoint{}, expected: 1},
+		{name: "15m period", endpoint: Endpoint{CandlePeriod: "15m"}, expected: 2},
+		{name: "invalid period", endpoint: Endpoint{CandlePeriod: "abc"}, expected: 1},
+		{name: "15m min period", endpoint: Endpoint{}, minPeriod: 15 * time.Minute, expected: 2},
+		{name: "shorter min period", endpoint: Endpoint{CandlePeriod: "15m"}, minPeriod: time.Minute, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := newPriceStore(zerolog.Nop(), tc.endpoint.candlePeriod(defaultCandlePeriod))
+			ps.SetMinCandlePeriod(tc.minPeriod)
+			ps.candles[ATOMUSDT.String()] = []types.CandlePrice{oldCandle}
+
+			ps.appendAndFilterCandles(newCandle, ATOMUSDT.String())
+			require.Len(t, ps.candles[ATOMUSDT.String()], tc.expected)
+		})
+	}
+}
+
+func TestPriceStore_SecondaryCandles(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	oldCandle := timestampedCandle(PastUnixTimeMillis(10 * time.Minute))
+	secondaryCandle := timestampedCandle(PastUnixTimeMillis(time.Minute))
+
+	// the secondary candles are used while no primary candle is recent
+	ps.candles[ATOMUSDT.String()] = []types.CandlePrice{{
+		Price:     math.LegacyOneDec(),
+		Volume:    math.LegacyOneDec(),
+		TimeStamp: int64(oldCandle),
+	}}
+	ps.setSecondaryCandlePair(secondaryCandle, ATOMUSDT.String(), 5*time.Minute)
+	candles, err := ps.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, int64(secondaryCandle), candles[ATOMUSDT][0].TimeStamp)
+
+	// the primary candles are preferred once one is recent
+	ps.setCandlePair(testCandle{}, ATOMUSDT.String())
+	candles, err = ps.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.NotEqual(t, int64(secondaryCandle), candles[ATOMUSDT][0].TimeStamp)
+}
+
+func TestPriceStore_GetTickerPricesStale(t *testing.T) {
+	now := time.Now()
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	ps.now = func() time.Time { return now }
+
+	ps.setTickerPair(testTicker{}, ATOMUSDT.String())
+	tickers, err := ps.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Contains(t, tickers, ATOMUSDT)
+
+	// the age of the tickers is not checked by default
+	now = now.Add(time.Hour)
+	tickers, err = ps.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Contains(t, tickers, ATOMUSDT)
+
+	// past the max ticker age, a candle doesn't refresh the ticker
+	ps.SetMaxTickerAge(time.Minute)
+	ps.setCandlePair(testCandle{}, ATOMUSDT.String())
+	tickers, err = ps.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.NotContains(t, tickers, ATOMUSDT)
+
+	// a new ticker is returned again
+	ps.setTickerPair(testTicker{}, ATOMUSDT.String())
+	tickers, err = ps.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Contains(t, tickers, ATOMUSDT)
+}
+
+func TestPriceStore_SetInvalidPrices(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+
+	// non-finite floats are dropped instead of stored as bogus prices
+	ps.setTickerPair(PolygonAggregatesResponse{Close: gomath.Inf(1), Volume: 1}, ATOMUSDT.String())
+	ps.setCandlePair(PolygonAggregatesResponse{Close: gomath.NaN(), Volume: 1}, ATOMUSDT.String())
+	_, ok := ps.GetLastUpdate(ATOMUSDT)
+	require.False(t, ok)
+
+	// non-positive prices are dropped
+	ps.setTickerPair(PolygonAggregatesResponse{Close: 0, Volume: 1}, ATOMUSDT.String())
+	ps.setCandlePair(PolygonAggregatesResponse{Close: -1, Volume: 1, Timestamp: PastUnixTimeMillis(0)}, ATOMUSDT.String())
+	_, ok = ps.GetLastUpdate(ATOMUSDT)
+	require.False(t, ok)
+
+	ps.setTickerPair(PolygonAggregatesResponse{Close: 1.1, Volume: 1}, ATOMUSDT.String())
+	tickers, err := ps.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.1"), tickers[ATOMUSDT].Price)
+}
+
+func TestPriceStore_CandleTimestamp(t *testing.T) {
+	interval := time.Minute
+	closeTime := time.Now().Truncate(interval)
+	openTime := closeTime.Add(-interval)
+
+	openStore := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	openStore.SetCandleTimestamp(CandleTimestampOpen, interval)
+	openStore.setCandlePair(timestampedCandle(openTime.UnixMilli()), ATOMUSDT.String())
+
+	// the close timestamp is emitted with a slight delay
+	closeStore := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	closeStore.SetCandleTimestamp(CandleTimestampClose, interval)
+	closeStore.setCandlePair(timestampedCandle(closeTime.Add(250*time.Millisecond).UnixMilli()), ATOMUSDT.String())
+
+	midStore := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	midStore.SetCandleTimestamp(CandleTimestampMid, interval)
+	midStore.setCandlePair(timestampedCandle(openTime.Add(interval/2).UnixMilli()), ATOMUSDT.String())
+
+	for _, ps := range []*priceStore{&openStore, &closeStore, &midStore} {
+		candles, err := ps.GetCandlePrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Len(t, candles[ATOMUSDT], 1)
+		require.Equal(t, closeTime.UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+	}
+
+	// the candle in progress is not dated in the future
+	liveStore := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	liveStore.SetCandleTimestamp(CandleTimestampOpen, interval)
+	liveStore.setCandlePair(timestampedCandle(closeTime.UnixMilli()), ATOMUSDT.String())
+	candles, err := liveStore.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, closeTime.UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+
+	// without a convention the timestamps are kept as reported
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	ps.setCandlePair(timestampedCandle(openTime.UnixMilli()), ATOMUSDT.String())
+	candles, err = ps.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, openTime.UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+}
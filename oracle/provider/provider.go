@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/ojo-network/price-feeder/oracle/types"
@@ -17,7 +19,9 @@ const (
 	ProviderOkx         types.ProviderName = "okx"
 	ProviderGate        types.ProviderName = "gate"
 	ProviderCoinbase    types.ProviderName = "coinbase"
+	ProviderBitso       types.ProviderName = "bitso"
 	ProviderBitget      types.ProviderName = "bitget"
+	ProviderBybit       types.ProviderName = "bybit"
 	ProviderMexc        types.ProviderName = "mexc"
 	ProviderCrypto      types.ProviderName = "crypto"
 	ProviderPolygon     types.ProviderName = "polygon"
@@ -27,6 +31,11 @@ const (
 	ProviderEthPancake  types.ProviderName = "eth-pancake"
 	ProviderEthCurve    types.ProviderName = "eth-curve"
 	ProviderKujira      types.ProviderName = "kujira"
+	ProviderKuCoin      types.ProviderName = "kucoin"
+	ProviderNormalized  types.ProviderName = "normalized"
+	ProviderCoinGecko   types.ProviderName = "coingecko"
+	ProviderDeribit     types.ProviderName = "deribit"
+	ProviderBitfinex    types.ProviderName = "bitfinex"
 	ProviderMock        types.ProviderName = "mock"
 )
 
@@ -50,10 +59,57 @@ type (
 		// pairs and adds them to the providers subscribed pairs
 		SubscribeCurrencyPairs(...types.CurrencyPair)
 
+		// UnsubscribeCurrencyPairs stops receiving prices for the currency pairs,
+		// if the provider supports it, and removes them and their stored prices
+		// from the providers subscribed pairs
+		UnsubscribeCurrencyPairs(...types.CurrencyPair)
+
 		// StartConnections starts the websocket connections.
 		StartConnections()
 	}
 
+	// HealthChecker defines an optional interface a provider can implement to
+	// report whether it is currently able to serve prices, so it can be skipped
+	// instead of waiting for it to time out.
+	HealthChecker interface {
+		// IsHealthy returns whether the provider is currently receiving data.
+		IsHealthy() bool
+	}
+
+	// LastUpdateReporter defines an optional interface a provider can implement
+	// to report when it last received a price for a currency pair, so a frozen
+	// connection can be detected even while its last prices are still cached.
+	LastUpdateReporter interface {
+		// GetLastUpdate returns when a ticker or candle was last received for
+		// the currency pair, and false if none was received yet.
+		GetLastUpdate(types.CurrencyPair) (time.Time, bool)
+	}
+
+	// CandleTimestampNormalizer defines an optional interface a provider can
+	// implement to normalize the timestamps of its candles to the close of their
+	// interval.
+	CandleTimestampNormalizer interface {
+		// SetCandleTimestamp sets the convention the provider timestamps its
+		// candles of the given interval with.
+		SetCandleTimestamp(CandleTimestamp, time.Duration)
+	}
+
+	// TickerAgeLimiter defines an optional interface a provider can implement
+	// to stop returning the tickers which were not received recently.
+	TickerAgeLimiter interface {
+		// SetMaxTickerAge sets how long the tickers are returned for after they
+		// were last received. A max age of zero disables the check.
+		SetMaxTickerAge(time.Duration)
+	}
+
+	// CandleRetainer defines an optional interface a provider can implement to
+	// keep its candles for at least the TVWAP window.
+	CandleRetainer interface {
+		// SetMinCandlePeriod sets the minimum period the candles are kept for
+		// before being pruned.
+		SetMinCandlePeriod(time.Duration)
+	}
+
 	// Endpoint defines an override setting in our config for the
 	// hardcoded rest and websocket api endpoints.
 	Endpoint struct {
@@ -68,16 +124,125 @@ type (
 
 		// APIKey for API Key protected endpoints
 		APIKey string `toml:"apikey"`
+
+		// RestTimeout defines the timeout of the REST requests to the
+		// provider, ex. "5s". Defaults to 10s when it is empty.
+		RestTimeout string `toml:"rest_timeout" mapstructure:"rest_timeout"`
+
+		// MaxResponseSize defines the maximum size, in bytes, of the REST
+		// responses read from the provider. Defaults to 8 MiB when it is zero.
+		MaxResponseSize int64 `toml:"max_response_size" mapstructure:"max_response_size"`
+
+		// PriceSource defines which price is used as the ticker price, ex. "mid".
+		// Only supported by the providers in BookPriceProviders, or in
+		// DepthPriceProviders for the depth price, and defaults to the last
+		// trade price.
+		PriceSource PriceSource `toml:"price_source" mapstructure:"price_source"`
+
+		// DepthNotionals defines the notional of each quote currency, ex.
+		// {USD = "100000", BTC = "2"}, filled against the order book of the
+		// pairs quoted in it to compute their depth price. Required by the
+		// depth price source. Pairs quoted in other currencies use their last
+		// price.
+		DepthNotionals map[string]string `toml:"depth_notionals" mapstructure:"depth_notionals"`
+
+		// IndexTickerPairs defines the pairs, ex. "BTC-USDT", whose ticker price
+		// is taken from the provider's index price instead of its spot price.
+		// Only supported by Okx.
+		IndexTickerPairs []string `toml:"index_ticker_pairs" mapstructure:"index_ticker_pairs"`
+
+		// AvailablePairs defines a static list of the pairs available on the
+		// provider, ex. "ATOM/USDT", used instead of fetching them over REST
+		// when subscribing.
+		AvailablePairs []string `toml:"available_pairs" mapstructure:"available_pairs"`
+
+		// AvailablePairsRefresh defines the interval at which the static
+		// available pairs are refreshed over REST, ex. "24h". They are never
+		// refreshed when it is empty.
+		AvailablePairsRefresh string `toml:"available_pairs_refresh" mapstructure:"available_pairs_refresh"`
+
+		// AvailablePairsCacheTTL defines how long the available pairs fetched
+		// from the provider are reused when subscribing again, ex. "5m".
+		// Defaults to 5m when it is empty, and "0s" disables the cache.
+		AvailablePairsCacheTTL string `toml:"available_pairs_cache_ttl" mapstructure:"available_pairs_cache_ttl"`
+
+		// ReconnectMaxInterval defines the maximum interval between attempts
+		// to reconnect a dropped websocket connection, ex. "5m". Defaults to
+		// defaultReconnectMaxInterval when it is empty.
+		ReconnectMaxInterval string `toml:"reconnect_max_interval" mapstructure:"reconnect_max_interval"`
+
+		// CandlePeriod defines how long candles are kept before being pruned,
+		// ex. "15m". Defaults to the candle period of the provider when it is
+		// empty, and is lengthened to the TVWAP window when it is shorter, so
+		// the TVWAP weights all the candles within its window.
+		CandlePeriod string `toml:"candle_period" mapstructure:"candle_period"`
+
+		// SyntheticVolume defines the volume, ex. "1000", stored with each
+		// price of a provider which doesn't report volume, weighting it against
+		// the other providers in the VWAP. Only supported by Deribit, and
+		// defaults to 1.
+		SyntheticVolume string `toml:"synthetic_volume" mapstructure:"synthetic_volume"`
+
+		// CandleTimestamp defines the point of their interval the provider
+		// timestamps its candles at, ex. "open", so they are normalized to the
+		// close of their interval and align with the candles of the other
+		// providers. The timestamps are kept as reported when it is empty.
+		CandleTimestamp CandleTimestamp `toml:"candle_timestamp" mapstructure:"candle_timestamp"`
+
+		// CandleInterval defines the interval of the provider's candles, ex.
+		// "5m", used to normalize their timestamps. Defaults to 1m.
+		CandleInterval string `toml:"candle_interval" mapstructure:"candle_interval"`
+
+		// SecondaryCandleInterval defines a coarser candle interval, ex. "5m",
+		// subscribed to in addition to the provider's candles and used for the
+		// pairs without any recent candle, such as thin pairs whose 1m candles
+		// are often empty. Only supported by the providers in
+		// SecondaryCandleIntervalProviders.
+		SecondaryCandleInterval string `toml:"secondary_candle_interval" mapstructure:"secondary_candle_interval"`
+
+		// PairCoverageWindow defines how long after connecting or reconnecting
+		// the provider is given to send data for each subscribed pair, ex.
+		// "2m", before the pairs which received nothing are logged. Only
+		// supported by the providers in PairCoverageProviders, and defaults
+		// to 1m.
+		PairCoverageWindow string `toml:"pair_coverage_window" mapstructure:"pair_coverage_window"`
+
+		// EnableRESTFallback defines whether the tickers are polled from the
+		// REST API while the websocket is down. Only supported by the
+		// providers in RESTFallbackProviders, and enabled by default.
+		EnableRESTFallback *bool `toml:"enable_rest_fallback" mapstructure:"enable_rest_fallback"`
 	}
 )
 
-// PastUnixTime returns a millisecond timestamp that represents the unix time
-// minus t.
+// PastUnixTimeMillis returns a unix timestamp in milliseconds that represents
+// the current time minus t. Candle timestamps are compared against it, so
+// every provider must store its candle timestamps in milliseconds.
+func PastUnixTimeMillis(t time.Duration) int64 {
+	return time.Now().Add(t * -1).UnixMilli()
+}
+
+// PastUnixTime returns a unix timestamp in milliseconds that represents the
+// current time minus t.
+//
+// Deprecated: use PastUnixTimeMillis, which makes the unit explicit.
 func PastUnixTime(t time.Duration) int64 {
-	return time.Now().Add(t*-1).Unix() * int64(time.Second/time.Millisecond)
+	return PastUnixTimeMillis(t)
 }
 
 // SecondsToMilli converts seconds to milliseconds for our unix timestamps.
+// Providers reporting candle timestamps in seconds must convert them with it.
 func SecondsToMilli(t int64) int64 {
 	return t * int64(time.Second/time.Millisecond)
 }
+
+// checkFinite returns an error if any of the values parsed from a provider's
+// floats is NaN or infinite, which would otherwise be formatted into a bogus
+// price or volume.
+func checkFinite(values ...float64) error {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("non-finite value: %f", v)
+		}
+	}
+	return nil
+}
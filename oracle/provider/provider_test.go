@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPastUnixTimeMillis(t *testing.T) {
+	now := time.Now().UnixMilli()
+
+	require.InDelta(t, now, PastUnixTimeMillis(0), float64(time.Second/time.Millisecond))
+	require.InDelta(t, now-60000, PastUnixTimeMillis(time.Minute), float64(time.Second/time.Millisecond))
+	require.InDelta(t, PastUnixTimeMillis(time.Minute), PastUnixTime(time.Minute), float64(time.Second/time.Millisecond))
+}
+
+func TestSecondsToMilli(t *testing.T) {
+	require.Equal(t, int64(1645756200000), SecondsToMilli(1645756200))
+}
+
+// TestCandleTimestampUnits checks that every provider stores its candle
+// timestamps in milliseconds, the unit PastUnixTimeMillis returns and the
+// candle pruning and TVWAP computation compare against. Candles are built
+// from a timestamp in each provider's native unit.
+func TestCandleTimestampUnits(t *testing.T) {
+	nowSeconds := time.Now().Unix()
+	nowMillis := time.Now().UnixMilli()
+
+	testCases := map[types.ProviderName]providerCandle{
+		ProviderBinance: BinanceCandle{
+			Metadata: BinanceCandleMetadata{Close: "1", Volume: "1", TimeStamp: nowMillis},
+		},
+		ProviderBitget: BitgetCandle{Close: "1", Volume: "1", TimeStamp: nowMillis},
+		ProviderBybit:  BybitCandle{Close: "1", Volume: "1", Start: nowMillis},
+		ProviderCrypto: CryptoCandle{Close: "1", Volume: "1", Timestamp: nowMillis},
+		ProviderGate:   GateCandle{Close: "1", Volume: "1", TimeStamp: nowSeconds},
+		ProviderHuobi: HuobiCandle{
+			Tick: HuobiCandleTick{Close: 1, Volume: 1, TimeStamp: nowSeconds},
+		},
+		ProviderKraken: KrakenCandle{Close: "1", Volume: "1", TimeStamp: nowSeconds},
+		ProviderKuCoin: KuCoinCandle{Candles: []string{strconv.FormatInt(nowSeconds, 10), "1", "1", "1", "1", "1", "1"}},
+		ProviderMexc: MexcCandle{
+			Data: MexcCandleData{Close: big.NewFloat(1), Volume: big.NewFloat(1), TimeStamp: nowSeconds},
+		},
+		ProviderOkx:     OkxCandlePair{Close: "1", Volume: "1", TimeStamp: nowMillis},
+		ProviderOsmosis: OsmosisCandle{Close: "1", Volume: "1", EndTime: nowMillis},
+		ProviderPolygon: PolygonAggregatesResponse{Close: 1, Volume: 1, Timestamp: nowMillis},
+	}
+
+	for name, candle := range testCases {
+		candle := candle
+
+		t.Run(string(name), func(t *testing.T) {
+			candlePrice, err := candle.toCandlePrice()
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, candlePrice.TimeStamp, PastUnixTimeMillis(defaultCandlePeriod))
+			require.LessOrEqual(t, candlePrice.TimeStamp, PastUnixTimeMillis(-time.Minute))
+		})
+	}
+}
+
+func TestAddTradeToCandlesTimestampUnit(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop(), defaultCandlePeriod)
+	now := time.Now()
+
+	ps.addTradeToCandles(types.Trade{Time: now.UnixMilli(), Price: "1", Size: "1"}, "ATOMUSDT")
+	ps.addTradeToCandles(types.Trade{Time: now.UnixMilli(), Price: "2", Size: "1"}, "ATOMUSDT")
+
+	// trades of the same minute belong to a single candle ending on the minute
+	candles := ps.candles["ATOMUSDT"]
+	require.Len(t, candles, 1)
+	require.Equal(t, now.Truncate(time.Minute).Add(time.Minute).UnixMilli(), candles[0].TimeStamp)
+	require.GreaterOrEqual(t, candles[0].TimeStamp, PastUnixTimeMillis(defaultCandlePeriod))
+}
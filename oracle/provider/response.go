@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultMaxResponseSize is the maximum size, in bytes, of the body of a
+// provider REST response read by default.
+const defaultMaxResponseSize int64 = 8 << 20
+
+// ErrResponseTooLarge is returned when reading the body of a provider REST
+// response larger than the max response size.
+var ErrResponseTooLarge = errors.New("provider response too large")
+
+// maxResponseSize returns the maximum size, in bytes, of the body of the REST
+// responses read from the endpoint, so a misbehaving endpoint can't exhaust
+// the memory of the price feeder. Defaults to defaultMaxResponseSize.
+func (e Endpoint) maxResponseSize() int64 {
+	if e.MaxResponseSize <= 0 {
+		return defaultMaxResponseSize
+	}
+	return e.MaxResponseSize
+}
+
+// limitedBody reads a response body up to a limit, after which it returns an
+// ErrResponseTooLarge instead of silently truncating the body like an
+// io.LimitReader would.
+type limitedBody struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+// limitResponseBody wraps the body of a REST response of the endpoint so
+// reading more than its max response size from it fails with
+// ErrResponseTooLarge.
+func (e Endpoint) limitResponseBody(body io.Reader) io.Reader {
+	return newLimitedBody(body, e.maxResponseSize())
+}
+
+// newLimitedBody wraps a response body so reading more than limit bytes from
+// it fails with ErrResponseTooLarge.
+func newLimitedBody(body io.Reader, limit int64) io.Reader {
+	// one byte past the limit is read to tell a body of exactly the limit
+	// apart from a larger one
+	return &limitedBody{r: io.LimitReader(body, limit+1), limit: limit}
+}
+
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	n, err := lb.r.Read(p)
+	lb.read += int64(n)
+	if lb.read > lb.limit {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, lb.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLimitedBody(t *testing.T) {
+	bz, err := io.ReadAll(newLimitedBody(bytes.NewReader(make([]byte, 16)), 16))
+	require.NoError(t, err)
+	require.Len(t, bz, 16)
+
+	_, err = io.ReadAll(newLimitedBody(bytes.NewReader(make([]byte, 17)), 16))
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestNormalizedProvider_OversizedResponse(t *testing.T) {
+	body := `[` + strings.Repeat(`{"exchange":"binance","base":"ATOM","quote":"USDT","price":"10"},`, 100) + `{}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	_, err := NewNormalizedProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderNormalized, Rest: server.URL, MaxResponseSize: 1 << 10},
+		ATOMUSDT,
+	)
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+}
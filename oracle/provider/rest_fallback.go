@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// restFallbackThreshold defines how long the websocket connections of a
+	// provider must have been disconnected before its tickers are polled from
+	// its REST API instead.
+	restFallbackThreshold = 30 * time.Second
+
+	// restFallbackInterval defines how often the tickers are polled from the
+	// REST API while the websocket connections are disconnected.
+	restFallbackInterval = 10 * time.Second
+)
+
+// RESTFallbackProviders defines the websocket providers which can poll their
+// tickers from their REST API while their websocket is down.
+var RESTFallbackProviders = map[types.ProviderName]struct{}{
+	ProviderBinance:   {},
+	ProviderBinanceUS: {},
+	ProviderKraken:    {},
+	ProviderOkx:       {},
+}
+
+// restFallbackEnabled returns whether the tickers of the endpoint's provider
+// are polled from its REST API while its websocket is down. The fallback is
+// enabled by default for the providers supporting it.
+func (e Endpoint) restFallbackEnabled() bool {
+	if _, ok := RESTFallbackProviders[e.Name]; !ok {
+		return false
+	}
+	return e.EnableRESTFallback == nil || *e.EnableRESTFallback
+}
+
+// startRESTFallback polls the tickers of a provider with the given poll
+// function every restFallbackInterval while all of its websocket connections
+// have been disconnected for longer than restFallbackThreshold, so the
+// provider keeps serving tickers until it reconnects. It stops once the parent
+// context of the websocket controller is done.
+func startRESTFallback(wsc *WebsocketController, logger zerolog.Logger, poll func() error) {
+	ticker := time.NewTicker(restFallbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wsc.parentCtx.Done():
+			return
+		case <-ticker.C:
+			if wsc.DisconnectedFor() < restFallbackThreshold {
+				continue
+			}
+
+			logger.Warn().Msg("websocket disconnected; polling tickers from rest api")
+			if err := poll(); err != nil {
+				logger.Err(err).Msg("failed to poll tickers from rest api")
+			}
+		}
+	}
+}
+
+// startStaleTickerRESTFallback polls every restFallbackInterval the tickers of
+// the subscribed pairs of a provider which were not received within
+// restFallbackThreshold with the given poll function, so each pair keeps
+// serving tickers whether all of the websocket connections are down or only
+// the stream of the pair stalled. It stops once the parent context of the
+// websocket controller is done.
+func startStaleTickerRESTFallback(
+	wsc *WebsocketController,
+	ps *priceStore,
+	logger zerolog.Logger,
+	poll func(...types.CurrencyPair) error,
+) {
+	ticker := time.NewTicker(restFallbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wsc.parentCtx.Done():
+			return
+		case <-ticker.C:
+			stalePairs := ps.staleTickerPairs(restFallbackThreshold)
+			if len(stalePairs) == 0 {
+				continue
+			}
+
+			logger.Warn().Int("pairs", len(stalePairs)).Msg("no fresh websocket tickers; polling them from rest api")
+			if err := poll(stalePairs...); err != nil {
+				logger.Err(err).Msg("failed to poll tickers from rest api")
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SecondaryCandleIntervalProviders defines the providers which can subscribe
+// to a coarser secondary candle interval, and the intervals each supports.
+var SecondaryCandleIntervalProviders = map[types.ProviderName]map[string]time.Duration{
+	ProviderBinance:   binanceSecondaryCandleIntervals,
+	ProviderBinanceUS: binanceSecondaryCandleIntervals,
+}
+
+// binanceSecondaryCandleIntervals defines the kline intervals Binance can
+// subscribe to in addition to its 1m klines.
+var binanceSecondaryCandleIntervals = map[string]time.Duration{
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+}
+
+// secondaryCandleInterval returns the secondary candle interval of the
+// endpoint and its duration, or false if it is not set or not supported by
+// the endpoint's provider.
+func (e Endpoint) secondaryCandleInterval() (string, time.Duration, bool) {
+	if e.SecondaryCandleInterval == "" {
+		return "", 0, false
+	}
+	interval, ok := SecondaryCandleIntervalProviders[e.Name][e.SecondaryCandleInterval]
+	if !ok {
+		return "", 0, false
+	}
+	return e.SecondaryCandleInterval, interval, true
+}
@@ -84,6 +84,26 @@ func telemetryWebsocketMessage(n types.ProviderName, mt MessageType) {
 	)
 }
 
+// telemetryRESTFallbackMessage gives an standard way to add
+// `price_feeder_provider_message{type="x", provider="x", source="rest"}` metric.
+func telemetryRESTFallbackMessage(n types.ProviderName, mt MessageType) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"message",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			messageTypeLabel(mt),
+			{
+				Name:  "source",
+				Value: "rest",
+			},
+		},
+	)
+}
+
 // TelemetryFailure gives an standard way to add
 // `price_feeder_failure_provider{type="x", provider="x"}` metric.
 func TelemetryFailure(n types.ProviderName, mt MessageType) {
@@ -98,3 +118,91 @@ func TelemetryFailure(n types.ProviderName, mt MessageType) {
 		},
 	)
 }
+
+// TelemetryPairDelisted gives an standard way to add
+// `price_feeder_provider_pair_delisted{provider="x"}` metric.
+func TelemetryPairDelisted(n types.ProviderName) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"pair",
+			"delisted",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// TelemetryPriceStale gives an standard way to add
+// `price_feeder_provider_price_stale{provider="x",currency_pair="y"}` metric.
+func TelemetryPriceStale(n types.ProviderName, cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"price",
+			"stale",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			{
+				Name:  "currency_pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryCandleFromFuture gives an standard way to add
+// `price_feeder_provider_candle_future{provider="x"}` metric.
+func TelemetryCandleFromFuture(n types.ProviderName) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"candle",
+			"future",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// TelemetryProviderUnhealthySkip gives an standard way to add
+// `price_feeder_provider_unhealthy_skip{provider="x"}` metric.
+func TelemetryProviderUnhealthySkip(n types.ProviderName) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"unhealthy",
+			"skip",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// TelemetryProviderDivergence gives an standard way to add
+// `price_feeder_provider_divergence{provider="x",base="y"}` gauge, the
+// percentage deviation of a provider's price from the computed price.
+func TelemetryProviderDivergence(n types.ProviderName, cp types.CurrencyPair, divergence float32) {
+	telemetry.SetGaugeWithLabels(
+		[]string{
+			"provider",
+			"divergence",
+		},
+		divergence,
+		[]metrics.Label{
+			providerLabel(n),
+			{
+				Name:  "base",
+				Value: cp.Base,
+			},
+		},
+	)
+}
@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
-	"math"
+	"math/rand"
+	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -20,12 +22,23 @@ const (
 	defaultPingDuration       = 15 * time.Second
 	disabledPingDuration      = time.Duration(0)
 	startingReconnectDuration = 5 * time.Second
-	maxRetryMultiplier        = 25 // max retry duration: 52m5s
+
+	// defaultReconnectMaxInterval is the default cap of the reconnection
+	// backoff, overridden by the reconnect_max_interval of the endpoint.
+	defaultReconnectMaxInterval = 2 * time.Minute
+
+	// stableConnectionDuration is how long a connection must stay up for its
+	// reconnection backoff to start over from startingReconnectDuration.
+	stableConnectionDuration = 30 * time.Second
 )
 
 type (
 	MessageHandler func(int, *WebsocketConnection, []byte)
 
+	// websocketDialer dials a websocket url, defaulting to the gorilla
+	// websocket default dialer.
+	websocketDialer func(ctx context.Context, urlStr string) (*websocket.Conn, *http.Response, error)
+
 	WebsocketConnection struct {
 		parentCtx           context.Context
 		websocketCtx        context.Context
@@ -38,22 +51,57 @@ type (
 		pingMessageType     uint
 		logger              zerolog.Logger
 
+		// reconnectMaxInterval caps the exponential backoff between
+		// reconnection attempts.
+		reconnectMaxInterval time.Duration
+		dialer               websocketDialer
+
+		// pingMsg is the payload of the pings, defaulting to ping.
+		pingMsg []byte
+		// resolveURL returns the url to dial on each connection attempt,
+		// instead of websocketURL, for providers whose url expires.
+		resolveURL func() (url.URL, error)
+		// onReconnect is called with the subscription message each time the
+		// connection is subscribed again after a reconnection.
+		onReconnect func(subscriptionMsg interface{})
+
 		mtx              sync.Mutex
 		client           *websocket.Conn
 		reconnectCounter uint
+		connectedAt      time.Time
+		disconnectedAt   time.Time
+		stopped          bool
+		subscribed       bool
 	}
 
 	// WebsocketController defines a provider agnostic websocket handler
 	// that manages reconnecting, subscribing, and receiving messages.
 	WebsocketController struct {
-		parentCtx    context.Context
-		providerName types.ProviderName
-		websocketURL url.URL
-		logger       zerolog.Logger
-		connections  []*WebsocketConnection
+		parentCtx            context.Context
+		providerName         types.ProviderName
+		websocketURL         url.URL
+		reconnectMaxInterval time.Duration
+		logger               zerolog.Logger
+		connections          []*WebsocketConnection
+		pingMsg              []byte
+		resolveURL           func() (url.URL, error)
+		onReconnect          func(subscriptionMsg interface{})
 	}
 )
 
+// reconnectMaxInterval returns the parsed reconnect_max_interval of the
+// endpoint, or defaultReconnectMaxInterval when it is empty or invalid.
+func (e Endpoint) reconnectMaxInterval() time.Duration {
+	if e.ReconnectMaxInterval == "" {
+		return defaultReconnectMaxInterval
+	}
+	interval, err := time.ParseDuration(e.ReconnectMaxInterval)
+	if err != nil || interval <= 0 {
+		return defaultReconnectMaxInterval
+	}
+	return interval
+}
+
 func NewWebsocketController(
 	ctx context.Context,
 	providerName types.ProviderName,
@@ -62,6 +110,7 @@ func NewWebsocketController(
 	messageHandler MessageHandler,
 	pingDuration time.Duration,
 	pingMessageType uint,
+	reconnectMaxInterval time.Duration,
 	logger zerolog.Logger,
 ) *WebsocketController {
 	connections := make([]*WebsocketConnection, 0)
@@ -75,24 +124,27 @@ func NewWebsocketController(
 		}
 
 		connection := &WebsocketConnection{
-			parentCtx:       ctx,
-			providerName:    providerName,
-			websocketURL:    wsURL,
-			subscriptionMsg: subMsg,
-			messageHandler:  messageHandler,
-			pingDuration:    pingDuration,
-			pingMessageType: pingMessageType,
-			logger:          logger,
+			parentCtx:            ctx,
+			providerName:         providerName,
+			websocketURL:         wsURL,
+			subscriptionMsg:      subMsg,
+			messageHandler:       messageHandler,
+			pingDuration:         pingDuration,
+			pingMessageType:      pingMessageType,
+			logger:               logger,
+			reconnectMaxInterval: reconnectMaxInterval,
+			disconnectedAt:       time.Now(),
 		}
 		connections = append(connections, connection)
 	}
 
 	return &WebsocketController{
-		parentCtx:    ctx,
-		providerName: providerName,
-		websocketURL: websocketURL,
-		logger:       logger,
-		connections:  connections,
+		parentCtx:            ctx,
+		providerName:         providerName,
+		websocketURL:         websocketURL,
+		reconnectMaxInterval: reconnectMaxInterval,
+		logger:               logger,
+		connections:          connections,
 	}
 }
 
@@ -102,6 +154,69 @@ func (wsc *WebsocketController) StartConnections() {
 	}
 }
 
+// SetPingMessage sets the payload of the pings sent by the connections, for
+// providers which expect a specific ping message rather than ping.
+func (wsc *WebsocketController) SetPingMessage(msg []byte) {
+	wsc.pingMsg = msg
+	for _, conn := range wsc.connections {
+		conn.pingMsg = msg
+	}
+}
+
+// SetURLResolver sets the function returning the url each connection dials,
+// for providers whose websocket url must be requested before connecting.
+// It must be set before the connections are started.
+func (wsc *WebsocketController) SetURLResolver(resolveURL func() (url.URL, error)) {
+	wsc.resolveURL = resolveURL
+	for _, conn := range wsc.connections {
+		conn.resolveURL = resolveURL
+	}
+}
+
+// SetReconnectHandler sets the function called with the subscription message
+// of a connection each time it is subscribed again after a reconnection, for
+// providers which must recover the data missed while disconnected. It must be
+// set before the connections are started.
+func (wsc *WebsocketController) SetReconnectHandler(onReconnect func(subscriptionMsg interface{})) {
+	wsc.onReconnect = onReconnect
+	for _, conn := range wsc.connections {
+		conn.onReconnect = onReconnect
+	}
+}
+
+// IsHealthy returns true if at least one of the websocket connections is
+// currently connected, or if the controller has no connections.
+func (wsc *WebsocketController) IsHealthy() bool {
+	if len(wsc.connections) == 0 {
+		return true
+	}
+	for _, conn := range wsc.connections {
+		if conn.isConnected() {
+			return true
+		}
+	}
+	return false
+}
+
+// DisconnectedFor returns how long all of the websocket connections have been
+// disconnected, or zero if at least one of them is connected.
+func (wsc *WebsocketController) DisconnectedFor() time.Duration {
+	var lastDisconnectedAt time.Time
+	for _, conn := range wsc.connections {
+		disconnectedAt := conn.getDisconnectedAt()
+		if disconnectedAt.IsZero() {
+			return 0
+		}
+		if disconnectedAt.After(lastDisconnectedAt) {
+			lastDisconnectedAt = disconnectedAt
+		}
+	}
+	if lastDisconnectedAt.IsZero() {
+		return 0
+	}
+	return time.Since(lastDisconnectedAt)
+}
+
 // AddWebsocketConnection adds a new websocket connection to subribe to a
 // new pair.
 func (wsc *WebsocketController) AddWebsocketConnection(
@@ -112,38 +227,76 @@ func (wsc *WebsocketController) AddWebsocketConnection(
 ) {
 	for _, msg := range msgs {
 		conn := &WebsocketConnection{
-			parentCtx:       wsc.parentCtx,
-			providerName:    wsc.providerName,
-			websocketURL:    wsc.websocketURL,
-			subscriptionMsg: msg,
-			messageHandler:  messageHandler,
-			pingDuration:    pingDuration,
-			pingMessageType: pingMessageType,
-			logger:          wsc.logger,
+			parentCtx:            wsc.parentCtx,
+			providerName:         wsc.providerName,
+			websocketURL:         wsc.websocketURL,
+			subscriptionMsg:      msg,
+			messageHandler:       messageHandler,
+			pingDuration:         pingDuration,
+			pingMessageType:      pingMessageType,
+			logger:               wsc.logger,
+			reconnectMaxInterval: wsc.reconnectMaxInterval,
+			pingMsg:              wsc.pingMsg,
+			resolveURL:           wsc.resolveURL,
+			onReconnect:          wsc.onReconnect,
+			disconnectedAt:       time.Now(),
 		}
 		wsc.connections = append(wsc.connections, conn)
 		go conn.start()
 	}
 }
 
+// RemoveWebsocketConnections sends the unsubscription message at the same
+// index to each connection whose subscription message is in subscriptionMsgs,
+// then closes those connections without reconnecting them and removes them
+// from the controller.
+func (wsc *WebsocketController) RemoveWebsocketConnections(
+	subscriptionMsgs []interface{},
+	unsubscriptionMsgs []interface{},
+) {
+	connections := make([]*WebsocketConnection, 0, len(wsc.connections))
+	for _, conn := range wsc.connections {
+		removed := false
+		for i, msg := range subscriptionMsgs {
+			if !reflect.DeepEqual(conn.subscriptionMsg, msg) {
+				continue
+			}
+			if i < len(unsubscriptionMsgs) {
+				if err := conn.SendJSON(unsubscriptionMsgs[i]); err != nil {
+					conn.logger.Err(err).Send()
+				}
+			}
+			conn.stop()
+			removed = true
+			break
+		}
+		if !removed {
+			connections = append(connections, conn)
+		}
+	}
+	wsc.connections = connections
+}
+
 // start will continuously loop and attempt connecting to the websocket
-// until a successful connection is made. It then starts the ping
-// service and read listener in new go routines and sends a subscription
-// message using the passed in subscription message.
+// until a successful connection is made, backing off between attempts. It
+// then starts the ping service and read listener in new go routines and
+// sends a subscription message using the passed in subscription message.
 func (conn *WebsocketConnection) start() {
-	connectTicker := time.NewTicker(time.Millisecond)
-	defer connectTicker.Stop()
-
 	for {
+		if conn.isStopped() {
+			return
+		}
 		if err := conn.connect(); err != nil {
 			conn.logger.Err(err).Send()
-			select {
-			case <-conn.parentCtx.Done():
+			if !conn.waitToReconnect() {
 				return
-			case <-connectTicker.C:
-				connectTicker.Reset(conn.iterateRetryCounter())
-				continue
 			}
+			continue
+		}
+
+		if conn.isStopped() {
+			conn.close()
+			return
 		}
 
 		go conn.readWebSocket()
@@ -152,8 +305,19 @@ func (conn *WebsocketConnection) start() {
 		if err := conn.subscribe(conn.subscriptionMsg); err != nil {
 			conn.logger.Err(err).Send()
 			conn.close()
+			if !conn.waitToReconnect() {
+				return
+			}
 			continue
 		}
+
+		conn.mtx.Lock()
+		reconnected := conn.subscribed
+		conn.subscribed = true
+		conn.mtx.Unlock()
+		if reconnected && conn.onReconnect != nil {
+			go conn.onReconnect(conn.subscriptionMsg)
+		}
 		return
 	}
 }
@@ -164,24 +328,115 @@ func (conn *WebsocketConnection) connect() error {
 	defer conn.mtx.Unlock()
 
 	conn.logger.Debug().Msg("connecting to websocket")
-	connection, resp, err := websocket.DefaultDialer.Dial(conn.websocketURL.String(), nil)
+	if conn.resolveURL != nil {
+		websocketURL, err := conn.resolveURL()
+		if err != nil {
+			return fmt.Errorf(types.ErrWebsocketDial.Error(), conn.providerName, err)
+		}
+		conn.websocketURL = websocketURL
+	}
+	dial := conn.dialer
+	if dial == nil {
+		dial = func(ctx context.Context, urlStr string) (*websocket.Conn, *http.Response, error) {
+			return websocket.DefaultDialer.DialContext(ctx, urlStr, nil)
+		}
+	}
+	connection, resp, err := dial(conn.parentCtx, conn.websocketURL.String())
 	if err != nil {
 		return fmt.Errorf(types.ErrWebsocketDial.Error(), conn.providerName, err)
 	}
-	defer resp.Body.Close()
+	if resp != nil {
+		defer resp.Body.Close()
+	}
 	conn.client = connection
 	conn.websocketCtx, conn.websocketCancelFunc = context.WithCancel(conn.parentCtx)
 	conn.client.SetPingHandler(conn.pingHandler)
-	conn.reconnectCounter = 0
+	conn.connectedAt = time.Now()
+	conn.disconnectedAt = time.Time{}
 	return nil
 }
 
-func (conn *WebsocketConnection) iterateRetryCounter() time.Duration {
-	if conn.reconnectCounter < 25 {
+// getDisconnectedAt returns when the websocket connection was disconnected, or
+// the zero time if it is currently connected.
+func (conn *WebsocketConnection) getDisconnectedAt() time.Time {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+
+	return conn.disconnectedAt
+}
+
+// isConnected returns whether the websocket connection is currently established.
+func (conn *WebsocketConnection) isConnected() bool {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+
+	return conn.client != nil
+}
+
+// isStopped returns whether the websocket connection was stopped for good.
+func (conn *WebsocketConnection) isStopped() bool {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+
+	return conn.stopped
+}
+
+// stop closes the websocket connection for good, so it is not reconnected.
+func (conn *WebsocketConnection) stop() {
+	conn.mtx.Lock()
+	conn.stopped = true
+	conn.mtx.Unlock()
+
+	conn.close()
+}
+
+// waitToReconnect waits for the next reconnection interval, returning false
+// if the connection was stopped or its parent context is done meanwhile.
+func (conn *WebsocketConnection) waitToReconnect() bool {
+	timer := time.NewTimer(conn.nextReconnectInterval())
+	defer timer.Stop()
+
+	select {
+	case <-conn.parentCtx.Done():
+		return false
+	case <-timer.C:
+		return !conn.isStopped()
+	}
+}
+
+// nextReconnectInterval returns how long to wait before the next reconnection
+// attempt. The interval doubles from startingReconnectDuration on every
+// attempt up to the reconnectMaxInterval, with up to half of it randomized so
+// that connections dropped at once don't reconnect at once. It starts over
+// once a connection stayed up for stableConnectionDuration.
+func (conn *WebsocketConnection) nextReconnectInterval() time.Duration {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+
+	if !conn.connectedAt.IsZero() {
+		if conn.disconnectedAt.Sub(conn.connectedAt) >= stableConnectionDuration {
+			conn.reconnectCounter = 0
+		}
+		conn.connectedAt = time.Time{}
+	}
+
+	maxInterval := conn.reconnectMaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultReconnectMaxInterval
+	}
+
+	interval := startingReconnectDuration
+	for i := uint(0); i < conn.reconnectCounter && interval < maxInterval; i++ {
+		interval *= 2
+	}
+	if interval >= maxInterval {
+		interval = maxInterval
+	} else {
 		conn.reconnectCounter++
 	}
-	multiplier := math.Pow(float64(conn.reconnectCounter), 2)
-	return startingReconnectDuration * time.Duration(multiplier)
+
+	jitter := time.Duration(rand.Int63n(int64(interval/2) + 1))
+	return interval - jitter
 }
 
 // subscribe sends the WebsocketConnections subscription message to the websocket.
@@ -238,7 +493,11 @@ func (conn *WebsocketConnection) ping() error {
 	if conn.client == nil {
 		return fmt.Errorf("unable to ping closed connection")
 	}
-	err := conn.client.WriteMessage(int(conn.pingMessageType), ping)
+	msg := conn.pingMsg
+	if msg == nil {
+		msg = ping
+	}
+	err := conn.client.WriteMessage(int(conn.pingMessageType), msg)
 	if err != nil {
 		conn.logger.Err(fmt.Errorf(types.ErrWebsocketSend.Error(), conn.providerName, err)).Send()
 	}
@@ -292,7 +551,9 @@ func (conn *WebsocketConnection) close() {
 	defer conn.mtx.Unlock()
 
 	conn.logger.Debug().Msg("closing websocket")
-	conn.websocketCancelFunc()
+	if conn.websocketCancelFunc != nil {
+		conn.websocketCancelFunc()
+	}
 	if conn.client == nil {
 		return
 	}
@@ -300,13 +561,22 @@ func (conn *WebsocketConnection) close() {
 		conn.logger.Err(fmt.Errorf(types.ErrWebsocketClose.Error(), conn.providerName, err)).Send()
 	}
 	conn.client = nil
+	conn.disconnectedAt = time.Now()
 }
 
 // reconnect closes the current websocket and starts a new connection process
+// after the next reconnection interval.
 func (conn *WebsocketConnection) reconnect() {
 	conn.close()
-	go conn.start()
+	if conn.isStopped() {
+		return
+	}
 	telemetryWebsocketReconnect(conn.providerName)
+	go func() {
+		if conn.waitToReconnect() {
+			conn.start()
+		}
+	}()
 }
 
 // pingHandler is called by the websocket library whenever a ping message is received
@@ -1,7 +1,12 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
@@ -60,3 +65,125 @@ func TestWebsocketController_readSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsocketController_DisconnectedFor(t *testing.T) {
+	connected := &WebsocketConnection{client: new(websocket.Conn)}
+	disconnected := &WebsocketConnection{disconnectedAt: time.Now().Add(-time.Minute)}
+
+	c := &WebsocketController{
+		connections: []*WebsocketConnection{connected, disconnected},
+	}
+	require.True(t, c.IsHealthy())
+	require.Zero(t, c.DisconnectedFor())
+
+	// all connections are disconnected since the last one disconnected
+	c.connections[0] = &WebsocketConnection{disconnectedAt: time.Now().Add(-30 * time.Second)}
+	require.False(t, c.IsHealthy())
+	require.InDelta(t, 30*time.Second, c.DisconnectedFor(), float64(time.Second))
+}
+
+func TestWebsocketController_RemoveWebsocketConnections(t *testing.T) {
+	atomMsg := newBinanceSubscriptionMsg("atomusdt@ticker")
+	osmoMsg := newBinanceSubscriptionMsg("osmousdt@ticker")
+	c := &WebsocketController{
+		providerName: ProviderBinance,
+		connections: []*WebsocketConnection{
+			{subscriptionMsg: atomMsg},
+			{subscriptionMsg: osmoMsg},
+		},
+	}
+	removed := c.connections[0]
+
+	c.RemoveWebsocketConnections(
+		[]interface{}{atomMsg},
+		[]interface{}{newBinanceUnsubscriptionMsg("atomusdt@ticker")},
+	)
+
+	require.Len(t, c.connections, 1)
+	require.Equal(t, osmoMsg, c.connections[0].subscriptionMsg)
+	require.True(t, removed.isStopped())
+	require.False(t, c.connections[0].isStopped())
+
+	// a stopped connection is never reconnected
+	removed.start()
+	require.False(t, removed.isConnected())
+}
+
+func TestWebsocketConnection_ReconnectBackoff(t *testing.T) {
+	dials := 0
+	conn := &WebsocketConnection{
+		parentCtx:            context.Background(),
+		providerName:         ProviderMock,
+		reconnectMaxInterval: time.Minute,
+		dialer: func(context.Context, string) (*websocket.Conn, *http.Response, error) {
+			dials++
+			return nil, nil, fmt.Errorf("dial failed")
+		},
+	}
+
+	// each failed attempt doubles the interval, which is jittered by up to
+	// half of it, until it reaches the max interval
+	expected := []time.Duration{
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		time.Minute,
+		time.Minute,
+	}
+	for _, maxInterval := range expected {
+		require.Error(t, conn.connect())
+		interval := conn.nextReconnectInterval()
+		require.LessOrEqual(t, interval, maxInterval)
+		require.GreaterOrEqual(t, interval, maxInterval/2)
+	}
+	require.Equal(t, len(expected), dials)
+
+	// a short lived connection keeps backing off
+	conn.connectedAt = time.Now().Add(-10 * time.Second)
+	conn.disconnectedAt = time.Now()
+	require.GreaterOrEqual(t, conn.nextReconnectInterval(), 30*time.Second)
+
+	// a stable connection starts over from the base interval
+	conn.connectedAt = time.Now().Add(-stableConnectionDuration)
+	conn.disconnectedAt = time.Now()
+	require.LessOrEqual(t, conn.nextReconnectInterval(), startingReconnectDuration)
+	require.LessOrEqual(t, conn.nextReconnectInterval(), 10*time.Second)
+}
+
+func TestEndpoint_ReconnectMaxInterval(t *testing.T) {
+	require.Equal(t, defaultReconnectMaxInterval, Endpoint{}.reconnectMaxInterval())
+	require.Equal(t, 5*time.Minute, Endpoint{ReconnectMaxInterval: "5m"}.reconnectMaxInterval())
+	require.Equal(t, defaultReconnectMaxInterval, Endpoint{ReconnectMaxInterval: "foo"}.reconnectMaxInterval())
+}
+
+func TestWebsocketController_SetURLResolver(t *testing.T) {
+	dialedURLs := []string{}
+	c := &WebsocketController{
+		providerName: ProviderMock,
+		connections: []*WebsocketConnection{
+			{
+				parentCtx:    context.Background(),
+				providerName: ProviderMock,
+				websocketURL: url.URL{Scheme: "wss", Host: "expired"},
+				dialer: func(_ context.Context, urlStr string) (*websocket.Conn, *http.Response, error) {
+					dialedURLs = append(dialedURLs, urlStr)
+					return nil, nil, fmt.Errorf("dial failed")
+				},
+			},
+		},
+	}
+
+	c.SetURLResolver(func() (url.URL, error) {
+		return url.URL{Scheme: "wss", Host: "resolved", RawQuery: "token=token"}, nil
+	})
+	require.Error(t, c.connections[0].connect())
+	require.Equal(t, []string{"wss://resolved?token=token"}, dialedURLs)
+
+	// the connection is not dialed when the url can't be resolved
+	c.SetURLResolver(func() (url.URL, error) {
+		return url.URL{}, fmt.Errorf("bullet failed")
+	})
+	require.Error(t, c.connections[0].connect())
+	require.Len(t, dialedURLs, 1)
+}
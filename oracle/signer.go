@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"encoding/json"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// Signer defines the signer of the price snapshots published by the oracle,
+// which signs them with the price feeder's key.
+type Signer interface {
+	Sign(bz []byte) ([]byte, cryptotypes.PubKey, error)
+}
+
+// SetSigner sets the signer of the price snapshots. Once set, every price
+// computation signs its prices along with their timestamp and block height
+// once, and the signed snapshot is served by GetSignedPrices.
+func (o *Oracle) SetSigner(signer Signer) {
+	o.signer = signer
+}
+
+// GetSignedPrices returns the signed snapshot of the prices computed in the
+// last call to SetPrices, or nil if no signer is set or signing failed.
+func (o *Oracle) GetSignedPrices() *types.SignedPrices {
+	return o.signedPrices.Load()
+}
+
+// signPrices signs the JSON encoding of a snapshot of the given prices
+// computed at the given time and block height.
+func (o *Oracle) signPrices(
+	prices types.CurrencyPairDec,
+	timestamp time.Time,
+	height int64,
+) (*types.SignedPrices, error) {
+	bz, err := json.Marshal(types.PricesSnapshot{
+		Prices:    prices,
+		Timestamp: timestamp.UnixMilli(),
+		Height:    height,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signature, pubKey, err := o.signer.Sign(bz)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SignedPrices{
+		Snapshot:  bz,
+		Signature: signature,
+		PubKey:    pubKey.Bytes(),
+	}, nil
+}
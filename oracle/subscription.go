@@ -0,0 +1,53 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// priceSubscriptions holds the channels notified with the computed prices
+// every time a price computation completes.
+type priceSubscriptions struct {
+	mtx         sync.Mutex
+	subscribers map[chan types.CurrencyPairDec]struct{}
+}
+
+// SubscribePrices returns a channel receiving the computed prices every time a
+// price computation completes, and a function to cancel the subscription. A
+// subscriber which falls behind only receives the latest prices, so it never
+// blocks the price computation.
+func (o *Oracle) SubscribePrices() (<-chan types.CurrencyPairDec, func()) {
+	ch := make(chan types.CurrencyPairDec, 1)
+
+	o.priceSubscriptions.mtx.Lock()
+	if o.priceSubscriptions.subscribers == nil {
+		o.priceSubscriptions.subscribers = make(map[chan types.CurrencyPairDec]struct{})
+	}
+	o.priceSubscriptions.subscribers[ch] = struct{}{}
+	o.priceSubscriptions.mtx.Unlock()
+
+	unsubscribe := func() {
+		o.priceSubscriptions.mtx.Lock()
+		defer o.priceSubscriptions.mtx.Unlock()
+
+		delete(o.priceSubscriptions.subscribers, ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// notifyPriceSubscribers sends the prices to every subscriber, replacing any
+// prices it has not received yet. The prices must not be modified afterwards.
+func (o *Oracle) notifyPriceSubscribers(prices types.CurrencyPairDec) {
+	o.priceSubscriptions.mtx.Lock()
+	defer o.priceSubscriptions.mtx.Unlock()
+
+	for ch := range o.priceSubscriptions.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- prices
+	}
+}
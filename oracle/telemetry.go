@@ -0,0 +1,109 @@
+package oracle
+
+import (
+	"strconv"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/hashicorp/go-metrics"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// telemetryExchangeRate gives an standard way to add the
+// `price_feeder_exchange_rate{base="x"}` gauge of a computed price, and the
+// `price_feeder_exchange_rate_providers{base="x"}` gauge of the number of
+// providers which contributed to it.
+func telemetryExchangeRate(cp types.CurrencyPair, price math.LegacyDec, providers int) {
+	labels := []metrics.Label{
+		{
+			Name:  "base",
+			Value: cp.Base,
+		},
+	}
+
+	if value, err := price.Float64(); err == nil {
+		telemetry.SetGaugeWithLabels([]string{"exchange_rate"}, float32(value), labels)
+	}
+	telemetry.SetGaugeWithLabels([]string{"exchange_rate", "providers"}, float32(providers), labels)
+}
+
+// telemetryPriceDropped gives an standard way to add the
+// `price_feeder_price_dropped{base="x",reason="y"}` counter of a computed
+// price which was not reported.
+func telemetryPriceDropped(cp types.CurrencyPair, reason string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{"price", "dropped"},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "base",
+				Value: cp.Base,
+			},
+			{
+				Name:  "reason",
+				Value: reason,
+			},
+		},
+	)
+}
+
+// telemetryTxRejected gives an standard way to add the
+// `price_feeder_tx_rejected{codespace="x",code="y"}` counter of a broadcast
+// transaction rejected with a non-zero abci code.
+func telemetryTxRejected(codespace string, code uint32) {
+	telemetry.IncrCounterWithLabels(
+		[]string{"tx", "rejected"},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "codespace",
+				Value: codespace,
+			},
+			{
+				Name:  "code",
+				Value: strconv.FormatUint(uint64(code), 10),
+			},
+		},
+	)
+}
+
+// telemetryCircuitBreaker gives an standard way to add the
+// `price_feeder_provider_circuit_breaker{provider="x",state="y"}` counter of
+// the circuit of a provider opening or closing.
+func telemetryCircuitBreaker(providerName types.ProviderName, state string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{"provider", "circuit_breaker"},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "provider",
+				Value: providerName.String(),
+			},
+			{
+				Name:  "state",
+				Value: state,
+			},
+		},
+	)
+}
+
+// telemetryDeviationFiltered gives an standard way to add the
+// `price_feeder_deviation_filtered{provider="x",base="y"}` counter of a
+// provider price filtered out for deviating from the other providers.
+func telemetryDeviationFiltered(providerName types.ProviderName, cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{"deviation", "filtered"},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "provider",
+				Value: providerName.String(),
+			},
+			{
+				Name:  "base",
+				Value: cp.Base,
+			},
+		},
+	)
+}
@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// adaptiveTimeoutPercentile is the percentile of the observed latencies of a
+// provider used to compute its effective timeout.
+const adaptiveTimeoutPercentile = 0.95
+
+// AdaptiveTimeout tracks the recent fetch latencies of each provider and
+// computes an effective timeout per provider as the p95 of its latencies plus
+// a margin, bounded by a minimum and maximum timeout.
+type AdaptiveTimeout struct {
+	window     int
+	margin     time.Duration
+	minTimeout time.Duration
+	maxTimeout time.Duration
+
+	mtx       sync.RWMutex
+	latencies map[types.ProviderName][]time.Duration
+}
+
+// NewAdaptiveTimeout returns a new AdaptiveTimeout which keeps the last window
+// latencies of each provider.
+func NewAdaptiveTimeout(window int, margin, minTimeout, maxTimeout time.Duration) *AdaptiveTimeout {
+	return &AdaptiveTimeout{
+		window:     window,
+		margin:     margin,
+		minTimeout: minTimeout,
+		maxTimeout: maxTimeout,
+		latencies:  make(map[types.ProviderName][]time.Duration),
+	}
+}
+
+// Observe records a fetch latency for the given provider, discarding the
+// oldest latency once the window is full.
+func (at *AdaptiveTimeout) Observe(providerName types.ProviderName, latency time.Duration) {
+	at.mtx.Lock()
+	defer at.mtx.Unlock()
+
+	latencies := append(at.latencies[providerName], latency)
+	if len(latencies) > at.window {
+		latencies = latencies[len(latencies)-at.window:]
+	}
+	at.latencies[providerName] = latencies
+}
+
+// Timeout returns the effective timeout of the given provider. The maximum
+// timeout is returned until a latency has been observed for the provider.
+func (at *AdaptiveTimeout) Timeout(providerName types.ProviderName) time.Duration {
+	at.mtx.RLock()
+	defer at.mtx.RUnlock()
+
+	latencies := at.latencies[providerName]
+	if len(latencies) == 0 {
+		return at.maxTimeout
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	index := int(math.Ceil(adaptiveTimeoutPercentile*float64(len(sorted)))) - 1
+	timeout := sorted[index] + at.margin
+
+	if timeout < at.minTimeout {
+		return at.minTimeout
+	}
+	if timeout > at.maxTimeout {
+		return at.maxTimeout
+	}
+	return timeout
+}
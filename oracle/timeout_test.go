@@ -0,0 +1,44 @@
+package oracle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+)
+
+func TestAdaptiveTimeout(t *testing.T) {
+	at := oracle.NewAdaptiveTimeout(100, 10*time.Millisecond, 50*time.Millisecond, time.Second)
+
+	// no latency history uses the maximum timeout
+	require.Equal(t, time.Second, at.Timeout(provider.ProviderBinance))
+
+	// latencies of 1ms to 100ms have a p95 of 95ms
+	for i := 1; i <= 100; i++ {
+		at.Observe(provider.ProviderBinance, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 105*time.Millisecond, at.Timeout(provider.ProviderBinance))
+
+	// the window only keeps the last 100 latencies, so a slower provider
+	// pushes the p95 up: 51ms to 100ms and 50 latencies of 200ms
+	for i := 0; i < 50; i++ {
+		at.Observe(provider.ProviderBinance, 200*time.Millisecond)
+	}
+	require.Equal(t, 210*time.Millisecond, at.Timeout(provider.ProviderBinance))
+
+	// latencies are tracked per provider
+	require.Equal(t, time.Second, at.Timeout(provider.ProviderKraken))
+}
+
+func TestAdaptiveTimeoutBounds(t *testing.T) {
+	at := oracle.NewAdaptiveTimeout(10, 10*time.Millisecond, 50*time.Millisecond, 500*time.Millisecond)
+
+	at.Observe(provider.ProviderBinance, time.Millisecond)
+	require.Equal(t, 50*time.Millisecond, at.Timeout(provider.ProviderBinance))
+
+	at.Observe(provider.ProviderKraken, 2*time.Second)
+	require.Equal(t, 500*time.Millisecond, at.Timeout(provider.ProviderKraken))
+}
@@ -0,0 +1,23 @@
+package types
+
+// AggregationMethod defines how the prices of the providers of a currency
+// pair are combined into a single price.
+type AggregationMethod string
+
+const (
+	// AggregationMethodVWAP computes the volume weighted average of the
+	// provider prices, using a TVWAP for candles. This is the default method.
+	AggregationMethodVWAP AggregationMethod = "vwap"
+
+	// AggregationMethodMedian computes the median of the per-provider VWAPs,
+	// or TVWAPs for candles, so each provider counts once regardless of its
+	// volume.
+	AggregationMethodMedian AggregationMethod = "median"
+)
+
+// SupportedAggregationMethods defines a lookup table of all the supported
+// aggregation methods.
+var SupportedAggregationMethods = map[AggregationMethod]struct{}{
+	AggregationMethodVWAP:   {},
+	AggregationMethodMedian: {},
+}
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// AlertEventType defines the kind of critical event an alert is sent for.
+type AlertEventType string
+
+const (
+	// AlertEventMissedVote is sent when the oracle missed a vote.
+	AlertEventMissedVote AlertEventType = "missed_vote"
+
+	// AlertEventCircuitOpened is sent when the circuit of a provider opened
+	// after it failed repeatedly.
+	AlertEventCircuitOpened AlertEventType = "circuit_opened"
+
+	// AlertEventAllProvidersDown is sent when no provider returned any price
+	// during a tick.
+	AlertEventAllProvidersDown AlertEventType = "all_providers_down"
+
+	// AlertEventLowBalance is sent when the balance of the feeder account
+	// fell below the minimum balance.
+	AlertEventLowBalance AlertEventType = "low_balance"
+)
+
+// SupportedAlertEvents defines the alert events which can be filtered on.
+var SupportedAlertEvents = map[AlertEventType]struct{}{
+	AlertEventMissedVote:       {},
+	AlertEventCircuitOpened:    {},
+	AlertEventAllProvidersDown: {},
+	AlertEventLowBalance:       {},
+}
+
+// AlertEvent defines the payload of an alert about a critical event.
+type AlertEvent struct {
+	Type      AlertEventType `json:"type"`
+	Message   string         `json:"message"`
+	Height    int64          `json:"height,omitempty"`
+	Provider  ProviderName   `json:"provider,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import "cosmossdk.io/math"
+
+type (
+	// VoteAudit defines the per-provider breakdown of the prices of a vote,
+	// recorded alongside the vote for off-chain audit.
+	VoteAudit struct {
+		ExchangeRates string       `json:"exchange_rates"`
+		Assets        []AssetAudit `json:"assets"`
+	}
+
+	// AssetAudit defines the voted price of a single base currency and the
+	// providers which contributed to it.
+	AssetAudit struct {
+		Base      string               `json:"base"`
+		Price     math.LegacyDec       `json:"price"`
+		Providers []ProviderPriceAudit `json:"providers"`
+	}
+
+	// ProviderPriceAudit defines the USD price a provider contributed to a
+	// voted price, and whether it was taken from its candles or its ticker.
+	ProviderPriceAudit struct {
+		Provider ProviderName   `json:"provider"`
+		Pair     CurrencyPair   `json:"pair"`
+		Source   string         `json:"source"`
+		Price    math.LegacyDec `json:"price"`
+	}
+)
@@ -27,3 +27,20 @@ func NewCandlePrice(lastPrice, volume string, timeStamp int64) (CandlePrice, err
 
 	return CandlePrice{Price: price, Volume: volumeDec, TimeStamp: timeStamp}, nil
 }
+
+// LatestCandle returns the candle with the most recent timestamp, regardless
+// of its position in the given slice, since providers may order candles either
+// oldest-first or newest-first.
+func LatestCandle(candles []CandlePrice) (CandlePrice, bool) {
+	if len(candles) == 0 {
+		return CandlePrice{}, false
+	}
+
+	latest := candles[0]
+	for _, candle := range candles[1:] {
+		if candle.TimeStamp > latest.TimeStamp {
+			latest = candle
+		}
+	}
+	return latest, true
+}
@@ -35,3 +35,25 @@ func TestNewCandlePrice(t *testing.T) {
 		require.NotNil(t, err, "expected the returned error to not be nil")
 	})
 }
+
+func TestLatestCandle(t *testing.T) {
+	_, ok := LatestCandle(nil)
+	require.False(t, ok)
+
+	newest := CandlePrice{Price: math.LegacyNewDec(3), Volume: math.LegacyOneDec(), TimeStamp: 3000}
+	candles := []CandlePrice{
+		newest,
+		{Price: math.LegacyNewDec(2), Volume: math.LegacyOneDec(), TimeStamp: 2000},
+		{Price: math.LegacyNewDec(1), Volume: math.LegacyOneDec(), TimeStamp: 1000},
+	}
+
+	latest, ok := LatestCandle(candles)
+	require.True(t, ok)
+	require.Equal(t, newest, latest)
+
+	// reversing the order of the candles selects the same candle
+	reversed := []CandlePrice{candles[2], candles[1], candles[0]}
+	latest, ok = LatestCandle(reversed)
+	require.True(t, ok)
+	require.Equal(t, newest, latest)
+}
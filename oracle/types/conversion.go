@@ -0,0 +1,19 @@
+package types
+
+import "cosmossdk.io/math"
+
+type (
+	// ConversionStep defines a single conversion applied to a price, where the
+	// price is multiplied by the rate of the given currency pair.
+	ConversionStep struct {
+		Pair CurrencyPair   `json:"pair"`
+		Rate math.LegacyDec `json:"rate"`
+	}
+
+	// ConversionRoute defines the chain of conversions applied to convert a
+	// price to USD, in the order they were applied.
+	ConversionRoute []ConversionStep
+
+	// CurrencyPairConversionRoutes is a map of ConversionRoute by CurrencyPair
+	CurrencyPairConversionRoutes map[CurrencyPair]ConversionRoute
+)
@@ -1,6 +1,10 @@
 package types
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // CurrencyPair defines a currency exchange pair consisting of a base and a quote.
 // We primarily utilize the base for broadcasting exchange rates and use the
@@ -39,3 +43,16 @@ func (cp *CurrencyPair) UnmarshalText(text []byte) error {
 	type noMethod CurrencyPair
 	return json.Unmarshal(text, (*noMethod)(cp))
 }
+
+// ParseCurrencyPair parses a currency pair in the "BASE/QUOTE" format,
+// ex. "ATOM/USDT".
+func ParseCurrencyPair(s string) (CurrencyPair, error) {
+	base, quote, ok := strings.Cut(s, "/")
+	if !ok || base == "" || quote == "" {
+		return CurrencyPair{}, fmt.Errorf("invalid currency pair %q, expected BASE/QUOTE", s)
+	}
+	return CurrencyPair{
+		Base:  strings.ToUpper(base),
+		Quote: strings.ToUpper(quote),
+	}, nil
+}
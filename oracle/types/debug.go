@@ -0,0 +1,30 @@
+package types
+
+import "cosmossdk.io/math"
+
+type (
+	// PriceDebug defines the inputs and intermediate results of the last price
+	// computation for a single base currency, used for forensic analysis of a
+	// computed price.
+	PriceDebug struct {
+		Base      string               `json:"base"`
+		Providers []ProviderPriceDebug `json:"providers"`
+		TVWAP     *math.LegacyDec      `json:"tvwap,omitempty"`
+		VWAP      *math.LegacyDec      `json:"vwap,omitempty"`
+		Price     *math.LegacyDec      `json:"price,omitempty"`
+	}
+
+	// ProviderPriceDebug defines the raw ticker and latest candle a provider
+	// reported for a currency pair, their values converted to USD, and the
+	// reason they were filtered out of the price computation, if any.
+	ProviderPriceDebug struct {
+		Provider           ProviderName `json:"provider"`
+		Pair               CurrencyPair `json:"pair"`
+		Ticker             *TickerPrice `json:"ticker,omitempty"`
+		Candle             *CandlePrice `json:"candle,omitempty"`
+		ConvertedTicker    *TickerPrice `json:"converted_ticker,omitempty"`
+		ConvertedCandle    *CandlePrice `json:"converted_candle,omitempty"`
+		TickerFilterReason string       `json:"ticker_filter_reason,omitempty"`
+		CandleFilterReason string       `json:"candle_filter_reason,omitempty"`
+	}
+)
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// MissedVoteReason defines why the oracle missed a vote.
+type MissedVoteReason string
+
+const (
+	// MissedVoteReasonPriceFailure is recorded when the prices could not be
+	// set before voting.
+	MissedVoteReasonPriceFailure MissedVoteReason = "price_failure"
+
+	// MissedVoteReasonVotePeriodMissed is recorded when the vote of a prevote
+	// was not broadcast within the following vote period.
+	MissedVoteReasonVotePeriodMissed MissedVoteReason = "vote_period_missed"
+
+	// MissedVoteReasonBroadcastFailure is recorded when broadcasting a prevote
+	// or a vote failed.
+	MissedVoteReasonBroadcastFailure MissedVoteReason = "broadcast_failure"
+
+	// MissedVoteReasonEmptyPrices is recorded when a vote was broadcast without
+	// any prices.
+	MissedVoteReasonEmptyPrices MissedVoteReason = "empty_prices"
+
+	// MissedVoteReasonVotingPaused is recorded when a vote was due while
+	// voting was paused, such as during maintenance.
+	MissedVoteReasonVotingPaused MissedVoteReason = "voting_paused"
+
+	// MissedVoteReasonCircuitOpen is recorded when the prices could not be set
+	// because the circuit of every provider was open.
+	MissedVoteReasonCircuitOpen MissedVoteReason = "circuit_open"
+)
+
+// MissedVote defines a missed vote along with the block height at which it
+// was missed and why.
+type MissedVote struct {
+	Height    int64            `json:"height"`
+	Reason    MissedVoteReason `json:"reason"`
+	Error     string           `json:"error,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"sync"
+	"time"
 
 	"cosmossdk.io/math"
 )
@@ -17,6 +18,30 @@ type (
 		mx     sync.RWMutex
 	}
 
+	// TimestampedPrice defines a computed price and the time it was computed at.
+	TimestampedPrice struct {
+		Price     math.LegacyDec
+		Timestamp time.Time
+	}
+
+	// PriceBand defines the absolute range, in USD, the price of an asset must
+	// be within to be used.
+	PriceBand struct {
+		Min math.LegacyDec
+		Max math.LegacyDec
+	}
+
+	// TrustedProvider defines a highly trusted provider of a currency pair,
+	// whose price the prices of the other providers of the pair must be within
+	// MaxDeviationPct percent of to be used.
+	TrustedProvider struct {
+		Provider        ProviderName
+		MaxDeviationPct math.LegacyDec
+	}
+
+	// CurrencyPairTimestampedPrices is a map of TimestampedPrice by CurrencyPair
+	CurrencyPairTimestampedPrices map[CurrencyPair]TimestampedPrice
+
 	// CurrencyPairDec is a map of sdk.Dec by CurrencyPair
 	CurrencyPairDec map[CurrencyPair]math.LegacyDec
 
@@ -71,3 +96,8 @@ func (pwm *PricesWithMutex) clonePrices() CurrencyPairDecByProvider {
 func (n ProviderName) String() string {
 	return string(n)
 }
+
+// Contains returns whether the price is within the band, bounds included.
+func (pb PriceBand) Contains(price math.LegacyDec) bool {
+	return price.GTE(pb.Min) && price.LTE(pb.Max)
+}
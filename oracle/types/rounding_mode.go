@@ -0,0 +1,21 @@
+package types
+
+// RoundingMode defines how prices are rounded to the vote precision.
+type RoundingMode string
+
+const (
+	// RoundingModeHalfUp rounds prices to the nearest value, rounding halves
+	// away from zero. This is the default mode.
+	RoundingModeHalfUp RoundingMode = "half_up"
+
+	// RoundingModeTruncate truncates the digits of prices beyond the vote
+	// precision.
+	RoundingModeTruncate RoundingMode = "truncate"
+)
+
+// SupportedRoundingModes defines a lookup table of all the supported rounding
+// modes.
+var SupportedRoundingModes = map[RoundingMode]struct{}{
+	RoundingModeHalfUp:   {},
+	RoundingModeTruncate: {},
+}
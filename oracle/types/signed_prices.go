@@ -0,0 +1,23 @@
+package types
+
+import "encoding/json"
+
+type (
+	// PricesSnapshot defines the prices computed in a price computation along
+	// with when and at which block height they were computed, which is the
+	// payload signed by the price feeder.
+	PricesSnapshot struct {
+		Prices    CurrencyPairDec `json:"prices"`
+		Timestamp int64           `json:"timestamp"`
+		Height    int64           `json:"height"`
+	}
+
+	// SignedPrices defines a JSON encoded PricesSnapshot along with its
+	// signature by the price feeder's key and the public key to verify it
+	// with. The signature is over the exact bytes of Snapshot.
+	SignedPrices struct {
+		Snapshot  json.RawMessage `json:"snapshot"`
+		Signature []byte          `json:"signature"`
+		PubKey    []byte          `json:"pub_key"`
+	}
+)
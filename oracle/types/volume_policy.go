@@ -0,0 +1,27 @@
+package types
+
+// TickerVolumePolicy defines how tickers with a volume below the minimum
+// ticker volume are weighted when computing a VWAP.
+type TickerVolumePolicy string
+
+const (
+	// TickerVolumePolicyFloor raises the volume of the ticker to the minimum
+	// ticker volume. This is the default policy.
+	TickerVolumePolicyFloor TickerVolumePolicy = "floor"
+
+	// TickerVolumePolicyExclude excludes the ticker from the VWAP.
+	TickerVolumePolicyExclude TickerVolumePolicy = "exclude"
+
+	// TickerVolumePolicyEqualWeight weights the ticker with the average volume
+	// of the other tickers of the same pair, or equally to the other tickers
+	// below the minimum volume if there are no others.
+	TickerVolumePolicyEqualWeight TickerVolumePolicy = "equal_weight"
+)
+
+// SupportedTickerVolumePolicies defines a lookup table of all the supported
+// ticker volume policies.
+var SupportedTickerVolumePolicies = map[TickerVolumePolicy]struct{}{
+	TickerVolumePolicyFloor:       {},
+	TickerVolumePolicyExclude:     {},
+	TickerVolumePolicyEqualWeight: {},
+}
@@ -1,8 +1,10 @@
 package oracle
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"cosmossdk.io/math"
@@ -19,8 +21,9 @@ var (
 )
 
 const (
-	// tvwapCandlePeriod represents the time period we use for tvwap in minutes
-	tvwapCandlePeriod = 10 * time.Minute
+	// defaultTVWAPLookback represents the time period we use for tvwap in
+	// minutes, for the assets without a configured lookback.
+	defaultTVWAPLookback = 10 * time.Minute
 )
 
 // compute VWAP for each base by dividing the Σ {P * V} by Σ {V}
@@ -42,16 +45,31 @@ func vwap(weightedPrices, volumeSum types.CurrencyPairDec) types.CurrencyPairDec
 
 // ComputeVWAP computes the volume weighted average price for all price points
 // for each ticker/exchange pair. The provided prices argument reflects a mapping
-// of provider => {<base> => <TickerPrice>, ...}.
+// of provider => {<base> => <TickerPrice>, ...}. Tickers with a volume below
+// minimumTickerVolume are weighted according to the given policy. The volume of
+// each provider is scaled by its weight, defaulting to one, and the providers
+// with a zero weight are excluded.
 //
 // Ref: https://en.wikipedia.org/wiki/Volume-weighted_average_price
-func ComputeVWAP(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
+func ComputeVWAP(
+	prices types.AggregatedProviderPrices,
+	policy types.TickerVolumePolicy,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+) types.CurrencyPairDec {
 	var (
-		weightedPrices = make(types.CurrencyPairDec)
-		volumeSum      = make(types.CurrencyPairDec)
+		weightedPrices   = make(types.CurrencyPairDec)
+		volumeSum        = make(types.CurrencyPairDec)
+		tickerCount      = make(map[types.CurrencyPair]int64)
+		lowVolumePrices  = make(map[types.CurrencyPair][]math.LegacyDec)
+		lowVolumeWeights = make(map[types.CurrencyPair][]math.LegacyDec)
 	)
 
-	for _, providerPrices := range prices {
+	for providerName, providerPrices := range prices {
+		weight := providerWeight(providerName, providerWeights)
+		if weight.IsZero() {
+			continue
+		}
+
 		for base, tp := range providerPrices {
 			if _, ok := weightedPrices[base]; !ok {
 				weightedPrices[base] = math.LegacyZeroDec()
@@ -60,32 +78,107 @@ func ComputeVWAP(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
 				volumeSum[base] = math.LegacyZeroDec()
 			}
 			if tp.Volume.LT(minimumTickerVolume) {
-				tp.Volume = minimumTickerVolume
+				switch policy {
+				case types.TickerVolumePolicyExclude:
+					continue
+				case types.TickerVolumePolicyEqualWeight:
+					lowVolumePrices[base] = append(lowVolumePrices[base], tp.Price)
+					lowVolumeWeights[base] = append(lowVolumeWeights[base], weight)
+					continue
+				default:
+					tp.Volume = minimumTickerVolume
+				}
 			}
+			tp.Volume = tp.Volume.Mul(weight)
 
 			// weightedPrices[base] = Σ {P * V} for all TickerPrice
 			weightedPrices[base] = weightedPrices[base].Add(tp.Price.Mul(tp.Volume))
 
 			// track total volume for each base
 			volumeSum[base] = volumeSum[base].Add(tp.Volume)
+			tickerCount[base]++
+		}
+	}
+
+	// weight tickers below the minimum volume with the average volume of the
+	// other tickers, or equally if there are no other tickers, scaled by the
+	// weight of their provider
+	for base, lowPrices := range lowVolumePrices {
+		averageVolume := math.LegacyOneDec()
+		if tickerCount[base] > 0 {
+			averageVolume = volumeSum[base].QuoInt64(tickerCount[base])
+		}
+
+		for i, price := range lowPrices {
+			weight := averageVolume.Mul(lowVolumeWeights[base][i])
+			weightedPrices[base] = weightedPrices[base].Add(price.Mul(weight))
+			volumeSum[base] = volumeSum[base].Add(weight)
 		}
 	}
 
 	return vwap(weightedPrices, volumeSum)
 }
 
+// providerWeight returns the weight of the provider's volume, or one if it has
+// none.
+func providerWeight(
+	providerName types.ProviderName,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+) math.LegacyDec {
+	if w, ok := providerWeights[providerName]; ok {
+		return w
+	}
+	return math.LegacyOneDec()
+}
+
+// weightCandleVolumes returns a copy of the candles whose volumes are scaled
+// by the weight of their provider, defaulting to one, so the providers are
+// weighted in the TVWAP like in the VWAP. The candles without a volume are
+// given the minimum candle volume before being scaled.
+func weightCandleVolumes(
+	candles types.AggregatedProviderCandles,
+	providerWeights map[types.ProviderName]math.LegacyDec,
+) types.AggregatedProviderCandles {
+	weightedCandles := make(types.AggregatedProviderCandles, len(candles))
+	for providerName, cpCandles := range candles {
+		weight := providerWeight(providerName, providerWeights)
+		weightedCandles[providerName] = make(types.CurrencyPairCandles, len(cpCandles))
+		for cp, candlePrices := range cpCandles {
+			weighted := make([]types.CandlePrice, len(candlePrices))
+			for i, candle := range candlePrices {
+				if candle.Volume.IsZero() {
+					candle.Volume = minimumCandleVolume
+				}
+				candle.Volume = candle.Volume.Mul(weight)
+				weighted[i] = candle
+			}
+			weightedCandles[providerName][cp] = weighted
+		}
+	}
+	return weightedCandles
+}
+
 // ComputeTVWAP computes the time volume weighted average price for all points
 // for each exchange pair. Filters out any candles that did not occur within
 // timePeriod. The provided prices argument reflects a mapping of
-// provider => {<base> => <TickerPrice>, ...}.
+// provider => {<base> => <TickerPrice>, ...}. Only the candles within the
+// lookback of each base are weighted, defaulting to 10 minutes. The period
+// each provider's candles are weighted over is at least minPeriod, so a single
+// fresh candle's near-zero period doesn't inflate the weight unit of its
+// provider. The candles in progress, dated at their close less than
+// futureCandleTolerance from now, are weighted as of now.
 //
 // Ref : https://en.wikipedia.org/wiki/Time-weighted_average_price
-func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec, error) {
+func ComputeTVWAP(
+	prices types.AggregatedProviderCandles,
+	lookbacks map[string]time.Duration,
+	minPeriod time.Duration,
+) (types.CurrencyPairDec, error) {
 	var (
 		weightedPrices = make(types.CurrencyPairDec)
 		volumeSum      = make(types.CurrencyPairDec)
-		now            = provider.PastUnixTime(0)
-		timePeriod     = provider.PastUnixTime(tvwapCandlePeriod)
+		now            = provider.PastUnixTimeMillis(0)
+		periodFloor    = math.LegacyNewDec(minPeriod.Milliseconds())
 	)
 
 	for _, providerPrices := range prices {
@@ -107,9 +200,17 @@ func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec
 				return cp[i].TimeStamp < cp[j].TimeStamp
 			})
 
-			period := math.LegacyNewDec(now - cp[0].TimeStamp)
+			timePeriod := provider.PastUnixTimeMillis(tvwapLookback(base.Base, lookbacks))
+
+			// the candles in progress, dated at their close within the future
+			// candle tolerance, are weighted as of now
+			period := math.LegacyNewDec(now - min(cp[0].TimeStamp, now))
+			if minPeriod > 0 {
+				period = math.LegacyMaxDec(period, periodFloor)
+			}
 			if period.Equal(math.LegacyZeroDec()) {
-				return nil, fmt.Errorf("unable to divide by zero")
+				// a single candle dated now is weighted fully
+				period = math.LegacyOneDec()
 			}
 			// weightUnit = (1 - minimumTimeWeight) / period
 			weightUnit := math.LegacyOneDec().Sub(minimumTimeWeight).Quo(period)
@@ -117,9 +218,9 @@ func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec
 			// get weighted prices, and sum of volumes
 			for _, candle := range cp {
 				// we only want candles within the last timePeriod
-				if timePeriod < candle.TimeStamp && candle.TimeStamp <= now {
+				if timePeriod < candle.TimeStamp && candle.TimeStamp <= now+futureCandleTolerance.Milliseconds() {
 					// timeDiff = now - candle.TimeStamp
-					timeDiff := math.LegacyNewDec(now - candle.TimeStamp)
+					timeDiff := math.LegacyNewDec(now - min(candle.TimeStamp, now))
 					// set minimum candle volume for low-trading assets
 					if candle.Volume.Equal(math.LegacyZeroDec()) {
 						candle.Volume = minimumCandleVolume
@@ -140,8 +241,76 @@ func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec
 	return vwap(weightedPrices, volumeSum), nil
 }
 
+// FillCandleGaps returns the candles of each provider with filler candles
+// inserted into the gaps between them within the TVWAP lookback of their base,
+// every interval after the candle preceding each gap. The interval is raised to
+// the candle interval of the provider, if longer, so consecutive candles of a
+// provider with coarser candles are not taken for gaps. Filler candles carry
+// the price of the candle preceding the gap at the minimum candle volume, so
+// the candles form a continuous series without adding weight to the price.
+func FillCandleGaps(
+	candles types.AggregatedProviderCandles,
+	lookbacks map[string]time.Duration,
+	interval time.Duration,
+	candleIntervals map[types.ProviderName]time.Duration,
+) types.AggregatedProviderCandles {
+	filledCandles := make(types.AggregatedProviderCandles, len(candles))
+
+	for providerName, priceCandles := range candles {
+		step := interval.Milliseconds()
+		if candleInterval := candleIntervals[providerName].Milliseconds(); candleInterval > step {
+			step = candleInterval
+		}
+
+		filledCandles[providerName] = make(types.CurrencyPairCandles, len(priceCandles))
+		for cp, candlePrices := range priceCandles {
+			timePeriod := provider.PastUnixTimeMillis(tvwapLookback(cp.Base, lookbacks))
+			sorted := make([]types.CandlePrice, len(candlePrices))
+			copy(sorted, candlePrices)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				return sorted[i].TimeStamp < sorted[j].TimeStamp
+			})
+
+			filled := make([]types.CandlePrice, 0, len(sorted))
+			for i, candle := range sorted {
+				if i > 0 && step > 0 {
+					previous := sorted[i-1]
+					start := previous.TimeStamp + step
+					if start <= timePeriod {
+						// only fill within the TVWAP period
+						start += ((timePeriod-start)/step + 1) * step
+					}
+					for ts := start; ts < candle.TimeStamp; ts += step {
+						filled = append(filled, types.CandlePrice{
+							Price:     previous.Price,
+							Volume:    minimumCandleVolume,
+							TimeStamp: ts,
+						})
+					}
+				}
+				filled = append(filled, candle)
+			}
+			filledCandles[providerName][cp] = filled
+		}
+	}
+
+	return filledCandles
+}
+
+// tvwapLookback returns the TVWAP lookback of the base, or the default
+// lookback if none is set.
+func tvwapLookback(base string, lookbacks map[string]time.Duration) time.Duration {
+	if lookback, ok := lookbacks[base]; ok && lookback > 0 {
+		return lookback
+	}
+	return defaultTVWAPLookback
+}
+
 // StandardDeviation returns maps of the standard deviations and means of assets.
-// Will skip calculating for an asset if there are less than 3 prices.
+// Will skip calculating for an asset if there are less than 3 prices. An asset
+// whose standard deviation fails to compute is left out of the maps, so it
+// isn't filtered, and its error is joined into the returned error while the
+// other assets are still computed.
 func StandardDeviation(
 	prices types.CurrencyPairDecByProvider,
 ) (types.CurrencyPairDec, types.CurrencyPairDec, error) {
@@ -149,66 +318,81 @@ func StandardDeviation(
 		deviations = make(types.CurrencyPairDec)
 		means      = make(types.CurrencyPairDec)
 		priceSlice = make(map[types.CurrencyPair][]math.LegacyDec)
-		priceSums  = make(types.CurrencyPairDec)
+		errs       []error
 	)
 
 	for _, providerPrices := range prices {
 		for base, p := range providerPrices {
-			if _, ok := priceSums[base]; !ok {
-				priceSums[base] = math.LegacyZeroDec()
-			}
-			if _, ok := priceSlice[base]; !ok {
-				priceSlice[base] = []math.LegacyDec{}
-			}
-
-			priceSums[base] = priceSums[base].Add(p)
 			priceSlice[base] = append(priceSlice[base], p)
 		}
 	}
 
-	for base, sum := range priceSums {
+	for base, assetPrices := range priceSlice {
 		// Skip if standard deviation would not be meaningful
-		if len(priceSlice[base]) < 3 {
+		if len(assetPrices) < 3 {
 			continue
 		}
-		if _, ok := deviations[base]; !ok {
-			deviations[base] = math.LegacyZeroDec()
-		}
-		if _, ok := means[base]; !ok {
-			means[base] = math.LegacyZeroDec()
+
+		deviation, mean, err := standardDeviation(assetPrices)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to compute standard deviation of %s: %w", base, err))
+			continue
 		}
 
-		numPrices := int64(len(priceSlice[base]))
-		means[base] = sum.QuoInt64(numPrices)
-		varianceSum := math.LegacyZeroDec()
+		deviations[base] = deviation
+		means[base] = mean
+	}
+
+	return deviations, means, errors.Join(errs...)
+}
 
-		for _, price := range priceSlice[base] {
-			deviation := price.Sub(means[base])
-			varianceSum = varianceSum.Add(deviation.Mul(deviation))
+// standardDeviation returns the standard deviation and mean of the prices.
+// Arithmetic overflows panic in LegacyDec, so they are recovered and returned
+// as errors to keep them isolated to the asset.
+func standardDeviation(prices []math.LegacyDec) (deviation, mean math.LegacyDec, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
 		}
+	}()
 
-		variance := varianceSum.QuoInt64(numPrices)
+	sum := math.LegacyZeroDec()
+	for _, price := range prices {
+		sum = sum.Add(price)
+	}
 
-		standardDeviation, err := variance.ApproxSqrt()
-		if err != nil {
-			return make(types.CurrencyPairDec), make(types.CurrencyPairDec), err
-		}
+	numPrices := int64(len(prices))
+	mean = sum.QuoInt64(numPrices)
+	varianceSum := math.LegacyZeroDec()
 
-		deviations[base] = standardDeviation
+	for _, price := range prices {
+		diff := price.Sub(mean)
+		varianceSum = varianceSum.Add(diff.Mul(diff))
 	}
 
-	return deviations, means, nil
+	variance := varianceSum.QuoInt64(numPrices)
+
+	deviation, err = variance.ApproxSqrt()
+	if err != nil {
+		return math.LegacyDec{}, math.LegacyDec{}, err
+	}
+
+	return deviation, mean, nil
 }
 
 // ComputeTvwapsByProvider computes the tvwap prices from candles for each provider separately and returns them
 // in a map separated by provider name
-func ComputeTvwapsByProvider(prices types.AggregatedProviderCandles) (types.CurrencyPairDecByProvider, error) {
+func ComputeTvwapsByProvider(
+	prices types.AggregatedProviderCandles,
+	lookbacks map[string]time.Duration,
+	minPeriod time.Duration,
+) (types.CurrencyPairDecByProvider, error) {
 	tvwaps := make(types.CurrencyPairDecByProvider)
 	var err error
 
 	for providerName, candles := range prices {
 		singleProviderCandles := types.AggregatedProviderCandles{"providerName": candles}
-		tvwaps[providerName], err = ComputeTVWAP(singleProviderCandles)
+		tvwaps[providerName], err = ComputeTVWAP(singleProviderCandles, lookbacks, minPeriod)
 		if err != nil {
 			return nil, err
 		}
@@ -218,16 +402,209 @@ func ComputeTvwapsByProvider(prices types.AggregatedProviderCandles) (types.Curr
 
 // ComputeVwapsByProvider computes the vwap prices from tickers for each provider separately and returns them
 // in a map separated by provider name
-func ComputeVwapsByProvider(prices types.AggregatedProviderPrices) types.CurrencyPairDecByProvider {
+func ComputeVwapsByProvider(
+	prices types.AggregatedProviderPrices,
+	policy types.TickerVolumePolicy,
+) types.CurrencyPairDecByProvider {
 	vwaps := make(types.CurrencyPairDecByProvider)
 
 	for providerName, tickers := range prices {
 		singleProviderCandles := types.AggregatedProviderPrices{"providerName": tickers}
-		vwaps[providerName] = ComputeVWAP(singleProviderCandles)
+		vwaps[providerName] = ComputeVWAP(singleProviderCandles, policy, nil)
 	}
 	return vwaps
 }
 
+// ComputeTotalVolumes sums by base currency the volumes of the providers which
+// contributed to the price of each pair, i.e. whose ticker or candles of the
+// pair were not filtered out according to the given record. A provider counts
+// with the volume of its ticker, or with the volume of its candles if it only
+// supplies candles. The base currencies are uppercased.
+func ComputeTotalVolumes(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	record *filterRecord,
+) map[string]math.LegacyDec {
+	providerVolumes := make(map[types.ProviderName]map[types.CurrencyPair]math.LegacyDec)
+	setVolume := func(providerName types.ProviderName, cp types.CurrencyPair, volume math.LegacyDec) {
+		if _, ok := providerVolumes[providerName]; !ok {
+			providerVolumes[providerName] = make(map[types.CurrencyPair]math.LegacyDec)
+		}
+		providerVolumes[providerName][cp] = volume
+	}
+
+	for providerName, cpCandles := range candles {
+		for cp, cpCandle := range cpCandles {
+			if record.candleReason(providerName, cp) != "" {
+				continue
+			}
+			volume := math.LegacyZeroDec()
+			for _, candle := range cpCandle {
+				volume = volume.Add(candle.Volume)
+			}
+			setVolume(providerName, cp, volume)
+		}
+	}
+	for providerName, cpTickers := range tickers {
+		for cp, ticker := range cpTickers {
+			_, contributed := providerVolumes[providerName][cp]
+			reason := record.tickerReason(providerName, cp)
+			if contributed || reason == "" || reason == filterReasonTVWAPAvailable {
+				setVolume(providerName, cp, ticker.Volume)
+			}
+		}
+	}
+
+	totalVolumes := make(map[string]math.LegacyDec)
+	for _, cpVolumes := range providerVolumes {
+		for cp, volume := range cpVolumes {
+			base := strings.ToUpper(cp.Base)
+			if _, ok := totalVolumes[base]; !ok {
+				totalVolumes[base] = math.LegacyZeroDec()
+			}
+			totalVolumes[base] = totalVolumes[base].Add(volume)
+		}
+	}
+	return totalVolumes
+}
+
+// ComputeProviderDivergences computes the percentage deviation of each
+// provider's price from the computed price of each currency pair, using the
+// provider's TVWAP and falling back to its VWAP like CalcCurrencyPairRates.
+// Providers missing a pair, and pairs without a positive computed price, are
+// left out rather than reported as not diverging.
+func ComputeProviderDivergences(
+	prices types.CurrencyPairDec,
+	tvwapsByProvider types.CurrencyPairDecByProvider,
+	vwapsByProvider types.CurrencyPairDecByProvider,
+) types.CurrencyPairDecByProvider {
+	divergences := make(types.CurrencyPairDecByProvider)
+	hundred := math.LegacyNewDec(100)
+
+	for cp, price := range prices {
+		if !price.IsPositive() {
+			continue
+		}
+
+		providerPrices := make(map[types.ProviderName]math.LegacyDec)
+		for providerName, vwaps := range vwapsByProvider {
+			if vwap, ok := vwaps[cp]; ok {
+				providerPrices[providerName] = vwap
+			}
+		}
+		for providerName, tvwaps := range tvwapsByProvider {
+			if tvwap, ok := tvwaps[cp]; ok {
+				providerPrices[providerName] = tvwap
+			}
+		}
+
+		for providerName, providerPrice := range providerPrices {
+			if _, ok := divergences[providerName]; !ok {
+				divergences[providerName] = make(types.CurrencyPairDec)
+			}
+			divergences[providerName][cp] = providerPrice.Sub(price).Quo(price).Mul(hundred)
+		}
+	}
+
+	return divergences
+}
+
+// ComputeMedian computes the median of the per-provider VWAPs of each currency
+// pair, so each provider counts once regardless of its volume. For an even
+// number of providers, the two middle prices are averaged.
+func ComputeMedian(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
+	return median(ComputeVwapsByProvider(prices, types.TickerVolumePolicyFloor))
+}
+
+// ComputeMedianTVWAP computes the median of the per-provider TVWAPs of each
+// currency pair, so each provider counts once regardless of its volume. For an
+// even number of providers, the two middle prices are averaged.
+func ComputeMedianTVWAP(
+	prices types.AggregatedProviderCandles,
+	lookbacks map[string]time.Duration,
+	minPeriod time.Duration,
+) (types.CurrencyPairDec, error) {
+	tvwaps, err := ComputeTvwapsByProvider(prices, lookbacks, minPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return median(tvwaps), nil
+}
+
+// median computes the median price of each currency pair across providers.
+func median(pricesByProvider types.CurrencyPairDecByProvider) types.CurrencyPairDec {
+	providerPrices := make(map[types.CurrencyPair][]math.LegacyDec)
+	for _, prices := range pricesByProvider {
+		for cp, price := range prices {
+			providerPrices[cp] = append(providerPrices[cp], price)
+		}
+	}
+
+	medians := make(types.CurrencyPairDec, len(providerPrices))
+	for cp, prices := range providerPrices {
+		sort.Slice(prices, func(i, j int) bool {
+			return prices[i].LT(prices[j])
+		})
+
+		mid := len(prices) / 2
+		if len(prices)%2 == 0 {
+			medians[cp] = prices[mid-1].Add(prices[mid]).QuoInt64(2)
+		} else {
+			medians[cp] = prices[mid]
+		}
+	}
+
+	return medians
+}
+
+// RoundPrice rounds the price to the given number of decimal places using the
+// given rounding mode.
+func RoundPrice(price math.LegacyDec, precision uint64, mode types.RoundingMode) math.LegacyDec {
+	scale := math.LegacyNewDec(10).Power(precision)
+	scaled := price.Mul(scale)
+	rounded := scaled.TruncateDec()
+
+	if mode != types.RoundingModeTruncate && scaled.Sub(rounded).Abs().GTE(math.LegacyNewDecWithPrec(5, 1)) {
+		if scaled.IsNegative() {
+			rounded = rounded.Sub(math.LegacyOneDec())
+		} else {
+			rounded = rounded.Add(math.LegacyOneDec())
+		}
+	}
+
+	return rounded.Quo(scale)
+}
+
+// NudgePrices clamps each price to within the step, as a fraction of the
+// on-chain rate of its base, of that rate, so the voted prices move the
+// on-chain rates toward the computed prices without jumping to them. Prices
+// without an on-chain rate are kept as is.
+func NudgePrices(
+	prices types.CurrencyPairDec,
+	onChainRates map[string]math.LegacyDec,
+	step math.LegacyDec,
+) types.CurrencyPairDec {
+	nudged := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		rate, ok := onChainRates[cp.Base]
+		if !ok || !rate.IsPositive() {
+			nudged[cp] = price
+			continue
+		}
+
+		margin := rate.Mul(step)
+		switch {
+		case price.GT(rate.Add(margin)):
+			nudged[cp] = rate.Add(margin)
+		case price.LT(rate.Sub(margin)):
+			nudged[cp] = rate.Sub(margin)
+		default:
+			nudged[cp] = price
+		}
+	}
+	return nudged
+}
+
 // CreatePairProvidersFromCurrencyPairProvidersList will create the pair providers
 // map used by the price feeder Oracle from a CurrencyPairProvidersList defined by
 // Ojo's oracle module.
@@ -75,7 +75,7 @@ func TestComputeVWAP(t *testing.T) {
 		tc := tc
 
 		t.Run(name, func(t *testing.T) {
-			vwap := oracle.ComputeVWAP(tc.prices)
+			vwap := oracle.ComputeVWAP(tc.prices, types.TickerVolumePolicyFloor, nil)
 			require.Len(t, vwap, len(tc.expected))
 
 			for k, v := range tc.expected {
@@ -85,6 +85,269 @@ func TestComputeVWAP(t *testing.T) {
 	}
 }
 
+func TestComputeMedian(t *testing.T) {
+	testCases := map[string]struct {
+		prices   types.AggregatedProviderPrices
+		expected types.CurrencyPairDec
+	}{
+		"empty prices": {
+			prices:   make(types.AggregatedProviderPrices),
+			expected: make(types.CurrencyPairDec),
+		},
+		"nil prices": {
+			prices:   nil,
+			expected: make(types.CurrencyPairDec),
+		},
+		"valid prices": {
+			prices: types.AggregatedProviderPrices{
+				provider.ProviderBinance: {
+					ATOMUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("28.21000000"),
+						Volume: math.LegacyMustNewDecFromStr("2749102.78000000"),
+					},
+					OJOUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("1.13000000"),
+						Volume: math.LegacyMustNewDecFromStr("249102.38000000"),
+					},
+					LUNAUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("64.87000000"),
+						Volume: math.LegacyMustNewDecFromStr("7854934.69000000"),
+					},
+				},
+				provider.ProviderKraken: {
+					ATOMUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("28.268700"),
+						Volume: math.LegacyMustNewDecFromStr("178277.53314385"),
+					},
+					LUNAUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("64.87853000"),
+						Volume: math.LegacyMustNewDecFromStr("458917.46353577"),
+					},
+				},
+				"FOO": {
+					ATOMUSD: types.TickerPrice{
+						Price:  math.LegacyMustNewDecFromStr("28.168700"),
+						Volume: math.LegacyMustNewDecFromStr("4749102.53314385"),
+					},
+				},
+			},
+			expected: types.CurrencyPairDec{
+				ATOMUSD: math.LegacyMustNewDecFromStr("28.21000000"),
+				OJOUSD:  math.LegacyMustNewDecFromStr("1.13000000"),
+				LUNAUSD: math.LegacyMustNewDecFromStr("64.874265"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			median := oracle.ComputeMedian(tc.prices)
+			require.Len(t, median, len(tc.expected))
+
+			for k, v := range tc.expected {
+				require.Equalf(t, v, median[k], "unexpected median for %s", k)
+			}
+		})
+	}
+}
+
+func TestComputeMedianOutlier(t *testing.T) {
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("10"),
+				Volume: math.LegacyMustNewDecFromStr("100"),
+			},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("10.2"),
+				Volume: math.LegacyMustNewDecFromStr("100"),
+			},
+		},
+		"FOO": {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("100"),
+				Volume: math.LegacyMustNewDecFromStr("100000"),
+			},
+		},
+	}
+
+	// the high volume outlier dominates the VWAP but not the median
+	vwap := oracle.ComputeVWAP(prices, types.TickerVolumePolicyFloor, nil)
+	require.True(t, vwap[ATOMUSD].GT(math.LegacyNewDec(90)))
+
+	median := oracle.ComputeMedian(prices)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.2"), median[ATOMUSD])
+}
+
+func TestComputeMedianTVWAP(t *testing.T) {
+	now := provider.PastUnixTimeMillis(0)
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("10"),
+					Volume:    math.LegacyMustNewDecFromStr("100"),
+					TimeStamp: now,
+				},
+			},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("12"),
+					Volume:    math.LegacyMustNewDecFromStr("100000"),
+					TimeStamp: now,
+				},
+			},
+		},
+	}
+
+	// an even number of providers averages the two middle prices
+	median, err := oracle.ComputeMedianTVWAP(candles, nil, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("11"), median[ATOMUSD])
+}
+
+func TestRoundPrice(t *testing.T) {
+	testCases := map[string]struct {
+		price    string
+		mode     types.RoundingMode
+		expected string
+	}{
+		"half up rounds up":      {"0.1234565", types.RoundingModeHalfUp, "0.123457"},
+		"half up rounds down":    {"0.1234564", types.RoundingModeHalfUp, "0.123456"},
+		"truncate":               {"0.1234565", types.RoundingModeTruncate, "0.123456"},
+		"truncate keeps integer": {"28.9999999", types.RoundingModeTruncate, "28.999999"},
+		"half up carries":        {"28.9999999", types.RoundingModeHalfUp, "29"},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			rounded := oracle.RoundPrice(math.LegacyMustNewDecFromStr(tc.price), 6, tc.mode)
+			require.Equal(t, math.LegacyMustNewDecFromStr(tc.expected), rounded)
+		})
+	}
+}
+
+func TestNudgePrices(t *testing.T) {
+	onChainRates := map[string]math.LegacyDec{
+		"ATOM": math.LegacyMustNewDecFromStr("10"),
+		"OJO":  math.LegacyMustNewDecFromStr("2"),
+	}
+	prices := types.CurrencyPairDec{
+		// far above the on-chain rate
+		ATOMUSD: math.LegacyMustNewDecFromStr("15"),
+		// far below the on-chain rate
+		OJOUSD: math.LegacyMustNewDecFromStr("1"),
+		// without an on-chain rate
+		LUNAUSD: math.LegacyMustNewDecFromStr("0.5"),
+	}
+
+	nudged := oracle.NudgePrices(prices, onChainRates, math.LegacyMustNewDecFromStr("0.02"))
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.2"), nudged[ATOMUSD])
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.96"), nudged[OJOUSD])
+	require.Equal(t, prices[LUNAUSD], nudged[LUNAUSD])
+
+	// prices within the step are voted as is
+	nudged = oracle.NudgePrices(prices, onChainRates, math.LegacyMustNewDecFromStr("0.6"))
+	require.Equal(t, prices, nudged)
+}
+
+func TestComputeVWAPTickerVolumePolicy(t *testing.T) {
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("10"),
+				Volume: math.LegacyMustNewDecFromStr("100"),
+			},
+			OJOUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("5"),
+				Volume: math.LegacyZeroDec(),
+			},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("20"),
+				Volume: math.LegacyMustNewDecFromStr("300"),
+			},
+			OJOUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("7"),
+				Volume: math.LegacyZeroDec(),
+			},
+		},
+		"FOO": {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("100"),
+				Volume: math.LegacyZeroDec(),
+			},
+		},
+	}
+
+	t.Run("floor", func(t *testing.T) {
+		vwap := oracle.ComputeVWAP(prices, types.TickerVolumePolicyFloor, nil)
+
+		// the zero volume ticker still slightly shifts the average
+		require.True(t, vwap[ATOMUSD].GT(math.LegacyMustNewDecFromStr("17.5")))
+		require.True(t, vwap[ATOMUSD].LT(math.LegacyMustNewDecFromStr("17.500000001")))
+		require.Equal(t, math.LegacyMustNewDecFromStr("6"), vwap[OJOUSD])
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		vwap := oracle.ComputeVWAP(prices, types.TickerVolumePolicyExclude, nil)
+
+		require.Equal(t, math.LegacyMustNewDecFromStr("17.5"), vwap[ATOMUSD])
+		_, ok := vwap[OJOUSD]
+		require.False(t, ok)
+	})
+
+	t.Run("equal_weight", func(t *testing.T) {
+		vwap := oracle.ComputeVWAP(prices, types.TickerVolumePolicyEqualWeight, nil)
+
+		// the zero volume ticker is weighted with the average volume of 200
+		require.Equal(t, math.LegacyMustNewDecFromStr("45"), vwap[ATOMUSD])
+		require.Equal(t, math.LegacyMustNewDecFromStr("6"), vwap[OJOUSD])
+	})
+}
+
+func TestComputeVWAPProviderWeights(t *testing.T) {
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("10"),
+				Volume: math.LegacyMustNewDecFromStr("100"),
+			},
+		},
+		provider.ProviderOsmosis: {
+			ATOMUSD: types.TickerPrice{
+				Price:  math.LegacyMustNewDecFromStr("20"),
+				Volume: math.LegacyMustNewDecFromStr("100"),
+			},
+		},
+	}
+
+	// providers without a weight are weighted by their volume alone
+	vwap := oracle.ComputeVWAP(prices, types.TickerVolumePolicyFloor, nil)
+	require.Equal(t, math.LegacyMustNewDecFromStr("15"), vwap[ATOMUSD])
+
+	// the downweighted provider moves the vwap less
+	vwap = oracle.ComputeVWAP(prices, types.TickerVolumePolicyFloor, map[types.ProviderName]math.LegacyDec{
+		provider.ProviderOsmosis: math.LegacyMustNewDecFromStr("0.25"),
+	})
+	require.Equal(t, math.LegacyMustNewDecFromStr("12"), vwap[ATOMUSD])
+
+	// a zero weight excludes the provider
+	vwap = oracle.ComputeVWAP(prices, types.TickerVolumePolicyFloor, map[types.ProviderName]math.LegacyDec{
+		provider.ProviderOsmosis: math.LegacyZeroDec(),
+	})
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), vwap[ATOMUSD])
+}
+
 func TestComputeTVWAP(t *testing.T) {
 	testCases := map[string]struct {
 		candles  types.AggregatedProviderCandles
@@ -105,7 +368,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("25.09183"),
 							Volume:    math.LegacyMustNewDecFromStr("98444.123455"),
-							TimeStamp: provider.PastUnixTime(1 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 						},
 					},
 				},
@@ -114,21 +377,21 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.268700"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(2 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(2 * time.Minute),
 						},
 					},
 					OJOUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("1.13000000"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(2 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(2 * time.Minute),
 						},
 					},
 					LUNAUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("64.87853000"),
 							Volume:    math.LegacyMustNewDecFromStr("458917.46353577"),
-							TimeStamp: provider.PastUnixTime(1 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 						},
 					},
 				},
@@ -137,7 +400,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.168700"),
 							Volume:    math.LegacyMustNewDecFromStr("4749102.53314385"),
-							TimeStamp: provider.PastUnixTime(130 * time.Second),
+							TimeStamp: provider.PastUnixTimeMillis(130 * time.Second),
 						},
 					},
 				},
@@ -155,7 +418,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("25.09183"),
 							Volume:    math.LegacyMustNewDecFromStr("98444.123455"),
-							TimeStamp: provider.PastUnixTime(1 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 						},
 					},
 				},
@@ -164,21 +427,21 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.268700"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(2 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(2 * time.Minute),
 						},
 					},
 					OJOUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("1.13000000"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(2 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(2 * time.Minute),
 						},
 					},
 					LUNAUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("64.87853000"),
 							Volume:    math.LegacyMustNewDecFromStr("458917.46353577"),
-							TimeStamp: provider.PastUnixTime(1 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(1 * time.Minute),
 						},
 					},
 				},
@@ -187,7 +450,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.168700"),
 							Volume:    math.LegacyMustNewDecFromStr("4749102.53314385"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 				},
@@ -205,7 +468,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("25.09183"),
 							Volume:    math.LegacyMustNewDecFromStr("98444.123455"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 				},
@@ -214,21 +477,21 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.268700"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 					OJOUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("1.13000000"),
 							Volume:    math.LegacyMustNewDecFromStr("178277.53314385"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 					LUNAUSD: []types.CandlePrice{
 						{
 							Price:     math.LegacyMustNewDecFromStr("64.87853000"),
 							Volume:    math.LegacyMustNewDecFromStr("458917.46353577"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 				},
@@ -237,7 +500,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("28.168700"),
 							Volume:    math.LegacyMustNewDecFromStr("4749102.53314385"),
-							TimeStamp: provider.PastUnixTime(10 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(10 * time.Minute),
 						},
 					},
 				},
@@ -251,7 +514,7 @@ func TestComputeTVWAP(t *testing.T) {
 						{
 							Price:     math.LegacyMustNewDecFromStr("25.09183"),
 							Volume:    math.LegacyMustNewDecFromStr("98444.123455"),
-							TimeStamp: provider.PastUnixTime(-5 * time.Minute),
+							TimeStamp: provider.PastUnixTimeMillis(-5 * time.Minute),
 						},
 					},
 				},
@@ -264,17 +527,172 @@ func TestComputeTVWAP(t *testing.T) {
 		tc := tc
 
 		t.Run(name, func(t *testing.T) {
-			vwap, err := oracle.ComputeTVWAP(tc.candles)
+			vwap, err := oracle.ComputeTVWAP(tc.candles, nil, 0)
 			require.NoError(t, err)
 			require.Len(t, vwap, len(tc.expected))
 
+			// the candles are dated in milliseconds before the TVWAP is
+			// computed, so its time weights can drift by a millisecond
 			for k, v := range tc.expected {
-				require.Equalf(t, v, vwap[k], "unexpected VWAP for %s", k)
+				require.Truef(
+					t,
+					v.Sub(vwap[k]).Abs().LT(math.LegacyMustNewDecFromStr("0.0001")),
+					"unexpected VWAP for %s: expected %s, got %s", k, v, vwap[k],
+				)
 			}
 		})
 	}
 }
 
+func TestFillCandleGaps(t *testing.T) {
+	now := provider.PastUnixTimeMillis(0)
+	minute := time.Minute.Milliseconds()
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("12"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: now - 2*minute,
+				},
+				{
+					Price:     math.LegacyMustNewDecFromStr("10"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: now - 5*minute,
+				},
+				{
+					Price:     math.LegacyMustNewDecFromStr("9"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: now - 9*minute,
+				},
+				{
+					Price:     math.LegacyMustNewDecFromStr("8"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: now - 15*minute - minute/2,
+				},
+			},
+		},
+	}
+
+	filled := oracle.FillCandleGaps(candles, nil, time.Minute, nil)[provider.ProviderBinance][ATOMUSD]
+	minimumVolume := math.LegacyMustNewDecFromStr("0.0001")
+
+	expected := []types.CandlePrice{
+		{Price: math.LegacyMustNewDecFromStr("8"), Volume: math.LegacyOneDec(), TimeStamp: now - 15*minute - minute/2},
+		// the gap before the TVWAP period is only filled within it
+		{Price: math.LegacyMustNewDecFromStr("8"), Volume: minimumVolume, TimeStamp: now - 9*minute - minute/2},
+		{Price: math.LegacyMustNewDecFromStr("9"), Volume: math.LegacyOneDec(), TimeStamp: now - 9*minute},
+		{Price: math.LegacyMustNewDecFromStr("9"), Volume: minimumVolume, TimeStamp: now - 8*minute},
+		{Price: math.LegacyMustNewDecFromStr("9"), Volume: minimumVolume, TimeStamp: now - 7*minute},
+		{Price: math.LegacyMustNewDecFromStr("9"), Volume: minimumVolume, TimeStamp: now - 6*minute},
+		{Price: math.LegacyMustNewDecFromStr("10"), Volume: math.LegacyOneDec(), TimeStamp: now - 5*minute},
+		{Price: math.LegacyMustNewDecFromStr("10"), Volume: minimumVolume, TimeStamp: now - 4*minute},
+		{Price: math.LegacyMustNewDecFromStr("10"), Volume: minimumVolume, TimeStamp: now - 3*minute},
+		{Price: math.LegacyMustNewDecFromStr("12"), Volume: math.LegacyOneDec(), TimeStamp: now - 2*minute},
+	}
+	require.Equal(t, expected, filled)
+
+	// the candles given are not modified
+	require.Len(t, candles[provider.ProviderBinance][ATOMUSD], 4)
+
+	// consecutive candles of a provider with 5m candles are not gaps
+	candleIntervals := map[types.ProviderName]time.Duration{provider.ProviderBinance: 5 * time.Minute}
+	filled = oracle.FillCandleGaps(candles, nil, time.Minute, candleIntervals)[provider.ProviderBinance][ATOMUSD]
+	expected = []types.CandlePrice{
+		{Price: math.LegacyMustNewDecFromStr("8"), Volume: math.LegacyOneDec(), TimeStamp: now - 15*minute - minute/2},
+		{Price: math.LegacyMustNewDecFromStr("9"), Volume: math.LegacyOneDec(), TimeStamp: now - 9*minute},
+		{Price: math.LegacyMustNewDecFromStr("10"), Volume: math.LegacyOneDec(), TimeStamp: now - 5*minute},
+		{Price: math.LegacyMustNewDecFromStr("12"), Volume: math.LegacyOneDec(), TimeStamp: now - 2*minute},
+	}
+	require.Equal(t, expected, filled)
+}
+
+func TestComputeTVWAPMinPeriod(t *testing.T) {
+	fresh := provider.PastUnixTimeMillis(time.Second)
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("20"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: fresh,
+				},
+			},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("10"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: fresh,
+				},
+				{
+					Price:     math.LegacyMustNewDecFromStr("10"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: provider.PastUnixTimeMillis(5 * time.Minute),
+				},
+			},
+		},
+	}
+
+	// with both providers weighted over the same floored period, the single
+	// fresh candle weighs as much as the other provider's equally fresh candle,
+	// and twice its older candle: (20*0.8 + 10*0.8 + 10*0.4) / 2 = 14
+	tvwap, err := oracle.ComputeTVWAP(candles, nil, 10*time.Minute)
+	require.NoError(t, err)
+	require.InDelta(t, 14, tvwap[ATOMUSD].MustFloat64(), 0.01)
+
+	// a single candle stamped now has no period of its own to be weighted over
+	now := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{
+				{
+					Price:     math.LegacyMustNewDecFromStr("20"),
+					Volume:    math.LegacyOneDec(),
+					TimeStamp: provider.PastUnixTimeMillis(0),
+				},
+			},
+		},
+	}
+	tvwap, err = oracle.ComputeTVWAP(now, nil, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("20"), tvwap[ATOMUSD])
+}
+
+func TestComputeProviderDivergences(t *testing.T) {
+	prices := types.CurrencyPairDec{
+		ATOMUSD: math.LegacyNewDec(10),
+		OJOUSD:  math.LegacyZeroDec(),
+	}
+	tvwaps := types.CurrencyPairDecByProvider{
+		provider.ProviderBinance: {ATOMUSD: math.LegacyNewDec(11)},
+		provider.ProviderKraken:  {ATOMUSD: math.LegacyNewDec(9), LUNAUSD: math.LegacyNewDec(60)},
+	}
+	vwaps := types.CurrencyPairDecByProvider{
+		provider.ProviderBinance:  {ATOMUSD: math.LegacyNewDec(20)},
+		provider.ProviderHuobi:    {ATOMUSD: math.LegacyNewDec(10)},
+		provider.ProviderCoinbase: {OJOUSD: math.LegacyNewDec(1)},
+	}
+
+	divergences := oracle.ComputeProviderDivergences(prices, tvwaps, vwaps)
+
+	// the TVWAP is used over the VWAP of the same provider, and providers
+	// missing a priced pair are left out instead of reported as zero
+	expected := types.CurrencyPairDecByProvider{
+		provider.ProviderBinance: {ATOMUSD: math.LegacyNewDec(10)},
+		provider.ProviderKraken:  {ATOMUSD: math.LegacyNewDec(-10)},
+		provider.ProviderHuobi:   {ATOMUSD: math.LegacyZeroDec()},
+	}
+	require.Len(t, divergences, len(expected))
+	for providerName, providerDivergences := range expected {
+		require.Len(t, divergences[providerName], len(providerDivergences))
+		for cp, divergence := range providerDivergences {
+			require.Truef(t, divergence.Equal(divergences[providerName][cp]),
+				"unexpected divergence of %s for %s", providerName, cp)
+		}
+	}
+}
+
 func TestStandardDeviation(t *testing.T) {
 	type deviation struct {
 		mean      math.LegacyDec
@@ -387,3 +805,48 @@ func TestStandardDeviation(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeTVWAPLookbacks(t *testing.T) {
+	candles := func() []types.CandlePrice {
+		return []types.CandlePrice{
+			{
+				Price:     math.LegacyMustNewDecFromStr("10"),
+				Volume:    math.LegacyOneDec(),
+				TimeStamp: provider.PastUnixTimeMillis(3 * time.Minute),
+			},
+			{
+				Price:     math.LegacyMustNewDecFromStr("20"),
+				Volume:    math.LegacyOneDec(),
+				TimeStamp: provider.PastUnixTimeMillis(7 * time.Minute),
+			},
+			{
+				Price:     math.LegacyMustNewDecFromStr("30"),
+				Volume:    math.LegacyOneDec(),
+				TimeStamp: provider.PastUnixTimeMillis(12 * time.Minute),
+			},
+		}
+	}
+	prices := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: candles(),
+			OJOUSD:  candles(),
+			LUNAUSD: candles(),
+		},
+	}
+	lookbacks := map[string]time.Duration{
+		"ATOM": 5 * time.Minute,
+		"OJO":  15 * time.Minute,
+	}
+
+	tvwap, err := oracle.ComputeTVWAP(prices, lookbacks, 0)
+	require.NoError(t, err)
+
+	// the 7m old candle is excluded from the 5m lookback
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), tvwap[ATOMUSD])
+
+	// the 12m old candle is excluded from the default 10m lookback, but
+	// included in the 15m lookback
+	require.True(t, tvwap[LUNAUSD].GT(math.LegacyMustNewDecFromStr("10")))
+	require.True(t, tvwap[LUNAUSD].LT(math.LegacyMustNewDecFromStr("20")))
+	require.True(t, tvwap[OJOUSD].GT(tvwap[LUNAUSD]))
+}
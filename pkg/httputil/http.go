@@ -7,7 +7,8 @@ import (
 
 // Common HTTP methods and header values
 const (
-	MethodGET = "GET"
+	MethodGET  = "GET"
+	MethodPOST = "POST"
 )
 
 // ErrResponse defines an HTTP error response.
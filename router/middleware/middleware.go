@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/justinas/alice"
@@ -10,6 +13,7 @@ import (
 	"github.com/rs/zerolog/hlog"
 
 	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/pkg/httputil"
 )
 
 func Build(logger zerolog.Logger, cfg config.Config) alice.Chain {
@@ -70,3 +74,22 @@ func AddCORSMiddleware(mChain alice.Chain, logger zerolog.Logger, cfg config.Con
 
 	return mChain
 }
+
+// AddAdminAuthMiddleware appends middleware to a provided middleware chain
+// which rejects the requests without the configured admin token as their
+// bearer token.
+func AddAdminAuthMiddleware(mChain alice.Chain, cfg config.Config) alice.Chain {
+	adminToken := []byte(cfg.Server.AdminToken)
+
+	return mChain.Append(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), adminToken) != 1 {
+				httputil.RespondWithError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+}
@@ -9,7 +9,18 @@ import (
 // Oracle defines the Oracle interface contract that the v1 router depends on.
 type Oracle interface {
 	GetLastPriceSyncTimestamp() time.Time
-	GetPrices() types.CurrencyPairDec
+	GetFirstVoteTimestamp() time.Time
+	GetPricesSnapshot() types.CurrencyPairDec
+	GetSignedPrices() *types.SignedPrices
+	GetStalePrices() types.CurrencyPairTimestampedPrices
 	GetTvwapPrices() types.CurrencyPairDecByProvider
 	GetVwapPrices() types.CurrencyPairDecByProvider
+	GetConversionRoutes() types.CurrencyPairConversionRoutes
+	GetProviderPairs() map[types.ProviderName][]types.CurrencyPair
+	GetPriceDebug(base string) (types.PriceDebug, error)
+	GetDeviatingProviders(cp types.CurrencyPair) ([]types.ProviderName, error)
+	SubscribePrices() (<-chan types.CurrencyPairDec, func())
+	GetMissedVotes() []types.MissedVote
+	SetVotingPaused(paused bool)
+	IsVotingPaused() bool
 }
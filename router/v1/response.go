@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"net/http"
 
+	"cosmossdk.io/math"
+
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
 // Response constants
 const (
-	StatusAvailable = "available"
+	StatusAvailable   = "available"
+	StatusUnavailable = "unavailable"
+	StatusStarting    = "starting"
 )
 
 type (
@@ -19,20 +23,205 @@ type (
 		Status string `json:"status" yaml:"status"`
 		Oracle struct {
 			LastSync string `json:"last_sync"`
+			Age      string `json:"age,omitempty"`
 		} `json:"oracle"`
 	}
 
+	// ReadyResponse defines the response type for the readiness API handler.
+	ReadyResponse struct {
+		Status    string `json:"status"`
+		FirstVote string `json:"first_vote,omitempty"`
+	}
+
 	// PricesResponse defines the response type for getting the latest exchange
 	// rates from the oracle.
 	PricesResponse struct {
-		Prices types.CurrencyPairDec `json:"prices"`
+		Prices      types.CurrencyPairDec             `json:"prices"`
+		StalePrices map[types.CurrencyPair]StalePrice `json:"stale_prices,omitempty"`
+		Signed      *types.SignedPrices               `json:"signed,omitempty"`
+	}
+
+	// StalePrice defines the last known price of a currency pair which was not
+	// updated in the latest price computation, flagged as stale along with its
+	// age.
+	StalePrice struct {
+		Price math.LegacyDec `json:"price"`
+		Stale bool           `json:"stale"`
+		Age   string         `json:"age"`
+	}
+
+	// ScaledPricesResponse defines the response type for getting the latest
+	// exchange rates as integers of the given scale, i.e. each price
+	// multiplied by 10^exponent and rounded.
+	ScaledPricesResponse struct {
+		Scale       string                                  `json:"scale"`
+		Exponent    uint64                                  `json:"exponent"`
+		Prices      map[types.CurrencyPair]math.Int         `json:"prices"`
+		StalePrices map[types.CurrencyPair]ScaledStalePrice `json:"stale_prices,omitempty"`
+		Signed      *types.SignedPrices                     `json:"signed,omitempty"`
+	}
+
+	// ScaledStalePrice defines a StalePrice whose price is scaled to an
+	// integer.
+	ScaledStalePrice struct {
+		Price math.Int `json:"price"`
+		Stale bool     `json:"stale"`
+		Age   string   `json:"age"`
 	}
 
 	PricesPerProviderResponse struct {
 		Prices types.CurrencyPairDecByProvider `json:"providers"`
 	}
+
+	// AssetProviderPricesResponse defines the response type for getting the
+	// latest USD price of each provider for a single base.
+	AssetProviderPricesResponse struct {
+		Base      string                                `json:"base"`
+		Providers map[types.ProviderName]ProviderPrices `json:"providers"`
+	}
+
+	// ProviderPrices defines the VWAP and TVWAP of a single provider, which
+	// are omitted if the provider has no tickers or candles respectively.
+	ProviderPrices struct {
+		VWAP  *math.LegacyDec `json:"vwap,omitempty"`
+		TVWAP *math.LegacyDec `json:"tvwap,omitempty"`
+	}
+
+	// PairPricesResponse defines the response type for getting the latest
+	// price of a single pair along with the VWAP and TVWAP of each provider
+	// and the providers filtered out for deviating.
+	PairPricesResponse struct {
+		Pair               types.CurrencyPair                    `json:"pair"`
+		Price              *math.LegacyDec                       `json:"price,omitempty"`
+		Providers          map[types.ProviderName]ProviderPrices `json:"providers"`
+		DeviatingProviders []types.ProviderName                  `json:"deviating_providers"`
+	}
+
+	// ConversionRoutesResponse defines the response type for getting the
+	// conversion routes used to convert non-USD quoted pairs to USD.
+	ConversionRoutesResponse struct {
+		Routes types.CurrencyPairConversionRoutes `json:"routes"`
+	}
+
+	// PriceDebugResponse defines the response type for getting the inputs and
+	// intermediate results of the last price computation for a single base.
+	PriceDebugResponse struct {
+		Debug types.PriceDebug `json:"debug"`
+	}
+
+	// SubscriptionsResponse defines the response type for getting the
+	// currency pairs each provider is subscribed to.
+	SubscriptionsResponse struct {
+		Subscriptions map[types.ProviderName][]types.CurrencyPair `json:"subscriptions"`
+	}
+
+	// VotingResponse defines the response type for pausing and resuming
+	// voting.
+	VotingResponse struct {
+		Paused bool `json:"paused"`
+	}
+
+	// MissedVotesResponse defines the response type for getting the latest
+	// missed votes of the oracle, oldest first.
+	MissedVotesResponse struct {
+		MissedVotes []types.MissedVote `json:"missed_votes"`
+	}
 )
 
+// newAssetProviderPricesResponse indexes the VWAPs and TVWAPs of every
+// provider by the given base.
+func newAssetProviderPricesResponse(
+	base string,
+	vwaps, tvwaps types.CurrencyPairDecByProvider,
+) AssetProviderPricesResponse {
+	resp := AssetProviderPricesResponse{
+		Base:      base,
+		Providers: make(map[types.ProviderName]ProviderPrices),
+	}
+	for providerName, prices := range vwaps {
+		for cp, price := range prices {
+			if cp.Base != base {
+				continue
+			}
+			providerPrices := resp.Providers[providerName]
+			providerPrices.VWAP = &price
+			resp.Providers[providerName] = providerPrices
+		}
+	}
+	for providerName, prices := range tvwaps {
+		for cp, price := range prices {
+			if cp.Base != base {
+				continue
+			}
+			providerPrices := resp.Providers[providerName]
+			providerPrices.TVWAP = &price
+			resp.Providers[providerName] = providerPrices
+		}
+	}
+	return resp
+}
+
+// newPairPricesResponse indexes the VWAPs and TVWAPs of every provider for
+// the given pair.
+func newPairPricesResponse(
+	cp types.CurrencyPair,
+	vwaps, tvwaps types.CurrencyPairDecByProvider,
+) PairPricesResponse {
+	resp := PairPricesResponse{
+		Pair:      cp,
+		Providers: make(map[types.ProviderName]ProviderPrices),
+	}
+	for providerName, prices := range vwaps {
+		if price, ok := prices[cp]; ok {
+			providerPrices := resp.Providers[providerName]
+			providerPrices.VWAP = &price
+			resp.Providers[providerName] = providerPrices
+		}
+	}
+	for providerName, prices := range tvwaps {
+		if price, ok := prices[cp]; ok {
+			providerPrices := resp.Providers[providerName]
+			providerPrices.TVWAP = &price
+			resp.Providers[providerName] = providerPrices
+		}
+	}
+	return resp
+}
+
+// newScaledPricesResponse converts the prices of a PricesResponse to integers
+// of the given scale.
+func newScaledPricesResponse(resp PricesResponse, scale string, exponent uint64) ScaledPricesResponse {
+	scaledResp := ScaledPricesResponse{
+		Scale:    scale,
+		Exponent: exponent,
+		Prices:   make(map[types.CurrencyPair]math.Int, len(resp.Prices)),
+		Signed:   resp.Signed,
+	}
+	for cp, price := range resp.Prices {
+		scaledResp.Prices[cp] = scalePrice(price, exponent)
+	}
+
+	if resp.StalePrices != nil {
+		scaledResp.StalePrices = make(map[types.CurrencyPair]ScaledStalePrice, len(resp.StalePrices))
+		for cp, stalePrice := range resp.StalePrices {
+			scaledResp.StalePrices[cp] = ScaledStalePrice{
+				Price: scalePrice(stalePrice.Price, exponent),
+				Stale: stalePrice.Stale,
+				Age:   stalePrice.Age,
+			}
+		}
+	}
+
+	return scaledResp
+}
+
+// scalePrice multiplies the price by 10^exponent and rounds it to the nearest
+// integer. Since prices have 18 decimals, no precision is lost for exponents
+// up to 18.
+func scalePrice(price math.LegacyDec, exponent uint64) math.Int {
+	return price.Mul(math.LegacyNewDec(10).Power(exponent)).RoundInt()
+}
+
 // errorResponse defines the attributes of a JSON error response.
 type errorResponse struct {
 	Code  int    `json:"code,omitempty"`
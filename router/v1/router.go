@@ -1,9 +1,12 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,14 +14,33 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/ojo-network/price-feeder/pkg/httputil"
 	"github.com/ojo-network/price-feeder/router/middleware"
 )
 
 const (
 	APIPathPrefix = "/api/v1"
+
+	// providerPriceMetric is the name of the gauge of the prices of each
+	// provider served by the metrics-prices endpoint.
+	providerPriceMetric = "price_feeder_provider_price"
+
+	// prometheusContentType is the content type of the Prometheus text
+	// exposition format.
+	prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// scaleMicro is the scale query param value of the prices endpoint which
+	// returns the prices as integer micro-units.
+	scaleMicro = "micro"
 )
 
+// priceScales maps the supported scale query param values of the prices
+// endpoint to the decimal exponent the prices are multiplied by.
+var priceScales = map[string]uint64{
+	scaleMicro: 6,
+}
+
 // Router defines a router wrapper used for registering v1 API routes.
 type Router struct {
 	logger  zerolog.Logger
@@ -65,11 +87,21 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		mChain.ThenFunc(r.healthzHandler()),
 	).Methods(httputil.MethodGET)
 
+	v1Router.Handle(
+		"/ready",
+		mChain.ThenFunc(r.readyHandler()),
+	).Methods(httputil.MethodGET)
+
 	v1Router.Handle(
 		"/prices",
 		mChain.ThenFunc(r.pricesHandler()),
 	).Methods(httputil.MethodGET)
 
+	v1Router.Handle(
+		"/sse",
+		mChain.ThenFunc(r.sseHandler()),
+	).Methods(httputil.MethodGET)
+
 	v1Router.Handle(
 		"/prices/providers/tvwap",
 		mChain.ThenFunc(r.candlePricesHandler()),
@@ -80,6 +112,56 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		mChain.ThenFunc(r.tickerPricesHandler()),
 	).Methods(httputil.MethodGET)
 
+	v1Router.Handle(
+		"/metrics-prices",
+		mChain.ThenFunc(r.providerPricesMetricsHandler()),
+	).Methods(httputil.MethodGET)
+
+	v1Router.Handle(
+		"/prices/{base}/providers",
+		mChain.ThenFunc(r.assetProviderPricesHandler()),
+	).Methods(httputil.MethodGET)
+
+	v1Router.Handle(
+		"/prices/{base}/debug",
+		mChain.ThenFunc(r.priceDebugHandler()),
+	).Methods(httputil.MethodGET)
+
+	// registered after the other price routes, which take precedence
+	v1Router.Handle(
+		"/prices/{base}/{quote}",
+		mChain.ThenFunc(r.pairPricesHandler()),
+	).Methods(httputil.MethodGET)
+
+	v1Router.Handle(
+		"/conversions/routes",
+		mChain.ThenFunc(r.conversionRoutesHandler()),
+	).Methods(httputil.MethodGET)
+
+	v1Router.Handle(
+		"/subscriptions",
+		mChain.ThenFunc(r.subscriptionsHandler()),
+	).Methods(httputil.MethodGET)
+
+	v1Router.Handle(
+		"/votes/missed",
+		mChain.ThenFunc(r.missedVotesHandler()),
+	).Methods(httputil.MethodGET)
+
+	if r.cfg.Server.AdminToken != "" {
+		adminChain := middleware.AddAdminAuthMiddleware(mChain, r.cfg)
+
+		v1Router.Handle(
+			"/voting/pause",
+			adminChain.ThenFunc(r.votingHandler(true)),
+		).Methods(httputil.MethodPOST)
+
+		v1Router.Handle(
+			"/voting/resume",
+			adminChain.ThenFunc(r.votingHandler(false)),
+		).Methods(httputil.MethodPOST)
+	}
+
 	if r.cfg.Telemetry.Enabled {
 		v1Router.Handle(
 			"/metrics",
@@ -88,28 +170,164 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 	}
 }
 
+// healthzHandler reports the price feeder as available if the last price sync
+// happened within the health staleness window, as starting if no price sync
+// has happened yet, and as unavailable otherwise.
 func (r *Router) healthzHandler() http.HandlerFunc {
+	stalenessWindow, err := time.ParseDuration(r.cfg.Server.HealthStalenessWindow)
+	if err != nil {
+		stalenessWindow = r.cfg.DefaultHealthStalenessWindow()
+	}
+
 	return func(w http.ResponseWriter, _ *http.Request) {
 		resp := HealthZResponse{
-			Status: StatusAvailable,
+			Status: StatusStarting,
 		}
+		code := http.StatusServiceUnavailable
 
-		resp.Oracle.LastSync = r.oracle.GetLastPriceSyncTimestamp().Format(time.RFC3339)
+		lastSync := r.oracle.GetLastPriceSyncTimestamp()
+		if !lastSync.IsZero() {
+			age := time.Since(lastSync)
+			resp.Status = StatusUnavailable
+			resp.Oracle.LastSync = lastSync.Format(time.RFC3339)
+			resp.Oracle.Age = age.Round(time.Millisecond).String()
 
-		httputil.RespondWithJSON(w, http.StatusOK, resp)
+			if age <= stalenessWindow {
+				resp.Status = StatusAvailable
+				code = http.StatusOK
+			}
+		}
+
+		httputil.RespondWithJSON(w, code, resp)
 	}
 }
 
-func (r *Router) pricesHandler() http.HandlerFunc {
+// readyHandler reports the price feeder as available once its first vote has
+// been successfully broadcast, and as unavailable until then.
+func (r *Router) readyHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
+		resp := ReadyResponse{
+			Status: StatusUnavailable,
+		}
+		code := http.StatusServiceUnavailable
+
+		if firstVote := r.oracle.GetFirstVoteTimestamp(); !firstVote.IsZero() {
+			resp.Status = StatusAvailable
+			resp.FirstVote = firstVote.Format(time.RFC3339)
+			code = http.StatusOK
+		}
+
+		httputil.RespondWithJSON(w, code, resp)
+	}
+}
+
+func (r *Router) pricesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
 		resp := PricesResponse{
-			Prices: r.oracle.GetPrices(),
+			Prices: r.oracle.GetPricesSnapshot(),
+			Signed: r.oracle.GetSignedPrices(),
+		}
+
+		if includeStale := strings.TrimSpace(req.FormValue("include_stale")); includeStale != "" {
+			ok, err := strconv.ParseBool(includeStale)
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid include_stale value: %s", err))
+				return
+			}
+
+			if ok {
+				resp.StalePrices = make(map[types.CurrencyPair]StalePrice)
+				for cp, stalePrice := range r.oracle.GetStalePrices() {
+					resp.StalePrices[cp] = StalePrice{
+						Price: stalePrice.Price,
+						Stale: true,
+						Age:   time.Since(stalePrice.Timestamp).Round(time.Second).String(),
+					}
+				}
+			}
+		}
+
+		if scale := strings.TrimSpace(req.FormValue("scale")); scale != "" {
+			exponent, ok := priceScales[strings.ToLower(scale)]
+			if !ok {
+				writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid scale value: %s", scale))
+				return
+			}
+
+			scaledResp := newScaledPricesResponse(resp, strings.ToLower(scale), exponent)
+			httputil.RespondWithJSON(w, http.StatusOK, scaledResp)
+			return
 		}
 
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
 	}
 }
 
+// sseHandler streams the computed prices as Server-Sent Events every time a
+// price computation completes, optionally filtered to the comma separated
+// bases query param. The stream ends with the server write timeout when the
+// write deadline can't be lifted, after which clients are expected to
+// reconnect.
+func (r *Router) sseHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeErrorResponse(w, http.StatusInternalServerError, "streaming is not supported")
+			return
+		}
+
+		var bases map[string]struct{}
+		if basesParam := strings.TrimSpace(req.FormValue("bases")); basesParam != "" {
+			bases = make(map[string]struct{})
+			for _, base := range strings.Split(basesParam, ",") {
+				bases[strings.ToUpper(strings.TrimSpace(base))] = struct{}{}
+			}
+		}
+
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			r.logger.Debug().Err(err).Msg("failed to lift write deadline of price stream")
+		}
+
+		prices, unsubscribe := r.oracle.SubscribePrices()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+
+			case computedPrices := <-prices:
+				resp := PricesResponse{
+					Prices: make(types.CurrencyPairDec, len(computedPrices)),
+					Signed: r.oracle.GetSignedPrices(),
+				}
+				for cp, price := range computedPrices {
+					if _, ok := bases[cp.Base]; bases != nil && !ok {
+						continue
+					}
+					resp.Prices[cp] = price
+				}
+
+				bz, err := json.Marshal(resp)
+				if err != nil {
+					r.logger.Error().Err(err).Msg("failed to marshal price stream event")
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: prices\ndata: %s\n\n", bz); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
 func (r *Router) candlePricesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
 		resp := PricesPerProviderResponse{
@@ -130,6 +348,169 @@ func (r *Router) tickerPricesHandler() http.HandlerFunc {
 	}
 }
 
+// providerPricesMetricsHandler renders the VWAP and TVWAP prices of each
+// provider as the price_feeder_provider_price gauge in the Prometheus text
+// format, with an empty body when there are no prices.
+func (r *Router) providerPricesMetricsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		vwaps := r.oracle.GetVwapPrices()
+		tvwaps := r.oracle.GetTvwapPrices()
+
+		w.Header().Set("Content-Type", prometheusContentType)
+		w.WriteHeader(http.StatusOK)
+		if len(vwaps) == 0 && len(tvwaps) == 0 {
+			return
+		}
+
+		var sb strings.Builder
+		sb.WriteString("# HELP " + providerPriceMetric + " The price of a currency pair computed from a single provider.\n")
+		sb.WriteString("# TYPE " + providerPriceMetric + " gauge\n")
+		writeProviderPrices(&sb, "vwap", vwaps)
+		writeProviderPrices(&sb, "tvwap", tvwaps)
+
+		// unchecked err, too late for bad response
+		_, _ = w.Write([]byte(sb.String()))
+	}
+}
+
+// writeProviderPrices writes a price_feeder_provider_price sample for each
+// price of each provider, sorted by provider and currency pair.
+func writeProviderPrices(sb *strings.Builder, method string, prices types.CurrencyPairDecByProvider) {
+	providerNames := make([]types.ProviderName, 0, len(prices))
+	for providerName := range prices {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Slice(providerNames, func(i, j int) bool { return providerNames[i] < providerNames[j] })
+
+	for _, providerName := range providerNames {
+		pairs := make([]types.CurrencyPair, 0, len(prices[providerName]))
+		for cp := range prices[providerName] {
+			pairs = append(pairs, cp)
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].String() < pairs[j].String() })
+
+		for _, cp := range pairs {
+			fmt.Fprintf(
+				sb,
+				"%s{provider=%q,base=%q,quote=%q,method=%q} %s\n",
+				providerPriceMetric,
+				providerName,
+				cp.Base,
+				cp.Quote,
+				method,
+				prices[providerName][cp],
+			)
+		}
+	}
+}
+
+// assetProviderPricesHandler responds with the latest VWAP and TVWAP of each
+// provider for the base of the request, or 404 if no provider has a price for
+// it.
+func (r *Router) assetProviderPricesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		base := strings.ToUpper(mux.Vars(req)["base"])
+
+		resp := newAssetProviderPricesResponse(base, r.oracle.GetVwapPrices(), r.oracle.GetTvwapPrices())
+		if len(resp.Providers) == 0 {
+			writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("no provider prices for %s", base))
+			return
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// pairPricesHandler responds with the latest price of the pair of the request,
+// the VWAP and TVWAP of each provider and the providers filtered out for
+// deviating, or 404 if the pair is not tracked.
+func (r *Router) pairPricesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		vars := mux.Vars(req)
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(vars["base"]),
+			Quote: strings.ToUpper(vars["quote"]),
+		}
+
+		resp := newPairPricesResponse(cp, r.oracle.GetVwapPrices(), r.oracle.GetTvwapPrices())
+		if price, ok := r.oracle.GetPricesSnapshot()[cp]; ok {
+			resp.Price = &price
+		}
+		if resp.Price == nil && len(resp.Providers) == 0 {
+			writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("no price data for %s", cp))
+			return
+		}
+
+		deviatingProviders, err := r.oracle.GetDeviatingProviders(cp)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get deviating providers: %s", err))
+			return
+		}
+		resp.DeviatingProviders = deviatingProviders
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) priceDebugHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		base := strings.ToUpper(mux.Vars(req)["base"])
+
+		priceDebug, err := r.oracle.GetPriceDebug(base)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get price debug: %s", err))
+			return
+		}
+		if len(priceDebug.Providers) == 0 && priceDebug.Price == nil {
+			writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("no price data for %s", base))
+			return
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, PriceDebugResponse{Debug: priceDebug})
+	}
+}
+
+func (r *Router) conversionRoutesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		resp := ConversionRoutesResponse{
+			Routes: r.oracle.GetConversionRoutes(),
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) subscriptionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		resp := SubscriptionsResponse{
+			Subscriptions: r.oracle.GetProviderPairs(),
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) missedVotesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		resp := MissedVotesResponse{
+			MissedVotes: r.oracle.GetMissedVotes(),
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// votingHandler pauses or resumes the oracle's voting, while its prices keep
+// being collected and served.
+func (r *Router) votingHandler(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		r.oracle.SetVotingPaused(paused)
+		r.logger.Info().Bool("paused", paused).Msg("voting toggled through the admin api")
+
+		httputil.RespondWithJSON(w, http.StatusOK, VotingResponse{Paused: r.oracle.IsVotingPaused()})
+	}
+}
+
 func (r *Router) metricsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		format := strings.TrimSpace(req.FormValue("format"))
@@ -1,15 +1,18 @@
 package v1_test
 
 import (
+	"bufio"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"cosmossdk.io/math"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
@@ -25,6 +28,8 @@ var (
 	ATOMUSD = types.CurrencyPair{Base: "ATOM", Quote: "USD"}
 	OJOUSD  = types.CurrencyPair{Base: "OJO", Quote: "USD"}
 	FOOUSD  = types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	OJOUSDT = types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	USDTUSD = types.CurrencyPair{Base: "USDT", Quote: "USD"}
 
 	mockPrices = types.CurrencyPairDec{
 		ATOMUSD: math.LegacyMustNewDecFromStr("34.84"),
@@ -41,6 +46,54 @@ var (
 			OJOUSD:  math.LegacyMustNewDecFromStr("1.13000000"),
 		},
 	}
+
+	mockStalePrices = types.CurrencyPairTimestampedPrices{
+		FOOUSD: {
+			Price:     math.LegacyMustNewDecFromStr("1.5"),
+			Timestamp: time.Now().Add(-5 * time.Minute),
+		},
+	}
+
+	mockConversionRoutes = types.CurrencyPairConversionRoutes{
+		OJOUSDT: {
+			{Pair: USDTUSD, Rate: math.LegacyMustNewDecFromStr("0.9998")},
+		},
+	}
+
+	mockPriceDebug = types.PriceDebug{
+		Base: "OJO",
+		Providers: []types.ProviderPriceDebug{
+			{
+				Provider: provider.ProviderBinance,
+				Pair:     OJOUSD,
+				Ticker: &types.TickerPrice{
+					Price:  math.LegacyMustNewDecFromStr("4.21"),
+					Volume: math.LegacyMustNewDecFromStr("1000"),
+				},
+			},
+		},
+	}
+
+	mockProviderPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {OJOUSDT},
+		provider.ProviderEthUniswap: {
+			{Base: "OJO", Quote: "USDC", Address: "0x8ad599c3a0ff1de082011efddc58f1908eb6e6d8"},
+		},
+	}
+
+	mockMissedVotes = []types.MissedVote{
+		{
+			Height:    100,
+			Reason:    types.MissedVoteReasonBroadcastFailure,
+			Error:     "connection refused",
+			Timestamp: time.Now().Add(-time.Minute),
+		},
+		{
+			Height:    105,
+			Reason:    types.MissedVoteReasonVotePeriodMissed,
+			Timestamp: time.Now(),
+		},
+	}
 )
 
 type mockOracle struct{}
@@ -49,10 +102,22 @@ func (m mockOracle) GetLastPriceSyncTimestamp() time.Time {
 	return time.Now()
 }
 
-func (m mockOracle) GetPrices() types.CurrencyPairDec {
+func (m mockOracle) GetFirstVoteTimestamp() time.Time {
+	return time.Now()
+}
+
+func (m mockOracle) GetPricesSnapshot() types.CurrencyPairDec {
 	return mockPrices
 }
 
+func (m mockOracle) GetSignedPrices() *types.SignedPrices {
+	return nil
+}
+
+func (m mockOracle) GetStalePrices() types.CurrencyPairTimestampedPrices {
+	return mockStalePrices
+}
+
 func (m mockOracle) GetTvwapPrices() types.CurrencyPairDecByProvider {
 	return mockComputedPrices
 }
@@ -61,6 +126,113 @@ func (m mockOracle) GetVwapPrices() types.CurrencyPairDecByProvider {
 	return mockComputedPrices
 }
 
+func (m mockOracle) GetConversionRoutes() types.CurrencyPairConversionRoutes {
+	return mockConversionRoutes
+}
+
+func (m mockOracle) GetProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	return mockProviderPairs
+}
+
+func (m mockOracle) GetPriceDebug(base string) (types.PriceDebug, error) {
+	if base != mockPriceDebug.Base {
+		return types.PriceDebug{Base: base}, nil
+	}
+	return mockPriceDebug, nil
+}
+
+func (m mockOracle) GetDeviatingProviders(cp types.CurrencyPair) ([]types.ProviderName, error) {
+	if cp == ATOMUSD {
+		return []types.ProviderName{provider.ProviderKraken}, nil
+	}
+	return []types.ProviderName{}, nil
+}
+
+func (m mockOracle) SubscribePrices() (<-chan types.CurrencyPairDec, func()) {
+	return make(chan types.CurrencyPairDec), func() {}
+}
+
+func (m mockOracle) GetMissedVotes() []types.MissedVote {
+	return mockMissedVotes
+}
+
+func (m mockOracle) SetVotingPaused(bool) {}
+
+func (m mockOracle) IsVotingPaused() bool {
+	return false
+}
+
+type streamOracle struct {
+	mockOracle
+	prices chan types.CurrencyPairDec
+}
+
+func (m streamOracle) SubscribePrices() (<-chan types.CurrencyPairDec, func()) {
+	return m.prices, func() {}
+}
+
+type staleOracle struct {
+	mockOracle
+	lastSync time.Time
+}
+
+func (m staleOracle) GetLastPriceSyncTimestamp() time.Time {
+	return m.lastSync
+}
+
+type readyOracle struct {
+	mockOracle
+	firstVote *time.Time
+}
+
+func (m readyOracle) GetFirstVoteTimestamp() time.Time {
+	return *m.firstVote
+}
+
+type emptyPricesOracle struct {
+	mockOracle
+}
+
+func (m emptyPricesOracle) GetTvwapPrices() types.CurrencyPairDecByProvider {
+	return types.CurrencyPairDecByProvider{}
+}
+
+func (m emptyPricesOracle) GetVwapPrices() types.CurrencyPairDecByProvider {
+	return types.CurrencyPairDecByProvider{}
+}
+
+type scaledPricesOracle struct {
+	mockOracle
+}
+
+func (m scaledPricesOracle) GetPricesSnapshot() types.CurrencyPairDec {
+	return types.CurrencyPairDec{
+		ATOMUSD: math.LegacyMustNewDecFromStr("3.72"),
+	}
+}
+
+type votingOracle struct {
+	mockOracle
+	paused *bool
+}
+
+func (m votingOracle) SetVotingPaused(paused bool) {
+	*m.paused = paused
+}
+
+func (m votingOracle) IsVotingPaused() bool {
+	return *m.paused
+}
+
+type signedPricesOracle struct {
+	scaledPricesOracle
+	signed *types.SignedPrices
+}
+
+func (m signedPricesOracle) GetSignedPrices() *types.SignedPrices {
+	return m.signed
+}
+
 type mockMetrics struct{}
 
 func (mockMetrics) Gather(format string) (telemetry.GatherResponse, error) {
@@ -114,6 +286,107 @@ func (rts *RouterTestSuite) TestHealthz() {
 	rts.Require().Equal(respBody["status"], v1.StatusAvailable)
 }
 
+func TestHealthzUnavailable(t *testing.T) {
+	testCases := map[string]struct {
+		lastSync time.Time
+		status   string
+	}{
+		"stale price sync": {lastSync: time.Now().Add(-time.Minute), status: v1.StatusUnavailable},
+		"no price sync":    {status: v1.StatusStarting},
+	}
+
+	for name, tc := range testCases {
+		lastSync := tc.lastSync
+		status := tc.status
+
+		t.Run(name, func(t *testing.T) {
+			mux := mux.NewRouter()
+			cfg := config.Config{
+				Server: config.Server{
+					HealthStalenessWindow: "10s",
+				},
+			}
+			v1.New(zerolog.Nop(), cfg, staleOracle{lastSync: lastSync}, mockMetrics{}).
+				RegisterRoutes(mux, v1.APIPathPrefix)
+
+			req, err := http.NewRequest("GET", "/api/v1/healthz", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+			var respBody v1.HealthZResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+			require.Equal(t, status, respBody.Status)
+			require.Equal(t, lastSync.IsZero(), respBody.Oracle.LastSync == "")
+		})
+	}
+}
+
+func TestReady(t *testing.T) {
+	oracle := readyOracle{firstVote: &time.Time{}}
+	mux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, oracle, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	ready := func() (int, v1.ReadyResponse) {
+		req, err := http.NewRequest("GET", "/api/v1/ready", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		var respBody v1.ReadyResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+		return rr.Code, respBody
+	}
+
+	code, respBody := ready()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, v1.StatusUnavailable, respBody.Status)
+	require.Empty(t, respBody.FirstVote)
+
+	// simulate the first successful vote
+	*oracle.firstVote = time.Now()
+
+	code, respBody = ready()
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, v1.StatusAvailable, respBody.Status)
+	require.NotEmpty(t, respBody.FirstVote)
+}
+
+func TestSSE(t *testing.T) {
+	oracle := streamOracle{prices: make(chan types.CurrencyPairDec, 1)}
+	mux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, oracle, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/sse?bases=atom")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// simulate a completed price computation
+	oracle.prices <- mockPrices
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: prices\n", event)
+
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(data, "data: "))
+
+	var respBody v1.PricesResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &respBody))
+	require.Len(t, respBody.Prices, 1)
+	require.Equal(t, mockPrices[ATOMUSD], respBody.Prices[ATOMUSD])
+}
+
 func (rts *RouterTestSuite) TestPrices() {
 	req, err := http.NewRequest("GET", "/api/v1/prices", nil)
 	rts.Require().NoError(err)
@@ -128,6 +401,97 @@ func (rts *RouterTestSuite) TestPrices() {
 	rts.Require().Equal(respBody.Prices[FOOUSD], math.LegacyDec{})
 }
 
+func (rts *RouterTestSuite) TestPricesIncludeStale() {
+	req, err := http.NewRequest("GET", "/api/v1/prices", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Empty(respBody.StalePrices)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?include_stale=true", nil)
+	rts.Require().NoError(err)
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.PricesResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockPrices[ATOMUSD], respBody.Prices[ATOMUSD])
+	rts.Require().Len(respBody.StalePrices, 1)
+	rts.Require().True(respBody.StalePrices[FOOUSD].Stale)
+	rts.Require().Equal(mockStalePrices[FOOUSD].Price, respBody.StalePrices[FOOUSD].Price)
+
+	age, err := time.ParseDuration(respBody.StalePrices[FOOUSD].Age)
+	rts.Require().NoError(err)
+	rts.Require().GreaterOrEqual(age, 5*time.Minute)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?include_stale=foo", nil)
+	rts.Require().NoError(err)
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestPricesSignature(t *testing.T) {
+	signed := &types.SignedPrices{
+		Snapshot:  json.RawMessage(`{"prices":{"ATOMUSD":"3.720000000000000000"},"timestamp":1,"height":2}`),
+		Signature: []byte("signature"),
+		PubKey:    []byte("pub_key"),
+	}
+	signedMux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, signedPricesOracle{signed: signed}, mockMetrics{}).
+		RegisterRoutes(signedMux, v1.APIPathPrefix)
+
+	// the signed snapshot of the oracle is served as is, scaled or not
+	for _, url := range []string{"/api/v1/prices", "/api/v1/prices?scale=micro"} {
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		signedMux.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var respBody v1.PricesResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+		require.Equal(t, signed, respBody.Signed)
+	}
+
+	// the prices are not signed without a signer
+	unsignedMux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, mockOracle{}, mockMetrics{}).
+		RegisterRoutes(unsignedMux, v1.APIPathPrefix)
+	req, err := http.NewRequest("GET", "/api/v1/prices", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	unsignedMux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotContains(t, rr.Body.String(), "signature")
+}
+
+func TestPricesScale(t *testing.T) {
+	mux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, scaledPricesOracle{}, mockMetrics{}).
+		RegisterRoutes(mux, v1.APIPathPrefix)
+
+	req, err := http.NewRequest("GET", "/api/v1/prices?scale=micro", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var respBody v1.ScaledPricesResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+	require.Equal(t, "micro", respBody.Scale)
+	require.Equal(t, uint64(6), respBody.Exponent)
+	require.Equal(t, "3720000", respBody.Prices[ATOMUSD].String())
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?scale=foo", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func (rts *RouterTestSuite) TestTvwap() {
 	req, err := http.NewRequest("GET", "/api/v1/prices/providers/tvwap", nil)
 	rts.Require().NoError(err)
@@ -155,3 +519,184 @@ func (rts *RouterTestSuite) TestVwap() {
 		mockComputedPrices[provider.ProviderBinance][ATOMUSD],
 	)
 }
+
+func (rts *RouterTestSuite) TestProviderPricesMetrics() {
+	req, err := http.NewRequest("GET", "/api/v1/metrics-prices", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+	rts.Require().True(strings.HasPrefix(response.Header().Get("Content-Type"), "text/plain"))
+
+	body := response.Body.String()
+	rts.Require().Contains(body, "# TYPE price_feeder_provider_price gauge\n")
+	for _, method := range []string{"vwap", "tvwap"} {
+		rts.Require().Contains(
+			body,
+			`price_feeder_provider_price{provider="binance",base="ATOM",quote="USD",method="`+method+`"} `+
+				mockComputedPrices[provider.ProviderBinance][ATOMUSD].String()+"\n",
+		)
+		rts.Require().Contains(
+			body,
+			`price_feeder_provider_price{provider="kraken",base="OJO",quote="USD",method="`+method+`"} `+
+				mockComputedPrices[provider.ProviderKraken][OJOUSD].String()+"\n",
+		)
+	}
+}
+
+func TestProviderPricesMetricsEmpty(t *testing.T) {
+	mux := mux.NewRouter()
+	v1.New(zerolog.Nop(), config.Config{}, emptyPricesOracle{}, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	req, err := http.NewRequest("GET", "/api/v1/metrics-prices", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Body.String())
+}
+
+func (rts *RouterTestSuite) TestConversionRoutes() {
+	req, err := http.NewRequest("GET", "/api/v1/conversions/routes", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ConversionRoutesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Routes[OJOUSDT], 1)
+	rts.Require().Equal(USDTUSD, respBody.Routes[OJOUSDT][0].Pair)
+	rts.Require().Equal(
+		mockConversionRoutes[OJOUSDT][0].Rate,
+		respBody.Routes[OJOUSDT][0].Rate,
+	)
+}
+
+func (rts *RouterTestSuite) TestAssetProviderPrices() {
+	req, err := http.NewRequest("GET", "/api/v1/prices/atom/providers", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.AssetProviderPricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal("ATOM", respBody.Base)
+	rts.Require().Len(respBody.Providers, 2)
+	krakenPrices := respBody.Providers[provider.ProviderKraken]
+	rts.Require().Equal(mockComputedPrices[provider.ProviderKraken][ATOMUSD], *krakenPrices.VWAP)
+	rts.Require().Equal(mockComputedPrices[provider.ProviderKraken][ATOMUSD], *krakenPrices.TVWAP)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices/foo/providers", nil)
+	rts.Require().NoError(err)
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusNotFound, response.Code)
+}
+
+func (rts *RouterTestSuite) TestPairPrices() {
+	req, err := http.NewRequest("GET", "/api/v1/prices/atom/usd", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PairPricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(ATOMUSD, respBody.Pair)
+	rts.Require().Equal(mockPrices[ATOMUSD], *respBody.Price)
+	rts.Require().Len(respBody.Providers, 2)
+	binancePrices := respBody.Providers[provider.ProviderBinance]
+	rts.Require().Equal(mockComputedPrices[provider.ProviderBinance][ATOMUSD], *binancePrices.VWAP)
+	rts.Require().Equal(mockComputedPrices[provider.ProviderBinance][ATOMUSD], *binancePrices.TVWAP)
+	rts.Require().Equal([]types.ProviderName{provider.ProviderKraken}, respBody.DeviatingProviders)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices/foo/usdt", nil)
+	rts.Require().NoError(err)
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusNotFound, response.Code)
+}
+
+func (rts *RouterTestSuite) TestPriceDebug() {
+	req, err := http.NewRequest("GET", "/api/v1/prices/ojo/debug", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PriceDebugResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal("OJO", respBody.Debug.Base)
+	rts.Require().Len(respBody.Debug.Providers, 1)
+	rts.Require().Equal(provider.ProviderBinance, respBody.Debug.Providers[0].Provider)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices/foo/debug", nil)
+	rts.Require().NoError(err)
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusNotFound, response.Code)
+}
+
+func (rts *RouterTestSuite) TestSubscriptions() {
+	req, err := http.NewRequest("GET", "/api/v1/subscriptions", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.SubscriptionsResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockProviderPairs, respBody.Subscriptions)
+}
+
+func (rts *RouterTestSuite) TestMissedVotes() {
+	req, err := http.NewRequest("GET", "/api/v1/votes/missed", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.MissedVotesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.MissedVotes, 2)
+	rts.Require().Equal(int64(100), respBody.MissedVotes[0].Height)
+	rts.Require().Equal(types.MissedVoteReasonBroadcastFailure, respBody.MissedVotes[0].Reason)
+	rts.Require().Equal("connection refused", respBody.MissedVotes[0].Error)
+	rts.Require().Equal(int64(105), respBody.MissedVotes[1].Height)
+	rts.Require().Equal(types.MissedVoteReasonVotePeriodMissed, respBody.MissedVotes[1].Reason)
+}
+
+func TestVotingPause(t *testing.T) {
+	mux := mux.NewRouter()
+	cfg := config.Config{
+		Server: config.Server{
+			AdminToken: "secret",
+		},
+	}
+	var paused bool
+	v1.New(zerolog.Nop(), cfg, votingOracle{paused: &paused}, mockMetrics{}).
+		RegisterRoutes(mux, v1.APIPathPrefix)
+
+	votingRequest := func(path, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// requests without the admin token are rejected
+	require.Equal(t, http.StatusUnauthorized, votingRequest("/api/v1/voting/pause", "").Code)
+	require.Equal(t, http.StatusUnauthorized, votingRequest("/api/v1/voting/pause", "wrong").Code)
+	require.False(t, paused)
+
+	rr := votingRequest("/api/v1/voting/pause", "secret")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var respBody v1.VotingResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+	require.True(t, respBody.Paused)
+	require.True(t, paused)
+
+	rr = votingRequest("/api/v1/voting/resume", "secret")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+	require.False(t, respBody.Paused)
+	require.False(t, paused)
+}
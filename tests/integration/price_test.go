@@ -40,17 +40,21 @@ func TestPriceAccuracy(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	providerTimeout, _, err := cfg.ProviderTimeouts()
 	require.NoError(t, err)
 
 	deviations, err := cfg.DeviationsMap()
 	require.NoError(t, err)
 
+	tickInterval, err := time.ParseDuration(cfg.TickInterval)
+	require.NoError(t, err)
+
 	oracle := oracle.New(
 		logger,
 		client.OracleClient{},
 		cfg.ProviderPairs(),
 		providerTimeout,
+		tickInterval,
 		deviations,
 		cfg.ProviderEndpointsMap(),
 		false,